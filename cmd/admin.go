@@ -25,6 +25,7 @@ var (
 			subcmdCreateUser,
 			subcmdChangePassword,
 			subcmdRepoSyncReleases,
+			subcmdMigrateStorage,
 		},
 	}
 
@@ -0,0 +1,152 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/urfave/cli"
+)
+
+var subcmdMigrateStorage = cli.Command{
+	Name:   "migrate-storage",
+	Usage:  "Copy the stored files from the configured storage to another storage",
+	Action: runMigrateStorage,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "type, t",
+			Value: "",
+			Usage: "Kind of files to migrate, one of 'attachments', 'lfs', 'avatars' or 'repo-archive'",
+		},
+		cli.StringFlag{
+			Name:  "storage, s",
+			Value: setting.LocalStorageType,
+			Usage: "Type of the target storage, either 'local' or 'minio'",
+		},
+		cli.StringFlag{
+			Name:  "path, p",
+			Value: "",
+			Usage: "Directory of the target storage when it is a local storage",
+		},
+		cli.StringFlag{
+			Name:  "minio-endpoint",
+			Value: "",
+			Usage: "Minio storage endpoint",
+		},
+		cli.StringFlag{
+			Name:  "minio-access-key-id",
+			Value: "",
+			Usage: "Minio storage accessKeyID",
+		},
+		cli.StringFlag{
+			Name:  "minio-secret-access-key",
+			Value: "",
+			Usage: "Minio storage secretAccessKey",
+		},
+		cli.StringFlag{
+			Name:  "minio-bucket",
+			Value: "",
+			Usage: "Minio storage bucket",
+		},
+		cli.StringFlag{
+			Name:  "minio-location",
+			Value: "",
+			Usage: "Minio storage location to create bucket",
+		},
+		cli.StringFlag{
+			Name:  "minio-base-path",
+			Value: "",
+			Usage: "Minio storage base path on the bucket",
+		},
+		cli.BoolFlag{
+			Name:  "minio-use-ssl",
+			Usage: "Enable SSL for minio",
+		},
+		cli.StringFlag{
+			Name:  "config, c",
+			Value: "custom/conf/app.ini",
+			Usage: "Custom configuration file path",
+		},
+	},
+}
+
+func runMigrateStorage(c *cli.Context) error {
+	if err := argsSet(c, "type"); err != nil {
+		return err
+	}
+
+	if c.IsSet("config") {
+		setting.CustomConf = c.String("config")
+	}
+	setting.NewContext()
+
+	var srcCfg setting.Storage
+	switch tp := strings.ToLower(c.String("type")); tp {
+	case "attachments":
+		srcCfg = setting.AttachmentStorage
+	case "lfs":
+		srcCfg = setting.LFSStorage
+	case "avatars":
+		srcCfg = setting.AvatarStorage
+	case "repo-archive":
+		srcCfg = setting.RepoArchiveStorage
+	default:
+		return fmt.Errorf("Unsupported type: %s", tp)
+	}
+
+	dstCfg := setting.Storage{Type: strings.ToLower(c.String("storage"))}
+	switch dstCfg.Type {
+	case setting.LocalStorageType:
+		if !c.IsSet("path") {
+			return fmt.Errorf("--path is required for a local storage")
+		}
+		dstCfg.Path = c.String("path")
+		if !filepath.IsAbs(dstCfg.Path) {
+			dstCfg.Path = filepath.Join(setting.AppWorkPath, dstCfg.Path)
+		}
+	case setting.MinioStorageType:
+		dstCfg.Minio = setting.MinioStorageConfig{
+			Endpoint:        c.String("minio-endpoint"),
+			AccessKeyID:     c.String("minio-access-key-id"),
+			SecretAccessKey: c.String("minio-secret-access-key"),
+			Bucket:          c.String("minio-bucket"),
+			Location:        c.String("minio-location"),
+			BasePath:        c.String("minio-base-path"),
+			UseSSL:          c.Bool("minio-use-ssl"),
+		}
+	default:
+		return fmt.Errorf("Unsupported storage: %s", dstCfg.Type)
+	}
+
+	src, err := storage.NewStorage(srcCfg)
+	if err != nil {
+		return fmt.Errorf("source storage: %v", err)
+	}
+	dst, err := storage.NewStorage(dstCfg)
+	if err != nil {
+		return fmt.Errorf("target storage: %v", err)
+	}
+
+	var count int
+	if err = src.IterateObjects("", func(p string, obj storage.Object) error {
+		log.Trace("Migrating %s", p)
+		if _, err := dst.Save(p, obj); err != nil {
+			return fmt.Errorf("Save %s: %v", p, err)
+		}
+		count++
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d %s files have been migrated successfully!\n", count, c.String("type"))
+	return nil
+}
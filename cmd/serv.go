@@ -187,6 +187,11 @@ func runServ(c *cli.Context) error {
 		fail("mirror repository is read-only", "")
 	}
 
+	// Prohibit push to archived repositories.
+	if requestedMode > models.AccessModeRead && repo.IsArchived {
+		fail("repository is archived", "Cannot push to archived repository: %s/%s", username, reponame)
+	}
+
 	// Allow anonymous clone for public repositories.
 	var (
 		keyID int64
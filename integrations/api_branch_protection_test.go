@@ -0,0 +1,93 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIBranchProtection(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	const urlStr = "/api/v1/repos/user2/repo1/branch_protections"
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateBranchProtectionOption{
+		BranchName:             "master",
+		EnablePush:             true,
+		EnablePushWhitelist:    true,
+		PushWhitelistUsernames: []string{"user2"},
+		RequiredApprovals:      1,
+		EnableStatusCheck:      true,
+		StatusCheckContexts:    []string{"ci/build", "", "ci/build"},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var protection api.BranchProtection
+	DecodeJSON(t, resp, &protection)
+	assert.EqualValues(t, "master", protection.BranchName)
+	assert.True(t, protection.EnablePushWhitelist)
+	assert.EqualValues(t, []string{"user2"}, protection.PushWhitelistUsernames)
+	assert.EqualValues(t, []string{"ci/build"}, protection.StatusCheckContexts)
+	assert.EqualValues(t, 1, protection.RequiredApprovals)
+	models.AssertExistsAndLoadBean(t, &models.ProtectedBranch{RepoID: 1, BranchName: "master"})
+
+	// protecting it twice or protecting an unknown branch fails
+	session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.CreateBranchProtectionOption{
+		BranchName: "master",
+	}), http.StatusUnprocessableEntity)
+	session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.CreateBranchProtectionOption{
+		BranchName: "doesnotexist",
+	}), http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var protections []*api.BranchProtection
+	DecodeJSON(t, resp, &protections)
+	if assert.Len(t, protections, 1) {
+		assert.EqualValues(t, "master", protections[0].BranchName)
+	}
+
+	enableStatusCheck := false
+	req = NewRequestWithJSON(t, "PATCH", urlStr+"/master", &api.EditBranchProtectionOption{
+		EnableStatusCheck: &enableStatusCheck,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &protection)
+	assert.False(t, protection.EnableStatusCheck)
+	// fields which are not given are kept
+	assert.EqualValues(t, []string{"user2"}, protection.PushWhitelistUsernames)
+	assert.EqualValues(t, 1, protection.RequiredApprovals)
+
+	// whitelisted users must exist and have write access
+	session.MakeRequest(t, NewRequestWithJSON(t, "PATCH", urlStr+"/master", &api.EditBranchProtectionOption{
+		PushWhitelistUsernames: []string{"doesnotexist"},
+	}), http.StatusUnprocessableEntity)
+	session.MakeRequest(t, NewRequestWithJSON(t, "PATCH", urlStr+"/master", &api.EditBranchProtectionOption{
+		MergeWhitelistUsernames: []string{"user4"},
+	}), http.StatusUnprocessableEntity)
+	// teams can only be whitelisted in repositories of organizations
+	session.MakeRequest(t, NewRequestWithJSON(t, "PATCH", urlStr+"/master", &api.EditBranchProtectionOption{
+		PushWhitelistTeams: []string{"team1"},
+	}), http.StatusUnprocessableEntity)
+
+	session.MakeRequest(t, NewRequest(t, "DELETE", urlStr+"/master"), http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.ProtectedBranch{RepoID: 1, BranchName: "master"})
+	session.MakeRequest(t, NewRequest(t, "GET", urlStr+"/master"), http.StatusNotFound)
+}
+
+func TestAPIBranchProtectionNotAdmin(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user4")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+		BranchName: "master",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueDependencies(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	const urlStr = "/api/v1/repos/user2/repo1/issues/1/dependencies"
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.IssueMeta{Index: 3})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 3, apiIssue.Index)
+	models.AssertExistsAndLoadBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 3})
+
+	// issues of other repositories can be dependencies too
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.IssueMeta{Owner: "user2", Name: "repo2", Index: 1})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.IssueMeta{Index: 3}), http.StatusUnprocessableEntity)
+	session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.IssueMeta{Index: 1}), http.StatusUnprocessableEntity)
+	session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.IssueMeta{Index: 999}), http.StatusNotFound)
+
+	req = NewRequest(t, "GET", urlStr)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	assert.Len(t, apiIssues, 2)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/3/blocks")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.EqualValues(t, 1, apiIssues[0].Index)
+	}
+
+	// the issue can't be closed while it is blocked by an open issue
+	closed := string(api.StateClosed)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/issues/1", &api.EditIssueOption{State: &closed})
+	session.MakeRequest(t, req, http.StatusPreconditionFailed)
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.IssueMeta{Index: 3})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 3})
+	session.MakeRequest(t, NewRequestWithJSON(t, "DELETE", urlStr, &api.IssueMeta{Index: 3}), http.StatusNotFound)
+}
+
+func TestAPIIssueBlocks(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	const urlStr = "/api/v1/repos/user2/repo1/issues/3/blocks"
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.IssueMeta{Index: 1})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 1, apiIssue.Index)
+	models.AssertExistsAndLoadBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 3})
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.IssueMeta{Index: 1})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.IssueDependency{IssueID: 1, DependencyID: 3})
+}
+
+func TestAPIIssueDependenciesNotWriter(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user4")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/dependencies", &api.IssueMeta{Index: 3})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -44,20 +44,23 @@ func TestAPICreateIssue(t *testing.T) {
 
 	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues?state=all", owner.Name, repo.Name)
 	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueOption{
-		Body:     body,
-		Title:    title,
-		Assignee: owner.Name,
+		Body:      body,
+		Title:     title,
+		Assignees: []string{owner.Name},
 	})
 	resp := session.MakeRequest(t, req, http.StatusCreated)
 	var apiIssue api.Issue
 	DecodeJSON(t, resp, &apiIssue)
 	assert.Equal(t, apiIssue.Body, body)
 	assert.Equal(t, apiIssue.Title, title)
+	if assert.Len(t, apiIssue.Assignees, 1) {
+		assert.Equal(t, owner.Name, apiIssue.Assignees[0].UserName)
+	}
 
-	models.AssertExistsAndLoadBean(t, &models.Issue{
-		RepoID:     repo.ID,
-		AssigneeID: owner.ID,
-		Content:    body,
-		Title:      title,
-	})
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{
+		RepoID:  repo.ID,
+		Content: body,
+		Title:   title,
+	}).(*models.Issue)
+	models.AssertExistsAndLoadBean(t, &models.IssueAssignees{IssueID: issue.ID, AssigneeID: owner.ID})
 }
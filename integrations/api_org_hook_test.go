@@ -0,0 +1,22 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAPIOrgHookDeliveriesRequireOwner(t *testing.T) {
+	prepareTestEnv(t)
+	const urlStr = "/api/v1/orgs/user3/hooks/3/deliveries"
+
+	// user4 is a member of the organization, but not an owner
+	session := loginUser(t, "user4")
+	session.MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusForbidden)
+
+	session = loginUser(t, "user2")
+	session.MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusOK)
+}
@@ -0,0 +1,34 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIArchivedRepoIsReadOnly(t *testing.T) {
+	prepareTestEnv(t)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.SetArchiveRepoState(true))
+
+	session := loginUser(t, "user2")
+	for _, req := range []*http.Request{
+		NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/labels", &api.CreateLabelOption{Name: "archived", Color: "#abcdef"}),
+		NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/labels/1"),
+		NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/labels", &api.IssueLabelsOption{Labels: []int64{2}}),
+		NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/times", &api.AddTimeOption{Time: 60}),
+		NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/milestones", &api.CreateMilestoneOption{Title: "archived"}),
+		NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/milestones/1"),
+		NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/statuses/"+repo1CommitSHA, &api.CreateStatusOption{State: api.StatusSuccess}),
+	} {
+		session.MakeRequest(t, req, http.StatusForbidden)
+	}
+}
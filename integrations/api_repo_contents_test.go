@@ -0,0 +1,109 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const readmeSHA = "4b4851ad51df6a7d9f25c979345979eaeb5b349f"
+
+func TestAPIGetContents(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var contents api.ContentsResponse
+	DecodeJSON(t, resp, &contents)
+	assert.EqualValues(t, "README.md", contents.Name)
+	assert.EqualValues(t, "file", contents.Type)
+	assert.EqualValues(t, readmeSHA, contents.SHA)
+	assert.EqualValues(t, "base64", contents.Encoding)
+	content, err := base64.StdEncoding.DecodeString(contents.Content)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "# repo1\n\nDescription for repo1", string(content))
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var entries []*api.ContentsResponse
+	DecodeJSON(t, resp, &entries)
+	if assert.Len(t, entries, 1) {
+		assert.EqualValues(t, "README.md", entries[0].Path)
+		assert.Empty(t, entries[0].Content)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?ref=v1.1")
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/README.md?ref=doesnotexist")
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/doesnotexist.md")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPICreateUpdateDeleteFile(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	const urlStr = "/api/v1/repos/user2/repo1/contents/docs/VERSION"
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("1.0.0\n")),
+		Message: "Add VERSION",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var created api.FileResponse
+	DecodeJSON(t, resp, &created)
+	assert.EqualValues(t, "docs/VERSION", created.Content.Path)
+	assert.EqualValues(t, "Add VERSION", strings.TrimSpace(created.Commit.Message))
+
+	// creating it again fails
+	session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("1.0.0\n")),
+	}), http.StatusUnprocessableEntity)
+
+	// an outdated sha is a conflict
+	session.MakeRequest(t, NewRequestWithJSON(t, "PUT", urlStr, &api.UpdateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("1.0.1\n")),
+		SHA:     readmeSHA,
+	}), http.StatusConflict)
+
+	req = NewRequestWithJSON(t, "PUT", urlStr, &api.UpdateFileOptions{
+		Content:   base64.StdEncoding.EncodeToString([]byte("1.0.1\n")),
+		SHA:       created.Content.SHA,
+		NewBranch: "bump-version",
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var updated api.FileResponse
+	DecodeJSON(t, resp, &updated)
+	assert.NotEqual(t, created.Content.SHA, updated.Content.SHA)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/bump-version"), http.StatusOK)
+
+	req = NewRequestWithJSON(t, "DELETE", urlStr, &api.DeleteFileOptions{
+		SHA: created.Content.SHA,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var deleted api.FileResponse
+	DecodeJSON(t, resp, &deleted)
+	assert.Nil(t, deleted.Content)
+	assert.EqualValues(t, "Delete 'docs/VERSION'", strings.TrimSpace(deleted.Commit.Message))
+	session.MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusNotFound)
+}
+
+func TestAPICreateFileNotWriter(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user4")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/NEW.md", &api.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte("new")),
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	repo1CommitSHA = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	repo1TreeSHA   = "2a2f1d4670728a2e10049e345bd7a276468beab6"
+)
+
+func TestAPIListCommits(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	for _, urlStr := range []string{
+		"/api/v1/repos/user2/repo1/commits",
+		"/api/v1/repos/user2/repo1/commits?sha=v1.1",
+		"/api/v1/repos/user2/repo1/commits?path=README.md",
+	} {
+		resp := session.MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusOK)
+		var commits []*api.Commit
+		DecodeJSON(t, resp, &commits)
+		if assert.Len(t, commits, 1) {
+			assert.EqualValues(t, repo1CommitSHA, commits[0].SHA)
+			assert.EqualValues(t, repo1TreeSHA, commits[0].Tree.SHA)
+		}
+		assert.EqualValues(t, "1", resp.Header().Get("X-Total-Count"))
+	}
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits?sha=doesnotexist")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIGetSingleCommit(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/git/commits/%s", repo1CommitSHA)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var commit api.Commit
+	DecodeJSON(t, resp, &commit)
+	assert.EqualValues(t, repo1CommitSHA, commit.SHA)
+	assert.Len(t, commit.Parents, 0)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/0000000000000000000000000000000000000000")
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIGetTreeAndBlob(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	for _, sha := range []string{repo1TreeSHA, repo1CommitSHA, "master"} {
+		req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/git/trees/%s?recursive=1", sha)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var tree api.GitTreeResponse
+		DecodeJSON(t, resp, &tree)
+		assert.EqualValues(t, repo1TreeSHA, tree.SHA)
+		assert.False(t, tree.Truncated)
+		assert.EqualValues(t, 1, tree.TotalCount)
+		if assert.Len(t, tree.Entries, 1) {
+			assert.EqualValues(t, "README.md", tree.Entries[0].Path)
+			assert.EqualValues(t, "blob", tree.Entries[0].Type)
+			assert.EqualValues(t, "100644", tree.Entries[0].Mode)
+			assert.EqualValues(t, readmeSHA, tree.Entries[0].SHA)
+		}
+	}
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/git/blobs/%s", readmeSHA)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var blob api.GitBlobResponse
+	DecodeJSON(t, resp, &blob)
+	assert.EqualValues(t, readmeSHA, blob.SHA)
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "# repo1\n\nDescription for repo1", string(content))
+	assert.EqualValues(t, len(content), blob.Size)
+
+	defer func(maxBlobSize int64) {
+		setting.API.MaxBlobSize = maxBlobSize
+	}(setting.API.MaxBlobSize)
+	setting.API.MaxBlobSize = 10
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIListGitRefsAndTags(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	resp := session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs"), http.StatusOK)
+	var refs []*api.Reference
+	DecodeJSON(t, resp, &refs)
+	assert.Len(t, refs, 5)
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs/heads/master"), http.StatusOK)
+	DecodeJSON(t, resp, &refs)
+	if assert.Len(t, refs, 1) {
+		assert.EqualValues(t, "refs/heads/master", refs[0].Ref)
+		assert.EqualValues(t, repo1CommitSHA, refs[0].Object.SHA)
+		assert.EqualValues(t, "commit", refs[0].Object.Type)
+	}
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/refs/heads/doesnotexist"), http.StatusNotFound)
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/tags"), http.StatusOK)
+	var tags []*api.Tag
+	DecodeJSON(t, resp, &tags)
+	if assert.Len(t, tags, 1) {
+		assert.EqualValues(t, "v1.1", tags[0].Name)
+		assert.EqualValues(t, repo1CommitSHA, tags[0].Commit.SHA)
+	}
+}
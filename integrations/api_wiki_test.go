@@ -0,0 +1,93 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIListAndGetWikiPages(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	resp := session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/wiki/pages"), http.StatusOK)
+	var pages []*api.WikiPage
+	DecodeJSON(t, resp, &pages)
+	if assert.Len(t, pages, 1) {
+		assert.EqualValues(t, "Home", pages[0].Title)
+		assert.Empty(t, pages[0].Content)
+		assert.NotNil(t, pages[0].LastCommit)
+	}
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/wiki/pages/Home"), http.StatusOK)
+	var page api.WikiPage
+	DecodeJSON(t, resp, &page)
+	assert.EqualValues(t, "# Home page\n\nThis is the home page!\n", page.Content)
+	assert.Contains(t, page.HTML, "This is the home page!")
+	assert.EqualValues(t, "Add Home.md", strings.TrimSpace(page.LastCommit.Message))
+
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/wiki/pages/DoesNotExist"), http.StatusNotFound)
+}
+
+func TestAPICreateEditDeleteWikiPage(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/wiki/pages", &api.CreateWikiPageOptions{
+		Title:   "Deploy Runbook",
+		Content: "# Deploy\n\nRun `make deploy`.",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var page api.WikiPage
+	DecodeJSON(t, resp, &page)
+	assert.EqualValues(t, "Deploy Runbook", page.Title)
+	assert.EqualValues(t, "Deploy-Runbook", page.SubURL)
+
+	// the page already exists
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	content := "# Deploy\n\nRun `make release`."
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/wiki/pages/Deploy-Runbook", &api.EditWikiPageOptions{
+		Content: &content,
+		Message: "Use the release target",
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &page)
+	assert.EqualValues(t, content, page.Content)
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/wiki/pages/Deploy-Runbook/revisions"), http.StatusOK)
+	var revisions []*api.WikiCommit
+	DecodeJSON(t, resp, &revisions)
+	if assert.Len(t, revisions, 2) {
+		assert.EqualValues(t, "Use the release target", strings.TrimSpace(revisions[0].Message))
+	}
+
+	// renaming a page must not overwrite another one
+	title := "Home"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1/wiki/pages/Deploy-Runbook", &api.EditWikiPageOptions{
+		Title: &title,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	session.MakeRequest(t, NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/wiki/pages/Deploy-Runbook"), http.StatusNoContent)
+	session.MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/wiki/pages/Deploy-Runbook"), http.StatusNotFound)
+}
+
+func TestAPICreateWikiPageNotWriter(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user4")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/wiki/pages", &api.CreateWikiPageOptions{
+		Title:   "Page",
+		Content: "content",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
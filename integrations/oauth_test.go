@@ -0,0 +1,53 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	oauthTestClientID     = "da7da3ba-9a13-4167-856f-3899de0b0138"
+	oauthTestClientSecret = "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA"
+)
+
+func TestRefreshTokenRequiresClientSecret(t *testing.T) {
+	prepareTestEnv(t)
+
+	refreshToken, err := (&models.OAuth2Token{
+		GrantID: 1,
+		Type:    models.TypeRefreshToken,
+		Counter: 1,
+	}).SignToken()
+	assert.NoError(t, err)
+
+	req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     oauthTestClientID,
+		"refresh_token": refreshToken,
+	})
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     oauthTestClientID,
+		"client_secret": "invalid",
+		"refresh_token": refreshToken,
+	})
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     oauthTestClientID,
+		"client_secret": oauthTestClientSecret,
+		"refresh_token": refreshToken,
+	})
+	MakeRequest(t, req, http.StatusOK)
+}
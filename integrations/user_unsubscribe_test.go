@@ -0,0 +1,44 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsubscribe(t *testing.T) {
+	prepareTestEnv(t)
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	urlStr := "/user/unsubscribe/" + user.UnsubscribeToken()
+
+	// opening the link only asks for confirmation
+	MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusOK)
+	user = models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.NotEqual(t, models.EmailNotificationsDisabled, user.EmailNotifications())
+
+	MakeRequest(t, NewRequest(t, "POST", urlStr), http.StatusOK)
+	user = models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.Equal(t, models.EmailNotificationsDisabled, user.EmailNotifications())
+}
+
+func TestUnsubscribeOneClick(t *testing.T) {
+	prepareTestEnv(t)
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	req := NewRequestWithValues(t, "POST", "/user/unsubscribe/"+user.UnsubscribeToken(), map[string]string{
+		"List-Unsubscribe": "One-Click",
+	})
+	MakeRequest(t, req, http.StatusOK)
+	user = models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.Equal(t, models.EmailNotificationsDisabled, user.EmailNotifications())
+
+	MakeRequest(t, NewRequest(t, "GET", "/user/unsubscribe/2.invalid"), http.StatusNotFound)
+}
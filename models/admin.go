@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 
 	"github.com/Unknwon/com"
@@ -69,6 +70,39 @@ func removeAllWithNotice(e Engine, title, path string) {
 	}
 }
 
+// removeStorageWithNotice removes the object of given path from the storage and
+// creates a system notice when error occurs.
+func removeStorageWithNotice(e Engine, bucket storage.ObjectStorage, title, path string) {
+	if err := bucket.Delete(path); err != nil {
+		desc := fmt.Sprintf("%s [%s]: %v", title, path, err)
+		log.Warn(desc)
+		if err = createNotice(e, NoticeRepository, desc); err != nil {
+			log.Error(4, "CreateRepositoryNotice: %v", err)
+		}
+	}
+}
+
+// removeStorageDirWithNotice removes all objects below the directory of the
+// storage, and creates a system notice when error occurs.
+func removeStorageDirWithNotice(e Engine, bucket storage.ObjectStorage, title, dir string) {
+	var paths []string
+	if err := bucket.IterateObjects(dir, func(path string, obj storage.Object) error {
+		paths = append(paths, path)
+		return nil
+	}); err != nil {
+		desc := fmt.Sprintf("%s [%s]: %v", title, dir, err)
+		log.Warn(desc)
+		if err = createNotice(e, NoticeRepository, desc); err != nil {
+			log.Error(4, "CreateRepositoryNotice: %v", err)
+		}
+		return
+	}
+
+	for _, path := range paths {
+		removeStorageWithNotice(e, bucket, title, path)
+	}
+}
+
 // CountNotices returns number of notices.
 func CountNotices() int64 {
 	count, _ := x.Count(new(Notice))
@@ -5,13 +5,14 @@
 package models
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"os"
 	"path"
 
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 	api "code.gitea.io/sdk/gitea"
 
@@ -55,20 +56,25 @@ func (a *Attachment) APIFormat() *api.Attachment {
 	}
 }
 
-// AttachmentLocalPath returns where attachment is stored in local file
-// system based on given UUID.
-func AttachmentLocalPath(uuid string) string {
-	return path.Join(setting.AttachmentPath, uuid[0:1], uuid[1:2], uuid)
+// AttachmentRelativePath returns the relative path of the attachment
+// with the given UUID inside the attachments storage.
+func AttachmentRelativePath(uuid string) string {
+	return path.Join(uuid[0:1], uuid[1:2], uuid)
 }
 
-// LocalPath returns where attachment is stored in local file system.
-func (a *Attachment) LocalPath() string {
-	return AttachmentLocalPath(a.UUID)
+// RelativePath returns the relative path of the attachment inside the attachments storage.
+func (a *Attachment) RelativePath() string {
+	return AttachmentRelativePath(a.UUID)
+}
+
+// Open opens the stored file of the attachment for reading.
+func (a *Attachment) Open() (storage.Object, error) {
+	return storage.Attachments.Open(a.RelativePath())
 }
 
 // Size returns the file's size of the attachment
 func (a *Attachment) Size() (int64, error) {
-	fi, err := os.Stat(a.LocalPath())
+	fi, err := storage.Attachments.Stat(a.RelativePath())
 	if err != nil {
 		return 0, err
 	}
@@ -87,21 +93,8 @@ func NewAttachment(name string, buf []byte, file multipart.File) (_ *Attachment,
 		Name: name,
 	}
 
-	localPath := attach.LocalPath()
-	if err = os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
-		return nil, fmt.Errorf("MkdirAll: %v", err)
-	}
-
-	fw, err := os.Create(localPath)
-	if err != nil {
-		return nil, fmt.Errorf("Create: %v", err)
-	}
-	defer fw.Close()
-
-	if _, err = fw.Write(buf); err != nil {
-		return nil, fmt.Errorf("Write: %v", err)
-	} else if _, err = io.Copy(fw, file); err != nil {
-		return nil, fmt.Errorf("Copy: %v", err)
+	if _, err = storage.Attachments.Save(attach.RelativePath(), io.MultiReader(bytes.NewReader(buf), file)); err != nil {
+		return nil, fmt.Errorf("Save: %v", err)
 	}
 
 	if _, err := x.Insert(attach); err != nil {
@@ -197,7 +190,7 @@ func DeleteAttachments(attachments []*Attachment, remove bool) (int, error) {
 
 	if remove {
 		for i, a := range attachments {
-			if err := os.Remove(a.LocalPath()); err != nil {
+			if err := storage.Attachments.Delete(a.RelativePath()); err != nil {
 				return i, err
 			}
 		}
@@ -78,3 +78,75 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestProtectedBranchCanUserPush(t *testing.T) {
+	protectBranch := &ProtectedBranch{}
+	assert.False(t, protectBranch.CanUserPush(2))
+
+	protectBranch.CanPush = true
+	assert.True(t, protectBranch.CanUserPush(2))
+
+	protectBranch.EnableWhitelist = true
+	protectBranch.WhitelistUserIDs = []int64{2}
+	assert.True(t, protectBranch.CanUserPush(2))
+	assert.False(t, protectBranch.CanUserPush(3))
+
+	// the whitelist only restricts who may push if pushing is enabled at all
+	protectBranch.CanPush = false
+	assert.False(t, protectBranch.CanUserPush(2))
+	assert.False(t, protectBranch.CanUserPush(3))
+}
+
+func TestProtectedBranchCanUserMerge(t *testing.T) {
+	protectBranch := &ProtectedBranch{}
+	assert.True(t, protectBranch.CanUserMerge(2))
+
+	protectBranch.EnableMergeWhitelist = true
+	protectBranch.MergeWhitelistUserIDs = []int64{2}
+	assert.True(t, protectBranch.CanUserMerge(2))
+	assert.False(t, protectBranch.CanUserMerge(3))
+}
+
+func TestProtectedBranchGetGrantedApprovalsCount(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+	protectBranch := &ProtectedBranch{RepoID: repo.ID, BranchName: "master", RequiredApprovals: 1}
+
+	// reviewers without write access do not count
+	approvals, err := protectBranch.GetGrantedApprovalsCount(pr)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, approvals)
+
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	assert.NoError(t, repo.AddCollaborator(user5))
+	approvals, err = protectBranch.GetGrantedApprovalsCount(pr)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, approvals)
+}
+
+func TestPullRequestCheckUserAllowedToMerge(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	// unprotected branch
+	assert.NoError(t, pr.CheckUserAllowedToMerge(user2))
+
+	protectBranch := &ProtectedBranch{
+		RepoID:                pr.BaseRepoID,
+		BranchName:            pr.BaseBranch,
+		EnableMergeWhitelist:  true,
+		MergeWhitelistUserIDs: []int64{user2.ID},
+	}
+	_, err := x.Insert(protectBranch)
+	assert.NoError(t, err)
+	assert.NoError(t, pr.CheckUserAllowedToMerge(user2))
+	assert.True(t, IsErrNotAllowedToMerge(pr.CheckUserAllowedToMerge(user3)))
+
+	protectBranch.RequiredApprovals = 1
+	_, err = x.ID(protectBranch.ID).Cols("required_approvals").Update(protectBranch)
+	assert.NoError(t, err)
+	assert.True(t, IsErrNotAllowedToMerge(pr.CheckUserAllowedToMerge(user2)))
+}
@@ -483,6 +483,36 @@ func (err ErrAccessTokenEmpty) Error() string {
 	return fmt.Sprintf("access token is empty")
 }
 
+// ErrAccessTokenExpired represents a "AccessTokenExpired" kind of error.
+type ErrAccessTokenExpired struct {
+	ID int64
+}
+
+// IsErrAccessTokenExpired checks if an error is a ErrAccessTokenExpired.
+func IsErrAccessTokenExpired(err error) bool {
+	_, ok := err.(ErrAccessTokenExpired)
+	return ok
+}
+
+func (err ErrAccessTokenExpired) Error() string {
+	return fmt.Sprintf("access token has expired [id: %d]", err.ID)
+}
+
+// ErrInvalidAccessTokenScope represents a "InvalidAccessTokenScope" kind of error.
+type ErrInvalidAccessTokenScope struct {
+	Scope string
+}
+
+// IsErrInvalidAccessTokenScope checks if an error is a ErrInvalidAccessTokenScope.
+func IsErrInvalidAccessTokenScope(err error) bool {
+	_, ok := err.(ErrInvalidAccessTokenScope)
+	return ok
+}
+
+func (err ErrInvalidAccessTokenScope) Error() string {
+	return fmt.Sprintf("invalid access token scope [scope: %s]", err.Scope)
+}
+
 // ________                            .__                __  .__
 // \_____  \_______  _________    ____ |__|____________ _/  |_|__| ____   ____
 //  /   |   \_  __ \/ ___\__  \  /    \|  \___   /\__  \\   __\  |/  _ \ /    \
@@ -623,6 +653,22 @@ func (err ErrRepoAlreadyExist) Error() string {
 	return fmt.Sprintf("repository already exists [uname: %s, name: %s]", err.Uname, err.Name)
 }
 
+// ErrRepoArchived represents a "RepoArchived" kind of error.
+type ErrRepoArchived struct {
+	ID   int64
+	Name string
+}
+
+// IsErrRepoArchived checks if an error is a ErrRepoArchived.
+func IsErrRepoArchived(err error) bool {
+	_, ok := err.(ErrRepoArchived)
+	return ok
+}
+
+func (err ErrRepoArchived) Error() string {
+	return fmt.Sprintf("repository is archived [id: %d, name: %s]", err.ID, err.Name)
+}
+
 // ErrRepoRedirectNotExist represents a "RepoRedirectNotExist" kind of error.
 type ErrRepoRedirectNotExist struct {
 	OwnerID  int64
@@ -733,6 +779,53 @@ func (err ErrRepoFileAlreadyExist) Error() string {
 	return fmt.Sprintf("repository file already exists [file_name: %s]", err.FileName)
 }
 
+// ErrSHADoesNotMatch represents a "SHADoesNotMatch" kind of error.
+type ErrSHADoesNotMatch struct {
+	Path       string
+	GivenSHA   string
+	CurrentSHA string
+}
+
+// IsErrSHADoesNotMatch checks if an error is a ErrSHADoesNotMatch.
+func IsErrSHADoesNotMatch(err error) bool {
+	_, ok := err.(ErrSHADoesNotMatch)
+	return ok
+}
+
+func (err ErrSHADoesNotMatch) Error() string {
+	return fmt.Sprintf("sha does not match [path: %s, given_sha: %s, current_sha: %s]", err.Path, err.GivenSHA, err.CurrentSHA)
+}
+
+// ErrPushMirrorNotExist represents a "PushMirrorNotExist" kind of error.
+type ErrPushMirrorNotExist struct {
+	ID int64
+}
+
+// IsErrPushMirrorNotExist checks if an error is a ErrPushMirrorNotExist.
+func IsErrPushMirrorNotExist(err error) bool {
+	_, ok := err.(ErrPushMirrorNotExist)
+	return ok
+}
+
+func (err ErrPushMirrorNotExist) Error() string {
+	return fmt.Sprintf("push mirror does not exist [id: %d]", err.ID)
+}
+
+// ErrTopicNotExist represents a "TopicNotExist" kind of error.
+type ErrTopicNotExist struct {
+	Name string
+}
+
+// IsErrTopicNotExist checks if an error is a ErrTopicNotExist.
+func IsErrTopicNotExist(err error) bool {
+	_, ok := err.(ErrTopicNotExist)
+	return ok
+}
+
+func (err ErrTopicNotExist) Error() string {
+	return fmt.Sprintf("topic does not exist [name: %s]", err.Name)
+}
+
 // __________                             .__
 // \______   \____________    ____   ____ |  |__
 //  |    |  _/\_  __ \__  \  /    \_/ ___\|  |  \
@@ -822,6 +915,57 @@ func (err ErrWebhookNotExist) Error() string {
 	return fmt.Sprintf("webhook does not exist [id: %d]", err.ID)
 }
 
+// ErrHookTaskNotExist represents a "HookTaskNotExist" kind of error.
+type ErrHookTaskNotExist struct {
+	HookID int64
+	UUID   string
+}
+
+// IsErrHookTaskNotExist checks if an error is a ErrHookTaskNotExist.
+func IsErrHookTaskNotExist(err error) bool {
+	_, ok := err.(ErrHookTaskNotExist)
+	return ok
+}
+
+func (err ErrHookTaskNotExist) Error() string {
+	return fmt.Sprintf("hook task does not exist [hook_id: %d, uuid: %s]", err.HookID, err.UUID)
+}
+
+// Watch
+
+// ErrWatchNotExist represents a "WatchNotExist" kind of error.
+type ErrWatchNotExist struct {
+	UserID int64
+	RepoID int64
+}
+
+// IsErrWatchNotExist checks if an error is a ErrWatchNotExist.
+func IsErrWatchNotExist(err error) bool {
+	_, ok := err.(ErrWatchNotExist)
+	return ok
+}
+
+func (err ErrWatchNotExist) Error() string {
+	return fmt.Sprintf("watch does not exist [user_id: %d, repo_id: %d]", err.UserID, err.RepoID)
+}
+
+// Notification
+
+// ErrNotificationNotExist represents a "NotificationNotExist" kind of error.
+type ErrNotificationNotExist struct {
+	ID int64
+}
+
+// IsErrNotificationNotExist checks if an error is a ErrNotificationNotExist.
+func IsErrNotificationNotExist(err error) bool {
+	_, ok := err.(ErrNotificationNotExist)
+	return ok
+}
+
+func (err ErrNotificationNotExist) Error() string {
+	return fmt.Sprintf("notification does not exist [id: %d]", err.ID)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -846,6 +990,87 @@ func (err ErrIssueNotExist) Error() string {
 	return fmt.Sprintf("issue does not exist [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
 }
 
+// ErrInvalidReplyToken represents a "InvalidReplyToken" kind of error.
+type ErrInvalidReplyToken struct {
+	Token string
+}
+
+// IsErrInvalidReplyToken checks if an error is a ErrInvalidReplyToken.
+func IsErrInvalidReplyToken(err error) bool {
+	_, ok := err.(ErrInvalidReplyToken)
+	return ok
+}
+
+func (err ErrInvalidReplyToken) Error() string {
+	return fmt.Sprintf("invalid reply token [token: %s]", err.Token)
+}
+
+// ErrDependencyExists represents a "DependencyExists" kind of error.
+type ErrDependencyExists struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyExists checks if an error is a ErrDependencyExists.
+func IsErrDependencyExists(err error) bool {
+	_, ok := err.(ErrDependencyExists)
+	return ok
+}
+
+func (err ErrDependencyExists) Error() string {
+	return fmt.Sprintf("issue dependency already exists [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependencyNotExist represents a "DependencyNotExist" kind of error.
+type ErrDependencyNotExist struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrDependencyNotExist checks if an error is a ErrDependencyNotExist.
+func IsErrDependencyNotExist(err error) bool {
+	_, ok := err.(ErrDependencyNotExist)
+	return ok
+}
+
+func (err ErrDependencyNotExist) Error() string {
+	return fmt.Sprintf("issue dependency does not exist [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrCircularDependency represents a "CircularDependency" kind of error.
+type ErrCircularDependency struct {
+	IssueID      int64
+	DependencyID int64
+}
+
+// IsErrCircularDependency checks if an error is a ErrCircularDependency.
+func IsErrCircularDependency(err error) bool {
+	_, ok := err.(ErrCircularDependency)
+	return ok
+}
+
+func (err ErrCircularDependency) Error() string {
+	return fmt.Sprintf("issue dependency would be circular [issue_id: %d, dependency_id: %d]", err.IssueID, err.DependencyID)
+}
+
+// ErrDependenciesLeft represents an error that an issue can't be closed
+// because it is still blocked by open issues. NumHidden is the number of
+// those issues in repositories the doer can't read.
+type ErrDependenciesLeft struct {
+	IssueID   int64
+	NumHidden int
+}
+
+// IsErrDependenciesLeft checks if an error is a ErrDependenciesLeft.
+func IsErrDependenciesLeft(err error) bool {
+	_, ok := err.(ErrDependenciesLeft)
+	return ok
+}
+
+func (err ErrDependenciesLeft) Error() string {
+	return fmt.Sprintf("issue still has open dependencies [issue_id: %d, num_hidden: %d]", err.IssueID, err.NumHidden)
+}
+
 // __________      .__  .__ __________                                     __
 // \______   \__ __|  | |  |\______   \ ____  ________ __   ____   _______/  |_
 //  |     ___/  |  \  | |  | |       _// __ \/ ____/  |  \_/ __ \ /  ___/\   __\
@@ -913,6 +1138,22 @@ func (err ErrInvalidMergeStyle) Error() string {
 		err.ID, err.Style)
 }
 
+// ErrNotAllowedToMerge represents an error that a pull request may not be merged
+// because of the protection rules of its base branch or open dependencies
+type ErrNotAllowedToMerge struct {
+	Reason string
+}
+
+// IsErrNotAllowedToMerge checks if an error is an ErrNotAllowedToMerge.
+func IsErrNotAllowedToMerge(err error) bool {
+	_, ok := err.(ErrNotAllowedToMerge)
+	return ok
+}
+
+func (err ErrNotAllowedToMerge) Error() string {
+	return fmt.Sprintf("not allowed to merge [reason: %s]", err.Reason)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -936,6 +1177,75 @@ func (err ErrCommentNotExist) Error() string {
 	return fmt.Sprintf("comment does not exist [id: %d, issue_id: %d]", err.ID, err.IssueID)
 }
 
+// Reaction
+
+// ErrReactionNotExist represents a "ReactionNotExist" kind of error.
+type ErrReactionNotExist struct {
+	Type      string
+	IssueID   int64
+	CommentID int64
+}
+
+// IsErrReactionNotExist checks if an error is a ErrReactionNotExist.
+func IsErrReactionNotExist(err error) bool {
+	_, ok := err.(ErrReactionNotExist)
+	return ok
+}
+
+func (err ErrReactionNotExist) Error() string {
+	return fmt.Sprintf("reaction does not exist [type: %s, issue_id: %d, comment_id: %d]", err.Type, err.IssueID, err.CommentID)
+}
+
+// ErrReactionAlreadyExist represents a "ReactionAlreadyExist" kind of error.
+type ErrReactionAlreadyExist struct {
+	Type string
+}
+
+// IsErrReactionAlreadyExist checks if an error is a ErrReactionAlreadyExist.
+func IsErrReactionAlreadyExist(err error) bool {
+	_, ok := err.(ErrReactionAlreadyExist)
+	return ok
+}
+
+func (err ErrReactionAlreadyExist) Error() string {
+	return fmt.Sprintf("reaction already exists [type: %s]", err.Type)
+}
+
+// __________              .__
+// \______   \ _______  _|__| ______  _  __
+//  |       _// __ \  \/ /  |/ __ \ \/ \/ /
+//  |    |   \  ___/\   /|  \  ___/\     /
+//  |____|_  /\___  >\_/ |__|\___  >\/\_/
+//         \/     \/             \/
+
+// ErrReviewNotExist represents a "ReviewNotExist" kind of error.
+type ErrReviewNotExist struct {
+	ID int64
+}
+
+// IsErrReviewNotExist checks if an error is a ErrReviewNotExist.
+func IsErrReviewNotExist(err error) bool {
+	_, ok := err.(ErrReviewNotExist)
+	return ok
+}
+
+func (err ErrReviewNotExist) Error() string {
+	return fmt.Sprintf("review does not exist [id: %d]", err.ID)
+}
+
+// ErrReviewEmpty represents an error when a comment-only review has neither content nor code comments.
+type ErrReviewEmpty struct{}
+
+// IsErrReviewEmpty checks if an error is a ErrReviewEmpty.
+func IsErrReviewEmpty(err error) bool {
+	_, ok := err.(ErrReviewEmpty)
+	return ok
+}
+
+func (err ErrReviewEmpty) Error() string {
+	return "review has neither content nor code comments"
+}
+
 //  _________ __                                __         .__
 //  /   _____//  |_  ____ ________  _  _______ _/  |_  ____ |  |__
 //  \_____  \\   __\/  _ \\____ \ \/ \/ /\__  \\   __\/ ___\|  |  \
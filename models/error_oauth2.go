@@ -22,3 +22,51 @@ func IsErrOpenIDConnectInitialize(err error) bool {
 func (err ErrOpenIDConnectInitialize) Error() string {
 	return fmt.Sprintf("Failed to initialize OpenID Connect Provider with name '%s' with url '%s': %v", err.ProviderName, err.OpenIDConnectAutoDiscoveryURL, err.Cause)
 }
+
+// ErrOAuthClientIDInvalid will be thrown if client id cannot be found
+type ErrOAuthClientIDInvalid struct {
+	ClientID string
+}
+
+// IsErrOAuthClientIDInvalid checks if an error is a ErrOAuthClientIDInvalid.
+func IsErrOAuthClientIDInvalid(err error) bool {
+	_, ok := err.(ErrOAuthClientIDInvalid)
+	return ok
+}
+
+// Error returns the error message
+func (err ErrOAuthClientIDInvalid) Error() string {
+	return fmt.Sprintf("Client ID invalid [Client ID: %s]", err.ClientID)
+}
+
+// ErrOAuthApplicationNotFound will be thrown if id cannot be found
+type ErrOAuthApplicationNotFound struct {
+	ID int64
+}
+
+// IsErrOAuthApplicationNotFound checks if an error is a ErrOAuthApplicationNotFound.
+func IsErrOAuthApplicationNotFound(err error) bool {
+	_, ok := err.(ErrOAuthApplicationNotFound)
+	return ok
+}
+
+// Error returns the error message
+func (err ErrOAuthApplicationNotFound) Error() string {
+	return fmt.Sprintf("OAuth application not found [ID: %d]", err.ID)
+}
+
+// ErrOAuthGrantNotFound will be thrown if a grant cannot be found
+type ErrOAuthGrantNotFound struct {
+	ID int64
+}
+
+// IsErrOAuthGrantNotFound checks if an error is a ErrOAuthGrantNotFound.
+func IsErrOAuthGrantNotFound(err error) bool {
+	_, ok := err.(ErrOAuthGrantNotFound)
+	return ok
+}
+
+// Error returns the error message
+func (err ErrOAuthGrantNotFound) Error() string {
+	return fmt.Sprintf("OAuth grant not found [ID: %d]", err.ID)
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+)
+
+// GitReference represents a reference of a git repository
+type GitReference struct {
+	// Name is the full name of the reference, e.g. refs/heads/master
+	Name     string
+	ObjectID string
+	// ObjectType is "commit", or "tag" for annotated tags
+	ObjectType string
+}
+
+// GetGitReferences returns the references of a repository whose full name
+// starts with prefix, all of them if prefix is empty
+func GetGitReferences(r *git.Repository, prefix string) ([]*GitReference, error) {
+	stdout, err := git.NewCommand("for-each-ref", "--format=%(objectname) %(objecttype) %(refname)").RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*GitReference, 0, 10)
+	for _, line := range strings.Split(stdout, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid for-each-ref line: %s", line)
+		}
+		if !strings.HasPrefix(fields[2], prefix) {
+			continue
+		}
+		refs = append(refs, &GitReference{
+			Name:       fields[2],
+			ObjectID:   fields[0],
+			ObjectType: fields[1],
+		})
+	}
+	return refs, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGitReferences(t *testing.T) {
+	PrepareTestEnv(t)
+
+	gitRepo, err := git.OpenRepository(RepoPath("user2", "repo1"))
+	assert.NoError(t, err)
+
+	refs, err := GetGitReferences(gitRepo, "")
+	assert.NoError(t, err)
+	assert.Len(t, refs, 5)
+
+	refs, err = GetGitReferences(gitRepo, "refs/heads/feat")
+	assert.NoError(t, err)
+	if assert.Len(t, refs, 1) {
+		assert.EqualValues(t, "refs/heads/feature/1", refs[0].Name)
+		assert.EqualValues(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", refs[0].ObjectID)
+		assert.EqualValues(t, "commit", refs[0].ObjectType)
+	}
+
+	refs, err = GetGitReferences(gitRepo, "refs/tags/")
+	assert.NoError(t, err)
+	if assert.Len(t, refs, 1) {
+		assert.EqualValues(t, "refs/tags/v1.1", refs[0].Name)
+	}
+
+	refs, err = GetGitReferences(gitRepo, "refs/pull/")
+	assert.NoError(t, err)
+	assert.Len(t, refs, 0)
+}
@@ -37,8 +37,7 @@ type Issue struct {
 	MilestoneID     int64       `xorm:"INDEX"`
 	Milestone       *Milestone  `xorm:"-"`
 	Priority        int
-	AssigneeID      int64        `xorm:"INDEX"`
-	Assignee        *User        `xorm:"-"`
+	Assignees       []*User      `xorm:"-"`
 	IsClosed        bool         `xorm:"INDEX"`
 	IsRead          bool         `xorm:"-"`
 	IsPull          bool         `xorm:"INDEX"` // Indicates whether is a pull request or not.
@@ -115,22 +114,6 @@ func (issue *Issue) loadPoster(e Engine) (err error) {
 	return
 }
 
-func (issue *Issue) loadAssignee(e Engine) (err error) {
-	if issue.Assignee == nil && issue.AssigneeID > 0 {
-		issue.Assignee, err = getUserByID(e, issue.AssigneeID)
-		if err != nil {
-			issue.AssigneeID = -1
-			issue.Assignee = NewGhostUser()
-			if !IsErrUserNotExist(err) {
-				return fmt.Errorf("getUserByID.(assignee) [%d]: %v", issue.AssigneeID, err)
-			}
-			err = nil
-			return
-		}
-	}
-	return
-}
-
 func (issue *Issue) loadPullRequest(e Engine) (err error) {
 	if issue.IsPull && issue.PullRequest == nil {
 		issue.PullRequest, err = getPullRequestByIssueID(e, issue.ID)
@@ -206,7 +189,7 @@ func (issue *Issue) loadAttributes(e Engine) (err error) {
 		}
 	}
 
-	if err = issue.loadAssignee(e); err != nil {
+	if err = issue.loadAssignees(e); err != nil {
 		return
 	}
 
@@ -289,7 +272,7 @@ func (issue *Issue) State() api.StateType {
 
 // APIFormat assumes some fields assigned with values:
 // Required - Poster, Labels,
-// Optional - Milestone, Assignee, PullRequest
+// Optional - Milestone, Assignees, PullRequest
 func (issue *Issue) APIFormat() *api.Issue {
 	apiLabels := make([]*api.Label, len(issue.Labels))
 	for i := range issue.Labels {
@@ -297,24 +280,30 @@ func (issue *Issue) APIFormat() *api.Issue {
 	}
 
 	apiIssue := &api.Issue{
-		ID:       issue.ID,
-		URL:      issue.APIURL(),
-		Index:    issue.Index,
-		Poster:   issue.Poster.APIFormat(),
-		Title:    issue.Title,
-		Body:     issue.Content,
-		Labels:   apiLabels,
-		State:    issue.State(),
-		Comments: issue.NumComments,
-		Created:  issue.CreatedUnix.AsTime(),
-		Updated:  issue.UpdatedUnix.AsTime(),
+		ID:        issue.ID,
+		URL:       issue.APIURL(),
+		Index:     issue.Index,
+		Poster:    issue.Poster.APIFormat(),
+		Title:     issue.Title,
+		Body:      issue.Content,
+		Labels:    apiLabels,
+		State:     issue.State(),
+		Comments:  issue.NumComments,
+		Reactions: issue.Reactions.APISummary(issue.APIURL() + "/reactions"),
+		Created:   issue.CreatedUnix.AsTime(),
+		Updated:   issue.UpdatedUnix.AsTime(),
 	}
 
 	if issue.Milestone != nil {
 		apiIssue.Milestone = issue.Milestone.APIFormat()
 	}
-	if issue.Assignee != nil {
-		apiIssue.Assignee = issue.Assignee.APIFormat()
+	if len(issue.Assignees) > 0 {
+		apiIssue.Assignees = make([]*api.User, len(issue.Assignees))
+		for i := range issue.Assignees {
+			apiIssue.Assignees[i] = issue.Assignees[i].APIFormat()
+		}
+		// Kept for compatibility with clients only knowing a single assignee.
+		apiIssue.Assignee = apiIssue.Assignees[0]
 	}
 	if issue.IsPull {
 		apiIssue.PullRequest = &api.PullRequestMeta{
@@ -369,6 +358,24 @@ func (issue *Issue) sendLabelUpdatedWebhook(doer *User) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		if err = issue.loadRepo(x); err != nil {
+			log.Error(4, "loadRepo: %v", err)
+			return
+		}
+		// Reload the labels as they have just been changed
+		issue.Labels = nil
+		if err = issue.loadLabels(x); err != nil {
+			log.Error(4, "loadLabels: %v", err)
+			return
+		}
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action:     api.HookIssueLabelUpdated,
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -497,6 +504,15 @@ func (issue *Issue) ClearLabels(doer *User) (err error) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		issue.Labels = nil
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action:     api.HookIssueLabelCleared,
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -572,17 +588,15 @@ func (issue *Issue) ReplaceLabels(labels []*Label, doer *User) (err error) {
 	return sess.Commit()
 }
 
-// GetAssignee sets the Assignee attribute of this issue.
-func (issue *Issue) GetAssignee() (err error) {
-	if issue.AssigneeID == 0 || issue.Assignee != nil {
-		return nil
-	}
-
-	issue.Assignee, err = GetUserByID(issue.AssigneeID)
-	if IsErrUserNotExist(err) {
-		return nil
+// IsAssignee returns true if the user with given ID is one of the loaded
+// assignees of this issue.
+func (issue *Issue) IsAssignee(userID int64) bool {
+	for _, assignee := range issue.Assignees {
+		if assignee.ID == userID {
+			return true
+		}
 	}
-	return err
+	return false
 }
 
 // ReadBy sets issue to be read by given user.
@@ -612,6 +626,7 @@ func (issue *Issue) changeStatus(e *xorm.Session, doer *User, repo *Repository,
 	if issue.IsClosed == isClosed {
 		return nil
 	}
+
 	issue.IsClosed = isClosed
 	if isClosed {
 		issue.ClosedUnix = util.TimeStampNow()
@@ -651,7 +666,29 @@ func (issue *Issue) changeStatus(e *xorm.Session, doer *User, repo *Repository,
 	return nil
 }
 
-// ChangeStatus changes issue status to open or closed.
+// checkDependenciesLeft returns ErrDependenciesLeft if the issue is still blocked by
+// open issues. Blockers in repositories the doer can't read still block the issue,
+// their number is returned so that the doer can be told about them.
+func (issue *Issue) checkDependenciesLeft(e *xorm.Session, doer *User, repo *Repository) error {
+	// load the units in the session, checking the setting must not query outside of it
+	if err := repo.getUnits(e); err != nil {
+		return err
+	}
+	if !repo.IsDependencyCheckEnabled() {
+		return nil
+	}
+
+	visible, hidden, err := issue.countOpenDependencies(e, doer)
+	if err != nil {
+		return fmt.Errorf("countOpenDependencies: %v", err)
+	} else if visible+hidden > 0 {
+		return ErrDependenciesLeft{IssueID: issue.ID, NumHidden: hidden}
+	}
+	return nil
+}
+
+// ChangeStatus changes issue status to open or closed. It returns ErrDependenciesLeft
+// when closing an issue which is still blocked by open issues.
 func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
@@ -659,6 +696,14 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 		return err
 	}
 
+	// Merged pull requests are closed through setMerged, after the commits are already
+	// on the base branch, so only this user-facing path is blocked by dependencies.
+	if isClosed && !issue.IsClosed {
+		if err = issue.checkDependenciesLeft(sess, doer, repo); err != nil {
+			return err
+		}
+	}
+
 	if err = issue.changeStatus(sess, doer, repo, isClosed); err != nil {
 		return err
 	}
@@ -682,6 +727,19 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 			apiPullRequest.Action = api.HookIssueReOpened
 		}
 		err = PrepareWebhooks(repo, HookEventPullRequest, apiPullRequest)
+	} else {
+		apiIssue := &api.IssuePayload{
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		}
+		if isClosed {
+			apiIssue.Action = api.HookIssueClosed
+		} else {
+			apiIssue.Action = api.HookIssueReOpened
+		}
+		err = PrepareWebhooks(repo, HookEventIssues, apiIssue)
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v, is_closed: %v]: %v", issue.IsPull, isClosed, err)
@@ -729,6 +787,19 @@ func (issue *Issue) ChangeTitle(doer *User, title string) (err error) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action: api.HookIssueEdited,
+			Index:  issue.Index,
+			Changes: &api.ChangesPayload{
+				Title: &api.ChangesFromPayload{
+					From: oldTitle,
+				},
+			},
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -780,6 +851,19 @@ func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 			Repository:  issue.Repo.APIFormat(AccessModeNone),
 			Sender:      doer.APIFormat(),
 		})
+	} else {
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action: api.HookIssueEdited,
+			Index:  issue.Index,
+			Changes: &api.ChangesPayload{
+				Body: &api.ChangesFromPayload{
+					From: oldContent,
+				},
+			},
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
 	}
 	if err != nil {
 		log.Error(4, "PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
@@ -790,55 +874,6 @@ func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 	return nil
 }
 
-// ChangeAssignee changes the Assignee field of this issue.
-func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
-	var oldAssigneeID = issue.AssigneeID
-	issue.AssigneeID = assigneeID
-	if err = UpdateIssueUserByAssignee(issue); err != nil {
-		return fmt.Errorf("UpdateIssueUserByAssignee: %v", err)
-	}
-
-	sess := x.NewSession()
-	defer sess.Close()
-
-	if err = issue.loadRepo(sess); err != nil {
-		return fmt.Errorf("loadRepo: %v", err)
-	}
-
-	if _, err = createAssigneeComment(sess, doer, issue.Repo, issue, oldAssigneeID, assigneeID); err != nil {
-		return fmt.Errorf("createAssigneeComment: %v", err)
-	}
-
-	issue.Assignee, err = GetUserByID(issue.AssigneeID)
-	if err != nil && !IsErrUserNotExist(err) {
-		log.Error(4, "GetUserByID [assignee_id: %v]: %v", issue.AssigneeID, err)
-		return nil
-	}
-
-	// Error not nil here means user does not exist, which is remove assignee.
-	isRemoveAssignee := err != nil
-	if issue.IsPull {
-		issue.PullRequest.Issue = issue
-		apiPullRequest := &api.PullRequestPayload{
-			Index:       issue.Index,
-			PullRequest: issue.PullRequest.APIFormat(),
-			Repository:  issue.Repo.APIFormat(AccessModeNone),
-			Sender:      doer.APIFormat(),
-		}
-		if isRemoveAssignee {
-			apiPullRequest.Action = api.HookIssueUnassigned
-		} else {
-			apiPullRequest.Action = api.HookIssueAssigned
-		}
-		if err := PrepareWebhooks(issue.Repo, HookEventPullRequest, apiPullRequest); err != nil {
-			log.Error(4, "PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, isRemoveAssignee, err)
-			return nil
-		}
-	}
-	go HookQueue.Add(issue.RepoID)
-	return nil
-}
-
 // GetTasks returns the amount of tasks in the issues content
 func (issue *Issue) GetTasks() int {
 	return len(issueTasksPat.FindAllStringIndex(issue.Content, -1))
@@ -854,6 +889,7 @@ type NewIssueOptions struct {
 	Repo        *Repository
 	Issue       *Issue
 	LabelIDs    []int64
+	AssigneeIDs []int64
 	Attachments []string // In UUID format.
 	IsPull      bool
 }
@@ -876,14 +912,15 @@ func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 		}
 	}
 
-	if assigneeID := opts.Issue.AssigneeID; assigneeID > 0 {
+	// Assume assignees without write access are invalid and drop them silently.
+	assigneeIDs := make([]int64, 0, len(opts.AssigneeIDs))
+	for _, assigneeID := range opts.AssigneeIDs {
 		valid, err := hasAccess(e, assigneeID, opts.Repo, AccessModeWrite)
 		if err != nil {
 			return fmt.Errorf("hasAccess [user_id: %d, repo_id: %d]: %v", assigneeID, opts.Repo.ID, err)
 		}
-		if !valid {
-			opts.Issue.AssigneeID = 0
-			opts.Issue.Assignee = nil
+		if valid && !com.IsSliceContainsInt64(assigneeIDs, assigneeID) {
+			assigneeIDs = append(assigneeIDs, assigneeID)
 		}
 	}
 
@@ -898,12 +935,10 @@ func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 		}
 	}
 
-	if opts.Issue.AssigneeID > 0 {
-		if err = opts.Issue.loadRepo(e); err != nil {
-			return err
-		}
-		if _, err = createAssigneeComment(e, doer, opts.Issue.Repo, opts.Issue, -1, opts.Issue.AssigneeID); err != nil {
-			return err
+	opts.Issue.Assignees = make([]*User, 0, len(assigneeIDs))
+	for _, assigneeID := range assigneeIDs {
+		if _, err = opts.Issue.toggleAssignee(e, doer, assigneeID); err != nil {
+			return fmt.Errorf("toggleAssignee [id: %d]: %v", assigneeID, err)
 		}
 	}
 
@@ -961,8 +996,8 @@ func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 	return opts.Issue.loadAttributes(e)
 }
 
-// NewIssue creates new issue with labels for repository.
-func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string) (err error) {
+// NewIssue creates new issue with labels and assignees for repository.
+func NewIssue(repo *Repository, issue *Issue, labelIDs, assigneeIDs []int64, uuids []string) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -973,6 +1008,7 @@ func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string)
 		Repo:        repo,
 		Issue:       issue,
 		LabelIDs:    labelIDs,
+		AssigneeIDs: assigneeIDs,
 		Attachments: uuids,
 	}); err != nil {
 		return fmt.Errorf("newIssue: %v", err)
@@ -999,6 +1035,18 @@ func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string)
 		log.Error(4, "MailParticipants: %v", err)
 	}
 
+	if err = PrepareWebhooks(repo, HookEventIssues, &api.IssuePayload{
+		Action:     api.HookIssueOpened,
+		Index:      issue.Index,
+		Issue:      issue.APIFormat(),
+		Repository: repo.APIFormat(AccessModeNone),
+		Sender:     issue.Poster.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks: %v", err)
+	} else {
+		go HookQueue.Add(issue.RepoID)
+	}
+
 	return nil
 }
 
@@ -1117,7 +1165,8 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) error {
 	}
 
 	if opts.AssigneeID > 0 {
-		sess.And("issue.assignee_id=?", opts.AssigneeID)
+		sess.Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+			And("issue_assignees.assignee_id = ?", opts.AssigneeID)
 	}
 
 	if opts.PosterID > 0 {
@@ -1339,7 +1388,8 @@ func GetIssueStats(opts *IssueStatsOptions) (*IssueStats, error) {
 		}
 
 		if opts.AssigneeID > 0 {
-			sess.And("issue.assignee_id = ?", opts.AssigneeID)
+			sess.Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+				And("issue_assignees.assignee_id = ?", opts.AssigneeID)
 		}
 
 		if opts.PosterID > 0 {
@@ -1405,13 +1455,15 @@ func GetUserIssueStats(opts UserIssueStatsOptions) (*IssueStats, error) {
 		}
 	case FilterModeAssign:
 		stats.OpenCount, err = x.Where(cond).And("is_closed = ?", false).
-			And("assignee_id = ?", opts.UserID).
+			Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+			And("issue_assignees.assignee_id = ?", opts.UserID).
 			Count(new(Issue))
 		if err != nil {
 			return nil, err
 		}
 		stats.ClosedCount, err = x.Where(cond).And("is_closed = ?", true).
-			And("assignee_id = ?", opts.UserID).
+			Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+			And("issue_assignees.assignee_id = ?", opts.UserID).
 			Count(new(Issue))
 		if err != nil {
 			return nil, err
@@ -1433,7 +1485,8 @@ func GetUserIssueStats(opts UserIssueStatsOptions) (*IssueStats, error) {
 
 	cond = cond.And(builder.Eq{"issue.is_closed": opts.IsClosed})
 	stats.AssignCount, err = x.Where(cond).
-		And("assignee_id = ?", opts.UserID).
+		Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+		And("issue_assignees.assignee_id = ?", opts.UserID).
 		Count(new(Issue))
 	if err != nil {
 		return nil, err
@@ -1472,8 +1525,10 @@ func GetRepoIssueStats(repoID, uid int64, filterMode int, isPull bool) (numOpen
 
 	switch filterMode {
 	case FilterModeAssign:
-		openCountSession.And("assignee_id = ?", uid)
-		closedCountSession.And("assignee_id = ?", uid)
+		openCountSession.Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+			And("issue_assignees.assignee_id = ?", uid)
+		closedCountSession.Join("INNER", "issue_assignees", "issue.id = issue_assignees.issue_id").
+			And("issue_assignees.assignee_id = ?", uid)
 	case FilterModeCreate:
 		openCountSession.And("poster_id = ?", uid)
 		closedCountSession.And("poster_id = ?", uid)
@@ -0,0 +1,262 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/log"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/xorm"
+)
+
+// IssueAssignees saves all issue assignees
+type IssueAssignees struct {
+	ID         int64 `xorm:"pk autoincr"`
+	AssigneeID int64 `xorm:"INDEX"`
+	IssueID    int64 `xorm:"INDEX"`
+}
+
+func (issue *Issue) loadAssignees(e Engine) (err error) {
+	if issue.Assignees != nil {
+		return nil
+	}
+	issue.Assignees, err = getAssigneesByIssue(e, issue)
+	return err
+}
+
+// GetAssigneesByIssue returns everyone assigned to that issue
+func GetAssigneesByIssue(issue *Issue) (assignees []*User, err error) {
+	return getAssigneesByIssue(x, issue)
+}
+
+func getAssigneesByIssue(e Engine, issue *Issue) (assignees []*User, err error) {
+	assignees = make([]*User, 0, 5)
+	err = e.Table("user").
+		Join("INNER", "issue_assignees", "issue_assignees.assignee_id = `user`.id").
+		Where("issue_assignees.issue_id = ?", issue.ID).
+		Asc("`user`.name").
+		Find(&assignees)
+	return assignees, err
+}
+
+// IsUserAssignedToIssue returns true when the user is assigned to the issue
+func IsUserAssignedToIssue(issue *Issue, user *User) (isAssigned bool, err error) {
+	return isUserAssignedToIssue(x, issue, user)
+}
+
+func isUserAssignedToIssue(e Engine, issue *Issue, user *User) (isAssigned bool, err error) {
+	return e.Get(&IssueAssignees{IssueID: issue.ID, AssigneeID: user.ID})
+}
+
+// DeleteNotPassedAssignee deletes all assignees who aren't passed via the "assignees" array
+func DeleteNotPassedAssignee(issue *Issue, doer *User, assignees []*User) (err error) {
+	if err = issue.loadAssignees(x); err != nil {
+		return err
+	}
+
+	// Copy the slice, ToggleAssignee modifies issue.Assignees.
+	current := make([]*User, len(issue.Assignees))
+	copy(current, issue.Assignees)
+	for _, assignee := range current {
+		found := false
+		for _, keep := range assignees {
+			if assignee.ID == keep.ID {
+				found = true
+				break
+			}
+		}
+
+		// ToggleAssignee also creates the comment and fires the webhooks.
+		if !found {
+			if _, err = issue.ToggleAssignee(doer, assignee.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func clearAssigneeByUserID(e Engine, userID int64) (err error) {
+	_, err = e.Delete(&IssueAssignees{AssigneeID: userID})
+	return err
+}
+
+// ToggleAssignee assigns the user to the issue if not assigned yet, otherwise
+// unassigns the user, and creates an issue comment for it.
+func (issue *Issue) ToggleAssignee(doer *User, assigneeID int64) (removed bool, err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return false, err
+	}
+
+	if removed, err = issue.toggleAssignee(sess, doer, assigneeID); err != nil {
+		return false, err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return false, err
+	}
+
+	if issue.IsPull {
+		if err = issue.loadPullRequest(x); err != nil {
+			log.Error(4, "loadPullRequest: %v", err)
+			return removed, nil
+		}
+		issue.PullRequest.Issue = issue
+		apiPullRequest := &api.PullRequestPayload{
+			Index:       issue.Index,
+			PullRequest: issue.PullRequest.APIFormat(),
+			Repository:  issue.Repo.APIFormat(AccessModeNone),
+			Sender:      doer.APIFormat(),
+		}
+		if removed {
+			apiPullRequest.Action = api.HookIssueUnassigned
+		} else {
+			apiPullRequest.Action = api.HookIssueAssigned
+		}
+		if err = PrepareWebhooks(issue.Repo, HookEventPullRequest, apiPullRequest); err != nil {
+			log.Error(4, "PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, removed, err)
+			return removed, nil
+		}
+		go HookQueue.Add(issue.RepoID)
+	} else {
+		apiIssue := &api.IssuePayload{
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		}
+		if removed {
+			apiIssue.Action = api.HookIssueUnassigned
+		} else {
+			apiIssue.Action = api.HookIssueAssigned
+		}
+		if err = PrepareWebhooks(issue.Repo, HookEventIssues, apiIssue); err != nil {
+			log.Error(4, "PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, removed, err)
+			return removed, nil
+		}
+		go HookQueue.Add(issue.RepoID)
+	}
+	return removed, nil
+}
+
+func (issue *Issue) toggleAssignee(e *xorm.Session, doer *User, assigneeID int64) (removed bool, err error) {
+	if err = issue.loadRepo(e); err != nil {
+		return false, fmt.Errorf("loadRepo: %v", err)
+	}
+	if err = issue.loadAssignees(e); err != nil {
+		return false, fmt.Errorf("loadAssignees: %v", err)
+	}
+
+	for i, assignee := range issue.Assignees {
+		if assignee.ID == assigneeID {
+			if _, err = e.Delete(&IssueAssignees{AssigneeID: assigneeID, IssueID: issue.ID}); err != nil {
+				return false, err
+			}
+			issue.Assignees = append(issue.Assignees[:i], issue.Assignees[i+1:]...)
+			removed = true
+			break
+		}
+	}
+
+	if !removed {
+		valid, err := hasAccess(e, assigneeID, issue.Repo, AccessModeWrite)
+		if err != nil {
+			return false, fmt.Errorf("hasAccess [user_id: %d, repo_id: %d]: %v", assigneeID, issue.Repo.ID, err)
+		} else if !valid {
+			return false, ErrUserNotExist{assigneeID, "", 0}
+		}
+		assignee, err := getUserByID(e, assigneeID)
+		if err != nil {
+			return false, err
+		}
+
+		if _, err = e.Insert(&IssueAssignees{AssigneeID: assigneeID, IssueID: issue.ID}); err != nil {
+			return false, err
+		}
+		issue.Assignees = append(issue.Assignees, assignee)
+	}
+
+	if err = updateIssueUserByAssignee(e, issue); err != nil {
+		return false, fmt.Errorf("updateIssueUserByAssignee: %v", err)
+	}
+	if err = updateIssueCols(e, issue, "updated_unix"); err != nil {
+		return false, fmt.Errorf("updateIssueCols: %v", err)
+	}
+
+	comment, err := createAssigneeComment(e, doer, issue.Repo, issue, assigneeID, removed)
+	if err != nil {
+		return false, fmt.Errorf("createAssigneeComment: %v", err)
+	}
+
+	if !removed {
+		if err = createOrUpdateAssigneeNotification(e, issue, comment.ID, assigneeID, doer.ID); err != nil {
+			return false, fmt.Errorf("createOrUpdateAssigneeNotification: %v", err)
+		}
+	}
+	return removed, nil
+}
+
+// mergeAPIAssignees merges the deprecated single assignee of the API into the
+// list of assignee names, skipping it if it is already part of the list.
+func mergeAPIAssignees(oneAssignee string, multipleAssignees []string) []string {
+	if len(oneAssignee) == 0 {
+		return multipleAssignees
+	}
+	for _, name := range multipleAssignees {
+		if name == oneAssignee {
+			return multipleAssignees
+		}
+	}
+	return append(multipleAssignees, oneAssignee)
+}
+
+// MakeIDsFromAPIAssigneesToAdd returns the IDs of the users named as assignees in an API request
+func MakeIDsFromAPIAssigneesToAdd(oneAssignee string, multipleAssignees []string) ([]int64, error) {
+	names := mergeAPIAssignees(oneAssignee, multipleAssignees)
+	assigneeIDs := make([]int64, 0, len(names))
+	for _, name := range names {
+		u, err := GetUserByName(name)
+		if err != nil {
+			return nil, err
+		}
+		assigneeIDs = append(assigneeIDs, u.ID)
+	}
+	return assigneeIDs, nil
+}
+
+// UpdateAPIAssignee replaces the set of assignees of the issue with the users
+// named in an API request. An empty list clears all assignees.
+func UpdateAPIAssignee(issue *Issue, oneAssignee string, multipleAssignees []string, doer *User) error {
+	assigneeIDs, err := MakeIDsFromAPIAssigneesToAdd(oneAssignee, multipleAssignees)
+	if err != nil {
+		return err
+	}
+
+	assignees := make([]*User, len(assigneeIDs))
+	for i := range assigneeIDs {
+		assignees[i] = &User{ID: assigneeIDs[i]}
+	}
+	if err = DeleteNotPassedAssignee(issue, doer, assignees); err != nil {
+		return err
+	}
+
+	for _, assigneeID := range assigneeIDs {
+		isAssigned, err := IsUserAssignedToIssue(issue, &User{ID: assigneeID})
+		if err != nil {
+			return err
+		} else if isAssigned {
+			continue
+		}
+		if _, err = issue.ToggleAssignee(doer, assigneeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToggleAssignee(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	// Assign the repository owner
+	removed, err := issue.ToggleAssignee(doer, 2)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	AssertExistsAndLoadBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 2})
+	AssertExistsAndLoadBean(t, &Comment{IssueID: issue.ID, Type: CommentTypeAssignees, AssigneeID: 2}, Cond("removed_assignee=?", false))
+
+	isAssigned, err := IsUserAssignedToIssue(issue, &User{ID: 2})
+	assert.NoError(t, err)
+	assert.True(t, isAssigned)
+
+	// The existing assignee is kept
+	assignees, err := GetAssigneesByIssue(issue)
+	assert.NoError(t, err)
+	if assert.Len(t, assignees, 2) {
+		assert.EqualValues(t, 1, assignees[0].ID)
+		assert.EqualValues(t, 2, assignees[1].ID)
+	}
+
+	// Toggling again removes the assignee
+	removed, err = issue.ToggleAssignee(doer, 2)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 2})
+	AssertExistsAndLoadBean(t, &Comment{IssueID: issue.ID, Type: CommentTypeAssignees, AssigneeID: 2}, Cond("removed_assignee=?", true))
+
+	// User 4 has no write access to repo 1
+	_, err = issue.ToggleAssignee(doer, 4)
+	assert.True(t, IsErrUserNotExist(err))
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 4})
+
+	CheckConsistencyFor(t, &Issue{})
+}
+
+func TestToggleAssignee_Notification(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+
+	// The new assignee is notified
+	_, err := issue.ToggleAssignee(doer, 2)
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Notification{UserID: 2, IssueID: issue.ID, Status: NotificationStatusUnread, UpdatedBy: doer.ID})
+
+	// Neither unassigned users nor users assigning themselves are
+	removed, err := issue.ToggleAssignee(doer, 1)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	AssertNotExistsBean(t, &Notification{UserID: 1, IssueID: issue.ID, UpdatedBy: doer.ID})
+	_, err = issue.ToggleAssignee(doer, 1)
+	assert.NoError(t, err)
+	AssertNotExistsBean(t, &Notification{UserID: 1, IssueID: issue.ID, UpdatedBy: doer.ID})
+}
+
+func TestMakeIDsFromAPIAssigneesToAdd(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	IDs, err := MakeIDsFromAPIAssigneesToAdd("", []string{""})
+	assert.Error(t, err)
+	assert.Nil(t, IDs)
+
+	IDs, err = MakeIDsFromAPIAssigneesToAdd("", []string{"user2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, IDs)
+
+	IDs, err = MakeIDsFromAPIAssigneesToAdd("user1", []string{"user2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, IDs)
+
+	// The deprecated single assignee is not added twice
+	IDs, err = MakeIDsFromAPIAssigneesToAdd("user2", []string{"user2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, IDs)
+
+	_, err = MakeIDsFromAPIAssigneesToAdd("", []string{"user2", "none_existing_user"})
+	assert.True(t, IsErrUserNotExist(err))
+}
+
+func TestUpdateAPIAssignee(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	// Replace user 1 with user 2
+	assert.NoError(t, UpdateAPIAssignee(issue, "", []string{"user2"}, doer))
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 1})
+	AssertExistsAndLoadBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: 2})
+
+	// An empty list clears all assignees
+	assert.NoError(t, UpdateAPIAssignee(issue, "", []string{}, doer))
+	AssertNotExistsBean(t, &IssueAssignees{IssueID: issue.ID})
+}
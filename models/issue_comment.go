@@ -60,6 +60,14 @@ const (
 	CommentTypeAddTimeManual
 	// Cancel a stopwatch for time tracking
 	CommentTypeCancelTracking
+	// Comment on a line of a pull request diff, part of a review
+	CommentTypeCode
+	// Reviews approve, reject or comment on a pull request
+	CommentTypeReview
+	// Dependency added
+	CommentTypeAddDependency
+	// Dependency removed
+	CommentTypeRemoveDependency
 )
 
 // CommentTag defines comment tag type
@@ -75,26 +83,29 @@ const (
 
 // Comment represents a comment in commit and issue page.
 type Comment struct {
-	ID             int64 `xorm:"pk autoincr"`
-	Type           CommentType
-	PosterID       int64 `xorm:"INDEX"`
-	Poster         *User `xorm:"-"`
-	IssueID        int64 `xorm:"INDEX"`
-	LabelID        int64
-	Label          *Label `xorm:"-"`
-	OldMilestoneID int64
-	MilestoneID    int64
-	OldMilestone   *Milestone `xorm:"-"`
-	Milestone      *Milestone `xorm:"-"`
-	OldAssigneeID  int64
-	AssigneeID     int64
-	Assignee       *User `xorm:"-"`
-	OldAssignee    *User `xorm:"-"`
-	OldTitle       string
-	NewTitle       string
+	ID              int64 `xorm:"pk autoincr"`
+	Type            CommentType
+	PosterID        int64 `xorm:"INDEX"`
+	Poster          *User `xorm:"-"`
+	IssueID         int64 `xorm:"INDEX"`
+	LabelID         int64
+	Label           *Label `xorm:"-"`
+	OldMilestoneID  int64
+	MilestoneID     int64
+	OldMilestone    *Milestone `xorm:"-"`
+	Milestone       *Milestone `xorm:"-"`
+	AssigneeID      int64
+	RemovedAssignee bool
+	Assignee        *User `xorm:"-"`
+	OldTitle        string
+	NewTitle        string
+
+	DependentIssueID int64
+	DependentIssue   *Issue `xorm:"-"`
 
 	CommitID        int64
 	Line            int64
+	TreePath        string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
 
@@ -104,6 +115,10 @@ type Comment struct {
 	// Reference issue in commit message
 	CommitSHA string `xorm:"VARCHAR(40)"`
 
+	// Review the comment belongs to, for CommentTypeCode and CommentTypeReview
+	ReviewID int64   `xorm:"INDEX"`
+	Review   *Review `xorm:"-"`
+
 	Attachments []*Attachment `xorm:"-"`
 	Reactions   ReactionList  `xorm:"-"`
 
@@ -163,6 +178,16 @@ func (c *Comment) IssueURL() string {
 	return issue.HTMLURL()
 }
 
+// APIURL formats a API URL-string to the comment
+func (c *Comment) APIURL() string {
+	issue, err := GetIssueByID(c.IssueID)
+	if err != nil { // Silently dropping errors :unamused:
+		log.Error(4, "GetIssueByID(%d): %v", c.IssueID, err)
+		return ""
+	}
+	return fmt.Sprintf("%s/issues/comments/%d", issue.Repo.APIURL(), c.ID)
+}
+
 // PRURL formats a URL-string to the pull-request
 func (c *Comment) PRURL() string {
 	issue, err := GetIssueByID(c.IssueID)
@@ -180,14 +205,15 @@ func (c *Comment) PRURL() string {
 // APIFormat converts a Comment to the api.Comment format
 func (c *Comment) APIFormat() *api.Comment {
 	return &api.Comment{
-		ID:       c.ID,
-		Poster:   c.Poster.APIFormat(),
-		HTMLURL:  c.HTMLURL(),
-		IssueURL: c.IssueURL(),
-		PRURL:    c.PRURL(),
-		Body:     c.Content,
-		Created:  c.CreatedUnix.AsTime(),
-		Updated:  c.UpdatedUnix.AsTime(),
+		ID:        c.ID,
+		Poster:    c.Poster.APIFormat(),
+		HTMLURL:   c.HTMLURL(),
+		IssueURL:  c.IssueURL(),
+		PRURL:     c.PRURL(),
+		Body:      c.Content,
+		Reactions: c.Reactions.APISummary(c.APIURL() + "/reactions"),
+		Created:   c.CreatedUnix.AsTime(),
+		Updated:   c.UpdatedUnix.AsTime(),
 	}
 }
 
@@ -241,19 +267,9 @@ func (c *Comment) LoadMilestone() error {
 	return nil
 }
 
-// LoadAssignees if comment.Type is CommentTypeAssignees, then load assignees
+// LoadAssignees if comment.Type is CommentTypeAssignees, then load the assignee
 func (c *Comment) LoadAssignees() error {
 	var err error
-	if c.OldAssigneeID > 0 {
-		c.OldAssignee, err = getUserByID(x, c.OldAssigneeID)
-		if err != nil {
-			if !IsErrUserNotExist(err) {
-				return err
-			}
-			c.OldAssignee = NewGhostUser()
-		}
-	}
-
 	if c.AssigneeID > 0 {
 		c.Assignee, err = getUserByID(x, c.AssigneeID)
 		if err != nil {
@@ -266,6 +282,33 @@ func (c *Comment) LoadAssignees() error {
 	return nil
 }
 
+// LoadDependentIssue if comment.Type is CommentTypeAddDependency or CommentTypeRemoveDependency,
+// then load the dependent issue if it still exists and is visible to doer
+func (c *Comment) LoadDependentIssue(doer *User) error {
+	if c.DependentIssueID == 0 || c.DependentIssue != nil {
+		return nil
+	}
+
+	issue, err := getIssueByID(x, c.DependentIssueID)
+	if err != nil {
+		if IsErrIssueNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var doerID int64
+	if doer != nil {
+		doerID = doer.ID
+	}
+	if has, err := hasAccess(x, doerID, issue.Repo, AccessModeRead); err != nil {
+		return err
+	} else if has {
+		c.DependentIssue = issue
+	}
+	return nil
+}
+
 // MailParticipants sends new comment emails to repository watchers
 // and mentioned people.
 func (c *Comment) MailParticipants(e Engine, opType ActionType, issue *Issue) (err error) {
@@ -318,21 +361,24 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		LabelID = opts.Label.ID
 	}
 	comment := &Comment{
-		Type:           opts.Type,
-		PosterID:       opts.Doer.ID,
-		Poster:         opts.Doer,
-		IssueID:        opts.Issue.ID,
-		LabelID:        LabelID,
-		OldMilestoneID: opts.OldMilestoneID,
-		MilestoneID:    opts.MilestoneID,
-		OldAssigneeID:  opts.OldAssigneeID,
-		AssigneeID:     opts.AssigneeID,
-		CommitID:       opts.CommitID,
-		CommitSHA:      opts.CommitSHA,
-		Line:           opts.LineNum,
-		Content:        opts.Content,
-		OldTitle:       opts.OldTitle,
-		NewTitle:       opts.NewTitle,
+		Type:             opts.Type,
+		PosterID:         opts.Doer.ID,
+		Poster:           opts.Doer,
+		IssueID:          opts.Issue.ID,
+		LabelID:          LabelID,
+		OldMilestoneID:   opts.OldMilestoneID,
+		MilestoneID:      opts.MilestoneID,
+		AssigneeID:       opts.AssigneeID,
+		RemovedAssignee:  opts.RemovedAssignee,
+		CommitID:         opts.CommitID,
+		CommitSHA:        opts.CommitSHA,
+		Line:             opts.LineNum,
+		TreePath:         opts.TreePath,
+		Content:          opts.Content,
+		OldTitle:         opts.OldTitle,
+		NewTitle:         opts.NewTitle,
+		DependentIssueID: opts.DependentIssueID,
+		ReviewID:         opts.ReviewID,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -474,14 +520,14 @@ func createMilestoneComment(e *xorm.Session, doer *User, repo *Repository, issue
 	})
 }
 
-func createAssigneeComment(e *xorm.Session, doer *User, repo *Repository, issue *Issue, oldAssigneeID, assigneeID int64) (*Comment, error) {
+func createAssigneeComment(e *xorm.Session, doer *User, repo *Repository, issue *Issue, assigneeID int64, removedAssignee bool) (*Comment, error) {
 	return createComment(e, &CreateCommentOptions{
-		Type:          CommentTypeAssignees,
-		Doer:          doer,
-		Repo:          repo,
-		Issue:         issue,
-		OldAssigneeID: oldAssigneeID,
-		AssigneeID:    assigneeID,
+		Type:            CommentTypeAssignees,
+		Doer:            doer,
+		Repo:            repo,
+		Issue:           issue,
+		AssigneeID:      assigneeID,
+		RemovedAssignee: removedAssignee,
 	})
 }
 
@@ -514,17 +560,20 @@ type CreateCommentOptions struct {
 	Issue *Issue
 	Label *Label
 
-	OldMilestoneID int64
-	MilestoneID    int64
-	OldAssigneeID  int64
-	AssigneeID     int64
-	OldTitle       string
-	NewTitle       string
-	CommitID       int64
-	CommitSHA      string
-	LineNum        int64
-	Content        string
-	Attachments    []string // UUIDs of attachments
+	OldMilestoneID   int64
+	MilestoneID      int64
+	AssigneeID       int64
+	RemovedAssignee  bool
+	OldTitle         string
+	NewTitle         string
+	DependentIssueID int64
+	CommitID         int64
+	CommitSHA        string
+	LineNum          int64
+	TreePath         string
+	ReviewID         int64
+	Content          string
+	Attachments      []string // UUIDs of attachments
 }
 
 // CreateComment creates comment of issue or commit.
@@ -552,7 +601,7 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content string, attachments []string) (*Comment, error) {
-	return CreateComment(&CreateCommentOptions{
+	comment, err := CreateComment(&CreateCommentOptions{
 		Type:        CommentTypeComment,
 		Doer:        doer,
 		Repo:        repo,
@@ -560,6 +609,34 @@ func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content stri
 		Content:     content,
 		Attachments: attachments,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	comment.sendWebhook(doer, api.HookIssueCommentCreated, nil)
+	return comment, nil
+}
+
+// sendWebhook triggers the issue_comment webhooks of the repository of the comment.
+func (c *Comment) sendWebhook(doer *User, action api.HookIssueCommentAction, changes *api.ChangesPayload) {
+	issue, err := GetIssueByID(c.IssueID)
+	if err != nil {
+		log.Error(4, "GetIssueByID [%d]: %v", c.IssueID, err)
+		return
+	}
+
+	if err = PrepareWebhooks(issue.Repo, HookEventIssueComment, &api.IssueCommentPayload{
+		Action:     action,
+		Issue:      issue.APIFormat(),
+		Comment:    c.APIFormat(),
+		Changes:    changes,
+		Repository: issue.Repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [comment: %d, action: %s]: %v", c.ID, action, err)
+	} else {
+		go HookQueue.Add(issue.RepoID)
+	}
 }
 
 // CreateRefComment creates a commit reference comment to issue.
@@ -605,10 +682,11 @@ func GetCommentByID(id int64) (*Comment, error) {
 
 // FindCommentsOptions describes the conditions to Find comments
 type FindCommentsOptions struct {
-	RepoID  int64
-	IssueID int64
-	Since   int64
-	Type    CommentType
+	RepoID   int64
+	IssueID  int64
+	ReviewID int64
+	Since    int64
+	Type     CommentType
 }
 
 func (opts *FindCommentsOptions) toConds() builder.Cond {
@@ -619,6 +697,9 @@ func (opts *FindCommentsOptions) toConds() builder.Cond {
 	if opts.IssueID > 0 {
 		cond = cond.And(builder.Eq{"comment.issue_id": opts.IssueID})
 	}
+	if opts.ReviewID > 0 {
+		cond = cond.And(builder.Eq{"comment.review_id": opts.ReviewID})
+	}
 	if opts.Since > 0 {
 		cond = cond.And(builder.Gte{"comment.updated_unix": opts.Since})
 	}
@@ -672,17 +753,22 @@ func GetCommentsByRepoIDSince(repoID, since int64) ([]*Comment, error) {
 }
 
 // UpdateComment updates information of comment.
-func UpdateComment(c *Comment) error {
+func UpdateComment(doer *User, c *Comment, oldContent string) error {
 	if _, err := x.ID(c.ID).AllCols().Update(c); err != nil {
 		return err
 	} else if c.Type == CommentTypeComment {
 		UpdateIssueIndexer(c.IssueID)
+		c.sendWebhook(doer, api.HookIssueCommentEdited, &api.ChangesPayload{
+			Body: &api.ChangesFromPayload{
+				From: oldContent,
+			},
+		})
 	}
 	return nil
 }
 
 // DeleteComment deletes the comment
-func DeleteComment(comment *Comment) error {
+func DeleteComment(doer *User, comment *Comment) error {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -708,6 +794,7 @@ func DeleteComment(comment *Comment) error {
 		return err
 	} else if comment.Type == CommentTypeComment {
 		UpdateIssueIndexer(comment.IssueID)
+		comment.sendWebhook(doer, api.HookIssueCommentDeleted, nil)
 	}
 	return nil
 }
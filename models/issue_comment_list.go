@@ -0,0 +1,47 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// CommentList defines a list of comments
+type CommentList []*Comment
+
+func (comments CommentList) getCommentIDs() []int64 {
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	return ids
+}
+
+func (comments CommentList) loadReactions(e Engine) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	reactions := make([]*Reaction, 0, len(comments))
+	if err := e.
+		In("comment_id", comments.getCommentIDs()).
+		Asc("created_unix", "id").
+		Find(&reactions); err != nil {
+		return err
+	}
+
+	reactionMaps := make(map[int64]ReactionList, len(comments))
+	for _, reaction := range reactions {
+		reactionMaps[reaction.CommentID] = append(reactionMaps[reaction.CommentID], reaction)
+	}
+	for _, comment := range comments {
+		comment.Reactions = reactionMaps[comment.ID]
+		if comment.Reactions == nil {
+			comment.Reactions = ReactionList{}
+		}
+	}
+	return nil
+}
+
+// LoadReactions loads the reactions of the comments, without their users
+func (comments CommentList) LoadReactions() error {
+	return comments.loadReactions(x)
+}
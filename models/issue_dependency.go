@@ -0,0 +1,217 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+// IssueDependency represents that an issue is blocked by another issue,
+// which may belong to another repository.
+type IssueDependency struct {
+	ID           int64          `xorm:"pk autoincr"`
+	UserID       int64          `xorm:"NOT NULL"`
+	IssueID      int64          `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+	DependencyID int64          `xorm:"UNIQUE(issue_dependency) INDEX NOT NULL"`
+	CreatedUnix  util.TimeStamp `xorm:"created"`
+}
+
+func issueDependencyExists(e Engine, issueID, dependencyID int64) (bool, error) {
+	return e.Get(&IssueDependency{IssueID: issueID, DependencyID: dependencyID})
+}
+
+// issueDependsOn returns whether the issue is blocked by the target issue,
+// either directly or through other dependencies.
+func issueDependsOn(e Engine, issueID, targetID int64) (bool, error) {
+	visited := map[int64]bool{issueID: true}
+	queue := []int64{issueID}
+	for len(queue) > 0 {
+		dependencyIDs := make([]int64, 0, 10)
+		if err := e.Table("issue_dependency").
+			Cols("dependency_id").
+			In("issue_id", queue).
+			Find(&dependencyIDs); err != nil {
+			return false, err
+		}
+
+		queue = queue[:0]
+		for _, id := range dependencyIDs {
+			if id == targetID {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+	return false, nil
+}
+
+func createDependencyComment(e *xorm.Session, doer *User, issue, dependency *Issue, cmtType CommentType) error {
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+	_, err := createComment(e, &CreateCommentOptions{
+		Type:             cmtType,
+		Doer:             doer,
+		Repo:             issue.Repo,
+		Issue:            issue,
+		DependentIssueID: dependency.ID,
+	})
+	return err
+}
+
+// CreateIssueDependency marks the issue as blocked by the dependency.
+func CreateIssueDependency(doer *User, issue, dependency *Issue) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if has, err := issueDependencyExists(sess, issue.ID, dependency.ID); err != nil {
+		return err
+	} else if has {
+		return ErrDependencyExists{issue.ID, dependency.ID}
+	}
+
+	if issue.ID == dependency.ID {
+		return ErrCircularDependency{issue.ID, dependency.ID}
+	} else if circular, err := issueDependsOn(sess, dependency.ID, issue.ID); err != nil {
+		return err
+	} else if circular {
+		return ErrCircularDependency{issue.ID, dependency.ID}
+	}
+
+	if _, err = sess.Insert(&IssueDependency{
+		UserID:       doer.ID,
+		IssueID:      issue.ID,
+		DependencyID: dependency.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err = createDependencyComment(sess, doer, issue, dependency, CommentTypeAddDependency); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// RemoveIssueDependency removes the dependency of the issue.
+func RemoveIssueDependency(doer *User, issue, dependency *Issue) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if affected, err := sess.Delete(&IssueDependency{IssueID: issue.ID, DependencyID: dependency.ID}); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrDependencyNotExist{issue.ID, dependency.ID}
+	}
+
+	if err = createDependencyComment(sess, doer, issue, dependency, CommentTypeRemoveDependency); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+func deleteIssueDependencies(e Engine, issueIDs []int64) error {
+	if _, err := e.In("issue_id", issueIDs).Delete(&IssueDependency{}); err != nil {
+		return err
+	}
+	_, err := e.In("dependency_id", issueIDs).Delete(&IssueDependency{})
+	return err
+}
+
+// getDependencyIssues returns the issues joined through the given column of
+// issue_dependency, leaving out those in repositories the doer can't read.
+func getDependencyIssues(e Engine, doer *User, joinCol, whereCol string, issueID int64) (IssueList, error) {
+	issues := make(IssueList, 0, 5)
+	if err := e.
+		Join("INNER", "issue_dependency", "issue_dependency."+joinCol+" = issue.id").
+		Where("issue_dependency."+whereCol+" = ?", issueID).
+		Asc("issue_dependency.id").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	if err := issues.loadAttributes(e); err != nil {
+		return nil, err
+	}
+
+	var doerID int64
+	if doer != nil {
+		doerID = doer.ID
+	}
+	visible := issues[:0]
+	for _, issue := range issues {
+		if has, err := hasAccess(e, doerID, issue.Repo, AccessModeRead); err != nil {
+			return nil, err
+		} else if has {
+			visible = append(visible, issue)
+		}
+	}
+	return visible, nil
+}
+
+// BlockedBy returns the issues blocking this issue which are visible to doer.
+func (issue *Issue) BlockedBy(doer *User) (IssueList, error) {
+	return getDependencyIssues(x, doer, "dependency_id", "issue_id", issue.ID)
+}
+
+// Blocking returns the issues blocked by this issue which are visible to doer.
+func (issue *Issue) Blocking(doer *User) (IssueList, error) {
+	return getDependencyIssues(x, doer, "issue_id", "dependency_id", issue.ID)
+}
+
+func (issue *Issue) hasOpenDependencies(e Engine) (bool, error) {
+	return e.Table("issue").
+		Join("INNER", "issue_dependency", "issue_dependency.dependency_id = issue.id").
+		Where("issue_dependency.issue_id = ?", issue.ID).
+		And("issue.is_closed = ?", false).
+		Exist()
+}
+
+// countOpenDependencies returns the numbers of open issues blocking this issue
+// which are visible and hidden to doer.
+func (issue *Issue) countOpenDependencies(e Engine, doer *User) (visible, hidden int, err error) {
+	issues := make(IssueList, 0, 5)
+	if err = e.
+		Join("INNER", "issue_dependency", "issue_dependency.dependency_id = issue.id").
+		Where("issue_dependency.issue_id = ?", issue.ID).
+		And("issue.is_closed = ?", false).
+		Find(&issues); err != nil {
+		return 0, 0, err
+	}
+	if _, err = issues.loadRepositories(e); err != nil {
+		return 0, 0, err
+	}
+
+	var doerID int64
+	if doer != nil {
+		doerID = doer.ID
+	}
+	for _, dependency := range issues {
+		if has, err := hasAccess(e, doerID, dependency.Repo, AccessModeRead); err != nil {
+			return 0, 0, err
+		} else if has {
+			visible++
+		} else {
+			hidden++
+		}
+	}
+	return visible, hidden, nil
+}
+
+// HasOpenDependencies returns whether the issue is blocked by any open issue.
+func (issue *Issue) HasOpenDependencies() (bool, error) {
+	return issue.hasOpenDependencies(x)
+}
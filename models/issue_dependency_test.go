@@ -0,0 +1,135 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIssueDependency(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	issue5 := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue3))
+	AssertExistsAndLoadBean(t, &IssueDependency{IssueID: 1, DependencyID: 3, UserID: 2})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeAddDependency, IssueID: 1, DependentIssueID: 3})
+
+	err := CreateIssueDependency(user2, issue1, issue3)
+	assert.True(t, IsErrDependencyExists(err))
+
+	err = CreateIssueDependency(user2, issue1, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+	err = CreateIssueDependency(user2, issue3, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+
+	// circles through other issues are detected as well
+	assert.NoError(t, CreateIssueDependency(user2, issue3, issue5))
+	err = CreateIssueDependency(user2, issue5, issue1)
+	assert.True(t, IsErrCircularDependency(err))
+}
+
+func TestRemoveIssueDependency(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+
+	err := RemoveIssueDependency(user2, issue1, issue3)
+	assert.True(t, IsErrDependencyNotExist(err))
+
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue3))
+	assert.NoError(t, RemoveIssueDependency(user2, issue1, issue3))
+	AssertNotExistsBean(t, &IssueDependency{IssueID: 1, DependencyID: 3})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeRemoveDependency, IssueID: 1, DependentIssueID: 3})
+}
+
+func TestIssue_BlockedBy(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	// issue 4 belongs to a private repository of user2
+	issue4 := AssertExistsAndLoadBean(t, &Issue{ID: 4}).(*Issue)
+
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue3))
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue4))
+
+	issues, err := issue1.BlockedBy(user2)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 2) {
+		assert.EqualValues(t, 3, issues[0].ID)
+		assert.EqualValues(t, 4, issues[1].ID)
+	}
+
+	issues, err = issue1.BlockedBy(nil)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 3, issues[0].ID)
+	}
+
+	issues, err = issue3.Blocking(user2)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+	}
+}
+
+func TestIssue_HasOpenDependencies(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	// issue 5 is closed
+	issue5 := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue5))
+	blocked, err := issue1.HasOpenDependencies()
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue3))
+	blocked, err = issue1.HasOpenDependencies()
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+
+	assert.NoError(t, issue1.loadRepo(x))
+	err = issue1.ChangeStatus(user2, issue1.Repo, true)
+	if assert.True(t, IsErrDependenciesLeft(err)) {
+		assert.Zero(t, err.(ErrDependenciesLeft).NumHidden)
+	}
+	AssertExistsAndLoadBean(t, &Issue{ID: 1}, Cond("is_closed = ?", false))
+}
+
+func TestIssue_ChangeStatusHiddenDependencies(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	// user 5 can't read the private repository of issue 6
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue6 := AssertExistsAndLoadBean(t, &Issue{ID: 6}).(*Issue)
+	assert.NoError(t, CreateIssueDependency(user2, issue1, issue6))
+
+	issues, err := issue1.BlockedBy(user5)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 0)
+
+	// the hidden blocker still blocks the issue, and the doer is told about it
+	assert.NoError(t, issue1.loadRepo(x))
+	err = issue1.ChangeStatus(user5, issue1.Repo, true)
+	if assert.True(t, IsErrDependenciesLeft(err)) {
+		assert.EqualValues(t, 1, err.(ErrDependenciesLeft).NumHidden)
+	}
+	AssertExistsAndLoadBean(t, &Issue{ID: 1}, Cond("is_closed = ?", false))
+}
@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 
+	"code.gitea.io/gitea/modules/log"
+
 	"github.com/go-xorm/xorm"
 
 	api "code.gitea.io/sdk/gitea"
@@ -96,18 +98,43 @@ func (label *Label) ForegroundColor() template.CSS {
 	return template.CSS("#000")
 }
 
+// sendWebhook triggers the label webhooks of the repository of the label.
+func (label *Label) sendWebhook(doer *User, action HookLabelAction) {
+	repo, err := GetRepositoryByID(label.RepoID)
+	if err != nil {
+		log.Error(4, "GetRepositoryByID [%d]: %v", label.RepoID, err)
+		return
+	}
+
+	if err = PrepareWebhooks(repo, HookEventLabel, &LabelPayload{
+		Action:     action,
+		Label:      label.APIFormat(),
+		Repository: repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [label: %d, action: %s]: %v", label.ID, action, err)
+	} else {
+		go HookQueue.Add(repo.ID)
+	}
+}
+
 func newLabel(e Engine, label *Label) error {
 	_, err := e.Insert(label)
 	return err
 }
 
 // NewLabel creates a new label for a repository
-func NewLabel(label *Label) error {
-	return newLabel(x, label)
+func NewLabel(doer *User, label *Label) error {
+	if err := newLabel(x, label); err != nil {
+		return err
+	}
+
+	label.sendWebhook(doer, HookLabelCreated)
+	return nil
 }
 
 // NewLabels creates new labels for a repository.
-func NewLabels(labels ...*Label) error {
+func NewLabels(doer *User, labels ...*Label) error {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -118,7 +145,14 @@ func NewLabels(labels ...*Label) error {
 			return err
 		}
 	}
-	return sess.Commit()
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+
+	for _, label := range labels {
+		label.sendWebhook(doer, HookLabelCreated)
+	}
+	return nil
 }
 
 // getLabelInRepoByName returns a label by Name in given repository.
@@ -227,13 +261,18 @@ func updateLabel(e Engine, l *Label) error {
 }
 
 // UpdateLabel updates label information.
-func UpdateLabel(l *Label) error {
-	return updateLabel(x, l)
+func UpdateLabel(doer *User, l *Label) error {
+	if err := updateLabel(x, l); err != nil {
+		return err
+	}
+
+	l.sendWebhook(doer, HookLabelEdited)
+	return nil
 }
 
 // DeleteLabel delete a label of given repository.
-func DeleteLabel(repoID, labelID int64) error {
-	_, err := GetLabelInRepoByID(repoID, labelID)
+func DeleteLabel(doer *User, repoID, labelID int64) error {
+	label, err := GetLabelInRepoByID(repoID, labelID)
 	if err != nil {
 		if IsErrLabelNotExist(err) {
 			return nil
@@ -260,7 +299,12 @@ func DeleteLabel(repoID, labelID int64) error {
 		return err
 	}
 
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	label.sendWebhook(doer, HookLabelDeleted)
+	return nil
 }
 
 // .___                            .____          ___.          .__
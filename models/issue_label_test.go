@@ -50,7 +50,8 @@ func TestNewLabels(t *testing.T) {
 	for _, label := range labels {
 		AssertNotExistsBean(t, label)
 	}
-	assert.NoError(t, NewLabels(labels...))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, NewLabels(doer, labels...))
 	for _, label := range labels {
 		AssertExistsAndLoadBean(t, label, Cond("id = ?", label.ID))
 	}
@@ -138,7 +139,8 @@ func TestUpdateLabel(t *testing.T) {
 	label := AssertExistsAndLoadBean(t, &Label{ID: 1}).(*Label)
 	label.Color = "#ffff00"
 	label.Name = "newLabelName"
-	assert.NoError(t, UpdateLabel(label))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, UpdateLabel(doer, label))
 	newLabel := AssertExistsAndLoadBean(t, &Label{ID: 1}).(*Label)
 	assert.Equal(t, *label, *newLabel)
 	CheckConsistencyFor(t, &Label{}, &Repository{})
@@ -147,13 +149,14 @@ func TestUpdateLabel(t *testing.T) {
 func TestDeleteLabel(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	label := AssertExistsAndLoadBean(t, &Label{ID: 1}).(*Label)
-	assert.NoError(t, DeleteLabel(label.RepoID, label.ID))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, DeleteLabel(doer, label.RepoID, label.ID))
 	AssertNotExistsBean(t, &Label{ID: label.ID, RepoID: label.RepoID})
 
-	assert.NoError(t, DeleteLabel(label.RepoID, label.ID))
+	assert.NoError(t, DeleteLabel(doer, label.RepoID, label.ID))
 	AssertNotExistsBean(t, &Label{ID: label.ID, RepoID: label.RepoID})
 
-	assert.NoError(t, DeleteLabel(NonexistentID, NonexistentID))
+	assert.NoError(t, DeleteLabel(doer, NonexistentID, NonexistentID))
 	CheckConsistencyFor(t, &Label{}, &Repository{})
 }
 
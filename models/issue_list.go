@@ -154,38 +154,38 @@ func (issues IssueList) loadMilestones(e Engine) error {
 	return nil
 }
 
-func (issues IssueList) getAssigneeIDs() []int64 {
-	var ids = make(map[int64]struct{}, len(issues))
-	for _, issue := range issues {
-		if _, ok := ids[issue.AssigneeID]; !ok {
-			ids[issue.AssigneeID] = struct{}{}
-		}
-	}
-	return keysInt64(ids)
-}
-
 func (issues IssueList) loadAssignees(e Engine) error {
-	assigneeIDs := issues.getAssigneeIDs()
-	if len(assigneeIDs) == 0 {
+	if len(issues) == 0 {
 		return nil
 	}
 
-	assigneeMaps := make(map[int64]*User, len(assigneeIDs))
-	err := e.
-		In("id", assigneeIDs).
-		Find(&assigneeMaps)
+	type AssigneeIssue struct {
+		IssueAssignee *IssueAssignees `xorm:"extends"`
+		Assignee      *User           `xorm:"extends"`
+	}
+
+	var assignees = make(map[int64][]*User, len(issues))
+	rows, err := e.Table("issue_assignees").
+		Join("INNER", "user", "`user`.id = `issue_assignees`.assignee_id").
+		In("`issue_assignees`.issue_id", issues.getIssueIDs()).
+		Rows(new(AssigneeIssue))
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	for _, issue := range issues {
-		if issue.AssigneeID <= 0 {
-			continue
-		}
-		var ok bool
-		if issue.Assignee, ok = assigneeMaps[issue.AssigneeID]; !ok {
-			issue.Assignee = NewGhostUser()
+	for rows.Next() {
+		var assigneeIssue AssigneeIssue
+		err = rows.Scan(&assigneeIssue)
+		if err != nil {
+			return err
 		}
+
+		assignees[assigneeIssue.IssueAssignee.IssueID] = append(assignees[assigneeIssue.IssueAssignee.IssueID], assigneeIssue.Assignee)
+	}
+
+	for _, issue := range issues {
+		issue.Assignees = assignees[issue.ID]
 	}
 	return nil
 }
@@ -290,6 +290,36 @@ func (issues IssueList) loadComments(e Engine) (err error) {
 	return nil
 }
 
+// loadReactions loads the reactions on the issues themselves, without the
+// reactions on their comments
+func (issues IssueList) loadReactions(e Engine) (err error) {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	reactions := make([]*Reaction, 0, len(issues))
+	if err = e.
+		In("issue_id", issues.getIssueIDs()).
+		And("comment_id = ?", 0).
+		Asc("created_unix", "id").
+		Find(&reactions); err != nil {
+		return err
+	}
+
+	reactionMaps := make(map[int64]ReactionList, len(issues))
+	for _, reaction := range reactions {
+		reactionMaps[reaction.IssueID] = append(reactionMaps[reaction.IssueID], reaction)
+	}
+	for _, issue := range issues {
+		issue.Reactions = reactionMaps[issue.ID]
+		if issue.Reactions == nil {
+			// prevents issue.loadReactions from querying them again
+			issue.Reactions = ReactionList{}
+		}
+	}
+	return nil
+}
+
 // loadAttributes loads all attributes, expect for attachments and comments
 func (issues IssueList) loadAttributes(e Engine) (err error) {
 	if _, err = issues.loadRepositories(e); err != nil {
@@ -316,6 +346,10 @@ func (issues IssueList) loadAttributes(e Engine) (err error) {
 		return
 	}
 
+	if err = issues.loadReactions(e); err != nil {
+		return
+	}
+
 	return nil
 }
 
@@ -46,8 +46,8 @@ func TestIssueList_LoadAttributes(t *testing.T) {
 		if issue.PosterID > 0 {
 			assert.EqualValues(t, issue.PosterID, issue.Poster.ID)
 		}
-		if issue.AssigneeID > 0 {
-			assert.EqualValues(t, issue.AssigneeID, issue.Assignee.ID)
+		for _, assignee := range issue.Assignees {
+			AssertExistsAndLoadBean(t, &IssueAssignees{IssueID: issue.ID, AssigneeID: assignee.ID})
 		}
 		if issue.MilestoneID > 0 {
 			assert.EqualValues(t, issue.MilestoneID, issue.Milestone.ID)
@@ -5,7 +5,11 @@
 package models
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/Unknwon/com"
 
@@ -18,10 +22,65 @@ func (issue *Issue) mailSubject() string {
 	return fmt.Sprintf("[%s] %s (#%d)", issue.Repo.Name, issue.Title, issue.Index)
 }
 
+// replyTokenSignatureLength keeps reply-to addresses within the 64 characters
+// allowed in the local part of an email address.
+const replyTokenSignatureLength = 32
+
+// replyTokenSignature is invalidated when the user changes their password,
+// since Rands is regenerated at that time.
+func (issue *Issue) replyTokenSignature(u *User) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	mac.Write([]byte(fmt.Sprintf("reply:%d:%d:%s", issue.ID, u.ID, u.Rands)))
+	return hex.EncodeToString(mac.Sum(nil))[:replyTokenSignatureLength]
+}
+
+// ReplyToken returns a token signed for the user, which allows them to comment
+// on the issue by replying to a notification email.
+func (issue *Issue) ReplyToken(u *User) string {
+	return fmt.Sprintf("%d.%d.%s", issue.ID, u.ID, issue.replyTokenSignature(u))
+}
+
+// ReplyToAddress returns the address the user can reply to in order to comment
+// on the issue.
+func (issue *Issue) ReplyToAddress(u *User) string {
+	return strings.Replace(setting.IncomingEmail.ReplyToAddress, setting.IncomingEmailTokenPlaceholder, issue.ReplyToken(u), 1)
+}
+
+// GetIssueByReplyToken returns the issue and the user the given reply token was
+// signed for.
+func GetIssueByReplyToken(token string) (*Issue, *User, error) {
+	fields := strings.Split(token, ".")
+	if len(fields) != 3 {
+		return nil, nil, ErrInvalidReplyToken{token}
+	}
+
+	issue, err := GetIssueByID(com.StrTo(fields[0]).MustInt64())
+	if err != nil {
+		if IsErrIssueNotExist(err) {
+			return nil, nil, ErrInvalidReplyToken{token}
+		}
+		return nil, nil, err
+	}
+	u, err := GetUserByID(com.StrTo(fields[1]).MustInt64())
+	if err != nil {
+		if IsErrUserNotExist(err) {
+			return nil, nil, ErrInvalidReplyToken{token}
+		}
+		return nil, nil, err
+	}
+
+	if !hmac.Equal([]byte(issue.replyTokenSignature(u)), []byte(strings.ToLower(fields[2]))) {
+		return nil, nil, ErrInvalidReplyToken{token}
+	}
+	return issue, u, nil
+}
+
 // mailIssueCommentToParticipants can be used for both new issue creation and comment.
 // This function sends two list of emails:
 // 1. Repository watchers and users who are participated in comments.
 // 2. Users who are not in 1. but get mentioned in current issue/comment.
+// Users who do not want to receive an email for every notification are skipped,
+// except for mentions if they chose to be notified of those only.
 func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content string, comment *Comment, mentions []string) error {
 	if !setting.Service.EnableNotifyMail {
 		return nil
@@ -46,12 +105,17 @@ func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content
 		participants = append(participants, issue.Poster)
 	}
 
-	// Assignee must receive any communications
-	if issue.Assignee != nil && issue.AssigneeID > 0 && issue.AssigneeID != doer.ID {
-		participants = append(participants, issue.Assignee)
+	// Assignees must receive any communications
+	if err = issue.loadAssignees(e); err != nil {
+		return fmt.Errorf("loadAssignees [issue_id: %d]: %v", issue.ID, err)
+	}
+	for _, assignee := range issue.Assignees {
+		if assignee.ID != doer.ID {
+			participants = append(participants, assignee)
+		}
 	}
 
-	tos := make([]string, 0, len(watchers)) // List of email addresses.
+	tos := make([]*User, 0, len(watchers))
 	names := make([]string, 0, len(watchers))
 	for i := range watchers {
 		if watchers[i].UserID == doer.ID {
@@ -62,11 +126,11 @@ func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content
 		if err != nil {
 			return fmt.Errorf("GetUserByID [%d]: %v", watchers[i].UserID, err)
 		}
-		if to.IsOrganization() {
+		if to.IsOrganization() || to.EmailNotifications() != EmailNotificationsEnabled {
 			continue
 		}
 
-		tos = append(tos, to.Email)
+		tos = append(tos, to)
 		names = append(names, to.Name)
 	}
 	for i := range participants {
@@ -74,9 +138,11 @@ func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content
 			continue
 		} else if com.IsSliceContainsStr(names, participants[i].Name) {
 			continue
+		} else if participants[i].EmailNotifications() != EmailNotificationsEnabled {
+			continue
 		}
 
-		tos = append(tos, participants[i].Email)
+		tos = append(tos, participants[i])
 		names = append(names, participants[i].Name)
 	}
 
@@ -84,15 +150,25 @@ func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content
 
 	// Mail mentioned people and exclude watchers.
 	names = append(names, doer.Name)
-	tos = make([]string, 0, len(mentions)) // list of user names.
+	tos = make([]*User, 0, len(mentions))
 	for i := range mentions {
 		if com.IsSliceContainsStr(names, mentions[i]) {
 			continue
 		}
 
-		tos = append(tos, mentions[i])
+		to, err := getUserByName(e, mentions[i])
+		if err != nil {
+			continue
+		}
+		if !to.IsMailable() {
+			continue
+		}
+		switch to.EmailNotifications() {
+		case EmailNotificationsEnabled, EmailNotificationsOnMention:
+			tos = append(tos, to)
+		}
 	}
-	SendIssueMentionMail(issue, doer, content, comment, getUserEmailsByNames(e, tos))
+	SendIssueMentionMail(issue, doer, content, comment, tos)
 
 	return nil
 }
@@ -0,0 +1,50 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssue_ReplyToAddress(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	setting.IncomingEmail.ReplyToAddress = "incoming+%{token}@example.com"
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	token := issue.ReplyToken(user)
+	assert.Equal(t, "incoming+"+token+"@example.com", issue.ReplyToAddress(user))
+	// the local part of an address is limited to 64 characters
+	assert.True(t, len("incoming+"+token) <= 64)
+
+	replyIssue, replyUser, err := GetIssueByReplyToken(token)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, replyIssue.ID)
+	assert.EqualValues(t, 2, replyUser.ID)
+}
+
+func TestGetIssueByReplyToken(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	other := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	for _, token := range []string{
+		"",
+		"1.2",
+		"1.2.invalid",
+		// a token signed for another user
+		"1.2." + issue.replyTokenSignature(other),
+		"1.9999." + issue.replyTokenSignature(user),
+	} {
+		_, _, err := GetIssueByReplyToken(token)
+		assert.True(t, IsErrInvalidReplyToken(err), token)
+	}
+}
@@ -5,6 +5,7 @@
 package models
 
 import (
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	api "code.gitea.io/sdk/gitea"
@@ -86,8 +87,22 @@ func (m *Milestone) APIFormat() *api.Milestone {
 	return apiMilestone
 }
 
+// sendWebhook triggers the milestone webhooks of the given repository.
+func (m *Milestone) sendWebhook(repo *Repository, doer *User, action HookMilestoneAction) {
+	if err := PrepareWebhooks(repo, HookEventMilestone, &MilestonePayload{
+		Action:     action,
+		Milestone:  m.APIFormat(),
+		Repository: repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [milestone: %d, action: %s]: %v", m.ID, action, err)
+	} else {
+		go HookQueue.Add(repo.ID)
+	}
+}
+
 // NewMilestone creates new milestone of repository.
-func NewMilestone(m *Milestone) (err error) {
+func NewMilestone(doer *User, m *Milestone) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -101,7 +116,17 @@ func NewMilestone(m *Milestone) (err error) {
 	if _, err = sess.Exec("UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
 		return err
 	}
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	repo, err := GetRepositoryByID(m.RepoID)
+	if err != nil {
+		log.Error(4, "GetRepositoryByID [%d]: %v", m.RepoID, err)
+		return nil
+	}
+	m.sendWebhook(repo, doer, HookMilestoneCreated)
+	return nil
 }
 
 func getMilestoneByRepoID(e Engine, repoID, id int64) (*Milestone, error) {
@@ -161,8 +186,18 @@ func updateMilestone(e Engine, m *Milestone) error {
 }
 
 // UpdateMilestone updates information of given milestone.
-func UpdateMilestone(m *Milestone) error {
-	return updateMilestone(x, m)
+func UpdateMilestone(doer *User, m *Milestone) error {
+	if err := updateMilestone(x, m); err != nil {
+		return err
+	}
+
+	repo, err := GetRepositoryByID(m.RepoID)
+	if err != nil {
+		log.Error(4, "GetRepositoryByID [%d]: %v", m.RepoID, err)
+		return nil
+	}
+	m.sendWebhook(repo, doer, HookMilestoneEdited)
+	return nil
 }
 
 func countRepoMilestones(e Engine, repoID int64) (int64, error) {
@@ -195,7 +230,7 @@ func MilestoneStats(repoID int64) (open int64, closed int64, err error) {
 }
 
 // ChangeMilestoneStatus changes the milestone open/closed status.
-func ChangeMilestoneStatus(m *Milestone, isClosed bool) (err error) {
+func ChangeMilestoneStatus(doer *User, m *Milestone, isClosed bool) (err error) {
 	repo, err := GetRepositoryByID(m.RepoID)
 	if err != nil {
 		return err
@@ -226,7 +261,16 @@ func ChangeMilestoneStatus(m *Milestone, isClosed bool) (err error) {
 	if _, err = sess.ID(repo.ID).Cols("num_milestones, num_closed_milestones").Update(repo); err != nil {
 		return err
 	}
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	if isClosed {
+		m.sendWebhook(repo, doer, HookMilestoneClosed)
+	} else {
+		m.sendWebhook(repo, doer, HookMilestoneOpened)
+	}
+	return nil
 }
 
 func changeMilestoneIssueStats(e *xorm.Session, issue *Issue) error {
@@ -307,11 +351,65 @@ func ChangeMilestoneAssign(issue *Issue, doer *User, oldMilestoneID int64) (err
 	if err = changeMilestoneAssign(sess, doer, issue, oldMilestoneID); err != nil {
 		return err
 	}
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	if oldMilestoneID != issue.MilestoneID {
+		issue.sendMilestoneWebhook(doer)
+	}
+	return nil
+}
+
+func (issue *Issue) sendMilestoneWebhook(doer *User) {
+	var err error
+	if issue.MilestoneID > 0 {
+		if issue.Milestone, err = getMilestoneByRepoID(x, issue.RepoID, issue.MilestoneID); err != nil {
+			log.Error(4, "getMilestoneByRepoID [%d]: %v", issue.MilestoneID, err)
+			return
+		}
+	} else {
+		issue.Milestone = nil
+	}
+
+	var action api.HookIssueAction
+	if issue.MilestoneID > 0 {
+		action = api.HookIssueMilestoned
+	} else {
+		action = api.HookIssueDemilestoned
+	}
+
+	if issue.IsPull {
+		if err = issue.loadPullRequest(x); err != nil {
+			log.Error(4, "loadPullRequest: %v", err)
+			return
+		}
+		issue.PullRequest.Issue = issue
+		err = PrepareWebhooks(issue.Repo, HookEventPullRequest, &api.PullRequestPayload{
+			Action:      action,
+			Index:       issue.Index,
+			PullRequest: issue.PullRequest.APIFormat(),
+			Repository:  issue.Repo.APIFormat(AccessModeNone),
+			Sender:      doer.APIFormat(),
+		})
+	} else {
+		err = PrepareWebhooks(issue.Repo, HookEventIssues, &api.IssuePayload{
+			Action:     action,
+			Index:      issue.Index,
+			Issue:      issue.APIFormat(),
+			Repository: issue.Repo.APIFormat(AccessModeNone),
+			Sender:     doer.APIFormat(),
+		})
+	}
+	if err != nil {
+		log.Error(4, "PrepareWebhooks [is_pull: %v, milestone: %d]: %v", issue.IsPull, issue.MilestoneID, err)
+	} else {
+		go HookQueue.Add(issue.RepoID)
+	}
 }
 
 // DeleteMilestoneByRepoID deletes a milestone from a repository.
-func DeleteMilestoneByRepoID(repoID, id int64) error {
+func DeleteMilestoneByRepoID(doer *User, repoID, id int64) error {
 	m, err := GetMilestoneByRepoID(repoID, id)
 	if err != nil {
 		if IsErrMilestoneNotExist(err) {
@@ -353,5 +451,10 @@ func DeleteMilestoneByRepoID(repoID, id int64) error {
 	if _, err = sess.Exec("UPDATE `issue` SET milestone_id = 0 WHERE milestone_id = ?", m.ID); err != nil {
 		return err
 	}
-	return sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	m.sendWebhook(repo, doer, HookMilestoneDeleted)
+	return nil
 }
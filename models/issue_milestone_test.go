@@ -50,7 +50,8 @@ func TestNewMilestone(t *testing.T) {
 		Content: "milestoneContent",
 	}
 
-	assert.NoError(t, NewMilestone(milestone))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, NewMilestone(doer, milestone))
 	AssertExistsAndLoadBean(t, milestone)
 	CheckConsistencyFor(t, &Repository{ID: milestone.RepoID}, &Milestone{})
 }
@@ -137,7 +138,8 @@ func TestUpdateMilestone(t *testing.T) {
 	milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
 	milestone.Name = "newMilestoneName"
 	milestone.Content = "newMilestoneContent"
-	assert.NoError(t, UpdateMilestone(milestone))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, UpdateMilestone(doer, milestone))
 	AssertExistsAndLoadBean(t, milestone)
 	CheckConsistencyFor(t, &Milestone{})
 }
@@ -198,12 +200,13 @@ func TestMilestoneStats(t *testing.T) {
 func TestChangeMilestoneStatus(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
 
-	assert.NoError(t, ChangeMilestoneStatus(milestone, true))
+	assert.NoError(t, ChangeMilestoneStatus(doer, milestone, true))
 	AssertExistsAndLoadBean(t, &Milestone{ID: 1}, "is_closed=1")
 	CheckConsistencyFor(t, &Repository{ID: milestone.RepoID}, &Milestone{})
 
-	assert.NoError(t, ChangeMilestoneStatus(milestone, false))
+	assert.NoError(t, ChangeMilestoneStatus(doer, milestone, false))
 	AssertExistsAndLoadBean(t, &Milestone{ID: 1}, "is_closed=0")
 	CheckConsistencyFor(t, &Repository{ID: milestone.RepoID}, &Milestone{})
 }
@@ -247,9 +250,10 @@ func TestChangeMilestoneAssign(t *testing.T) {
 
 func TestDeleteMilestoneByRepoID(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
-	assert.NoError(t, DeleteMilestoneByRepoID(1, 1))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, DeleteMilestoneByRepoID(doer, 1, 1))
 	AssertNotExistsBean(t, &Milestone{ID: 1})
 	CheckConsistencyFor(t, &Repository{ID: 1})
 
-	assert.NoError(t, DeleteMilestoneByRepoID(NonexistentID, NonexistentID))
+	assert.NoError(t, DeleteMilestoneByRepoID(doer, NonexistentID, NonexistentID))
 }
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
+	api "code.gitea.io/sdk/gitea"
 
 	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
@@ -26,10 +27,23 @@ type Reaction struct {
 	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
 }
 
+// ReactionTypes are the allowed types of reactions
+var ReactionTypes = []string{"+1", "-1", "laugh", "confused", "heart", "hooray"}
+
+// IsValidReaction returns true if content is an allowed type of reaction
+func IsValidReaction(content string) bool {
+	for _, reactionType := range ReactionTypes {
+		if content == reactionType {
+			return true
+		}
+	}
+	return false
+}
+
 // FindReactionsOptions describes the conditions to Find reactions
 type FindReactionsOptions struct {
 	IssueID   int64
-	CommentID int64
+	CommentID int64 // -1 to only find the reactions on the issue itself
 }
 
 func (opts *FindReactionsOptions) toConds() builder.Cond {
@@ -39,6 +53,8 @@ func (opts *FindReactionsOptions) toConds() builder.Cond {
 	}
 	if opts.CommentID > 0 {
 		cond = cond.And(builder.Eq{"reaction.comment_id": opts.CommentID})
+	} else if opts.CommentID == -1 {
+		cond = cond.And(builder.Eq{"reaction.comment_id": 0})
 	}
 	return cond
 }
@@ -51,7 +67,65 @@ func findReactions(e Engine, opts FindReactionsOptions) ([]*Reaction, error) {
 		Find(&reactions)
 }
 
+// FindIssueReactions returns the reactions on the issue itself, without the
+// reactions on its comments
+func FindIssueReactions(issue *Issue) (ReactionList, error) {
+	return findReactions(x, FindReactionsOptions{
+		IssueID:   issue.ID,
+		CommentID: -1,
+	})
+}
+
+// FindCommentReactions returns the reactions on the comment
+func FindCommentReactions(comment *Comment) (ReactionList, error) {
+	return findReactions(x, FindReactionsOptions{
+		IssueID:   comment.IssueID,
+		CommentID: comment.ID,
+	})
+}
+
+// reactionCond matches the reaction of the doer described by the options. The
+// columns are explicit since xorm ignores the zero comment ID of issue reactions.
+func (opts *ReactionOptions) reactionCond() builder.Cond {
+	var commentID int64
+	if opts.Comment != nil {
+		commentID = opts.Comment.ID
+	}
+	return builder.Eq{
+		"type":       opts.Type,
+		"user_id":    opts.Doer.ID,
+		"issue_id":   opts.Issue.ID,
+		"comment_id": commentID,
+	}
+}
+
+func getReaction(e Engine, opts *ReactionOptions) (*Reaction, error) {
+	reaction := new(Reaction)
+	has, err := e.Where(opts.reactionCond()).Get(reaction)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		err := ErrReactionNotExist{Type: opts.Type, IssueID: opts.Issue.ID}
+		if opts.Comment != nil {
+			err.CommentID = opts.Comment.ID
+		}
+		return nil, err
+	}
+	return reaction, nil
+}
+
+// GetReaction returns the reaction of the doer described by the options
+func GetReaction(opts *ReactionOptions) (*Reaction, error) {
+	return getReaction(x, opts)
+}
+
 func createReaction(e *xorm.Session, opts *ReactionOptions) (*Reaction, error) {
+	if _, err := getReaction(e, opts); err == nil {
+		return nil, ErrReactionAlreadyExist{opts.Type}
+	} else if !IsErrReactionNotExist(err) {
+		return nil, err
+	}
+
 	reaction := &Reaction{
 		Type:    opts.Type,
 		UserID:  opts.Doer.ID,
@@ -114,15 +188,7 @@ func CreateCommentReaction(doer *User, issue *Issue, comment *Comment, content s
 }
 
 func deleteReaction(e *xorm.Session, opts *ReactionOptions) error {
-	reaction := &Reaction{
-		Type:    opts.Type,
-		UserID:  opts.Doer.ID,
-		IssueID: opts.Issue.ID,
-	}
-	if opts.Comment != nil {
-		reaction.CommentID = opts.Comment.ID
-	}
-	_, err := e.Delete(reaction)
+	_, err := e.Where(opts.reactionCond()).Delete(new(Reaction))
 	return err
 }
 
@@ -160,6 +226,17 @@ func DeleteCommentReaction(doer *User, issue *Issue, comment *Comment, content s
 	})
 }
 
+// APIFormat converts a Reaction to the api.Reaction format,
+// the user of the reaction must have been loaded before
+func (r *Reaction) APIFormat() *api.Reaction {
+	return &api.Reaction{
+		ID:      r.ID,
+		User:    r.User.APIFormat(),
+		Content: r.Type,
+		Created: r.CreatedUnix.AsTime(),
+	}
+}
+
 // ReactionList represents list of reactions
 type ReactionList []*Reaction
 
@@ -224,6 +301,32 @@ func (list ReactionList) LoadUsers() ([]*User, error) {
 	return list.loadUsers(x)
 }
 
+// APISummary returns the number of reactions of each type, url is the API URL
+// of the reactions
+func (list ReactionList) APISummary(url string) *api.ReactionSummary {
+	summary := &api.ReactionSummary{
+		URL:        url,
+		TotalCount: len(list),
+	}
+	for _, reaction := range list {
+		switch reaction.Type {
+		case "+1":
+			summary.PlusOne++
+		case "-1":
+			summary.MinusOne++
+		case "laugh":
+			summary.Laugh++
+		case "confused":
+			summary.Confused++
+		case "heart":
+			summary.Heart++
+		case "hooray":
+			summary.Hooray++
+		}
+	}
+	return summary
+}
+
 // GetFirstUsers returns first reacted user display names separated by comma
 func (list ReactionList) GetFirstUsers() string {
 	var buffer bytes.Buffer
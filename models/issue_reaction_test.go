@@ -164,3 +164,82 @@ func TestIssueCommentReactionCount(t *testing.T) {
 
 	AssertNotExistsBean(t, &Reaction{Type: "heart", UserID: user1.ID, IssueID: issue1.ID, CommentID: comment1.ID})
 }
+
+func TestIssueDeleteReactionKeepsCommentReactions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	comment1 := AssertExistsAndLoadBean(t, &Comment{ID: 1}).(*Comment)
+
+	addReaction(t, user1, issue1, nil, "heart")
+	addReaction(t, user1, issue1, comment1, "heart")
+
+	assert.NoError(t, DeleteIssueReaction(user1, issue1, "heart"))
+
+	reactions, err := FindIssueReactions(issue1)
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 0)
+	AssertExistsAndLoadBean(t, &Reaction{Type: "heart", UserID: user1.ID, IssueID: issue1.ID, CommentID: comment1.ID})
+}
+
+func TestFindIssueAndCommentReactions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	comment1 := AssertExistsAndLoadBean(t, &Comment{ID: 1}).(*Comment)
+
+	addReaction(t, user1, issue1, nil, "+1")
+	addReaction(t, user2, issue1, nil, "+1")
+	addReaction(t, user2, issue1, nil, "hooray")
+	addReaction(t, user1, issue1, comment1, "laugh")
+
+	reactions, err := FindIssueReactions(issue1)
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 3)
+
+	summary := reactions.APISummary("url")
+	assert.Equal(t, "url", summary.URL)
+	assert.Equal(t, 3, summary.TotalCount)
+	assert.Equal(t, 2, summary.PlusOne)
+	assert.Equal(t, 1, summary.Hooray)
+	assert.Equal(t, 0, summary.Laugh)
+
+	reactions, err = FindCommentReactions(comment1)
+	assert.NoError(t, err)
+	if assert.Len(t, reactions, 1) {
+		assert.Equal(t, "laugh", reactions[0].Type)
+	}
+
+	comments := CommentList{comment1}
+	assert.NoError(t, comments.LoadReactions())
+	assert.Len(t, comment1.Reactions, 1)
+
+	issues := IssueList{issue1}
+	assert.NoError(t, issues.loadReactions(x))
+	assert.Len(t, issue1.Reactions, 3)
+}
+
+func TestGetReaction(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	opts := &ReactionOptions{Type: "heart", Doer: user1, Issue: issue1}
+
+	_, err := GetReaction(opts)
+	assert.True(t, IsErrReactionNotExist(err))
+
+	addReaction(t, user1, issue1, nil, "heart")
+	reaction, err := GetReaction(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "heart", reaction.Type)
+
+	_, err = CreateReaction(opts)
+	assert.True(t, IsErrReactionAlreadyExist(err))
+
+	assert.True(t, IsValidReaction("+1"))
+	assert.False(t, IsValidReaction("rocket"))
+}
@@ -34,7 +34,7 @@ func newIssueUsers(e Engine, repo *Repository, issue *Issue) error {
 		issueUsers = append(issueUsers, &IssueUser{
 			IssueID:    issue.ID,
 			UID:        assignee.ID,
-			IsAssigned: assignee.ID == issue.AssigneeID,
+			IsAssigned: issue.IsAssignee(assignee.ID),
 		})
 		isPosterAssignee = isPosterAssignee || assignee.ID == issue.PosterID
 	}
@@ -56,14 +56,12 @@ func updateIssueUserByAssignee(e Engine, issue *Issue) (err error) {
 		return err
 	}
 
-	// Assignee ID equals to 0 means clear assignee.
-	if issue.AssigneeID > 0 {
-		if _, err = e.Exec("UPDATE `issue_user` SET is_assigned = ? WHERE uid = ? AND issue_id = ?", true, issue.AssigneeID, issue.ID); err != nil {
+	for _, assignee := range issue.Assignees {
+		if _, err = e.Exec("UPDATE `issue_user` SET is_assigned = ? WHERE uid = ? AND issue_id = ?", true, assignee.ID, issue.ID); err != nil {
 			return err
 		}
 	}
-
-	return updateIssue(e, issue)
+	return nil
 }
 
 // UpdateIssueUserByAssignee updates issue-user relation for assignee.
@@ -74,6 +72,9 @@ func UpdateIssueUserByAssignee(issue *Issue) (err error) {
 		return err
 	}
 
+	if err = issue.loadAssignees(sess); err != nil {
+		return err
+	}
 	if err = updateIssueUserByAssignee(sess, issue); err != nil {
 		return err
 	}
@@ -39,13 +39,13 @@ func TestUpdateIssueUserByAssignee(t *testing.T) {
 	// artificially change assignee in issue_user table
 	AssertSuccessfulInsert(t, &IssueUser{IssueID: issue.ID, UID: 5, IsAssigned: true})
 	_, err := x.Cols("is_assigned").
-		Update(&IssueUser{IsAssigned: false}, &IssueUser{IssueID: issue.ID, UID: issue.AssigneeID})
+		Update(&IssueUser{IsAssigned: false}, &IssueUser{IssueID: issue.ID, UID: 1})
 	assert.NoError(t, err)
 
 	assert.NoError(t, UpdateIssueUserByAssignee(issue))
 
 	// issue_user table should now be correct again
-	AssertExistsAndLoadBean(t, &IssueUser{IssueID: issue.ID, UID: issue.AssigneeID}, "is_assigned=1")
+	AssertExistsAndLoadBean(t, &IssueUser{IssueID: issue.ID, UID: 1}, "is_assigned=1")
 	AssertExistsAndLoadBean(t, &IssueUser{IssueID: issue.ID, UID: 5}, "is_assigned=0")
 }
 
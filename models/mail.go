@@ -30,6 +30,7 @@ const (
 	mailIssueMention base.TplName = "issue/mention"
 
 	mailNotifyCollaborator base.TplName = "notify/collaborator"
+	mailNotifyDigest       base.TplName = "notify/digest"
 )
 
 var templates *template.Template
@@ -149,7 +150,7 @@ func composeTplData(subject, body, link string) map[string]interface{} {
 	return data
 }
 
-func composeIssueCommentMessage(issue *Issue, doer *User, content string, comment *Comment, tplName base.TplName, tos []string, info string) *mailer.Message {
+func composeIssueCommentMessage(issue *Issue, doer *User, content string, comment *Comment, tplName base.TplName, to *User, info string) *mailer.Message {
 	subject := issue.mailSubject()
 	body := string(markup.RenderByType(markdown.MarkupName, []byte(content), issue.Repo.HTMLURL(), issue.Repo.ComposeMetas()))
 
@@ -160,6 +161,8 @@ func composeIssueCommentMessage(issue *Issue, doer *User, content string, commen
 		data = composeTplData(subject, body, issue.HTMLURL())
 	}
 	data["Doer"] = doer
+	data["UnsubscribeLink"] = to.UnsubscribeURL()
+	data["ReplyByEmail"] = setting.IncomingEmail.Enabled
 
 	var mailBody bytes.Buffer
 
@@ -167,24 +170,63 @@ func composeIssueCommentMessage(issue *Issue, doer *User, content string, commen
 		log.Error(3, "Template: %v", err)
 	}
 
-	msg := mailer.NewMessageFrom(tos, doer.DisplayName(), setting.MailService.FromEmail, subject, mailBody.String())
-	msg.Info = fmt.Sprintf("Subject: %s, %s", subject, info)
+	msg := mailer.NewMessageFrom([]string{to.Email}, doer.DisplayName(), setting.MailService.FromEmail, subject, mailBody.String())
+	setUnsubscribeHeaders(msg, to)
+	if setting.IncomingEmail.Enabled {
+		msg.SetHeader("Reply-To", issue.ReplyToAddress(to))
+	}
+	msg.Info = fmt.Sprintf("UID: %d, subject: %s, %s", to.ID, subject, info)
 	return msg
 }
 
+// setUnsubscribeHeaders allows mail clients to offer a one-click unsubscribe
+// button, see RFC 8058.
+func setUnsubscribeHeaders(msg *mailer.Message, to *User) {
+	msg.SetHeader("List-Unsubscribe", "<"+to.UnsubscribeURL()+">")
+	msg.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+}
+
 // SendIssueCommentMail composes and sends issue comment emails to target receivers.
-func SendIssueCommentMail(issue *Issue, doer *User, content string, comment *Comment, tos []string) {
-	if len(tos) == 0 {
-		return
+func SendIssueCommentMail(issue *Issue, doer *User, content string, comment *Comment, tos []*User) {
+	for _, to := range tos {
+		mailer.SendAsync(composeIssueCommentMessage(issue, doer, content, comment, mailIssueComment, to, "issue comment"))
 	}
-
-	mailer.SendAsync(composeIssueCommentMessage(issue, doer, content, comment, mailIssueComment, tos, "issue comment"))
 }
 
 // SendIssueMentionMail composes and sends issue mention emails to target receivers.
-func SendIssueMentionMail(issue *Issue, doer *User, content string, comment *Comment, tos []string) {
-	if len(tos) == 0 {
+func SendIssueMentionMail(issue *Issue, doer *User, content string, comment *Comment, tos []*User) {
+	for _, to := range tos {
+		mailer.SendAsync(composeIssueCommentMessage(issue, doer, content, comment, mailIssueMention, to, "issue mention"))
+	}
+}
+
+// SendNotificationDigestMail composes and sends a digest of the given unread
+// notifications to the user.
+func SendNotificationDigestMail(u *User, notifications []*Notification) {
+	if len(notifications) == 0 {
 		return
 	}
-	mailer.SendAsync(composeIssueCommentMessage(issue, doer, content, comment, mailIssueMention, tos, "issue mention"))
+
+	subject := fmt.Sprintf("You have %d unread notifications", len(notifications))
+	if len(notifications) == 1 {
+		subject = "You have 1 unread notification"
+	}
+
+	data := composeTplData(subject, "", setting.AppURL+"notifications")
+	data["Username"] = u.DisplayName()
+	data["Notifications"] = notifications
+	data["UnsubscribeLink"] = u.UnsubscribeURL()
+
+	var content bytes.Buffer
+
+	if err := templates.ExecuteTemplate(&content, string(mailNotifyDigest), data); err != nil {
+		log.Error(3, "Template: %v", err)
+		return
+	}
+
+	msg := mailer.NewMessage([]string{u.Email}, subject, content.String())
+	setUnsubscribeHeaders(msg, u)
+	msg.Info = fmt.Sprintf("UID: %d, notification digest", u.ID)
+
+	mailer.SendAsync(msg)
 }
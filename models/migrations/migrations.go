@@ -168,6 +168,36 @@ var migrations = []Migration{
 	NewMigration("remove is_owner, num_teams columns from org_user", removeIsOwnerColumnFromOrgUser),
 	// v57 -> v58
 	NewMigration("add closed_unix column for issues", addIssueClosedTime),
+	// v58 -> v59
+	NewMigration("add review", addReview),
+	// v59 -> v60
+	NewMigration("add protected branch merge rules", addProtectedBranchMergeRules),
+	// v60 -> v61
+	NewMigration("add push mirror table", addPushMirror),
+	// v61 -> v62
+	NewMigration("add multiple assignees", addMultipleAssignees),
+	// v62 -> v63
+	NewMigration("add topic and repo_topic tables", addTopicTables),
+	// v63 -> v64
+	NewMigration("add is_archived column to repository", addRepoArchived),
+	// v64 -> v65
+	NewMigration("add scopes, repository restriction and expiry to access tokens", addAccessTokenScopes),
+	// v65 -> v66
+	NewMigration("add oauth2 application, authorization code and grant tables", addOAuth2Provider),
+	// v66 -> v67
+	NewMigration("add delivery attempts to hook tasks and failing state to webhooks", addHookTaskAttempts),
+	// v67 -> v68
+	NewMigration("add is_system_webhook column to webhook", addSystemWebhookColumn),
+	// v68 -> v69
+	NewMigration("add notification subjects and watch events", addNotificationSubjectsAndWatchEvents),
+	// v69 -> v70
+	NewMigration("add email notifications preference to user", addEmailNotificationsPreference),
+	// v70 -> v71
+	NewMigration("add issue_dependency table and dependency check to issue units", addIssueDependencies),
+	// v71 -> v72
+	NewMigration("enable push on protected branches with a push whitelist", enablePushForWhitelistedBranches),
+	// v72 -> v73
+	NewMigration("add confidential_client column to oauth2_application", addConfidentialClientColumnToOAuth2Application),
 }
 
 // Migrate database to current version
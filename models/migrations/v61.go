@@ -0,0 +1,62 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addMultipleAssignees(x *xorm.Engine) error {
+	// IssueAssignees see models/issue_assignees.go
+	type IssueAssignees struct {
+		ID         int64 `xorm:"pk autoincr"`
+		AssigneeID int64 `xorm:"INDEX"`
+		IssueID    int64 `xorm:"INDEX"`
+	}
+
+	// Comment see models/issue_comment.go, only the new column is needed
+	type Comment struct {
+		RemovedAssignee bool
+	}
+
+	if err := x.Sync2(new(IssueAssignees), new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	// Move the single assignee of every issue into the new table.
+	if _, err := sess.Exec("INSERT INTO issue_assignees (assignee_id, issue_id) SELECT assignee_id, id FROM issue WHERE assignee_id > 0"); err != nil {
+		return fmt.Errorf("copy assignees: %v", err)
+	}
+
+	// A change of the assignee was stored in a single comment with both the old
+	// and the new assignee, keep the unassign of the old one as its own comment.
+	if _, err := sess.Exec("INSERT INTO comment (type, poster_id, issue_id, assignee_id, removed_assignee, created_unix, updated_unix) "+
+		"SELECT type, poster_id, issue_id, old_assignee_id, ?, created_unix, updated_unix FROM comment WHERE type = ? AND assignee_id > 0 AND old_assignee_id > 0", true, 9); err != nil {
+		return fmt.Errorf("split assignee change comments: %v", err)
+	}
+
+	// Unassign comments used to store the previous assignee in old_assignee_id,
+	// they now keep it in assignee_id and are flagged as removed.
+	if _, err := sess.Exec("UPDATE comment SET removed_assignee = ?, assignee_id = old_assignee_id WHERE type = ? AND assignee_id = 0 AND old_assignee_id > 0", true, 9); err != nil {
+		return fmt.Errorf("update assignee comments: %v", err)
+	}
+
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+
+	if err := dropTableColumns(x, "issue", "assignee_id"); err != nil {
+		return err
+	}
+	return dropTableColumns(x, "comment", "old_assignee_id")
+}
@@ -0,0 +1,32 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addAccessTokenScopes(x *xorm.Engine) error {
+	// AccessToken see models/token.go
+	type AccessToken struct {
+		Scopes      []string       `xorm:"TEXT JSON"`
+		RepoIDs     []int64        `xorm:"TEXT JSON"`
+		ExpiredUnix util.TimeStamp `xorm:"INDEX"`
+	}
+
+	if err := x.Sync2(new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Existing tokens keep the full rights of their owner.
+	if _, err := x.Exec("UPDATE access_token SET scopes = ?", `["all"]`); err != nil {
+		return fmt.Errorf("update scopes: %v", err)
+	}
+	return nil
+}
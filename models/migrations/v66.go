@@ -0,0 +1,32 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addHookTaskAttempts(x *xorm.Engine) error {
+	// HookTask see models/webhook.go
+	type HookTask struct {
+		Attempts        int            `xorm:"NOT NULL DEFAULT 0"`
+		NextAttemptUnix util.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	// Webhook see models/webhook.go
+	type Webhook struct {
+		IsFailing        bool           `xorm:"INDEX NOT NULL DEFAULT false"`
+		FailingSinceUnix util.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(HookTask), new(Webhook)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addNotificationSubjectsAndWatchEvents(x *xorm.Engine) error {
+	// Notification see models/notification.go
+	type Notification struct {
+		CommentID int64 `xorm:"NOT NULL DEFAULT 0"`
+		ReleaseID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	// Watch see models/repo_watch.go
+	type Watch struct {
+		// issues, pull requests, comments and releases, see models.WatchEventsDefault
+		Events int `xorm:"NOT NULL DEFAULT 15"`
+	}
+
+	if err := x.Sync2(new(Notification), new(Watch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
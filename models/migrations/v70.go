@@ -0,0 +1,62 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addIssueDependencies(x *xorm.Engine) error {
+	// IssueDependency see models/issue_dependency.go
+	type IssueDependency struct {
+		ID           int64          `xorm:"pk autoincr"`
+		UserID       int64          `xorm:"NOT NULL"`
+		IssueID      int64          `xorm:"UNIQUE(issue_dependency) NOT NULL"`
+		DependencyID int64          `xorm:"UNIQUE(issue_dependency) INDEX NOT NULL"`
+		CreatedUnix  util.TimeStamp `xorm:"created"`
+	}
+
+	// Comment see models/issue_comment.go
+	type Comment struct {
+		DependentIssueID int64
+	}
+
+	// RepoUnit see models/repo_unit.go
+	type RepoUnit struct {
+		ID     int64
+		Type   int
+		Config map[string]interface{} `xorm:"JSON"`
+	}
+
+	if err := x.Sync2(new(IssueDependency)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(Comment)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Updating existing issue units
+	units := make([]*RepoUnit, 0, 100)
+	if err := x.Where("`type` = ?", V16UnitTypeIssues).Find(&units); err != nil {
+		return fmt.Errorf("Query repo units: %v", err)
+	}
+	for _, unit := range units {
+		if unit.Config == nil {
+			unit.Config = make(map[string]interface{})
+		}
+		if _, ok := unit.Config["EnableDependencyCheck"]; !ok {
+			unit.Config["EnableDependencyCheck"] = setting.Service.DefaultEnableDependencyCheck
+		}
+		if _, err := x.ID(unit.ID).Cols("config").Update(unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"github.com/go-xorm/xorm"
+)
+
+func enablePushForWhitelistedBranches(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		CanPush         bool `xorm:"NOT NULL DEFAULT false"`
+		EnableWhitelist bool
+	}
+
+	// The push whitelist used to grant pushing by itself, now it only
+	// restricts who may push to branches which allow pushing at all.
+	_, err := x.Where("enable_whitelist = ?", true).Cols("can_push").Update(&ProtectedBranch{
+		CanPush: true,
+	})
+	return err
+}
@@ -0,0 +1,23 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"github.com/go-xorm/xorm"
+)
+
+// OAuth2ApplicationV72 describes the added client type of oauth2 applications
+type OAuth2ApplicationV72 struct {
+	ConfidentialClient bool `xorm:"NOT NULL DEFAULT TRUE"`
+}
+
+// TableName sets the table name to `oauth2_application`
+func (*OAuth2ApplicationV72) TableName() string {
+	return "oauth2_application"
+}
+
+func addConfidentialClientColumnToOAuth2Application(x *xorm.Engine) error {
+	return x.Sync2(new(OAuth2ApplicationV72))
+}
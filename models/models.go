@@ -119,6 +119,15 @@ func init() {
 		new(RepoIndexerStatus),
 		new(LFSLock),
 		new(Reaction),
+		new(Review),
+		new(PushMirror),
+		new(IssueAssignees),
+		new(Topic),
+		new(RepoTopic),
+		new(OAuth2Application),
+		new(OAuth2AuthorizationCode),
+		new(OAuth2Grant),
+		new(IssueDependency),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -6,8 +6,17 @@ package models
 
 import (
 	"fmt"
+	"path"
+	"strings"
+	"time"
 
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/builder"
 )
 
 type (
@@ -33,6 +42,8 @@ const (
 	NotificationSourcePullRequest
 	// NotificationSourceCommit is a notification of a commit
 	NotificationSourceCommit
+	// NotificationSourceRelease is a notification of a release
+	NotificationSourceRelease
 )
 
 // Notification represents a notification
@@ -44,12 +55,16 @@ type Notification struct {
 	Status NotificationStatus `xorm:"SMALLINT INDEX NOT NULL"`
 	Source NotificationSource `xorm:"SMALLINT INDEX NOT NULL"`
 
-	IssueID  int64  `xorm:"INDEX NOT NULL"`
-	CommitID string `xorm:"INDEX"`
+	IssueID   int64  `xorm:"INDEX NOT NULL"`
+	CommentID int64  `xorm:"NOT NULL DEFAULT 0"`
+	CommitID  string `xorm:"INDEX"`
+	ReleaseID int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
 
 	UpdatedBy int64 `xorm:"INDEX NOT NULL"`
 
 	Issue      *Issue      `xorm:"-"`
+	Comment    *Comment    `xorm:"-"`
+	Release    *Release    `xorm:"-"`
 	Repository *Repository `xorm:"-"`
 
 	CreatedUnix util.TimeStamp `xorm:"created INDEX NOT NULL"`
@@ -59,20 +74,30 @@ type Notification struct {
 // CreateOrUpdateIssueNotifications creates an issue notification
 // for each watcher, or updates it if already exists
 func CreateOrUpdateIssueNotifications(issue *Issue, notificationAuthorID int64) error {
+	return createOrUpdateIssueNotificationsTx(issue, 0, notificationAuthorID)
+}
+
+// CreateOrUpdateCommentNotifications creates an issue notification pointing
+// to the new comment for each watcher, or updates it if already exists
+func CreateOrUpdateCommentNotifications(issue *Issue, commentID, notificationAuthorID int64) error {
+	return createOrUpdateIssueNotificationsTx(issue, commentID, notificationAuthorID)
+}
+
+func createOrUpdateIssueNotificationsTx(issue *Issue, commentID, notificationAuthorID int64) error {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
 		return err
 	}
 
-	if err := createOrUpdateIssueNotifications(sess, issue, notificationAuthorID); err != nil {
+	if err := createOrUpdateIssueNotifications(sess, issue, commentID, notificationAuthorID); err != nil {
 		return err
 	}
 
 	return sess.Commit()
 }
 
-func createOrUpdateIssueNotifications(e Engine, issue *Issue, notificationAuthorID int64) error {
+func createOrUpdateIssueNotifications(e Engine, issue *Issue, commentID, notificationAuthorID int64) error {
 	issueWatches, err := getIssueWatchers(e, issue.ID)
 	if err != nil {
 		return err
@@ -102,9 +127,9 @@ func createOrUpdateIssueNotifications(e Engine, issue *Issue, notificationAuthor
 		alreadyNotified[userID] = struct{}{}
 
 		if notificationExists(notifications, issue.ID, userID) {
-			return updateIssueNotification(e, userID, issue.ID, notificationAuthorID)
+			return updateIssueNotification(e, userID, issue.ID, commentID, notificationAuthorID)
 		}
-		return createIssueNotification(e, userID, issue, notificationAuthorID)
+		return createIssueNotification(e, userID, issue, commentID, notificationAuthorID)
 	}
 
 	for _, issueWatch := range issueWatches {
@@ -119,7 +144,26 @@ func createOrUpdateIssueNotifications(e Engine, issue *Issue, notificationAuthor
 		}
 	}
 
+	// assignees are notified of every change of the issue, like the watchers
+	if err = issue.loadAssignees(e); err != nil {
+		return err
+	}
+	for _, assignee := range issue.Assignees {
+		if err := notifyUser(assignee.ID); err != nil {
+			return err
+		}
+	}
+
+	event := WatchEventIssue
+	if commentID != 0 {
+		event = WatchEventComment
+	} else if issue.IsPull {
+		event = WatchEventPullRequest
+	}
 	for _, watch := range watches {
+		if !watch.HasEvent(event) {
+			continue
+		}
 		if err := notifyUser(watch.UserID); err != nil {
 			return err
 		}
@@ -127,6 +171,159 @@ func createOrUpdateIssueNotifications(e Engine, issue *Issue, notificationAuthor
 	return nil
 }
 
+// createOrUpdateAssigneeNotification creates an issue notification for a user
+// who has been assigned to the issue, or updates it if already exists
+func createOrUpdateAssigneeNotification(e Engine, issue *Issue, commentID, assigneeID, notificationAuthorID int64) error {
+	if assigneeID == notificationAuthorID {
+		return nil
+	}
+
+	notification, err := getIssueNotification(e, assigneeID, issue.ID)
+	if err != nil {
+		return err
+	}
+	if notification.ID > 0 {
+		return updateIssueNotification(e, assigneeID, issue.ID, commentID, notificationAuthorID)
+	}
+	return createIssueNotification(e, assigneeID, issue, commentID, notificationAuthorID)
+}
+
+// CreateOrUpdateMentionNotifications creates an issue notification for each
+// user mentioned in the issue or its comment, or updates it if already exists.
+// Users without read access to the repository are not notified.
+func CreateOrUpdateMentionNotifications(issue *Issue, commentID int64, mentions []string, notificationAuthorID int64) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := issue.loadRepo(sess); err != nil {
+		return err
+	}
+
+	for i := range mentions {
+		mentions[i] = strings.ToLower(mentions[i])
+	}
+	users := make([]*User, 0, len(mentions))
+	if err := sess.
+		In("lower_name", mentions).
+		And("type = ?", UserTypeIndividual).
+		Find(&users); err != nil {
+		return fmt.Errorf("find mentioned users: %v", err)
+	}
+
+	for _, user := range users {
+		if user.ID == notificationAuthorID || !user.IsActive || user.ProhibitLogin {
+			continue
+		}
+		if has, err := hasAccess(sess, user.ID, issue.Repo, AccessModeRead); err != nil {
+			return err
+		} else if !has {
+			continue
+		}
+
+		notification, err := getIssueNotification(sess, user.ID, issue.ID)
+		if err != nil {
+			return err
+		}
+		if notification.ID > 0 {
+			err = updateIssueNotification(sess, user.ID, issue.ID, commentID, notificationAuthorID)
+		} else {
+			err = createIssueNotification(sess, user.ID, issue, commentID, notificationAuthorID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// CreateOrUpdateReleaseNotifications creates a release notification for each
+// watcher, or updates it if already exists
+func CreateOrUpdateReleaseNotifications(rel *Release, notificationAuthorID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	watches, err := getWatchers(sess, rel.RepoID)
+	if err != nil {
+		return err
+	}
+
+	for _, watch := range watches {
+		if watch.UserID == notificationAuthorID || !watch.HasEvent(WatchEventRelease) {
+			continue
+		}
+
+		notification := new(Notification)
+		has, err := sess.
+			Where("user_id = ? AND release_id = ?", watch.UserID, rel.ID).
+			Get(notification)
+		if err != nil {
+			return err
+		}
+
+		notification.Status = NotificationStatusUnread
+		notification.UpdatedBy = notificationAuthorID
+		if has {
+			_, err = sess.ID(notification.ID).Update(notification)
+		} else {
+			notification.UserID = watch.UserID
+			notification.RepoID = rel.RepoID
+			notification.Source = NotificationSourceRelease
+			notification.ReleaseID = rel.ID
+			_, err = sess.Insert(notification)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// CreatePushNotifications creates a commit notification of the pushed head
+// commit for each watcher of pushes
+func CreatePushNotifications(repo *Repository, commitID string, notificationAuthorID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	watches, err := getWatchers(sess, repo.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, watch := range watches {
+		if watch.UserID == notificationAuthorID || !watch.HasEvent(WatchEventPush) {
+			continue
+		}
+
+		if _, err = sess.Insert(&Notification{
+			UserID:    watch.UserID,
+			RepoID:    repo.ID,
+			Status:    NotificationStatusUnread,
+			Source:    NotificationSourceCommit,
+			CommitID:  commitID,
+			UpdatedBy: notificationAuthorID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
 func getNotificationsByIssueID(e Engine, issueID int64) (notifications []*Notification, err error) {
 	err = e.
 		Where("issue_id = ?", issueID).
@@ -144,12 +341,13 @@ func notificationExists(notifications []*Notification, issueID, userID int64) bo
 	return false
 }
 
-func createIssueNotification(e Engine, userID int64, issue *Issue, updatedByID int64) error {
+func createIssueNotification(e Engine, userID int64, issue *Issue, commentID, updatedByID int64) error {
 	notification := &Notification{
 		UserID:    userID,
 		RepoID:    issue.RepoID,
 		Status:    NotificationStatusUnread,
 		IssueID:   issue.ID,
+		CommentID: commentID,
 		UpdatedBy: updatedByID,
 	}
 
@@ -163,7 +361,7 @@ func createIssueNotification(e Engine, userID int64, issue *Issue, updatedByID i
 	return err
 }
 
-func updateIssueNotification(e Engine, userID, issueID, updatedByID int64) error {
+func updateIssueNotification(e Engine, userID, issueID, commentID, updatedByID int64) error {
 	notification, err := getIssueNotification(e, userID, issueID)
 	if err != nil {
 		return err
@@ -171,8 +369,13 @@ func updateIssueNotification(e Engine, userID, issueID, updatedByID int64) error
 
 	notification.Status = NotificationStatusUnread
 	notification.UpdatedBy = updatedByID
+	cols := []string{"status", "updated_by", "updated_unix"}
+	if commentID != 0 {
+		notification.CommentID = commentID
+		cols = append(cols, "comment_id")
+	}
 
-	_, err = e.ID(notification.ID).Update(notification)
+	_, err = e.ID(notification.ID).Cols(cols...).Update(notification)
 	return err
 }
 
@@ -208,6 +411,186 @@ func notificationsForUser(e Engine, user *User, statuses []NotificationStatus, p
 	return
 }
 
+// FindNotificationOptions represent the filters for notifications. If an ID is 0 it will be ignored.
+type FindNotificationOptions struct {
+	UserID            int64
+	RepoID            int64
+	Status            []NotificationStatus
+	Participating     bool // Only notifications of issues the user posted, commented or explicitly watches
+	UpdatedAfterUnix  int64
+	UpdatedBeforeUnix int64
+	Page              int
+	PageSize          int
+}
+
+func (opts *FindNotificationOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.UserID != 0 {
+		cond = cond.And(builder.Eq{"notification.user_id": opts.UserID})
+	}
+	if opts.RepoID != 0 {
+		cond = cond.And(builder.Eq{"notification.repo_id": opts.RepoID})
+	}
+	if len(opts.Status) > 0 {
+		cond = cond.And(builder.In("notification.status", opts.Status))
+	}
+	if opts.Participating {
+		cond = cond.And(builder.Or(
+			builder.Expr("notification.issue_id IN (SELECT issue_id FROM issue_watch WHERE issue_watch.user_id = ? AND issue_watch.is_watching = ?)", opts.UserID, true),
+			builder.Expr("notification.issue_id IN (SELECT id FROM issue WHERE issue.poster_id = ?)", opts.UserID),
+			builder.Expr("notification.issue_id IN (SELECT issue_id FROM comment WHERE comment.poster_id = ?)", opts.UserID),
+		))
+	}
+	if opts.UpdatedAfterUnix != 0 {
+		cond = cond.And(builder.Gte{"notification.updated_unix": opts.UpdatedAfterUnix})
+	}
+	if opts.UpdatedBeforeUnix != 0 {
+		cond = cond.And(builder.Lte{"notification.updated_unix": opts.UpdatedBeforeUnix})
+	}
+	return cond
+}
+
+// FindNotifications returns the notifications matching the given options, most recently updated first
+func FindNotifications(opts *FindNotificationOptions) ([]*Notification, error) {
+	return findNotifications(x, opts)
+}
+
+func findNotifications(e Engine, opts *FindNotificationOptions) (notifications []*Notification, err error) {
+	sess := e.Where(opts.toConds()).OrderBy("notification.updated_unix DESC")
+	if opts.PageSize > 0 {
+		if opts.Page <= 0 {
+			opts.Page = 1
+		}
+		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
+	}
+	err = sess.Find(&notifications)
+	return
+}
+
+// CountNotifications returns the number of notifications matching the given options
+func CountNotifications(opts *FindNotificationOptions) (int64, error) {
+	return x.Where(opts.toConds()).Count(&Notification{})
+}
+
+// LoadAttributes loads the repository and the subject of the notification
+func (n *Notification) LoadAttributes() error {
+	return n.loadAttributes(x)
+}
+
+func (n *Notification) loadAttributes(e Engine) (err error) {
+	if n.Repository == nil {
+		if n.Repository, err = getRepositoryByID(e, n.RepoID); err != nil {
+			return fmt.Errorf("getRepositoryByID [%d]: %v", n.RepoID, err)
+		}
+	}
+	if n.Issue == nil && n.IssueID != 0 {
+		if n.Issue, err = getIssueByID(e, n.IssueID); err != nil {
+			return fmt.Errorf("getIssueByID [%d]: %v", n.IssueID, err)
+		}
+		n.Issue.Repo = n.Repository
+	}
+	if n.Comment == nil && n.CommentID != 0 {
+		comment := new(Comment)
+		// the comment may have been deleted since, the notification then points to the issue
+		if has, err := e.ID(n.CommentID).Get(comment); err != nil {
+			return fmt.Errorf("get comment [%d]: %v", n.CommentID, err)
+		} else if has {
+			n.Comment = comment
+		}
+	}
+	if n.Release == nil && n.ReleaseID != 0 {
+		n.Release = new(Release)
+		if has, err := e.ID(n.ReleaseID).Get(n.Release); err != nil {
+			return fmt.Errorf("get release [%d]: %v", n.ReleaseID, err)
+		} else if !has {
+			return ErrReleaseNotExist{n.ReleaseID, ""}
+		}
+		n.Release.Repo = n.Repository
+	}
+	return nil
+}
+
+// HTMLURL returns the absolute URL to the subject of this notification.
+// LoadAttributes must have been called before
+func (n *Notification) HTMLURL() string {
+	switch n.Source {
+	case NotificationSourceIssue, NotificationSourcePullRequest:
+		if n.Comment != nil {
+			return fmt.Sprintf("%s#%s", n.Issue.HTMLURL(), n.Comment.HashTag())
+		}
+		return n.Issue.HTMLURL()
+	case NotificationSourceCommit:
+		return n.Repository.HTMLURL() + "/commit/" + n.CommitID
+	case NotificationSourceRelease:
+		return n.Repository.HTMLURL() + "/releases"
+	}
+	return n.Repository.HTMLURL()
+}
+
+// Title returns the title of the subject of this notification.
+// LoadAttributes must have been called before
+func (n *Notification) Title() string {
+	switch n.Source {
+	case NotificationSourceIssue, NotificationSourcePullRequest:
+		return n.Issue.Title
+	case NotificationSourceCommit:
+		return n.CommitID
+	case NotificationSourceRelease:
+		return n.Release.Title
+	}
+	return ""
+}
+
+// APIURL returns the absolute API URL to this notification thread.
+func (n *Notification) APIURL() string {
+	return setting.AppURL + path.Join("api/v1/notifications/threads", fmt.Sprint(n.ID))
+}
+
+// APIFormat converts a Notification to the api.NotificationThread format,
+// LoadAttributes must have been called before
+func (n *Notification) APIFormat() *api.NotificationThread {
+	result := &api.NotificationThread{
+		ID:         n.ID,
+		Repository: n.Repository.APIFormat(AccessModeRead),
+		Unread:     n.Status == NotificationStatusUnread,
+		Pinned:     n.Status == NotificationStatusPinned,
+		UpdatedAt:  n.UpdatedUnix.AsTime(),
+		URL:        n.APIURL(),
+	}
+
+	switch n.Source {
+	case NotificationSourceIssue:
+		result.Subject = &api.NotificationSubject{
+			Type:  "Issue",
+			Title: n.Issue.Title,
+			URL:   n.Issue.APIURL(),
+		}
+	case NotificationSourcePullRequest:
+		result.Subject = &api.NotificationSubject{
+			Type:  "Pull",
+			Title: n.Issue.Title,
+			URL:   n.Repository.APIURL() + "/" + path.Join("pulls", fmt.Sprint(n.Issue.Index)),
+		}
+	case NotificationSourceCommit:
+		result.Subject = &api.NotificationSubject{
+			Type:  "Commit",
+			Title: n.CommitID,
+			URL:   n.Repository.APIURL() + "/" + path.Join("git/commits", n.CommitID),
+		}
+	case NotificationSourceRelease:
+		result.Subject = &api.NotificationSubject{
+			Type:  "Release",
+			Title: n.Release.Title,
+			URL:   n.Release.APIURL(),
+		}
+	}
+	if n.Comment != nil && result.Subject != nil {
+		result.Subject.LatestCommentURL = n.Repository.APIURL() + "/" + path.Join("issues/comments", fmt.Sprint(n.Comment.ID))
+	}
+
+	return result
+}
+
 // GetRepo returns the repo of the notification
 func (n *Notification) GetRepo() (*Repository, error) {
 	n.Repository = new(Repository)
@@ -273,6 +656,11 @@ func SetNotificationStatus(notificationID int64, user *User, status Notification
 	return err
 }
 
+// GetNotificationByID returns the notification with the given ID
+func GetNotificationByID(notificationID int64) (*Notification, error) {
+	return getNotificationByID(notificationID)
+}
+
 func getNotificationByID(notificationID int64) (*Notification, error) {
 	notification := new(Notification)
 	ok, err := x.
@@ -284,7 +672,7 @@ func getNotificationByID(notificationID int64) (*Notification, error) {
 	}
 
 	if !ok {
-		return nil, fmt.Errorf("Notification %d does not exists", notificationID)
+		return nil, ErrNotificationNotExist{ID: notificationID}
 	}
 
 	return notification, nil
@@ -299,3 +687,97 @@ func UpdateNotificationStatuses(user *User, currentStatus NotificationStatus, de
 		Update(n)
 	return err
 }
+
+// UpdateNotificationsStatus updates the status of all of a user's notifications matching the given options
+func UpdateNotificationsStatus(user *User, opts *FindNotificationOptions, status NotificationStatus) error {
+	opts.UserID = user.ID
+	n := &Notification{Status: status, UpdatedBy: user.ID}
+	_, err := x.
+		Where(opts.toConds()).
+		Cols("status", "updated_by", "updated_unix").
+		Update(n)
+	return err
+}
+
+const notificationDigest = "notification_digest"
+
+// notificationDigestSlack tolerates the drift of the cron schedule, so that a
+// weekly digest is not postponed by a whole day when the task runs slightly early.
+const notificationDigestSlack = time.Hour
+
+// SendNotificationDigests mails their unread notifications to the users who
+// chose to receive daily or weekly digests, once their period has elapsed.
+func SendNotificationDigests() {
+	if !setting.Service.EnableNotifyMail {
+		return
+	}
+	if !taskStatusTable.StartIfNotRunning(notificationDigest) {
+		return
+	}
+	defer taskStatusTable.Stop(notificationDigest)
+
+	log.Trace("Doing: SendNotificationDigests")
+
+	users := make([]*User, 0, 10)
+	if err := x.
+		Where("type = ? AND is_active = ?", UserTypeIndividual, true).
+		In("email_notifications_preference", EmailNotificationsDaily, EmailNotificationsWeekly).
+		Find(&users); err != nil {
+		log.Error(4, "SendNotificationDigests: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, u := range users {
+		if !isNotificationDigestDue(u, now) {
+			continue
+		}
+		if err := sendNotificationDigest(x, u, now); err != nil {
+			log.Error(4, "sendNotificationDigest [user_id: %d]: %v", u.ID, err)
+		}
+	}
+}
+
+func isNotificationDigestDue(u *User, now time.Time) bool {
+	period := 24 * time.Hour
+	if u.EmailNotifications() == EmailNotificationsWeekly {
+		period = 7 * 24 * time.Hour
+	}
+	return u.LastDigestUnix.AsTime().Add(period - notificationDigestSlack).Before(now)
+}
+
+// getDigestNotifications returns the user's notifications which are still unread and
+// were updated since their last digest.
+func getDigestNotifications(e Engine, u *User) ([]*Notification, error) {
+	notifications, err := findNotifications(e, &FindNotificationOptions{
+		UserID:           u.ID,
+		Status:           []NotificationStatus{NotificationStatusUnread},
+		UpdatedAfterUnix: int64(u.LastDigestUnix) + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := notifications[:0]
+	for _, n := range notifications {
+		if err = n.loadAttributes(e); err != nil {
+			log.Error(4, "loadAttributes [notification_id: %d]: %v", n.ID, err)
+			continue
+		}
+		loaded = append(loaded, n)
+	}
+	return loaded, nil
+}
+
+func sendNotificationDigest(e Engine, u *User, now time.Time) error {
+	notifications, err := getDigestNotifications(e, u)
+	if err != nil {
+		return fmt.Errorf("getDigestNotifications: %v", err)
+	}
+
+	SendNotificationDigestMail(u, notifications)
+
+	u.LastDigestUnix = util.TimeStamp(now.Unix())
+	_, err = e.ID(u.ID).Cols("last_digest_unix").Update(u)
+	return err
+}
@@ -6,6 +6,9 @@ package models
 
 import (
 	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +28,68 @@ func TestCreateOrUpdateIssueNotifications(t *testing.T) {
 	assert.Equal(t, NotificationStatusUnread, notf.Status)
 }
 
+func TestCreateOrUpdateCommentNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	// user 4 does not want to be notified of comments
+	assert.NoError(t, SetWatchEvents(4, issue.RepoID, WatchEventIssue))
+
+	assert.NoError(t, CreateOrUpdateCommentNotifications(issue, 2, 2))
+
+	notf := AssertExistsAndLoadBean(t, &Notification{UserID: 1, IssueID: issue.ID}).(*Notification)
+	assert.Equal(t, NotificationStatusUnread, notf.Status)
+	assert.EqualValues(t, 2, notf.CommentID)
+	AssertNotExistsBean(t, &Notification{UserID: 4, IssueID: issue.ID})
+}
+
+func TestCreateOrUpdateMentionNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	assert.NoError(t, CreateOrUpdateMentionNotifications(issue, 0, []string{"User4", "user2", "nonexistent"}, 2))
+
+	notf := AssertExistsAndLoadBean(t, &Notification{UserID: 4, IssueID: issue.ID}).(*Notification)
+	assert.Equal(t, NotificationStatusUnread, notf.Status)
+	// the author of the mention is not notified
+	AssertNotExistsBean(t, &Notification{UserID: 2, IssueID: issue.ID})
+}
+
+func TestCreateOrUpdateReleaseNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	rel := &Release{RepoID: 1, PublisherID: 1, TagName: "v1.0", LowerTagName: "v1.0", Title: "v1.0"}
+	_, err := x.Insert(rel)
+	assert.NoError(t, err)
+
+	assert.NoError(t, CreateOrUpdateReleaseNotifications(rel, 1))
+	notf := AssertExistsAndLoadBean(t, &Notification{UserID: 4, ReleaseID: rel.ID}).(*Notification)
+	assert.Equal(t, NotificationSourceRelease, notf.Source)
+	assert.Equal(t, NotificationStatusUnread, notf.Status)
+	// the publisher is not notified
+	AssertNotExistsBean(t, &Notification{UserID: 1, ReleaseID: rel.ID})
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.NoError(t, SetNotificationStatus(notf.ID, user, NotificationStatusRead))
+	assert.NoError(t, CreateOrUpdateReleaseNotifications(rel, 1))
+	AssertExistsAndLoadBean(t, &Notification{ID: notf.ID, Status: NotificationStatusUnread})
+	cnt, err := CountNotifications(&FindNotificationOptions{UserID: 4, RepoID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, cnt)
+}
+
+func TestCreatePushNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, SetWatchEvents(4, repo.ID, WatchEventsAll))
+
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	assert.NoError(t, CreatePushNotifications(repo, commitID, 2))
+
+	notf := AssertExistsAndLoadBean(t, &Notification{UserID: 4, CommitID: commitID}).(*Notification)
+	assert.Equal(t, NotificationSourceCommit, notf.Source)
+	// user 1 is not notified of pushes by default
+	AssertNotExistsBean(t, &Notification{UserID: 1, CommitID: commitID})
+}
+
 func TestNotificationsForUser(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
@@ -99,3 +164,109 @@ func TestUpdateNotificationStatuses(t *testing.T) {
 	AssertExistsAndLoadBean(t,
 		&Notification{ID: notfPinned.ID, Status: NotificationStatusPinned})
 }
+
+func TestFindNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notfs, err := FindNotifications(&FindNotificationOptions{
+		UserID: 2,
+		Status: []NotificationStatus{NotificationStatusUnread, NotificationStatusPinned},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 2)
+
+	notfs, err = FindNotifications(&FindNotificationOptions{UserID: 2, RepoID: 1, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 1)
+
+	// user 2 neither posted, commented nor watches issue 2
+	notfs, err = FindNotifications(&FindNotificationOptions{UserID: 2, Participating: true})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 0)
+
+	// user 1 posted issue 1
+	notfs, err = FindNotifications(&FindNotificationOptions{UserID: 1, Participating: true})
+	assert.NoError(t, err)
+	if assert.Len(t, notfs, 1) {
+		assert.EqualValues(t, 1, notfs[0].ID)
+	}
+
+	notfs, err = FindNotifications(&FindNotificationOptions{UserID: 2, UpdatedAfterUnix: 946684801})
+	assert.NoError(t, err)
+	assert.Len(t, notfs, 0)
+
+	cnt, err := CountNotifications(&FindNotificationOptions{UserID: 2, UpdatedBeforeUnix: 946684800})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, cnt)
+}
+
+func TestGetNotificationByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notf, err := GetNotificationByID(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, notf.UserID)
+
+	_, err = GetNotificationByID(NonexistentID)
+	assert.True(t, IsErrNotificationNotExist(err))
+}
+
+func TestNotification_APIFormat(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	notf := AssertExistsAndLoadBean(t, &Notification{ID: 1}).(*Notification)
+	assert.NoError(t, notf.LoadAttributes())
+
+	apiNotf := notf.APIFormat()
+	assert.EqualValues(t, 1, apiNotf.ID)
+	assert.True(t, apiNotf.Unread)
+	assert.False(t, apiNotf.Pinned)
+	assert.EqualValues(t, 1, apiNotf.Repository.ID)
+	if assert.NotNil(t, apiNotf.Subject) {
+		assert.Equal(t, "issue1", apiNotf.Subject.Title)
+		assert.Equal(t, notf.Issue.APIURL(), apiNotf.Subject.URL)
+	}
+}
+
+func TestUpdateNotificationsStatus(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, UpdateNotificationsStatus(user, &FindNotificationOptions{
+		RepoID: 1,
+		Status: []NotificationStatus{NotificationStatusUnread},
+	}, NotificationStatusRead))
+	AssertExistsAndLoadBean(t, &Notification{ID: 4, Status: NotificationStatusRead})
+	AssertExistsAndLoadBean(t, &Notification{ID: 3, Status: NotificationStatusPinned})
+	// notifications of other users are left untouched
+	AssertExistsAndLoadBean(t, &Notification{ID: 1, Status: NotificationStatusUnread})
+}
+
+func TestIsNotificationDigestDue(t *testing.T) {
+	now := time.Now()
+	daily := &User{EmailNotificationsPreference: EmailNotificationsDaily}
+	weekly := &User{EmailNotificationsPreference: EmailNotificationsWeekly}
+
+	daily.LastDigestUnix = util.TimeStamp(now.Add(-24 * time.Hour).Unix())
+	weekly.LastDigestUnix = daily.LastDigestUnix
+	assert.True(t, isNotificationDigestDue(daily, now))
+	assert.False(t, isNotificationDigestDue(weekly, now))
+
+	weekly.LastDigestUnix = util.TimeStamp(now.Add(-7 * 24 * time.Hour).Unix())
+	assert.True(t, isNotificationDigestDue(weekly, now))
+}
+
+func TestGetDigestNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+
+	notifications, err := getDigestNotifications(x, user)
+	assert.NoError(t, err)
+	if assert.Len(t, notifications, 1) {
+		assert.EqualValues(t, 1, notifications[0].ID)
+		assert.NotNil(t, notifications[0].Issue)
+		assert.Equal(t, "issue1", notifications[0].Title())
+	}
+
+	// notifications already sent in the previous digest are left out
+	user.LastDigestUnix = 946684800
+	notifications, err = getDigestNotifications(x, user)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 0)
+}
@@ -0,0 +1,526 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/go-xorm/xorm"
+	gouuid "github.com/satori/go.uuid"
+
+	"code.gitea.io/gitea/modules/generate"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// OAuth2Application represents an OAuth2 client registered by a user or an organization.
+type OAuth2Application struct {
+	ID           int64 `xorm:"pk autoincr"`
+	UID          int64 `xorm:"INDEX"`
+	Owner        *User `xorm:"-"`
+	Name         string
+	ClientID     string   `xorm:"UNIQUE"`
+	ClientSecret string   // SHA256 hash of the secret, the secret itself is only shown once
+	RedirectURIs []string `xorm:"redirect_uris JSON TEXT"`
+	// Public clients like native or browser applications can't keep their secret
+	// confidential and may use PKCE instead to obtain tokens.
+	ConfidentialClient bool `xorm:"NOT NULL DEFAULT TRUE"`
+
+	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix util.TimeStamp `xorm:"INDEX updated"`
+}
+
+// TableName sets the table name to `oauth2_application`
+func (app *OAuth2Application) TableName() string {
+	return "oauth2_application"
+}
+
+// PrimaryRedirectURI returns the first redirect uri or an empty string if empty
+func (app *OAuth2Application) PrimaryRedirectURI() string {
+	if len(app.RedirectURIs) == 0 {
+		return ""
+	}
+	return app.RedirectURIs[0]
+}
+
+// ContainsRedirectURI checks if the given redirect uri has been registered for the application
+func (app *OAuth2Application) ContainsRedirectURI(redirectURI string) bool {
+	for _, uri := range app.RedirectURIs {
+		if uri == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func hashOAuth2ClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateClientSecret generates a new client secret, saves its hash and returns the plain secret
+func (app *OAuth2Application) GenerateClientSecret() (string, error) {
+	secret, err := generate.GetRandomString(48)
+	if err != nil {
+		return "", err
+	}
+	app.ClientSecret = hashOAuth2ClientSecret(secret)
+	if _, err = x.ID(app.ID).Cols("client_secret").Update(app); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// ValidateClientSecret validates the given secret against the saved hash
+func (app *OAuth2Application) ValidateClientSecret(secret string) bool {
+	if len(secret) == 0 || len(app.ClientSecret) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(app.ClientSecret), []byte(hashOAuth2ClientSecret(secret))) == 1
+}
+
+// LoadOwner loads the user or organization owning the application
+func (app *OAuth2Application) LoadOwner() (err error) {
+	if app.Owner == nil {
+		app.Owner, err = GetUserByID(app.UID)
+	}
+	return err
+}
+
+func (app *OAuth2Application) getGrantByUserID(e Engine, userID int64) (*OAuth2Grant, error) {
+	grant := new(OAuth2Grant)
+	has, err := e.Where("user_id = ? AND application_id = ?", userID, app.ID).Get(grant)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return grant, nil
+}
+
+// GetGrantByUserID returns the grant of the given user for the application, or nil if there is none
+func (app *OAuth2Application) GetGrantByUserID(userID int64) (*OAuth2Grant, error) {
+	return app.getGrantByUserID(x, userID)
+}
+
+// CreateGrant generates a grant for the given user, or updates the scope of an existing one
+func (app *OAuth2Application) CreateGrant(userID int64, scope string) (*OAuth2Grant, error) {
+	grant, err := app.GetGrantByUserID(userID)
+	if err != nil {
+		return nil, err
+	} else if grant != nil {
+		if grant.Scope != scope {
+			grant.Scope = scope
+			if _, err = x.ID(grant.ID).Cols("scope").Update(grant); err != nil {
+				return nil, err
+			}
+		}
+		return grant, nil
+	}
+
+	grant = &OAuth2Grant{
+		UserID:        userID,
+		ApplicationID: app.ID,
+		Counter:       1,
+		Scope:         scope,
+	}
+	if _, err = x.Insert(grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// GetOAuth2ApplicationByClientID returns the oauth2 application with the given client_id
+func GetOAuth2ApplicationByClientID(clientID string) (*OAuth2Application, error) {
+	app := new(OAuth2Application)
+	has, err := x.Where("client_id = ?", clientID).Get(app)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuthClientIDInvalid{ClientID: clientID}
+	}
+	return app, nil
+}
+
+func getOAuth2ApplicationByID(e Engine, id int64) (*OAuth2Application, error) {
+	app := new(OAuth2Application)
+	has, err := e.ID(id).Get(app)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrOAuthApplicationNotFound{ID: id}
+	}
+	return app, nil
+}
+
+// GetOAuth2ApplicationByID returns the oauth2 application with the given id
+func GetOAuth2ApplicationByID(id int64) (*OAuth2Application, error) {
+	return getOAuth2ApplicationByID(x, id)
+}
+
+// GetOAuth2ApplicationsByUserID returns all oauth2 applications owned by the given user or organization
+func GetOAuth2ApplicationsByUserID(userID int64) ([]*OAuth2Application, error) {
+	apps := make([]*OAuth2Application, 0, 5)
+	return apps, x.Where("uid = ?", userID).Desc("id").Find(&apps)
+}
+
+// CreateOAuth2ApplicationOptions holds options to create an oauth2 application
+type CreateOAuth2ApplicationOptions struct {
+	Name               string
+	UserID             int64
+	RedirectURIs       []string
+	ConfidentialClient bool
+}
+
+// CreateOAuth2Application inserts a new oauth2 application
+func CreateOAuth2Application(opts CreateOAuth2ApplicationOptions) (*OAuth2Application, error) {
+	app := &OAuth2Application{
+		UID:          opts.UserID,
+		Name:         opts.Name,
+		ClientID:     gouuid.NewV4().String(),
+		RedirectURIs: opts.RedirectURIs,
+
+		ConfidentialClient: opts.ConfidentialClient,
+	}
+	if _, err := x.Insert(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// UpdateOAuth2ApplicationOptions holds options to update an oauth2 application
+type UpdateOAuth2ApplicationOptions struct {
+	ID                 int64
+	Name               string
+	UserID             int64
+	RedirectURIs       []string
+	ConfidentialClient bool
+}
+
+// UpdateOAuth2Application updates the name, redirect uris and client type of an oauth2 application
+func UpdateOAuth2Application(opts UpdateOAuth2ApplicationOptions) (*OAuth2Application, error) {
+	app, err := GetOAuth2ApplicationByID(opts.ID)
+	if err != nil {
+		return nil, err
+	} else if app.UID != opts.UserID {
+		return nil, ErrOAuthApplicationNotFound{ID: opts.ID}
+	}
+
+	app.Name = opts.Name
+	app.RedirectURIs = opts.RedirectURIs
+	app.ConfidentialClient = opts.ConfidentialClient
+	if _, err = x.ID(app.ID).Cols("name", "redirect_uris", "confidential_client").Update(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func deleteOAuth2Application(sess *xorm.Session, id, userID int64) error {
+	if deleted, err := sess.Delete(&OAuth2Application{ID: id, UID: userID}); err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrOAuthApplicationNotFound{ID: id}
+	}
+
+	grantIDs := make([]int64, 0, 10)
+	if err := sess.Table("oauth2_grant").Where("application_id = ?", id).
+		Cols("id").Find(&grantIDs); err != nil {
+		return err
+	}
+	if len(grantIDs) == 0 {
+		return nil
+	}
+
+	if _, err := sess.In("grant_id", grantIDs).Delete(new(OAuth2AuthorizationCode)); err != nil {
+		return err
+	}
+	_, err := sess.In("id", grantIDs).Delete(new(OAuth2Grant))
+	return err
+}
+
+// DeleteOAuth2Application deletes the application with the given id and the grants and codes related to it.
+// It verifies that the application belongs to the given user or organization.
+func DeleteOAuth2Application(id, userID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if err := deleteOAuth2Application(sess, id, userID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// deleteOAuth2ByUserID deletes the applications owned by a user or an organization
+// and the grants the user has given to applications.
+func deleteOAuth2ByUserID(sess *xorm.Session, userID int64) error {
+	appIDs := make([]int64, 0, 5)
+	if err := sess.Table("oauth2_application").Where("uid = ?", userID).
+		Cols("id").Find(&appIDs); err != nil {
+		return err
+	}
+	for _, appID := range appIDs {
+		if err := deleteOAuth2Application(sess, appID, userID); err != nil {
+			return err
+		}
+	}
+
+	grantIDs := make([]int64, 0, 5)
+	if err := sess.Table("oauth2_grant").Where("user_id = ?", userID).
+		Cols("id").Find(&grantIDs); err != nil {
+		return err
+	}
+	if len(grantIDs) == 0 {
+		return nil
+	}
+	if _, err := sess.In("grant_id", grantIDs).Delete(new(OAuth2AuthorizationCode)); err != nil {
+		return err
+	}
+	_, err := sess.In("id", grantIDs).Delete(new(OAuth2Grant))
+	return err
+}
+
+//////////////////////////////////////////////////////
+
+// OAuth2AuthorizationCode is a code to obtain an access token in combination with the client secret once. It has a limited lifetime.
+type OAuth2AuthorizationCode struct {
+	ID                  int64        `xorm:"pk autoincr"`
+	Grant               *OAuth2Grant `xorm:"-"`
+	GrantID             int64        `xorm:"INDEX"`
+	Code                string       `xorm:"INDEX UNIQUE"`
+	CodeChallenge       string
+	CodeChallengeMethod string
+	RedirectURI         string
+	ValidUntil          util.TimeStamp `xorm:"index"`
+}
+
+// TableName sets the table name to `oauth2_authorization_code`
+func (code *OAuth2AuthorizationCode) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+// GenerateRedirectURI generates a redirect URI for a successful authorization request. State will be used if not empty.
+func (code *OAuth2AuthorizationCode) GenerateRedirectURI(state string) (redirect *url.URL, err error) {
+	if redirect, err = url.Parse(code.RedirectURI); err != nil {
+		return
+	}
+	q := redirect.Query()
+	if state != "" {
+		q.Set("state", state)
+	}
+	q.Set("code", code.Code)
+	redirect.RawQuery = q.Encode()
+	return
+}
+
+// IsExpired returns true if the code may no longer be exchanged for a token
+func (code *OAuth2AuthorizationCode) IsExpired() bool {
+	return code.ValidUntil <= util.TimeStampNow()
+}
+
+// Invalidate deletes the auth code from the database to invalidate this code
+func (code *OAuth2AuthorizationCode) Invalidate() error {
+	_, err := x.Delete(code)
+	return err
+}
+
+// ValidateCodeChallenge validates the given verifier against the saved code challenge. This is part of the PKCE implementation.
+func (code *OAuth2AuthorizationCode) ValidateCodeChallenge(verifier string) bool {
+	switch code.CodeChallengeMethod {
+	case "S256":
+		// base64url(SHA256(verifier)) see https://tools.ietf.org/html/rfc7636#section-4.6
+		h := sha256.Sum256([]byte(verifier))
+		hashedVerifier := base64.RawURLEncoding.EncodeToString(h[:])
+		return subtle.ConstantTimeCompare([]byte(hashedVerifier), []byte(code.CodeChallenge)) == 1
+	case "plain":
+		return subtle.ConstantTimeCompare([]byte(verifier), []byte(code.CodeChallenge)) == 1
+	case "":
+		return true
+	default:
+		// unsupported method -> return false
+		return false
+	}
+}
+
+// GetOAuth2AuthorizationByCode returns an authorization by its code
+func GetOAuth2AuthorizationByCode(code string) (*OAuth2AuthorizationCode, error) {
+	authCode := new(OAuth2AuthorizationCode)
+	if has, err := x.Where("code = ?", code).Get(authCode); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	authCode.Grant = new(OAuth2Grant)
+	if has, err := x.ID(authCode.GrantID).Get(authCode.Grant); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return authCode, nil
+}
+
+//////////////////////////////////////////////////////
+
+// OAuth2Grant represents the permission of a user for a specific application to access resources
+type OAuth2Grant struct {
+	ID            int64              `xorm:"pk autoincr"`
+	UserID        int64              `xorm:"INDEX unique(user_application)"`
+	Application   *OAuth2Application `xorm:"-"`
+	ApplicationID int64              `xorm:"INDEX unique(user_application)"`
+	Counter       int64              `xorm:"NOT NULL DEFAULT 1"`
+	Scope         string             `xorm:"TEXT"`
+	Nonce         string             `xorm:"TEXT"`
+	CreatedUnix   util.TimeStamp     `xorm:"created"`
+	UpdatedUnix   util.TimeStamp     `xorm:"updated"`
+}
+
+// TableName sets the table name to `oauth2_grant`
+func (grant *OAuth2Grant) TableName() string {
+	return "oauth2_grant"
+}
+
+// GenerateNewAuthorizationCode generates a new authorization code for a grant and saves it to the database
+func (grant *OAuth2Grant) GenerateNewAuthorizationCode(redirectURI, codeChallenge, codeChallengeMethod string) (*OAuth2AuthorizationCode, error) {
+	secret, err := generate.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	code := &OAuth2AuthorizationCode{
+		Grant:               grant,
+		GrantID:             grant.ID,
+		RedirectURI:         redirectURI,
+		Code:                secret,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+		// Authorization codes should be exchanged right away, see https://tools.ietf.org/html/rfc6749#section-4.1.2
+		ValidUntil: util.TimeStampNow().Add(10 * 60),
+	}
+	if _, err := x.Insert(code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// IncreaseCounter increases the counter and updates the grant, invalidating previously issued refresh tokens
+func (grant *OAuth2Grant) IncreaseCounter() error {
+	if _, err := x.ID(grant.ID).Incr("counter").Update(new(OAuth2Grant)); err != nil {
+		return err
+	}
+	updatedGrant, err := getOAuth2GrantByID(x, grant.ID)
+	if err != nil {
+		return err
+	}
+	grant.Counter = updatedGrant.Counter
+	return nil
+}
+
+// ScopeContains returns true if the grant scope contains the specified scope
+func (grant *OAuth2Grant) ScopeContains(scope string) bool {
+	for _, currentScope := range strings.Fields(grant.Scope) {
+		if scope == currentScope {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNonce updates the current nonce value of a grant
+func (grant *OAuth2Grant) SetNonce(nonce string) error {
+	grant.Nonce = nonce
+	_, err := x.ID(grant.ID).Cols("nonce").Update(grant)
+	return err
+}
+
+// AccessTokenScopes returns the access token scopes requested by the grant scope.
+// A grant that does not request any of them has the full rights of its user.
+func (grant *OAuth2Grant) AccessTokenScopes() []AccessTokenScope {
+	scopes := make([]AccessTokenScope, 0, 2)
+	for _, name := range strings.Fields(grant.Scope) {
+		for _, scope := range AccessTokenScopes {
+			if string(scope) == name {
+				scopes = append(scopes, scope)
+				break
+			}
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = append(scopes, AccessTokenScopeAll)
+	}
+	return scopes
+}
+
+// LoadApplication loads the application the grant belongs to
+func (grant *OAuth2Grant) LoadApplication() (err error) {
+	if grant.Application == nil {
+		grant.Application, err = GetOAuth2ApplicationByID(grant.ApplicationID)
+	}
+	return err
+}
+
+func getOAuth2GrantByID(e Engine, id int64) (*OAuth2Grant, error) {
+	grant := new(OAuth2Grant)
+	if has, err := e.ID(id).Get(grant); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return grant, nil
+}
+
+// GetOAuth2GrantByID returns the grant with the given ID, or nil if it does not exist
+func GetOAuth2GrantByID(id int64) (*OAuth2Grant, error) {
+	return getOAuth2GrantByID(x, id)
+}
+
+// GetOAuth2GrantsByUserID lists all grants of a certain user together with their applications
+func GetOAuth2GrantsByUserID(userID int64) ([]*OAuth2Grant, error) {
+	grants := make([]*OAuth2Grant, 0, 5)
+	if err := x.Where("user_id = ?", userID).Desc("id").Find(&grants); err != nil {
+		return nil, err
+	}
+
+	appIDs := make([]int64, 0, len(grants))
+	for _, grant := range grants {
+		appIDs = append(appIDs, grant.ApplicationID)
+	}
+	apps := make(map[int64]*OAuth2Application, len(appIDs))
+	if len(appIDs) > 0 {
+		if err := x.In("id", appIDs).Find(&apps); err != nil {
+			return nil, err
+		}
+	}
+
+	result := grants[:0]
+	for _, grant := range grants {
+		if app, ok := apps[grant.ApplicationID]; ok {
+			grant.Application = app
+			result = append(result, grant)
+		}
+	}
+	return result, nil
+}
+
+// RevokeOAuth2Grant deletes the grant with the given id and user id, together with its authorization codes
+func RevokeOAuth2Grant(grantID, userID int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if deleted, err := sess.Delete(&OAuth2Grant{ID: grantID, UserID: userID}); err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrOAuthGrantNotFound{ID: grantID}
+	}
+	if _, err := sess.Where("grant_id = ?", grantID).Delete(new(OAuth2AuthorizationCode)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
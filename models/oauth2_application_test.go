@@ -0,0 +1,275 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuth2Application_GenerateClientSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	secret, err := app.GenerateClientSecret()
+	assert.NoError(t, err)
+	assert.True(t, len(secret) > 0)
+	AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1, ClientSecret: app.ClientSecret})
+	assert.True(t, app.ValidateClientSecret(secret))
+}
+
+func TestOAuth2Application_ValidateClientSecret(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	assert.True(t, app.ValidateClientSecret("4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA"))
+	assert.False(t, app.ValidateClientSecret("fewijfowejgfiowjeoifew"))
+	assert.False(t, app.ValidateClientSecret(""))
+}
+
+func TestOAuth2Application_ContainsRedirectURI(t *testing.T) {
+	app := &OAuth2Application{
+		RedirectURIs: []string{"a", "b", "c"},
+	}
+	assert.True(t, app.ContainsRedirectURI("a"))
+	assert.True(t, app.ContainsRedirectURI("b"))
+	assert.True(t, app.ContainsRedirectURI("c"))
+	assert.False(t, app.ContainsRedirectURI("d"))
+}
+
+func TestGetOAuth2ApplicationByClientID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := GetOAuth2ApplicationByClientID("da7da3ba-9a13-4167-856f-3899de0b0138")
+	assert.NoError(t, err)
+	assert.Equal(t, "da7da3ba-9a13-4167-856f-3899de0b0138", app.ClientID)
+
+	app, err = GetOAuth2ApplicationByClientID("invalid client id")
+	assert.Error(t, err)
+	assert.True(t, IsErrOAuthClientIDInvalid(err))
+}
+
+func TestCreateOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := CreateOAuth2Application(CreateOAuth2ApplicationOptions{
+		Name:               "newapp",
+		UserID:             1,
+		RedirectURIs:       []string{"https://example.com/callback"},
+		ConfidentialClient: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "newapp", app.Name)
+	assert.Len(t, app.ClientID, 36)
+	AssertExistsAndLoadBean(t, &OAuth2Application{Name: "newapp", ConfidentialClient: true})
+}
+
+func TestUpdateOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app, err := UpdateOAuth2Application(UpdateOAuth2ApplicationOptions{
+		ID:           1,
+		Name:         "renamed",
+		UserID:       1,
+		RedirectURIs: []string{"https://example.com/other"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/other"}, app.RedirectURIs)
+	AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1, Name: "renamed"}, Cond("confidential_client = ?", false))
+
+	// only the owner may update the application
+	_, err = UpdateOAuth2Application(UpdateOAuth2ApplicationOptions{ID: 1, Name: "stolen", UserID: 2})
+	assert.True(t, IsErrOAuthApplicationNotFound(err))
+}
+
+func TestDeleteOAuth2Application(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.True(t, IsErrOAuthApplicationNotFound(DeleteOAuth2Application(1, 2)))
+
+	assert.NoError(t, DeleteOAuth2Application(1, 1))
+	AssertNotExistsBean(t, &OAuth2Application{ID: 1})
+	AssertNotExistsBean(t, &OAuth2Grant{ApplicationID: 1})
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{GrantID: 1})
+}
+
+func TestOAuth2Application_GetGrantByUserID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	grant, err := app.GetGrantByUserID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), grant.UserID)
+
+	grant, err = app.GetGrantByUserID(34923458)
+	assert.NoError(t, err)
+	assert.Nil(t, grant)
+}
+
+func TestOAuth2Application_CreateGrant(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
+	grant, err := app.CreateGrant(2, "")
+	assert.NoError(t, err)
+	assert.NotNil(t, grant)
+	assert.Equal(t, int64(2), grant.UserID)
+	assert.Equal(t, int64(1), grant.ApplicationID)
+	assert.Equal(t, "", grant.Scope)
+
+	// an existing grant is reused with the new scope
+	grant, err = app.CreateGrant(1, "openid")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), grant.ID)
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Scope: "openid"})
+}
+
+func TestGetOAuth2GrantByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant, err := GetOAuth2GrantByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), grant.ID)
+
+	grant, err = GetOAuth2GrantByID(34923458) // not existing
+	assert.NoError(t, err)
+	assert.Nil(t, grant)
+}
+
+func TestOAuth2Grant_IncreaseCounter(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 1}).(*OAuth2Grant)
+	assert.NoError(t, grant.IncreaseCounter())
+	assert.Equal(t, int64(2), grant.Counter)
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Counter: 2})
+}
+
+func TestOAuth2Grant_ScopeContains(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Scope: "openid profile"}).(*OAuth2Grant)
+	assert.True(t, grant.ScopeContains("openid"))
+	assert.True(t, grant.ScopeContains("profile"))
+	assert.False(t, grant.ScopeContains("email"))
+}
+
+func TestOAuth2Grant_AccessTokenScopes(t *testing.T) {
+	grant := &OAuth2Grant{Scope: "openid profile"}
+	assert.Equal(t, []AccessTokenScope{AccessTokenScopeAll}, grant.AccessTokenScopes())
+
+	grant.Scope = "openid repo:read issue"
+	assert.Equal(t, []AccessTokenScope{AccessTokenScopeRepoRead, AccessTokenScopeIssue}, grant.AccessTokenScopes())
+}
+
+func TestOAuth2Grant_GenerateNewAuthorizationCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1}).(*OAuth2Grant)
+	code, err := grant.GenerateNewAuthorizationCode("https://example.com/callback", "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg", "S256")
+	assert.NoError(t, err)
+	assert.NotNil(t, code)
+	assert.Len(t, code.Code, 32)
+	assert.False(t, code.IsExpired())
+}
+
+func TestGetOAuth2GrantsByUserID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grants, err := GetOAuth2GrantsByUserID(1)
+	assert.NoError(t, err)
+	if assert.Len(t, grants, 1) {
+		assert.Equal(t, int64(1), grants[0].ID)
+		assert.Equal(t, "Test", grants[0].Application.Name)
+	}
+
+	grants, err = GetOAuth2GrantsByUserID(34134)
+	assert.NoError(t, err)
+	assert.Empty(t, grants)
+}
+
+func TestRevokeOAuth2Grant(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	assert.True(t, IsErrOAuthGrantNotFound(RevokeOAuth2Grant(1, 2)))
+
+	assert.NoError(t, RevokeOAuth2Grant(1, 1))
+	AssertNotExistsBean(t, &OAuth2Grant{ID: 1, UserID: 1})
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{GrantID: 1})
+}
+
+func TestGetOAuth2AuthorizationByCode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	code, err := GetOAuth2AuthorizationByCode("authcode")
+	assert.NoError(t, err)
+	assert.NotNil(t, code)
+	assert.Equal(t, "authcode", code.Code)
+	assert.Equal(t, int64(1), code.ID)
+	assert.Equal(t, int64(1), code.Grant.ID)
+
+	code, err = GetOAuth2AuthorizationByCode("does not exist")
+	assert.NoError(t, err)
+	assert.Nil(t, code)
+}
+
+func TestOAuth2AuthorizationCode_ValidateCodeChallenge(t *testing.T) {
+	// test plain
+	code := &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "plain",
+		CodeChallenge:       "test123",
+	}
+	assert.True(t, code.ValidateCodeChallenge("test123"))
+	assert.False(t, code.ValidateCodeChallenge("ierwgjoergjio"))
+
+	// test S256
+	code = &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "S256",
+		CodeChallenge:       "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg",
+	}
+	assert.True(t, code.ValidateCodeChallenge("N1Zo9-8Rfwhkt68r1r29ty8YwIraXR8eh_1Qwxg7yQXsonBt"))
+	assert.False(t, code.ValidateCodeChallenge("wiogjerogorewngoenrgoiuenorg"))
+
+	// test unknown
+	code = &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "monkey",
+		CodeChallenge:       "foiwgjioriogeiogjerger",
+	}
+	assert.False(t, code.ValidateCodeChallenge("foiwgjioriogeiogjerger"))
+
+	// test no code challenge
+	code = &OAuth2AuthorizationCode{
+		CodeChallengeMethod: "",
+		CodeChallenge:       "foierjiogerogerg",
+	}
+	assert.True(t, code.ValidateCodeChallenge(""))
+}
+
+func TestOAuth2AuthorizationCode_GenerateRedirectURI(t *testing.T) {
+	code := &OAuth2AuthorizationCode{
+		RedirectURI: "https://example.com/callback",
+		Code:        "thecode",
+	}
+
+	redirect, err := code.GenerateRedirectURI("thestate")
+	assert.NoError(t, err)
+	assert.Equal(t, redirect.String(), "https://example.com/callback?code=thecode&state=thestate")
+
+	redirect, err = code.GenerateRedirectURI("")
+	assert.NoError(t, err)
+	assert.Equal(t, redirect.String(), "https://example.com/callback?code=thecode")
+}
+
+func TestOAuth2AuthorizationCode_Invalidate(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	code := AssertExistsAndLoadBean(t, &OAuth2AuthorizationCode{Code: "authcode"}).(*OAuth2AuthorizationCode)
+	assert.NoError(t, code.Invalidate())
+	AssertNotExistsBean(t, &OAuth2AuthorizationCode{Code: "authcode"})
+}
+
+func TestOAuth2Token_SignAndParse(t *testing.T) {
+	token := &OAuth2Token{
+		GrantID: 1,
+		Type:    TypeRefreshToken,
+		Counter: 3,
+	}
+	signed, err := token.SignToken()
+	assert.NoError(t, err)
+
+	parsed, err := ParseOAuth2Token(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), parsed.GrantID)
+	assert.Equal(t, TypeRefreshToken, parsed.Type)
+	assert.Equal(t, int64(3), parsed.Counter)
+
+	_, err = ParseOAuth2Token(signed + "x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// OAuth2TokenType represents the type of token for an oauth application
+type OAuth2TokenType int
+
+const (
+	// TypeAccessToken is a token with short lifetime to access the api
+	TypeAccessToken OAuth2TokenType = 0
+	// TypeRefreshToken is token with long lifetime to refresh access tokens obtained by the client
+	TypeRefreshToken OAuth2TokenType = 1
+)
+
+// OAuth2Token represents a JWT token used to authenticate a client
+type OAuth2Token struct {
+	GrantID int64           `json:"gnt"`
+	Type    OAuth2TokenType `json:"tt"`
+	Counter int64           `json:"cnt,omitempty"`
+	jwt.StandardClaims
+}
+
+// ParseOAuth2Token parses a signed jwt string
+func ParseOAuth2Token(jwtToken string) (*OAuth2Token, error) {
+	parsedToken, err := jwt.ParseWithClaims(jwtToken, &OAuth2Token{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Method == nil || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing algo: %v", token.Header["alg"])
+		}
+		return setting.OAuth2.JWTSecretBytes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var token *OAuth2Token
+	var ok bool
+	if token, ok = parsedToken.Claims.(*OAuth2Token); !ok || !parsedToken.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return token, nil
+}
+
+// SignToken signs the token with the JWT secret
+func (token *OAuth2Token) SignToken() (string, error) {
+	token.IssuedAt = time.Now().Unix()
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, token)
+	return jwtToken.SignedString(setting.OAuth2.JWTSecretBytes)
+}
+
+// OIDCToken represents an OpenID Connect id_token
+type OIDCToken struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+
+	// Scope profile
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+	Website           string `json:"website,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+
+	// Scope email
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+}
+
+// SignToken signs an id_token with the JWT secret
+func (token *OIDCToken) SignToken() (string, error) {
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, token)
+	return jwtToken.SignedString(setting.OAuth2.JWTSecretBytes)
+}
+
+// GetUserIDByOAuth2AccessToken returns the id of the user the given oauth2 access token
+// has been issued for, together with the grant it belongs to. It returns 0 if the
+// token is not a valid access token or its grant has been revoked.
+func GetUserIDByOAuth2AccessToken(accessToken string) (int64, *OAuth2Grant, error) {
+	token, err := ParseOAuth2Token(accessToken)
+	if err != nil || token.Type != TypeAccessToken {
+		return 0, nil, nil
+	}
+	grant, err := GetOAuth2GrantByID(token.GrantID)
+	if err != nil || grant == nil {
+		return 0, nil, err
+	}
+	return grant.UserID, grant, nil
+}
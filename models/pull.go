@@ -198,6 +198,7 @@ func (pr *PullRequest) APIFormat() *api.PullRequest {
 		Labels:    apiIssue.Labels,
 		Milestone: apiIssue.Milestone,
 		Assignee:  apiIssue.Assignee,
+		Assignees: apiIssue.Assignees,
 		State:     apiIssue.State,
 		Comments:  apiIssue.Comments,
 		HTMLURL:   pr.Issue.HTMLURL(),
@@ -260,6 +261,69 @@ func (pr *PullRequest) CanAutoMerge() bool {
 	return pr.Status == PullRequestStatusMergeable
 }
 
+// CheckUserAllowedToMerge checks whether doer may merge the pull request under the
+// protection rules of its base branch and whether it is still blocked by open dependencies.
+// It returns ErrNotAllowedToMerge with the reason when the merge is blocked.
+func (pr *PullRequest) CheckUserAllowedToMerge(doer *User) error {
+	if err := pr.GetBaseRepo(); err != nil {
+		return err
+	}
+	if pr.BaseRepo.IsDependencyCheckEnabled() {
+		if err := pr.LoadIssue(); err != nil {
+			return fmt.Errorf("LoadIssue: %v", err)
+		}
+		if blocked, err := pr.Issue.HasOpenDependencies(); err != nil {
+			return fmt.Errorf("HasOpenDependencies: %v", err)
+		} else if blocked {
+			return ErrNotAllowedToMerge{"it is still blocked by open dependencies"}
+		}
+	}
+
+	protectBranch, err := GetProtectedBranchBy(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("GetProtectedBranchBy: %v", err)
+	} else if protectBranch == nil {
+		return nil
+	}
+
+	if doer == nil || !protectBranch.CanUserMerge(doer.ID) {
+		return ErrNotAllowedToMerge{"you are not whitelisted to merge into this branch"}
+	}
+
+	if protectBranch.RequiredApprovals > 0 {
+		approvals, rejections, err := protectBranch.getOfficialReviews(x, pr)
+		if err != nil {
+			return fmt.Errorf("getOfficialReviews: %v", err)
+		}
+		if rejections > 0 {
+			return ErrNotAllowedToMerge{"changes have been requested by a reviewer"}
+		}
+		if approvals < protectBranch.RequiredApprovals {
+			return ErrNotAllowedToMerge{fmt.Sprintf("%d of %d required approvals granted", approvals, protectBranch.RequiredApprovals)}
+		}
+	}
+
+	if protectBranch.EnableStatusCheck && len(protectBranch.StatusCheckContexts) > 0 {
+		baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+		if err != nil {
+			return fmt.Errorf("OpenRepository: %v", err)
+		}
+		sha, err := baseGitRepo.GetRefCommitID(pr.GetGitRefName())
+		if err != nil {
+			return fmt.Errorf("GetRefCommitID(%s): %v", pr.GetGitRefName(), err)
+		}
+		missing, err := protectBranch.GetMissingStatusChecks(pr.BaseRepo, sha)
+		if err != nil {
+			return fmt.Errorf("GetMissingStatusChecks: %v", err)
+		}
+		if len(missing) > 0 {
+			return ErrNotAllowedToMerge{fmt.Sprintf("required status checks have not succeeded: %s", strings.Join(missing, ", "))}
+		}
+	}
+
+	return nil
+}
+
 // MergeStyle represents the approach to merge commits into base branch.
 type MergeStyle string
 
@@ -689,7 +753,7 @@ func (pr *PullRequest) testPatch() (err error) {
 }
 
 // NewPullRequest creates new pull request with labels for repository.
-func NewPullRequest(repo *Repository, pull *Issue, labelIDs []int64, uuids []string, pr *PullRequest, patch []byte) (err error) {
+func NewPullRequest(repo *Repository, pull *Issue, labelIDs, assigneeIDs []int64, uuids []string, pr *PullRequest, patch []byte) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -700,6 +764,7 @@ func NewPullRequest(repo *Repository, pull *Issue, labelIDs []int64, uuids []str
 		Repo:        repo,
 		Issue:       pull,
 		LabelIDs:    labelIDs,
+		AssigneeIDs: assigneeIDs,
 		Attachments: uuids,
 		IsPull:      true,
 	}); err != nil {
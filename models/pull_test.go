@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -237,3 +239,28 @@ func TestChangeUsernameInPullRequests(t *testing.T) {
 	}
 	CheckConsistencyFor(t, &PullRequest{})
 }
+
+func TestPullRequest_setMergedBlocked(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue3 := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	assert.NoError(t, CreateIssueDependency(user2, issue3, issue1))
+	assert.NoError(t, issue3.loadRepo(x))
+
+	// closing the blocked pull request is refused
+	assert.True(t, IsErrDependenciesLeft(issue3.ChangeStatus(user2, issue3.Repo, true)))
+
+	// but its commits may already be on the base branch, as manuallyMerged finds them
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr.MergedCommitID = "1234567890123456789012345678901234567890"
+	pr.MergedUnix = util.TimeStampNow()
+	pr.Status = PullRequestStatusManuallyMerged
+	pr.Merger = user2
+	pr.MergerID = user2.ID
+	assert.NoError(t, pr.setMerged())
+
+	AssertExistsAndLoadBean(t, &PullRequest{ID: 2, HasMerged: true})
+	AssertExistsAndLoadBean(t, &Issue{ID: 3}, Cond("is_closed=?", true))
+	CheckConsistencyFor(t, &Issue{}, &PullRequest{})
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -100,6 +101,25 @@ func (r *Release) APIFormat() *api.Release {
 	}
 }
 
+// sendWebhook triggers the release webhooks of the repository of the release.
+func (r *Release) sendWebhook(doer *User, action api.HookReleaseAction) {
+	if err := r.loadAttributes(x); err != nil {
+		log.Error(4, "loadAttributes [release: %d]: %v", r.ID, err)
+		return
+	}
+
+	if err := PrepareWebhooks(r.Repo, HookEventRelease, &api.ReleasePayload{
+		Action:     action,
+		Release:    r.APIFormat(),
+		Repository: r.Repo.APIFormat(AccessModeNone),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [release: %d, action: %s]: %v", r.ID, action, err)
+	} else {
+		go HookQueue.Add(r.RepoID)
+	}
+}
+
 // IsReleaseExist returns true if release with given tag name already exists.
 func IsReleaseExist(repoID int64, tagName string) (bool, error) {
 	if len(tagName) == 0 {
@@ -189,9 +209,18 @@ func CreateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return err
 	}
 
-	err = addReleaseAttachments(rel.ID, attachmentUUIDs)
+	if err = addReleaseAttachments(rel.ID, attachmentUUIDs); err != nil {
+		return err
+	}
 
-	return err
+	if !rel.IsDraft {
+		if err = rel.loadAttributes(x); err != nil {
+			log.Error(4, "loadAttributes [release: %d]: %v", rel.ID, err)
+		} else {
+			rel.sendWebhook(rel.Publisher, api.HookReleasePublished)
+		}
+	}
+	return nil
 }
 
 // GetRelease returns release by given ID.
@@ -351,7 +380,7 @@ func SortReleases(rels []*Release) {
 }
 
 // UpdateRelease updates information of a release.
-func UpdateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
+func UpdateRelease(doer *User, gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
 	if err = createTag(gitRepo, rel); err != nil {
 		return err
 	}
@@ -362,9 +391,14 @@ func UpdateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return err
 	}
 
-	err = addReleaseAttachments(rel.ID, attachmentUUIDs)
+	if err = addReleaseAttachments(rel.ID, attachmentUUIDs); err != nil {
+		return err
+	}
 
-	return err
+	if !rel.IsDraft {
+		rel.sendWebhook(doer, api.HookReleaseUpdated)
+	}
+	return nil
 }
 
 // DeleteReleaseByID deletes a release and corresponding Git tag by given ID.
@@ -397,6 +431,9 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 		if _, err = x.ID(rel.ID).Delete(new(Release)); err != nil {
 			return fmt.Errorf("Delete: %v", err)
 		}
+		if _, err = x.Delete(&Notification{ReleaseID: rel.ID}); err != nil {
+			return fmt.Errorf("delete notifications: %v", err)
+		}
 	} else {
 		rel.IsTag = true
 		rel.IsDraft = false
@@ -409,6 +446,8 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 		}
 	}
 
+	rel.Repo = repo
+	rel.sendWebhook(u, api.HookReleaseDeleted)
 	return nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/options"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/sync"
 	"code.gitea.io/gitea/modules/util"
 	api "code.gitea.io/sdk/gitea"
@@ -190,6 +191,8 @@ type Repository struct {
 	IsMirror bool `xorm:"INDEX"`
 	*Mirror  `xorm:"-"`
 
+	IsArchived bool `xorm:"INDEX"`
+
 	ExternalMetas map[string]string `xorm:"-"`
 	Units         []*RepoUnit       `xorm:"-"`
 
@@ -198,6 +201,7 @@ type Repository struct {
 	BaseRepo      *Repository        `xorm:"-"`
 	Size          int64              `xorm:"NOT NULL DEFAULT 0"`
 	IndexerStatus *RepoIndexerStatus `xorm:"-"`
+	Topics        []string           `xorm:"TEXT JSON"`
 
 	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix util.TimeStamp `xorm:"INDEX updated"`
@@ -284,6 +288,7 @@ func (repo *Repository) innerAPIFormat(mode AccessMode, isParent bool) *api.Repo
 		Fork:          repo.IsFork,
 		Parent:        parent,
 		Mirror:        repo.IsMirror,
+		Archived:      repo.IsArchived,
 		HTMLURL:       repo.HTMLURL(),
 		SSHURL:        cloneLink.SSH,
 		CloneURL:      cloneLink.HTTPS,
@@ -690,7 +695,7 @@ func (repo *Repository) AllowsPulls() bool {
 
 // CanEnableEditor returns true if repository meets the requirements of web editor.
 func (repo *Repository) CanEnableEditor() bool {
-	return !repo.IsMirror
+	return !repo.IsMirror && !repo.IsArchived
 }
 
 // GetWriters returns all users that have write access to the repository.
@@ -1307,7 +1312,11 @@ func createRepository(e *xorm.Session, doer, u *User, repo *Repository) (err err
 			units = append(units, RepoUnit{
 				RepoID: repo.ID,
 				Type:   tp,
-				Config: &IssuesConfig{EnableTimetracker: setting.Service.DefaultEnableTimetracking, AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime},
+				Config: &IssuesConfig{
+					EnableTimetracker:                setting.Service.DefaultEnableTimetracking,
+					AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime,
+					EnableDependencyCheck:            setting.Service.DefaultEnableDependencyCheck,
+				},
 			})
 		} else {
 			units = append(units, RepoUnit{
@@ -1322,6 +1331,10 @@ func createRepository(e *xorm.Session, doer, u *User, repo *Repository) (err err
 		return err
 	}
 
+	if err = copyDefaultWebhooksToRepo(e, repo.ID); err != nil {
+		return fmt.Errorf("copyDefaultWebhooksToRepo: %v", err)
+	}
+
 	u.NumRepos++
 	// Remember visibility preference.
 	u.LastRepoVisibility = repo.IsPrivate
@@ -1704,6 +1717,13 @@ func UpdateRepository(repo *Repository, visibilityChanged bool) (err error) {
 	return sess.Commit()
 }
 
+// SetArchiveRepoState sets if a repo is archived
+func (repo *Repository) SetArchiveRepoState(isArchived bool) (err error) {
+	repo.IsArchived = isArchived
+	_, err = x.ID(repo.ID).Cols("is_archived").Update(repo)
+	return err
+}
+
 // UpdateRepositoryUnits updates a repository's units
 func UpdateRepositoryUnits(repo *Repository, units []RepoUnit) (err error) {
 	sess := x.NewSession()
@@ -1770,8 +1790,10 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&Access{RepoID: repo.ID},
 		&Action{RepoID: repo.ID},
 		&Watch{RepoID: repoID},
+		&Notification{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Mirror{RepoID: repoID},
+		&PushMirror{RepoID: repoID},
 		&Milestone{RepoID: repoID},
 		&Release{RepoID: repoID},
 		&Collaboration{RepoID: repoID},
@@ -1782,6 +1804,10 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = removeTopicsFromRepo(sess, repoID); err != nil {
+		return fmt.Errorf("removeTopicsFromRepo: %v", err)
+	}
+
 	// Delete comments and attachments.
 	issueIDs := make([]int64, 0, 25)
 	attachmentPaths := make([]string, 0, len(issueIDs))
@@ -1800,6 +1826,15 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueUser{}); err != nil {
 			return err
 		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&Review{}); err != nil {
+			return err
+		}
+		if _, err = sess.In("issue_id", issueIDs).Delete(&IssueAssignees{}); err != nil {
+			return err
+		}
+		if err = deleteIssueDependencies(sess, issueIDs); err != nil {
+			return err
+		}
 
 		attachments := make([]*Attachment, 0, 5)
 		if err = sess.
@@ -1808,7 +1843,7 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 			return err
 		}
 		for j := range attachments {
-			attachmentPaths = append(attachmentPaths, attachments[j].LocalPath())
+			attachmentPaths = append(attachmentPaths, attachments[j].RelativePath())
 		}
 
 		if _, err = sess.In("issue_id", issueIDs).Delete(&Attachment{}); err != nil {
@@ -1842,9 +1877,12 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 
 	// Remove attachment files.
 	for i := range attachmentPaths {
-		removeAllWithNotice(sess, "Delete attachment", attachmentPaths[i])
+		removeStorageWithNotice(sess, storage.Attachments, "Delete attachment", attachmentPaths[i])
 	}
 
+	// Remove repository archives, they are not stored inside of the repository directory anymore.
+	removeStorageDirWithNotice(sess, storage.RepoArchives, "Delete repository archives", RepoArchiveRelativePath(repoID, ""))
+
 	// Remove LFS objects
 	var lfsObjects []*LFSMetaObject
 	if err = sess.Where("repository_id=?", repoID).Find(&lfsObjects); err != nil {
@@ -1861,11 +1899,7 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 			continue
 		}
 
-		oidPath := filepath.Join(v.Oid[0:2], v.Oid[2:4], v.Oid[4:len(v.Oid)])
-		err = os.Remove(filepath.Join(setting.LFS.ContentPath, oidPath))
-		if err != nil {
-			return err
-		}
+		removeStorageWithNotice(sess, storage.LFS, "Delete orphaned LFS file", v.RelativePath())
 	}
 
 	if _, err := sess.Delete(&LFSMetaObject{RepositoryID: repoID}); err != nil {
@@ -1914,6 +1948,25 @@ func GetRepositoryByOwnerAndName(ownerName, repoName string) (*Repository, error
 	return &repo, nil
 }
 
+// GetRepositoryIDsByFullNames returns the IDs of the repositories with the given
+// "owner/name" full names.
+func GetRepositoryIDsByFullNames(fullNames []string) ([]int64, error) {
+	repoIDs := make([]int64, 0, len(fullNames))
+	for _, fullName := range fullNames {
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 {
+			return nil, ErrRepoNotExist{0, 0, "", fullName}
+		}
+
+		repo, err := GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		repoIDs = append(repoIDs, repo.ID)
+	}
+	return repoIDs, nil
+}
+
 // GetRepositoryByName returns the repository by given name under user if exists.
 func GetRepositoryByName(ownerID int64, name string) (*Repository, error) {
 	repo := &Repository{
@@ -2003,15 +2056,26 @@ func GetPrivateRepositoryCount(u *User) (int64, error) {
 	return getPrivateRepositoryCount(x, u)
 }
 
+// RepoArchiveRelativePath returns the relative path of an archive of the given
+// repository inside the repository archives storage.
+func RepoArchiveRelativePath(repoID int64, name string) string {
+	return path.Join(com.ToStr(repoID), name)
+}
+
 // DeleteRepositoryArchives deletes all repositories' archives.
 func DeleteRepositoryArchives() error {
-	return x.
+	if err := x.
 		Where("id > 0").
 		Iterate(new(Repository),
 			func(idx int, bean interface{}) error {
 				repo := bean.(*Repository)
+				// Archives used to be stored inside of the repository directory.
 				return os.RemoveAll(filepath.Join(repo.RepoPath(), "archives"))
-			})
+			}); err != nil {
+		return err
+	}
+
+	return deleteRepositoryArchivesOlderThan(time.Now())
 }
 
 // DeleteOldRepositoryArchives deletes old repository archives.
@@ -2023,47 +2087,32 @@ func DeleteOldRepositoryArchives() {
 
 	log.Trace("Doing: ArchiveCleanup")
 
-	if err := x.Where("id > 0").Iterate(new(Repository), deleteOldRepositoryArchives); err != nil {
+	if err := deleteRepositoryArchivesOlderThan(time.Now().Add(-setting.Cron.ArchiveCleanup.OlderThan)); err != nil {
 		log.Error(4, "ArchiveClean: %v", err)
 	}
 }
 
-func deleteOldRepositoryArchives(idx int, bean interface{}) error {
-	repo := bean.(*Repository)
-	basePath := filepath.Join(repo.RepoPath(), "archives")
-
-	for _, ty := range []string{"zip", "targz"} {
-		path := filepath.Join(basePath, ty)
-		file, err := os.Open(path)
+func deleteRepositoryArchivesOlderThan(olderThan time.Time) error {
+	var toDelete []string
+	if err := storage.RepoArchives.IterateObjects("", func(path string, obj storage.Object) error {
+		info, err := obj.Stat()
 		if err != nil {
-			if !os.IsNotExist(err) {
-				log.Warn("Unable to open directory %s: %v", path, err)
-				return err
-			}
-
-			// If the directory doesn't exist, that's okay.
-			continue
-		}
-
-		files, err := file.Readdir(0)
-		file.Close()
-		if err != nil {
-			log.Warn("Unable to read directory %s: %v", path, err)
 			return err
 		}
-
-		minimumOldestTime := time.Now().Add(-setting.Cron.ArchiveCleanup.OlderThan)
-		for _, info := range files {
-			if info.ModTime().Before(minimumOldestTime) && !info.IsDir() {
-				toDelete := filepath.Join(path, info.Name())
-				// This is a best-effort purge, so we do not check error codes to confirm removal.
-				if err = os.Remove(toDelete); err != nil {
-					log.Trace("Unable to delete %s, but proceeding: %v", toDelete, err)
-				}
-			}
+		if info.ModTime().Before(olderThan) {
+			toDelete = append(toDelete, path)
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
+	for _, path := range toDelete {
+		// This is a best-effort purge, so we do not check error codes to confirm removal.
+		if err := storage.RepoArchives.Delete(path); err != nil {
+			log.Trace("Unable to delete %s, but proceeding: %v", path, err)
+		}
+	}
 	return nil
 }
 
@@ -2429,7 +2478,21 @@ func ForkRepository(doer, u *User, oldRepo *Repository, name, desc string) (_ *R
 		}
 	}
 
-	return repo, sess2.Commit()
+	if err = sess2.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = PrepareWebhooks(oldRepo, HookEventFork, &api.ForkPayload{
+		Forkee: repo.APIFormat(AccessModeNone),
+		Repo:   oldRepo.APIFormat(AccessModeNone),
+		Sender: doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [repo_id: %d]: %v", oldRepo.ID, err)
+	} else {
+		go HookQueue.Add(oldRepo.ID)
+	}
+
+	return repo, nil
 }
 
 // GetForks returns all the forks of the repository
@@ -72,6 +72,31 @@ func (repo *Repository) CheckoutNewBranch(oldBranch, newBranch string) error {
 	return checkoutNewBranch(repo.RepoPath(), repo.LocalCopyPath(), oldBranch, newBranch)
 }
 
+// checkRepoFileSHA makes sure the file of the branch still has the given blob SHA,
+// so that changes made since the file was read are not overwritten.
+func (repo *Repository) checkRepoFileSHA(branch, treePath, sha string) error {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %v", err)
+	}
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		return fmt.Errorf("GetBranchCommit [branch: %s]: %v", branch, err)
+	}
+
+	var currentSHA string
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err == nil {
+		currentSHA = entry.ID.String()
+	} else if !git.IsErrNotExist(err) {
+		return fmt.Errorf("GetTreeEntryByPath [path: %s]: %v", treePath, err)
+	}
+	if currentSHA != sha {
+		return ErrSHADoesNotMatch{treePath, sha, currentSHA}
+	}
+	return nil
+}
+
 // UpdateRepoFileOptions holds the repository file update options
 type UpdateRepoFileOptions struct {
 	LastCommitID string
@@ -82,10 +107,16 @@ type UpdateRepoFileOptions struct {
 	Message      string
 	Content      string
 	IsNewFile    bool
+	// SHA is the blob SHA the file is expected to have on OldBranch, it is not checked if empty
+	SHA string
 }
 
 // UpdateRepoFile adds or updates a file in repository.
 func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (err error) {
+	if repo.IsArchived {
+		return ErrRepoArchived{repo.ID, repo.Name}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
@@ -95,6 +126,12 @@ func (repo *Repository) UpdateRepoFile(doer *User, opts UpdateRepoFileOptions) (
 		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
 	}
 
+	if len(opts.SHA) > 0 {
+		if err = repo.checkRepoFileSHA(opts.OldBranch, opts.OldTreeName, opts.SHA); err != nil {
+			return err
+		}
+	}
+
 	if opts.OldBranch != opts.NewBranch {
 		if err := repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
 			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
@@ -249,10 +286,16 @@ type DeleteRepoFileOptions struct {
 	NewBranch    string
 	TreePath     string
 	Message      string
+	// SHA is the blob SHA the file is expected to have on OldBranch, it is not checked if empty
+	SHA string
 }
 
 // DeleteRepoFile deletes a repository file
 func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (err error) {
+	if repo.IsArchived {
+		return ErrRepoArchived{repo.ID, repo.Name}
+	}
+
 	repoWorkingPool.CheckIn(com.ToStr(repo.ID))
 	defer repoWorkingPool.CheckOut(com.ToStr(repo.ID))
 
@@ -262,6 +305,12 @@ func (repo *Repository) DeleteRepoFile(doer *User, opts DeleteRepoFileOptions) (
 		return fmt.Errorf("UpdateLocalCopyBranch [branch: %s]: %v", opts.OldBranch, err)
 	}
 
+	if len(opts.SHA) > 0 {
+		if err = repo.checkRepoFileSHA(opts.OldBranch, opts.TreePath, opts.SHA); err != nil {
+			return err
+		}
+	}
+
 	if opts.OldBranch != opts.NewBranch {
 		if err := repo.CheckoutNewBranch(opts.OldBranch, opts.NewBranch); err != nil {
 			return fmt.Errorf("CheckoutNewBranch [old_branch: %s, new_branch: %s]: %v", opts.OldBranch, opts.NewBranch, err)
@@ -478,6 +527,10 @@ func (repo *Repository) UploadRepoFiles(doer *User, opts UploadRepoFileOptions)
 		return nil
 	}
 
+	if repo.IsArchived {
+		return ErrRepoArchived{repo.ID, repo.Name}
+	}
+
 	uploads, err := GetUploadsByUUIDs(opts.Files)
 	if err != nil {
 		return fmt.Errorf("GetUploadsByUUIDs [uuids: %v]: %v", opts.Files, err)
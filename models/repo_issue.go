@@ -32,3 +32,14 @@ func (repo *Repository) AllowOnlyContributorsToTrackTime() bool {
 	}
 	return u.IssuesConfig().AllowOnlyContributorsToTrackTime
 }
+
+// IsDependencyCheckEnabled returns whether issues can't be closed and pull requests can't be merged
+// while they have open dependencies. It returns the default value from config if an error occurs.
+func (repo *Repository) IsDependencyCheckEnabled() bool {
+	var u *RepoUnit
+	var err error
+	if u, err = repo.GetUnit(UnitTypeIssues); err != nil {
+		return setting.Service.DefaultEnableDependencyCheck
+	}
+	return u.IssuesConfig().EnableDependencyCheck
+}
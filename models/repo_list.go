@@ -111,6 +111,7 @@ func (repos MirrorRepositoryList) LoadAttributes() error {
 // SearchRepoOptions holds the search options
 type SearchRepoOptions struct {
 	Keyword   string
+	TopicOnly bool // Match Keyword exactly against topics only instead of repository names and topics
 	OwnerID   int64
 	OrderBy   SearchOrderBy
 	Private   bool // Include private repositories in results
@@ -131,6 +132,12 @@ type SearchRepoOptions struct {
 	// True -> include just mirrors
 	// False -> include just non-mirrors
 	Mirror util.OptionalBool
+	// None -> include archived AND non-archived
+	// True -> include just archived
+	// False -> include just non-archived
+	Archived util.OptionalBool
+	// Only include these repositories if not empty
+	RepoIDs []int64
 }
 
 //SearchOrderBy is used to sort the result
@@ -197,8 +204,20 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (RepositoryList, int64, err
 		}
 	}
 
+	if len(opts.RepoIDs) > 0 {
+		cond = cond.And(builder.In("repository.id", opts.RepoIDs))
+	}
+
 	if opts.Keyword != "" {
-		cond = cond.And(builder.Like{"lower_name", strings.ToLower(opts.Keyword)})
+		keyword := strings.ToLower(opts.Keyword)
+		if opts.TopicOnly {
+			cond = cond.And(builder.Expr("repository.id IN (SELECT repo_topic.repo_id FROM repo_topic INNER JOIN topic ON topic.id = repo_topic.topic_id WHERE topic.name = ?)", keyword))
+		} else {
+			cond = cond.And(builder.Or(
+				builder.Like{"lower_name", keyword},
+				builder.Expr("repository.id IN (SELECT repo_topic.repo_id FROM repo_topic INNER JOIN topic ON topic.id = repo_topic.topic_id WHERE topic.name LIKE ?)", "%"+keyword+"%"),
+			))
+		}
 	}
 
 	if opts.Fork != util.OptionalBoolNone {
@@ -209,6 +228,10 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (RepositoryList, int64, err
 		cond = cond.And(builder.Eq{"is_mirror": opts.Mirror == util.OptionalBoolTrue})
 	}
 
+	if opts.Archived != util.OptionalBoolNone {
+		cond = cond.And(builder.Eq{"is_archived": opts.Archived == util.OptionalBoolTrue})
+	}
+
 	if len(opts.OrderBy) == 0 {
 		opts.OrderBy = SearchOrderByAlphabetically
 	}
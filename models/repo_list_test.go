@@ -109,6 +109,9 @@ func TestSearchRepositoryByName(t *testing.T) {
 		{name: "PublicAndPrivateRepositoriesOfUser",
 			opts:  &SearchRepoOptions{Page: 1, PageSize: 10, OwnerID: 15, Private: true, Collaborate: util.OptionalBoolFalse},
 			count: 4},
+		{name: "PublicAndPrivateRepositoriesOfUserRestrictedToIDs",
+			opts:  &SearchRepoOptions{Page: 1, PageSize: 10, OwnerID: 15, Private: true, Collaborate: util.OptionalBoolFalse, RepoIDs: []int64{17, 19, 1}},
+			count: 2},
 		{name: "PublicAndPrivateRepositoriesOfUser2",
 			opts:  &SearchRepoOptions{Page: 1, PageSize: 10, OwnerID: 18, Private: true, Collaborate: util.OptionalBoolFalse},
 			count: 0},
@@ -239,6 +239,8 @@ func MirrorUpdate() {
 		}); err != nil {
 		log.Error(4, "MirrorUpdate: %v", err)
 	}
+
+	pushMirrorUpdate()
 }
 
 // SyncMirrors checks and syncs mirrors.
@@ -281,7 +283,8 @@ func SyncMirrors() {
 	}
 }
 
-// InitSyncMirrors initializes a go routine to sync the mirrors
+// InitSyncMirrors initializes go routines to sync the mirrors and push mirrors
 func InitSyncMirrors() {
 	go SyncMirrors()
+	go SyncPushMirrors()
 }
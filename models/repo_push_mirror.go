@@ -0,0 +1,298 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/generate"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/Unknwon/com"
+	"github.com/go-xorm/xorm"
+	"gopkg.in/ini.v1"
+)
+
+// PushMirrorQueue holds an UniqueQueue object of the push mirrors to sync
+var PushMirrorQueue = sync.NewUniqueQueue(setting.Repository.MirrorQueueLength)
+
+// PushMirror represents a remote repository all refs of a repository are pushed to.
+type PushMirror struct {
+	ID         int64       `xorm:"pk autoincr"`
+	RepoID     int64       `xorm:"INDEX"`
+	Repo       *Repository `xorm:"-"`
+	RemoteName string
+
+	// Interval of zero means the mirror is only pushed after pushes to the repository.
+	Interval       time.Duration
+	CreatedUnix    util.TimeStamp `xorm:"INDEX created"`
+	LastUpdateUnix util.TimeStamp `xorm:"INDEX"`
+	NextUpdateUnix util.TimeStamp `xorm:"INDEX"`
+	LastError      string         `xorm:"TEXT"`
+
+	address string `xorm:"-"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (m *PushMirror) AfterLoad(session *xorm.Session) {
+	if m == nil {
+		return
+	}
+
+	var err error
+	m.Repo, err = getRepositoryByID(session, m.RepoID)
+	if err != nil {
+		log.Error(3, "getRepositoryByID[%d]: %v", m.ID, err)
+	}
+}
+
+// ScheduleNextUpdate calculates and sets next update time.
+func (m *PushMirror) ScheduleNextUpdate() {
+	if m.Interval > 0 {
+		m.NextUpdateUnix = util.TimeStampNow().AddDuration(m.Interval)
+	} else {
+		m.NextUpdateUnix = 0
+	}
+}
+
+func (m *PushMirror) readAddress() {
+	if len(m.address) > 0 || m.Repo == nil {
+		return
+	}
+	cfg, err := ini.Load(m.Repo.GitConfigPath())
+	if err != nil {
+		log.Error(4, "Load: %v", err)
+		return
+	}
+	m.address = cfg.Section(fmt.Sprintf("remote \"%s\"", m.RemoteName)).Key("url").Value()
+}
+
+// Address returns push mirror address from Git repository config without credentials.
+func (m *PushMirror) Address() string {
+	m.readAddress()
+	return util.SanitizeURLCredentials(m.address, false)
+}
+
+// FullAddress returns push mirror address from Git repository config.
+func (m *PushMirror) FullAddress() string {
+	m.readAddress()
+	return m.address
+}
+
+// APIFormat converts a PushMirror to api.PushMirror
+func (m *PushMirror) APIFormat() *api.PushMirror {
+	apiMirror := &api.PushMirror{
+		ID:            m.ID,
+		RemoteName:    m.RemoteName,
+		RemoteAddress: m.Address(),
+		Interval:      m.Interval.String(),
+		Created:       m.CreatedUnix.AsTime(),
+		LastError:     m.LastError,
+	}
+	if m.LastUpdateUnix > 0 {
+		apiMirror.LastUpdate = m.LastUpdateUnix.AsTimePtr()
+	}
+	return apiMirror
+}
+
+// ParsePushMirrorAddr checks if given remote address is a valid push mirror
+// address, and returns composed URL with needed username and password.
+func ParsePushMirrorAddr(addr, username, password string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if !strings.HasPrefix(addr, "http://") &&
+		!strings.HasPrefix(addr, "https://") &&
+		!strings.HasPrefix(addr, "ssh://") {
+		return "", ErrInvalidCloneAddr{IsURLError: true}
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil || len(u.Host) == 0 {
+		return "", ErrInvalidCloneAddr{IsURLError: true}
+	}
+	if len(username)+len(password) > 0 {
+		u.User = url.UserPassword(username, password)
+	}
+	return u.String(), nil
+}
+
+// CreatePushMirror adds a new push mirror with given address to the repository.
+// The address, which may contain credentials, is kept in the Git repository
+// config only.
+func CreatePushMirror(repo *Repository, addr string, interval time.Duration) (*PushMirror, error) {
+	suffix, err := generate.GetRandomString(10)
+	if err != nil {
+		return nil, fmt.Errorf("GetRandomString: %v", err)
+	}
+
+	m := &PushMirror{
+		RepoID:     repo.ID,
+		Repo:       repo,
+		RemoteName: "remote_mirror_" + suffix,
+		Interval:   interval,
+	}
+	m.ScheduleNextUpdate()
+
+	repoPath := repo.RepoPath()
+	if _, stderr, err := process.GetManager().ExecDir(-1, repoPath,
+		fmt.Sprintf("CreatePushMirror (git remote add): %s", repoPath),
+		"git", "remote", "add", "--mirror=push", m.RemoteName, addr); err != nil {
+		return nil, util.URLSanitizedError(fmt.Errorf("git remote add: %v - %s", err, stderr), addr)
+	}
+
+	if _, err = x.Insert(m); err != nil {
+		if err := removePushMirrorRemote(repoPath, m.RemoteName); err != nil {
+			log.Error(4, "removePushMirrorRemote: %v", err)
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func removePushMirrorRemote(repoPath, remoteName string) error {
+	if _, stderr, err := process.GetManager().ExecDir(-1, repoPath,
+		fmt.Sprintf("removePushMirrorRemote (git remote remove): %s", repoPath),
+		"git", "remote", "remove", remoteName); err != nil {
+		return fmt.Errorf("git remote remove: %v - %s", err, stderr)
+	}
+	return nil
+}
+
+func getPushMirrorByID(e Engine, id int64) (*PushMirror, error) {
+	m := new(PushMirror)
+	has, err := e.ID(id).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPushMirrorNotExist{id}
+	}
+	return m, nil
+}
+
+// GetPushMirrorByID returns the push mirror with given ID.
+func GetPushMirrorByID(id int64) (*PushMirror, error) {
+	return getPushMirrorByID(x, id)
+}
+
+// GetPushMirrorsByRepoID returns all push mirrors of a repository.
+func GetPushMirrorsByRepoID(repoID int64) ([]*PushMirror, error) {
+	mirrors := make([]*PushMirror, 0, 5)
+	return mirrors, x.
+		Where("repo_id = ?", repoID).
+		Asc("id").
+		Find(&mirrors)
+}
+
+// UpdatePushMirror updates the push mirror
+func UpdatePushMirror(m *PushMirror) error {
+	_, err := x.ID(m.ID).AllCols().Update(m)
+	return err
+}
+
+// DeletePushMirror removes the push mirror and its remote from the repository.
+func DeletePushMirror(m *PushMirror) error {
+	if _, err := x.ID(m.ID).Delete(new(PushMirror)); err != nil {
+		return err
+	}
+	PushMirrorQueue.Remove(m.ID)
+
+	if m.Repo == nil {
+		return nil
+	}
+	return removePushMirrorRemote(m.Repo.RepoPath(), m.RemoteName)
+}
+
+// runSync pushes all refs of the repository to the remote, and returns
+// the sanitized error output if the push failed.
+func (m *PushMirror) runSync() error {
+	repoPath := m.Repo.RepoPath()
+	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
+
+	if _, stderr, err := process.GetManager().ExecDir(
+		timeout, repoPath, fmt.Sprintf("PushMirror.runSync: %s", repoPath),
+		"git", "push", "--mirror", m.RemoteName); err != nil {
+		// sanitize the output, since it may contain the remote address, which may
+		// contain a password
+		return fmt.Errorf("%v - %s", err, util.SanitizeMessage(stderr, m.FullAddress()))
+	}
+	return nil
+}
+
+// SyncPushMirror pushes the repository to the push mirror with given ID and
+// records the result.
+func SyncPushMirror(id int64) error {
+	m, err := GetPushMirrorByID(id)
+	if err != nil {
+		return err
+	} else if m.Repo == nil {
+		return fmt.Errorf("disconnected push mirror found: %d", m.ID)
+	}
+
+	m.LastError = ""
+	if err := m.runSync(); err != nil {
+		m.LastError = err.Error()
+		desc := fmt.Sprintf("Failed to push mirror repository '%s' to '%s': %s", m.Repo.RepoPath(), m.Address(), m.LastError)
+		log.Error(4, desc)
+		if err = CreateRepositoryNotice(desc); err != nil {
+			log.Error(4, "CreateRepositoryNotice: %v", err)
+		}
+	}
+
+	m.LastUpdateUnix = util.TimeStampNow()
+	m.ScheduleNextUpdate()
+	_, err = x.ID(m.ID).Cols("last_update_unix", "next_update_unix", "last_error").Update(m)
+	return err
+}
+
+// AddPushMirrorsToQueue queues all push mirrors of a repository for sync.
+func AddPushMirrorsToQueue(repoID int64) {
+	mirrors, err := GetPushMirrorsByRepoID(repoID)
+	if err != nil {
+		log.Error(4, "GetPushMirrorsByRepoID [%d]: %v", repoID, err)
+		return
+	}
+	for _, m := range mirrors {
+		PushMirrorQueue.Add(m.ID)
+	}
+}
+
+// pushMirrorUpdate queues push mirrors whose scheduled update is due.
+func pushMirrorUpdate() {
+	if err := x.
+		Where("next_update_unix>0 AND next_update_unix<=?", time.Now().Unix()).
+		Iterate(new(PushMirror), func(idx int, bean interface{}) error {
+			m := bean.(*PushMirror)
+			if m.Repo == nil {
+				log.Error(4, "Disconnected push mirror found: %d", m.ID)
+				return nil
+			}
+
+			PushMirrorQueue.Add(m.ID)
+			return nil
+		}); err != nil {
+		log.Error(4, "pushMirrorUpdate: %v", err)
+	}
+}
+
+// SyncPushMirrors checks and syncs push mirrors.
+func SyncPushMirrors() {
+	// Start listening on new sync requests.
+	for id := range PushMirrorQueue.Queue() {
+		log.Trace("SyncPushMirrors [push_mirror_id: %v]", id)
+		PushMirrorQueue.Remove(id)
+
+		if err := SyncPushMirror(com.StrTo(id).MustInt64()); err != nil {
+			log.Error(4, "SyncPushMirror [%s]: %v", id, err)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/Unknwon/com"
 	"github.com/stretchr/testify/assert"
@@ -176,3 +177,33 @@ func TestTransferOwnership(t *testing.T) {
 
 	CheckConsistencyFor(t, &Repository{}, &User{}, &Team{})
 }
+
+func TestSetArchiveRepoState(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.True(t, repo.CanEnableEditor())
+	assert.True(t, repo.CanCreateBranch())
+
+	assert.NoError(t, repo.SetArchiveRepoState(true))
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1, IsArchived: true}).(*Repository)
+	assert.False(t, repo.CanEnableEditor())
+	assert.False(t, repo.CanCreateBranch())
+
+	err := repo.UpdateRepoFile(&User{ID: 2}, UpdateRepoFileOptions{})
+	assert.True(t, IsErrRepoArchived(err))
+
+	repos, count, err := SearchRepositoryByName(&SearchRepoOptions{
+		Archived: util.OptionalBoolTrue,
+		Page:     1,
+		PageSize: 10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	assert.NoError(t, repo.SetArchiveRepoState(false))
+	AssertExistsAndLoadBean(t, &Repository{ID: 1}, Cond("is_archived = ?", false))
+}
@@ -6,11 +6,40 @@ package models
 
 import "fmt"
 
+// WatchEvent is a kind of repository event watchers can be notified of
+type WatchEvent int
+
+const (
+	// WatchEventIssue notifies of new and updated issues
+	WatchEventIssue WatchEvent = 1 << iota
+	// WatchEventPullRequest notifies of new and updated pull requests
+	WatchEventPullRequest
+	// WatchEventComment notifies of comments on issues and pull requests
+	WatchEventComment
+	// WatchEventRelease notifies of published releases
+	WatchEventRelease
+	// WatchEventPush notifies of pushes to the default branch
+	WatchEventPush
+)
+
+const (
+	// WatchEventsDefault are the events a new watcher is notified of
+	WatchEventsDefault = WatchEventIssue | WatchEventPullRequest | WatchEventComment | WatchEventRelease
+	// WatchEventsAll are all the events a watcher can be notified of
+	WatchEventsAll = WatchEventsDefault | WatchEventPush
+)
+
 // Watch is connection request for receiving repository notification.
 type Watch struct {
-	ID     int64 `xorm:"pk autoincr"`
-	UserID int64 `xorm:"UNIQUE(watch)"`
-	RepoID int64 `xorm:"UNIQUE(watch)"`
+	ID     int64      `xorm:"pk autoincr"`
+	UserID int64      `xorm:"UNIQUE(watch)"`
+	RepoID int64      `xorm:"UNIQUE(watch)"`
+	Events WatchEvent `xorm:"NOT NULL DEFAULT 15"`
+}
+
+// HasEvent returns true if the watcher wants to be notified of the given kind of event
+func (w *Watch) HasEvent(event WatchEvent) bool {
+	return w.Events&event == event
 }
 
 func isWatching(e Engine, userID, repoID int64) bool {
@@ -28,7 +57,7 @@ func watchRepo(e Engine, userID, repoID int64, watch bool) (err error) {
 		if isWatching(e, userID, repoID) {
 			return nil
 		}
-		if _, err = e.Insert(&Watch{RepoID: repoID, UserID: userID}); err != nil {
+		if _, err = e.Insert(&Watch{RepoID: repoID, UserID: userID, Events: WatchEventsDefault}); err != nil {
 			return err
 		}
 		_, err = e.Exec("UPDATE `repository` SET num_watches = num_watches + 1 WHERE id = ?", repoID)
@@ -36,7 +65,7 @@ func watchRepo(e Engine, userID, repoID int64, watch bool) (err error) {
 		if !isWatching(e, userID, repoID) {
 			return nil
 		}
-		if _, err = e.Delete(&Watch{0, userID, repoID}); err != nil {
+		if _, err = e.Delete(&Watch{UserID: userID, RepoID: repoID}); err != nil {
 			return err
 		}
 		_, err = e.Exec("UPDATE `repository` SET num_watches = num_watches - 1 WHERE id = ?", repoID)
@@ -49,6 +78,42 @@ func WatchRepo(userID, repoID int64, watch bool) (err error) {
 	return watchRepo(x, userID, repoID, watch)
 }
 
+// GetWatch returns the watch of a user on a repository
+func GetWatch(userID, repoID int64) (*Watch, error) {
+	watch := new(Watch)
+	has, err := x.
+		Where("user_id = ? AND repo_id = ?", userID, repoID).
+		Get(watch)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrWatchNotExist{UserID: userID, RepoID: repoID}
+	}
+	return watch, nil
+}
+
+// SetWatchEvents watches the repository if not watched yet and sets the
+// kinds of events the user wants to be notified of
+func SetWatchEvents(userID, repoID int64, events WatchEvent) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := watchRepo(sess, userID, repoID, true); err != nil {
+		return err
+	}
+	if _, err := sess.
+		Where("user_id = ? AND repo_id = ?", userID, repoID).
+		Cols("events").
+		Update(&Watch{Events: events & WatchEventsAll}); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
 func getWatchers(e Engine, repoID int64) ([]*Watch, error) {
 	watches := make([]*Watch, 0, 10)
 	return watches, e.Where("`watch`.repo_id=?", repoID).
@@ -98,3 +98,24 @@ func TestNotifyWatchers(t *testing.T) {
 		OpType:    action.OpType,
 	})
 }
+
+func TestSetWatchEvents(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	const repoID = 3
+	const userID = 2
+
+	_, err := GetWatch(userID, repoID)
+	assert.True(t, IsErrWatchNotExist(err))
+
+	assert.NoError(t, SetWatchEvents(userID, repoID, WatchEventRelease|WatchEventPush))
+	watch, err := GetWatch(userID, repoID)
+	assert.NoError(t, err)
+	assert.True(t, watch.HasEvent(WatchEventRelease))
+	assert.True(t, watch.HasEvent(WatchEventPush))
+	assert.False(t, watch.HasEvent(WatchEventIssue))
+	CheckConsistencyFor(t, &Repository{ID: repoID})
+
+	assert.NoError(t, SetWatchEvents(userID, repoID, 0))
+	AssertExistsAndLoadBean(t, &Watch{ID: watch.ID}, "events = 0")
+	CheckConsistencyFor(t, &Repository{ID: repoID})
+}
@@ -0,0 +1,586 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/builder"
+	"github.com/go-xorm/xorm"
+)
+
+// ReviewType defines the sort of feedback a review gives
+type ReviewType int
+
+// ReviewTypeUnknown unknown review type
+const ReviewTypeUnknown ReviewType = -1
+
+const (
+	// ReviewTypePending is a review which is not published yet
+	ReviewTypePending ReviewType = iota
+	// ReviewTypeApprove approves changes
+	ReviewTypeApprove
+	// ReviewTypeComment gives general feedback
+	ReviewTypeComment
+	// ReviewTypeReject gives feedback blocking merge
+	ReviewTypeReject
+)
+
+// Icon returns the corresponding icon for the review type
+func (rt ReviewType) Icon() string {
+	switch rt {
+	case ReviewTypeApprove:
+		return "eye"
+	case ReviewTypeReject:
+		return "x"
+	case ReviewTypeComment, ReviewTypeUnknown:
+		return "comment"
+	default:
+		return "comment"
+	}
+}
+
+// APIState returns the review state used by the API for the review type
+func (rt ReviewType) APIState() api.ReviewStateType {
+	switch rt {
+	case ReviewTypePending:
+		return api.ReviewStatePending
+	case ReviewTypeApprove:
+		return api.ReviewStateApproved
+	case ReviewTypeReject:
+		return api.ReviewStateRequestChanges
+	default:
+		return api.ReviewStateComment
+	}
+}
+
+// ReviewTypeFromAPIState converts an API review state into a review type
+func ReviewTypeFromAPIState(state api.ReviewStateType) ReviewType {
+	switch state {
+	case api.ReviewStatePending:
+		return ReviewTypePending
+	case api.ReviewStateApproved:
+		return ReviewTypeApprove
+	case api.ReviewStateRequestChanges:
+		return ReviewTypeReject
+	case api.ReviewStateComment:
+		return ReviewTypeComment
+	default:
+		return ReviewTypeUnknown
+	}
+}
+
+// Review represents collection of code comments giving feedback for a PR
+type Review struct {
+	ID         int64 `xorm:"pk autoincr"`
+	Type       ReviewType
+	Reviewer   *User  `xorm:"-"`
+	ReviewerID int64  `xorm:"INDEX"`
+	Issue      *Issue `xorm:"-"`
+	IssueID    int64  `xorm:"INDEX"`
+	Content    string `xorm:"TEXT"`
+	// CommitID is the head commit of the pull request the review was made against
+	CommitID string `xorm:"VARCHAR(40)"`
+
+	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix util.TimeStamp `xorm:"INDEX updated"`
+
+	// CodeComments are the line comments grouped by this review
+	CodeComments []*Comment `xorm:"-"`
+}
+
+func (r *Review) loadCodeComments(e Engine) (err error) {
+	if r.CodeComments != nil {
+		return nil
+	}
+	r.CodeComments, err = findComments(e, FindCommentsOptions{
+		ReviewID: r.ID,
+		Type:     CommentTypeCode,
+	})
+	return err
+}
+
+// LoadCodeComments loads the line comments of the review
+func (r *Review) LoadCodeComments() error {
+	return r.loadCodeComments(x)
+}
+
+func (r *Review) loadIssue(e Engine) (err error) {
+	if r.Issue != nil {
+		return nil
+	}
+	r.Issue, err = getIssueByID(e, r.IssueID)
+	return err
+}
+
+func (r *Review) loadReviewer(e Engine) (err error) {
+	if r.Reviewer != nil || r.ReviewerID == 0 {
+		return nil
+	}
+	r.Reviewer, err = getUserByID(e, r.ReviewerID)
+	if IsErrUserNotExist(err) {
+		r.ReviewerID = -1
+		r.Reviewer = NewGhostUser()
+		return nil
+	}
+	return err
+}
+
+func (r *Review) loadAttributes(e Engine) (err error) {
+	if err = r.loadReviewer(e); err != nil {
+		return fmt.Errorf("loadReviewer [%d]: %v", r.ReviewerID, err)
+	}
+	if err = r.loadIssue(e); err != nil {
+		return fmt.Errorf("loadIssue [%d]: %v", r.IssueID, err)
+	}
+	return nil
+}
+
+// LoadAttributes loads all attributes except CodeComments
+func (r *Review) LoadAttributes() error {
+	return r.loadAttributes(x)
+}
+
+// HTMLURL formats a URL-string to the review on the pull request page
+func (r *Review) HTMLURL() string {
+	if r.Issue == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s#pullrequestreview-%d", r.Issue.HTMLURL(), r.ID)
+}
+
+// APIFormat converts a Review to the api.PullReview format.
+// It assumes Reviewer and Issue have been loaded.
+func (r *Review) APIFormat() *api.PullReview {
+	return &api.PullReview{
+		ID:                r.ID,
+		Reviewer:          r.Reviewer.APIFormat(),
+		State:             r.Type.APIState(),
+		Body:              r.Content,
+		CommitID:          r.CommitID,
+		CodeCommentsCount: len(r.CodeComments),
+		Submitted:         r.UpdatedUnix.AsTime(),
+		HTMLURL:           r.HTMLURL(),
+	}
+}
+
+func getReviewByID(e Engine, id int64) (*Review, error) {
+	review := new(Review)
+	if has, err := e.ID(id).Get(review); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReviewNotExist{ID: id}
+	}
+	return review, nil
+}
+
+// GetReviewByID returns the review by the given ID
+func GetReviewByID(id int64) (*Review, error) {
+	return getReviewByID(x, id)
+}
+
+// FindReviewOptions represent possible filters to find reviews
+type FindReviewOptions struct {
+	Types      []ReviewType // include all types if empty
+	IssueID    int64
+	ReviewerID int64
+}
+
+func (opts *FindReviewOptions) toCond() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"issue_id": opts.IssueID})
+	}
+	if opts.ReviewerID > 0 {
+		cond = cond.And(builder.Eq{"reviewer_id": opts.ReviewerID})
+	}
+	if len(opts.Types) > 0 {
+		cond = cond.And(builder.In("type", opts.Types))
+	}
+	return cond
+}
+
+func findReviews(e Engine, opts FindReviewOptions) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	return reviews, e.Where(opts.toCond()).
+		Asc("created_unix").
+		Asc("id").
+		Find(&reviews)
+}
+
+// FindReviews returns reviews passing FindReviewOptions
+func FindReviews(opts FindReviewOptions) ([]*Review, error) {
+	return findReviews(x, opts)
+}
+
+// GetReviewsByIssueID returns all published reviews of an issue, oldest first
+func GetReviewsByIssueID(issueID int64) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	return reviews, x.Where("issue_id = ? AND type <> ?", issueID, ReviewTypePending).
+		Asc("created_unix").
+		Asc("id").
+		Find(&reviews)
+}
+
+func getReviewersByIssueID(e Engine, issueID int64) ([]*Review, error) {
+	reviews := make([]*Review, 0, 10)
+	if err := e.Where("issue_id = ? AND type IN (?, ?)", issueID, ReviewTypeApprove, ReviewTypeReject).
+		Desc("updated_unix").
+		Desc("id").
+		Find(&reviews); err != nil {
+		return nil, err
+	}
+
+	// Only the latest decisive review of every reviewer counts.
+	seen := make(map[int64]bool, len(reviews))
+	latest := make([]*Review, 0, len(reviews))
+	for _, review := range reviews {
+		if seen[review.ReviewerID] {
+			continue
+		}
+		seen[review.ReviewerID] = true
+		if err := review.loadReviewer(e); err != nil {
+			return nil, err
+		}
+		latest = append(latest, review)
+	}
+	return latest, nil
+}
+
+// GetReviewersByIssueID returns the latest approving or rejecting review
+// of every reviewer of the given pull request.
+func GetReviewersByIssueID(issueID int64) ([]*Review, error) {
+	return getReviewersByIssueID(x, issueID)
+}
+
+// CreateReviewOptions represent the options to create a review. Type, Issue and Reviewer are required.
+type CreateReviewOptions struct {
+	Content  string
+	Type     ReviewType
+	Issue    *Issue
+	Reviewer *User
+	CommitID string
+}
+
+func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
+	review := &Review{
+		Type:       opts.Type,
+		Issue:      opts.Issue,
+		IssueID:    opts.Issue.ID,
+		Reviewer:   opts.Reviewer,
+		ReviewerID: opts.Reviewer.ID,
+		Content:    opts.Content,
+		CommitID:   opts.CommitID,
+	}
+	if _, err := e.Insert(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// CreateReview creates a new review based on opts
+func CreateReview(opts CreateReviewOptions) (*Review, error) {
+	return createReview(x, opts)
+}
+
+func getCurrentReview(e Engine, reviewer *User, issue *Issue) (*Review, error) {
+	if reviewer == nil {
+		return nil, nil
+	}
+	reviews, err := findReviews(e, FindReviewOptions{
+		Types:      []ReviewType{ReviewTypePending},
+		IssueID:    issue.ID,
+		ReviewerID: reviewer.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, ErrReviewNotExist{}
+	}
+	reviews[0].Reviewer = reviewer
+	reviews[0].Issue = issue
+	return reviews[0], nil
+}
+
+// GetCurrentReview returns the pending review of reviewer for given issue
+func GetCurrentReview(reviewer *User, issue *Issue) (*Review, error) {
+	return getCurrentReview(x, reviewer, issue)
+}
+
+// UpdateReview updates a review
+func UpdateReview(r *Review) error {
+	if _, err := x.ID(r.ID).AllCols().Update(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateCodeCommentOptions defines options for creating a line comment on a pull request
+type CreateCodeCommentOptions struct {
+	Doer      *User
+	Repo      *Repository
+	Issue     *Issue
+	Content   string
+	TreePath  string
+	LineNum   int64
+	CommitSHA string
+	// ReviewID attaches the comment to an existing pending review.
+	// If it is zero, the pending review of Doer is used or a new one is started.
+	ReviewID int64
+}
+
+func createCodeComment(e *xorm.Session, opts *CreateCodeCommentOptions) (*Comment, error) {
+	var review *Review
+	var err error
+	if opts.ReviewID > 0 {
+		if review, err = getReviewByID(e, opts.ReviewID); err != nil {
+			return nil, err
+		}
+		if review.ReviewerID != opts.Doer.ID || review.IssueID != opts.Issue.ID || review.Type != ReviewTypePending {
+			return nil, ErrReviewNotExist{ID: opts.ReviewID}
+		}
+	} else {
+		review, err = getCurrentReview(e, opts.Doer, opts.Issue)
+		if err != nil && !IsErrReviewNotExist(err) {
+			return nil, err
+		}
+		if review == nil {
+			if review, err = createReview(e, CreateReviewOptions{
+				Type:     ReviewTypePending,
+				Issue:    opts.Issue,
+				Reviewer: opts.Doer,
+				CommitID: opts.CommitSHA,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return createComment(e, &CreateCommentOptions{
+		Type:      CommentTypeCode,
+		Doer:      opts.Doer,
+		Repo:      opts.Repo,
+		Issue:     opts.Issue,
+		Content:   opts.Content,
+		TreePath:  opts.TreePath,
+		LineNum:   opts.LineNum,
+		CommitSHA: opts.CommitSHA,
+		ReviewID:  review.ID,
+	})
+}
+
+// CreateCodeComment adds a line comment to the pending review of the doer,
+// starting a new pending review if there is none.
+func CreateCodeComment(opts *CreateCodeCommentOptions) (*Comment, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	comment, err := createCodeComment(sess, opts)
+	if err != nil {
+		return nil, err
+	}
+	return comment, sess.Commit()
+}
+
+// SubmitReview publishes the pending review of doer on the given pull request,
+// or creates a new review if doer has none pending. Submitting a review also
+// records it on the timeline of the pull request.
+func SubmitReview(doer *User, repo *Repository, issue *Issue, reviewType ReviewType, content, commitID string) (*Review, *Comment, error) {
+	if reviewType == ReviewTypePending || reviewType == ReviewTypeUnknown {
+		return nil, nil, fmt.Errorf("cannot submit a review of type %d", reviewType)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, nil, err
+	}
+
+	review, comment, err := submitReview(sess, doer, repo, issue, reviewType, content, commitID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	review.sendWebhook(doer, repo)
+	return review, comment, nil
+}
+
+func submitReview(e *xorm.Session, doer *User, repo *Repository, issue *Issue, reviewType ReviewType, content, commitID string) (*Review, *Comment, error) {
+	review, err := getCurrentReview(e, doer, issue)
+	if err != nil && !IsErrReviewNotExist(err) {
+		return nil, nil, err
+	}
+
+	if review == nil {
+		// An empty comment-only review carries no feedback.
+		if reviewType == ReviewTypeComment && len(content) == 0 {
+			return nil, nil, ErrReviewEmpty{}
+		}
+		if review, err = createReview(e, CreateReviewOptions{
+			Type:     reviewType,
+			Issue:    issue,
+			Reviewer: doer,
+			Content:  content,
+			CommitID: commitID,
+		}); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if err = review.loadCodeComments(e); err != nil {
+			return nil, nil, err
+		}
+		if reviewType == ReviewTypeComment && len(content) == 0 && len(review.CodeComments) == 0 {
+			return nil, nil, ErrReviewEmpty{}
+		}
+		review.Type = reviewType
+		review.Content = content
+		if len(commitID) > 0 {
+			review.CommitID = commitID
+		}
+		if _, err = e.ID(review.ID).Cols("type", "content", "commit_id").Update(review); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	comment, err := createComment(e, &CreateCommentOptions{
+		Type:      CommentTypeReview,
+		Doer:      doer,
+		Repo:      repo,
+		Issue:     issue,
+		Content:   content,
+		CommitSHA: review.CommitID,
+		ReviewID:  review.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	review.Reviewer = doer
+	review.Issue = issue
+	return review, comment, nil
+}
+
+// CreatePullReview adds the given line comments to the pending review of doer and
+// submits it in a single transaction. Reviews of type ReviewTypePending are kept
+// pending instead. Only Content, TreePath and LineNum of the comments are used.
+func CreatePullReview(doer *User, repo *Repository, issue *Issue, reviewType ReviewType, content, commitID string, comments []*CreateCodeCommentOptions) (*Review, error) {
+	if reviewType == ReviewTypeUnknown {
+		return nil, fmt.Errorf("cannot create a review of type %d", reviewType)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	for _, opts := range comments {
+		opts.Doer = doer
+		opts.Repo = repo
+		opts.Issue = issue
+		opts.CommitSHA = commitID
+		opts.ReviewID = 0
+		if _, err := createCodeComment(sess, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var review *Review
+	var err error
+	if reviewType == ReviewTypePending {
+		review, err = getCurrentReview(sess, doer, issue)
+		if IsErrReviewNotExist(err) {
+			review, err = createReview(sess, CreateReviewOptions{
+				Type:     ReviewTypePending,
+				Issue:    issue,
+				Reviewer: doer,
+				Content:  content,
+				CommitID: commitID,
+			})
+		} else if err == nil && content != "" {
+			// keep the body for when the pending review is submitted
+			review.Content = content
+			_, err = sess.ID(review.ID).Cols("content").Update(review)
+		}
+	} else {
+		review, _, err = submitReview(sess, doer, repo, issue, reviewType, content, commitID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, err
+	}
+
+	if reviewType != ReviewTypePending {
+		review.sendWebhook(doer, repo)
+	}
+	return review, nil
+}
+
+// sendWebhook triggers the pull_request_review webhooks for a submitted review.
+func (r *Review) sendWebhook(doer *User, repo *Repository) {
+	if err := r.Issue.loadPullRequest(x); err != nil {
+		log.Error(4, "loadPullRequest: %v", err)
+		return
+	}
+	r.Issue.PullRequest.Issue = r.Issue
+
+	if err := PrepareWebhooks(repo, HookEventPullRequestReview, &PullRequestReviewPayload{
+		Action:      HookReviewSubmitted,
+		Review:      r.APIFormat(),
+		PullRequest: r.Issue.PullRequest.APIFormat(),
+		Repository:  repo.APIFormat(AccessModeNone),
+		Sender:      doer.APIFormat(),
+	}); err != nil {
+		log.Error(4, "PrepareWebhooks [review: %d]: %v", r.ID, err)
+	} else {
+		go HookQueue.Add(repo.ID)
+	}
+}
+
+// LoadReview loads the review a review or code comment belongs to
+func (c *Comment) LoadReview() (err error) {
+	if c.ReviewID == 0 || c.Review != nil {
+		return nil
+	}
+	if c.Review, err = getReviewByID(x, c.ReviewID); err != nil {
+		if IsErrReviewNotExist(err) {
+			log.Warn("Comment %d cannot load review %d", c.ID, c.ReviewID)
+			return nil
+		}
+		return err
+	}
+	return c.Review.loadCodeComments(x)
+}
+
+// APIFormatCodeComment converts a line comment of a review to the api.PullReviewComment format
+func (c *Comment) APIFormatCodeComment() *api.PullReviewComment {
+	return &api.PullReviewComment{
+		ID:       c.ID,
+		Body:     c.Content,
+		Reviewer: c.Poster.APIFormat(),
+		ReviewID: c.ReviewID,
+		Path:     c.TreePath,
+		CommitID: c.CommitSHA,
+		LineNum:  c.Line,
+		HTMLURL:  c.HTMLURL(),
+		Created:  c.CreatedUnix.AsTime(),
+		Updated:  c.UpdatedUnix.AsTime(),
+	}
+}
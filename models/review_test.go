@@ -0,0 +1,186 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReviewByID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	review, err := GetReviewByID(2)
+	assert.NoError(t, err)
+	assert.Equal(t, ReviewTypeApprove, review.Type)
+	assert.Equal(t, int64(4), review.ReviewerID)
+
+	_, err = GetReviewByID(NonexistentID)
+	assert.True(t, IsErrReviewNotExist(err))
+}
+
+func TestFindReviews(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	reviews, err := FindReviews(FindReviewOptions{
+		Types:   []ReviewType{ReviewTypeApprove},
+		IssueID: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 2)
+
+	reviews, err = FindReviews(FindReviewOptions{
+		IssueID:    2,
+		ReviewerID: 4,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 2)
+
+	reviews, err = FindReviews(FindReviewOptions{
+		Types:   []ReviewType{ReviewTypeApprove, ReviewTypeReject},
+		IssueID: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 3)
+}
+
+func TestGetReviewsByIssueID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	reviews, err := GetReviewsByIssueID(2)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 4)
+	for _, review := range reviews {
+		assert.NotEqual(t, ReviewTypePending, review.Type)
+	}
+}
+
+func TestGetReviewersByIssueID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	reviewers, err := GetReviewersByIssueID(2)
+	assert.NoError(t, err)
+	if assert.Len(t, reviewers, 2) {
+		assert.Equal(t, int64(4), reviewers[1].ReviewerID)
+		assert.Equal(t, ReviewTypeReject, reviewers[1].Type)
+		assert.Equal(t, int64(5), reviewers[0].ReviewerID)
+		assert.Equal(t, ReviewTypeApprove, reviewers[0].Type)
+		assert.NotNil(t, reviewers[0].Reviewer)
+	}
+}
+
+func TestGetCurrentReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	review, err := GetCurrentReview(user2, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), review.ID)
+	assert.Equal(t, ReviewTypePending, review.Type)
+
+	_, err = GetCurrentReview(user4, issue)
+	assert.True(t, IsErrReviewNotExist(err))
+}
+
+func TestCreateCodeComment(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: issue.RepoID}).(*Repository)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	// the pending review of user2 collects the comment
+	comment, err := CreateCodeComment(&CreateCodeCommentOptions{
+		Doer:     user2,
+		Repo:     repo,
+		Issue:    issue,
+		Content:  "typo",
+		TreePath: "README.md",
+		LineNum:  4,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), comment.ReviewID)
+	AssertExistsAndLoadBean(t, &Comment{ID: comment.ID, Type: CommentTypeCode, TreePath: "README.md", Line: 4})
+
+	// user3 has no pending review, so one is started
+	comment, err = CreateCodeComment(&CreateCodeCommentOptions{
+		Doer:     user3,
+		Repo:     repo,
+		Issue:    issue,
+		Content:  "why?",
+		TreePath: "README.md",
+		LineNum:  1,
+	})
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Review{ID: comment.ReviewID, ReviewerID: user3.ID, Type: ReviewTypePending})
+}
+
+func TestSubmitReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: issue.RepoID}).(*Repository)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	// publishes the pending review
+	review, comment, err := SubmitReview(user2, repo, issue, ReviewTypeApprove, "ship it", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), review.ID)
+	AssertExistsAndLoadBean(t, &Review{ID: 1, Type: ReviewTypeApprove, Content: "ship it"})
+	AssertExistsAndLoadBean(t, &Comment{ID: comment.ID, Type: CommentTypeReview, ReviewID: 1})
+
+	// an empty comment-only review is refused
+	_, _, err = SubmitReview(user3, repo, issue, ReviewTypeComment, "", "")
+	assert.True(t, IsErrReviewEmpty(err))
+
+	_, _, err = SubmitReview(user3, repo, issue, ReviewTypePending, "", "")
+	assert.Error(t, err)
+
+	CheckConsistencyFor(t, &Issue{})
+}
+
+func TestCreatePullReview(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: issue.RepoID}).(*Repository)
+	user3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	// line comments alone make a comment-only review
+	review, err := CreatePullReview(user3, repo, issue, ReviewTypeComment, "", "", []*CreateCodeCommentOptions{
+		{Content: "typo", TreePath: "README.md", LineNum: 2},
+		{Content: "why?", TreePath: "README.md", LineNum: 3},
+	})
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Review{ID: review.ID, ReviewerID: user3.ID, Type: ReviewTypeComment})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeCode, ReviewID: review.ID, Line: 2})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeCode, ReviewID: review.ID, Line: 3})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeReview, ReviewID: review.ID})
+
+	_, err = CreatePullReview(user3, repo, issue, ReviewTypeComment, "", "", nil)
+	assert.True(t, IsErrReviewEmpty(err))
+
+	review, err = CreatePullReview(user3, repo, issue, ReviewTypePending, "", "", []*CreateCodeCommentOptions{
+		{Content: "later", TreePath: "README.md", LineNum: 1},
+	})
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Review{ID: review.ID, ReviewerID: user3.ID, Type: ReviewTypePending})
+	AssertNotExistsBean(t, &Comment{Type: CommentTypeReview, ReviewID: review.ID})
+
+	// the body of another pending review goes into the one already pending
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	review, err = CreatePullReview(user2, repo, issue, ReviewTypePending, "new body", "", nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, review.ID)
+	AssertExistsAndLoadBean(t, &Review{ID: 1, Type: ReviewTypePending, Content: "new body"})
+
+	CheckConsistencyFor(t, &Issue{})
+}
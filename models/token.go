@@ -5,14 +5,81 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"github.com/Unknwon/com"
 	gouuid "github.com/satori/go.uuid"
 
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/util"
+
+	api "code.gitea.io/sdk/gitea"
 )
 
+// AccessTokenScope represents a permission granted to an access token.
+type AccessTokenScope string
+
+const (
+	// AccessTokenScopeAll grants the full rights of the token owner
+	AccessTokenScopeAll AccessTokenScope = "all"
+	// AccessTokenScopeRepo grants read and write access to repositories,
+	// including their issues and pull requests
+	AccessTokenScopeRepo AccessTokenScope = "repo"
+	// AccessTokenScopeRepoRead grants read access to repositories
+	AccessTokenScopeRepoRead AccessTokenScope = "repo:read"
+	// AccessTokenScopeIssue grants read and write access to issues and pull requests
+	AccessTokenScopeIssue AccessTokenScope = "issue"
+	// AccessTokenScopeAdmin grants the site administration rights of the owner
+	AccessTokenScopeAdmin AccessTokenScope = "admin"
+	// AccessTokenScopeUser grants access to the profile, keys and settings of the owner
+	AccessTokenScopeUser AccessTokenScope = "user"
+	// AccessTokenScopeOrg grants access to organizations and teams
+	AccessTokenScopeOrg AccessTokenScope = "org"
+	// AccessTokenScopeNotification grants access to the notifications of the owner
+	AccessTokenScopeNotification AccessTokenScope = "notification"
+	// AccessTokenScopePublicOnly restricts the token to public repositories
+	AccessTokenScopePublicOnly AccessTokenScope = "public-only"
+)
+
+// AccessTokenScopes contains all valid access token scopes
+var AccessTokenScopes = []AccessTokenScope{
+	AccessTokenScopeAll,
+	AccessTokenScopeRepo,
+	AccessTokenScopeRepoRead,
+	AccessTokenScopeIssue,
+	AccessTokenScopeAdmin,
+	AccessTokenScopeUser,
+	AccessTokenScopeOrg,
+	AccessTokenScopeNotification,
+	AccessTokenScopePublicOnly,
+}
+
+// ParseAccessTokenScopes converts the given names into scopes, it returns
+// an ErrInvalidAccessTokenScope for the first unknown name.
+func ParseAccessTokenScopes(names []string) ([]AccessTokenScope, error) {
+	scopes := make([]AccessTokenScope, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) == 0 {
+			continue
+		}
+
+		var valid bool
+		for _, scope := range AccessTokenScopes {
+			if string(scope) == name {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, ErrInvalidAccessTokenScope{name}
+		}
+		scopes = append(scopes, AccessTokenScope(name))
+	}
+	return scopes, nil
+}
+
 // AccessToken represents a personal access token.
 type AccessToken struct {
 	ID   int64 `xorm:"pk autoincr"`
@@ -20,6 +87,10 @@ type AccessToken struct {
 	Name string
 	Sha1 string `xorm:"UNIQUE VARCHAR(40)"`
 
+	Scopes      []AccessTokenScope `xorm:"TEXT JSON"`
+	RepoIDs     []int64            `xorm:"TEXT JSON"` // Empty means all repositories
+	ExpiredUnix util.TimeStamp     `xorm:"INDEX"`     // Zero means the token never expires
+
 	CreatedUnix       util.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix       util.TimeStamp `xorm:"INDEX updated"`
 	HasRecentActivity bool           `xorm:"-"`
@@ -32,8 +103,88 @@ func (t *AccessToken) AfterLoad() {
 	t.HasRecentActivity = t.UpdatedUnix.AddDuration(7*24*time.Hour) > util.TimeStampNow()
 }
 
-// NewAccessToken creates new access token.
+// APIFormat converts an AccessToken to api.AccessToken
+func (t *AccessToken) APIFormat() *api.AccessToken {
+	scopes := make([]string, len(t.Scopes))
+	for i := range t.Scopes {
+		scopes[i] = string(t.Scopes[i])
+	}
+
+	apiToken := &api.AccessToken{
+		Name:   t.Name,
+		Sha1:   t.Sha1,
+		Scopes: scopes,
+	}
+	if t.ExpiredUnix > 0 {
+		expiresAt := t.ExpiredUnix.AsTime()
+		apiToken.ExpiresAt = &expiresAt
+	}
+	return apiToken
+}
+
+// HasScope returns true if the token has been granted the given scope.
+func (t *AccessToken) HasScope(scope AccessTokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+
+		// Public-only is a restriction and is never implied by another scope.
+		if scope == AccessTokenScopePublicOnly {
+			continue
+		}
+
+		switch s {
+		case AccessTokenScopeAll:
+			return true
+		case AccessTokenScopeRepo:
+			if scope == AccessTokenScopeRepoRead || scope == AccessTokenScopeIssue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasAnyScope returns true if the token has been granted at least one of the given scopes.
+func (t *AccessToken) HasAnyScope(scopes ...AccessTokenScope) bool {
+	for _, scope := range scopes {
+		if t.HasScope(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the token has an expiry date that has passed.
+func (t *AccessToken) IsExpired() bool {
+	return t.ExpiredUnix > 0 && t.ExpiredUnix <= util.TimeStampNow()
+}
+
+// CanAccessRepo returns true if the token is not restricted from the given repository.
+func (t *AccessToken) CanAccessRepo(repo *Repository) bool {
+	if repo.IsPrivate && t.HasScope(AccessTokenScopePublicOnly) {
+		return false
+	}
+	return len(t.RepoIDs) == 0 || com.IsSliceContainsInt64(t.RepoIDs, repo.ID)
+}
+
+// AllowsRepoAccess returns true if the token is allowed to access the given
+// repository with the given access mode.
+func (t *AccessToken) AllowsRepoAccess(repo *Repository, mode AccessMode) bool {
+	scope := AccessTokenScopeRepoRead
+	if mode >= AccessModeWrite {
+		scope = AccessTokenScopeRepo
+	}
+	return t.HasScope(scope) && t.CanAccessRepo(repo)
+}
+
+// NewAccessToken creates new access token. Tokens created without any scope
+// are granted the full rights of their owner.
 func NewAccessToken(t *AccessToken) error {
+	if len(t.Scopes) == 0 {
+		t.Scopes = []AccessTokenScope{AccessTokenScopeAll}
+	}
 	t.Sha1 = base.EncodeSha1(gouuid.NewV4().String())
 	_, err := x.Insert(t)
 	return err
@@ -50,6 +201,8 @@ func GetAccessTokenBySHA(sha string) (*AccessToken, error) {
 		return nil, err
 	} else if !has {
 		return nil, ErrAccessTokenNotExist{sha}
+	} else if t.IsExpired() {
+		return nil, ErrAccessTokenExpired{t.ID}
 	}
 	return t, nil
 }
@@ -7,6 +7,8 @@ package models
 import (
 	"testing"
 
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -91,3 +93,74 @@ func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, IsErrAccessTokenNotExist(err))
 }
+
+func TestParseAccessTokenScopes(t *testing.T) {
+	scopes, err := ParseAccessTokenScopes([]string{"repo:read", " Issue ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []AccessTokenScope{AccessTokenScopeRepoRead, AccessTokenScopeIssue}, scopes)
+
+	_, err = ParseAccessTokenScopes([]string{"repo", "delete-everything"})
+	assert.True(t, IsErrInvalidAccessTokenScope(err))
+}
+
+func TestAccessTokenHasScope(t *testing.T) {
+	token := &AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeAll}}
+	assert.True(t, token.HasScope(AccessTokenScopeAdmin))
+	assert.True(t, token.HasScope(AccessTokenScopeRepo))
+	assert.False(t, token.HasScope(AccessTokenScopePublicOnly))
+
+	token = &AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeRepo}}
+	assert.True(t, token.HasScope(AccessTokenScopeRepoRead))
+	assert.True(t, token.HasScope(AccessTokenScopeIssue))
+	assert.False(t, token.HasScope(AccessTokenScopeAdmin))
+	assert.False(t, token.HasScope(AccessTokenScopeUser))
+
+	token = &AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeRepoRead}}
+	assert.False(t, token.HasScope(AccessTokenScopeRepo))
+	assert.False(t, token.HasScope(AccessTokenScopeIssue))
+	assert.True(t, token.HasAnyScope(AccessTokenScopeIssue, AccessTokenScopeRepoRead))
+}
+
+func TestAccessTokenAllowsRepoAccess(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	publicRepo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	privateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+
+	token := &AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeRepoRead}}
+	assert.True(t, token.AllowsRepoAccess(publicRepo, AccessModeRead))
+	assert.True(t, token.AllowsRepoAccess(privateRepo, AccessModeRead))
+	assert.False(t, token.AllowsRepoAccess(publicRepo, AccessModeWrite))
+
+	token = &AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeRepo, AccessTokenScopePublicOnly}}
+	assert.True(t, token.AllowsRepoAccess(publicRepo, AccessModeWrite))
+	assert.False(t, token.AllowsRepoAccess(privateRepo, AccessModeRead))
+
+	token = &AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeRepo}, RepoIDs: []int64{2}}
+	assert.False(t, token.AllowsRepoAccess(publicRepo, AccessModeRead))
+	assert.True(t, token.AllowsRepoAccess(privateRepo, AccessModeWrite))
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	token := &AccessToken{
+		UID:         2,
+		Name:        "Expired token",
+		Scopes:      []AccessTokenScope{AccessTokenScopeRepoRead},
+		ExpiredUnix: util.TimeStampNow() - 60,
+	}
+	assert.NoError(t, NewAccessToken(token))
+
+	_, err := GetAccessTokenBySHA(token.Sha1)
+	assert.True(t, IsErrAccessTokenExpired(err))
+
+	token = &AccessToken{
+		UID:         2,
+		Name:        "Valid token",
+		ExpiredUnix: util.TimeStampNow() + 3600,
+	}
+	assert.NoError(t, NewAccessToken(token))
+
+	token, err = GetAccessTokenBySHA(token.Sha1)
+	assert.NoError(t, err)
+	assert.Equal(t, []AccessTokenScope{AccessTokenScopeAll}, token.Scopes)
+}
@@ -0,0 +1,336 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/builder"
+)
+
+const (
+	// MaxTopicsPerRepo is the maximum number of topics a repository can have
+	MaxTopicsPerRepo = 25
+	// MaxTopicLength is the maximum length of a single topic name
+	MaxTopicLength = 35
+)
+
+var topicPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Topic represents a topic of repositories
+type Topic struct {
+	ID          int64  `xorm:"pk autoincr"`
+	Name        string `xorm:"UNIQUE"`
+	RepoCount   int
+	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix util.TimeStamp `xorm:"INDEX updated"`
+}
+
+// RepoTopic represents associated repositories and topics
+type RepoTopic struct {
+	RepoID  int64 `xorm:"UNIQUE(s)"`
+	TopicID int64 `xorm:"UNIQUE(s)"`
+}
+
+// APIFormat converts a Topic to api.TopicResponse
+func (t *Topic) APIFormat() *api.TopicResponse {
+	return &api.TopicResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		RepoCount: t.RepoCount,
+		Created:   t.CreatedUnix.AsTime(),
+		Updated:   t.UpdatedUnix.AsTime(),
+	}
+}
+
+// ValidateTopic checks if a topic name is valid: lowercase letters, digits
+// and dashes, starting with a letter or digit and at most MaxTopicLength long.
+func ValidateTopic(topic string) bool {
+	return len(topic) <= MaxTopicLength && topicPattern.MatchString(topic)
+}
+
+// SanitizeAndValidateTopics lowercases and de-duplicates the given topics and
+// splits them into valid and invalid ones.
+func SanitizeAndValidateTopics(topics []string) (validTopics []string, invalidTopics []string) {
+	validTopics = make([]string, 0, len(topics))
+	invalidTopics = make([]string, 0)
+	seen := make(map[string]bool, len(topics))
+
+	for _, topic := range topics {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if len(topic) == 0 || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+
+		if ValidateTopic(topic) {
+			validTopics = append(validTopics, topic)
+		} else {
+			invalidTopics = append(invalidTopics, topic)
+		}
+	}
+	return validTopics, invalidTopics
+}
+
+func getTopicByName(e Engine, name string) (*Topic, error) {
+	topic := new(Topic)
+	has, err := e.Where("name = ?", name).Get(topic)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTopicNotExist{name}
+	}
+	return topic, nil
+}
+
+// GetTopicByName returns the topic with given name
+func GetTopicByName(name string) (*Topic, error) {
+	return getTopicByName(x, name)
+}
+
+// FindTopicOptions represents the options when finding topics
+type FindTopicOptions struct {
+	RepoID   int64
+	Keyword  string
+	Page     int
+	PageSize int
+}
+
+func (opts *FindTopicOptions) toConds() builder.Cond {
+	var cond = builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_topic.repo_id": opts.RepoID})
+	}
+
+	if opts.Keyword != "" {
+		cond = cond.And(builder.Like{"topic.name", strings.ToLower(opts.Keyword)})
+	}
+
+	return cond
+}
+
+// FindTopics retrieves the topics matching the given options, most used first
+func FindTopics(opts *FindTopicOptions) (topics []*Topic, err error) {
+	sess := x.Select("topic.*").Where(opts.toConds())
+	if opts.RepoID > 0 {
+		sess.Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id")
+	}
+	if opts.PageSize > 0 {
+		if opts.Page <= 0 {
+			opts.Page = 1
+		}
+		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
+	}
+	return topics, sess.Desc("topic.repo_count").Asc("topic.name").Find(&topics)
+}
+
+func getRepoTopicByName(e Engine, repoID int64, topicName string) (*Topic, error) {
+	topic := new(Topic)
+	has, err := e.Table("topic").Select("topic.*").
+		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
+		Where("repo_topic.repo_id = ? AND topic.name = ?", repoID, topicName).
+		Get(topic)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTopicNotExist{topicName}
+	}
+	return topic, nil
+}
+
+// GetRepoTopicByName returns the topic with given name if the repository has it
+func GetRepoTopicByName(repoID int64, topicName string) (*Topic, error) {
+	return getRepoTopicByName(x, repoID, topicName)
+}
+
+// CountRepoTopics returns the number of topics a repository has
+func CountRepoTopics(repoID int64) (int64, error) {
+	return x.Count(&RepoTopic{RepoID: repoID})
+}
+
+// addTopicByNameToRepo adds a topic to a repository, creating the topic if it
+// does not exist yet.
+func addTopicByNameToRepo(e Engine, repoID int64, topicName string) (*Topic, error) {
+	topic, err := getTopicByName(e, topicName)
+	if err != nil {
+		if !IsErrTopicNotExist(err) {
+			return nil, err
+		}
+		topic = &Topic{Name: topicName}
+		if _, err = e.Insert(topic); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = e.Insert(&RepoTopic{RepoID: repoID, TopicID: topic.ID}); err != nil {
+		return nil, err
+	}
+
+	if _, err = e.Incr("repo_count").ID(topic.ID).Update(new(Topic)); err != nil {
+		return nil, err
+	}
+	topic.RepoCount++
+	return topic, nil
+}
+
+// removeTopicFromRepo removes a topic from a repository and decrements its
+// repository count.
+func removeTopicFromRepo(e Engine, repoID int64, topic *Topic) error {
+	if _, err := e.Delete(&RepoTopic{RepoID: repoID, TopicID: topic.ID}); err != nil {
+		return err
+	}
+
+	if _, err := e.Decr("repo_count").ID(topic.ID).Update(new(Topic)); err != nil {
+		return err
+	}
+	topic.RepoCount--
+	return nil
+}
+
+// removeTopicsFromRepo removes all topics from a repository
+func removeTopicsFromRepo(e Engine, repoID int64) error {
+	if _, err := e.Decr("repo_count").
+		Where("id IN (SELECT topic_id FROM repo_topic WHERE repo_id = ?)", repoID).
+		Update(new(Topic)); err != nil {
+		return err
+	}
+
+	_, err := e.Delete(&RepoTopic{RepoID: repoID})
+	return err
+}
+
+// syncTopicsInRepository copies the topic names of a repository into its topics column
+func syncTopicsInRepository(e Engine, repoID int64) error {
+	topicNames := make([]string, 0, MaxTopicsPerRepo)
+	if err := e.Table("topic").Cols("name").
+		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
+		Where("repo_topic.repo_id = ?", repoID).
+		Asc("topic.name").
+		Find(&topicNames); err != nil {
+		return err
+	}
+
+	_, err := e.ID(repoID).Cols("topics").Update(&Repository{Topics: topicNames})
+	return err
+}
+
+// AddTopic adds a topic to a repository
+func AddTopic(repoID int64, topicName string) (*Topic, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	topic, err := getRepoTopicByName(sess, repoID, topicName)
+	if err == nil {
+		// Repository already has the topic.
+		return topic, nil
+	} else if !IsErrTopicNotExist(err) {
+		return nil, err
+	}
+
+	count, err := sess.Count(&RepoTopic{RepoID: repoID})
+	if err != nil {
+		return nil, err
+	} else if count >= MaxTopicsPerRepo {
+		return nil, fmt.Errorf("repository can not have more than %d topics", MaxTopicsPerRepo)
+	}
+
+	if topic, err = addTopicByNameToRepo(sess, repoID, topicName); err != nil {
+		return nil, fmt.Errorf("addTopicByNameToRepo: %v", err)
+	}
+	if err = syncTopicsInRepository(sess, repoID); err != nil {
+		return nil, fmt.Errorf("syncTopicsInRepository: %v", err)
+	}
+
+	return topic, sess.Commit()
+}
+
+// DeleteTopic removes a topic from a repository
+func DeleteTopic(repoID int64, topicName string) (*Topic, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	topic, err := getRepoTopicByName(sess, repoID, topicName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = removeTopicFromRepo(sess, repoID, topic); err != nil {
+		return nil, fmt.Errorf("removeTopicFromRepo: %v", err)
+	}
+	if err = syncTopicsInRepository(sess, repoID); err != nil {
+		return nil, fmt.Errorf("syncTopicsInRepository: %v", err)
+	}
+
+	return topic, sess.Commit()
+}
+
+// SaveTopics replaces the topics of a repository with the given ones. The
+// names are expected to be validated by SanitizeAndValidateTopics.
+func SaveTopics(repoID int64, topicNames ...string) error {
+	if len(topicNames) > MaxTopicsPerRepo {
+		return fmt.Errorf("repository can not have more than %d topics", MaxTopicsPerRepo)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	topics, err := findRepoTopics(sess, repoID)
+	if err != nil {
+		return err
+	}
+
+	var keep = make(map[string]bool, len(topicNames))
+	for _, name := range topicNames {
+		keep[name] = true
+	}
+
+	var existing = make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		existing[topic.Name] = true
+		if !keep[topic.Name] {
+			if err = removeTopicFromRepo(sess, repoID, topic); err != nil {
+				return fmt.Errorf("removeTopicFromRepo: %v", err)
+			}
+		}
+	}
+
+	for _, name := range topicNames {
+		if existing[name] {
+			continue
+		}
+		if _, err = addTopicByNameToRepo(sess, repoID, name); err != nil {
+			return fmt.Errorf("addTopicByNameToRepo: %v", err)
+		}
+	}
+
+	if err = syncTopicsInRepository(sess, repoID); err != nil {
+		return fmt.Errorf("syncTopicsInRepository: %v", err)
+	}
+
+	return sess.Commit()
+}
+
+func findRepoTopics(e Engine, repoID int64) ([]*Topic, error) {
+	topics := make([]*Topic, 0, MaxTopicsPerRepo)
+	return topics, e.Table("topic").Select("topic.*").
+		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
+		Where("repo_topic.repo_id = ?", repoID).
+		Find(&topics)
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTopic(t *testing.T) {
+	assert.True(t, ValidateTopic("golang"))
+	assert.True(t, ValidateTopic("4-dash-separated-words"))
+	assert.False(t, ValidateTopic("Golang"))
+	assert.False(t, ValidateTopic("-leading-dash"))
+	assert.False(t, ValidateTopic("with space"))
+	assert.False(t, ValidateTopic("a-topic-name-that-is-longer-than-35-chars"))
+
+	validTopics, invalidTopics := SanitizeAndValidateTopics([]string{" Golang", "golang", "sql", "", "bad_topic"})
+	assert.Equal(t, []string{"golang", "sql"}, validTopics)
+	assert.Equal(t, []string{"bad_topic"}, invalidTopics)
+}
+
+func TestFindTopics(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	topics, err := FindTopics(&FindTopicOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, topics, 4) {
+		// Most used topic first
+		assert.EqualValues(t, "golang", topics[0].Name)
+	}
+
+	topics, err = FindTopics(&FindTopicOptions{PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 2)
+
+	topics, err = FindTopics(&FindTopicOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 3)
+
+	topics, err = FindTopics(&FindTopicOptions{Keyword: "ql"})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 2)
+}
+
+func TestAddAndDeleteTopic(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	topic, err := AddTopic(2, "database")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, topic.RepoCount)
+	AssertExistsAndLoadBean(t, &RepoTopic{RepoID: 2, TopicID: topic.ID})
+
+	// Adding an existing topic is a no-op
+	topic, err = AddTopic(2, "database")
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Topic{ID: topic.ID, RepoCount: 2})
+
+	// A new topic is created on first use
+	topic, err = AddTopic(2, "newtopic")
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &Topic{ID: topic.ID, Name: "newtopic", RepoCount: 1})
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	assert.Equal(t, []string{"database", "golang", "newtopic", "sql"}, repo.Topics)
+
+	topic, err = DeleteTopic(2, "golang")
+	assert.NoError(t, err)
+	AssertNotExistsBean(t, &RepoTopic{RepoID: 2, TopicID: topic.ID})
+	AssertExistsAndLoadBean(t, &Topic{ID: topic.ID, RepoCount: 1})
+
+	_, err = DeleteTopic(2, "golang")
+	assert.True(t, IsErrTopicNotExist(err))
+}
+
+func TestSaveTopics(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, SaveTopics(1, "golang", "sql", "newtopic"))
+
+	topics, err := FindTopics(&FindTopicOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 3)
+
+	AssertExistsAndLoadBean(t, &Topic{Name: "database"}, Cond("repo_count = ?", 0))
+	AssertExistsAndLoadBean(t, &Topic{Name: "graphql"}, Cond("repo_count = ?", 0))
+	AssertExistsAndLoadBean(t, &Topic{Name: "golang", RepoCount: 2})
+	AssertExistsAndLoadBean(t, &Topic{Name: "sql", RepoCount: 2})
+	AssertExistsAndLoadBean(t, &Topic{Name: "newtopic", RepoCount: 1})
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, []string{"golang", "newtopic", "sql"}, repo.Topics)
+}
+
+func TestSearchRepositoryByTopic(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// Only the public repository is found
+	repos, count, err := SearchRepositoryByName(&SearchRepoOptions{
+		Keyword:   "golang",
+		TopicOnly: true,
+		Page:      1,
+		PageSize:  10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	repos, count, err = SearchRepositoryByName(&SearchRepoOptions{
+		Keyword:   "golang",
+		TopicOnly: true,
+		Private:   true,
+		Page:      1,
+		PageSize:  10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, repos, 2)
+
+	// Topics have to match exactly when searching by topic only
+	_, count, err = SearchRepositoryByName(&SearchRepoOptions{
+		Keyword:   "go",
+		TopicOnly: true,
+		Page:      1,
+		PageSize:  10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	// A plain keyword search also matches topics
+	_, count, err = SearchRepositoryByName(&SearchRepoOptions{
+		Keyword:  "graph",
+		Page:     1,
+		PageSize: 10,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
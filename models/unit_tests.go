@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/Unknwon/com"
 	"github.com/go-xorm/core"
@@ -62,6 +63,14 @@ func MainTest(m *testing.M, pathToGiteaRoot string) {
 		fatalTestError("url.Parse: %v\n", err)
 	}
 
+	setting.AttachmentStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "attachments")}
+	setting.LFSStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "lfs")}
+	setting.AvatarStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "avatars")}
+	setting.RepoArchiveStorage = setting.Storage{Type: setting.LocalStorageType, Path: filepath.Join(setting.AppDataPath, "repo-archive")}
+	if err = storage.Init(); err != nil {
+		fatalTestError("storage.Init: %v\n", err)
+	}
+
 	exitStatus := m.Run()
 	if err = removeAllWithRetry(setting.RepoRootPath); err != nil {
 		fatalTestError("os.RemoveAll: %v\n", err)
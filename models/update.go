@@ -80,6 +80,7 @@ func PushUpdate(branch string, opt PushUpdateOptions) error {
 	log.Trace("TriggerTask '%s/%s' by %s", repo.Name, branch, pusher.Name)
 
 	go AddTestPullRequestTask(pusher, repo.ID, branch, true)
+	go AddPushMirrorsToQueue(repo.ID)
 	return nil
 }
 
@@ -7,6 +7,7 @@ package models
 import (
 	"bytes"
 	"container/list"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha256"
 	"crypto/subtle"
@@ -37,6 +38,7 @@ import (
 	"code.gitea.io/gitea/modules/generate"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -53,6 +55,30 @@ const (
 
 const syncExternalUsers = "sync_external_users"
 
+const (
+	// EmailNotificationsEnabled sends an email for every notification
+	EmailNotificationsEnabled = "enabled"
+	// EmailNotificationsOnMention only sends an email when the user is mentioned
+	EmailNotificationsOnMention = "onmention"
+	// EmailNotificationsDisabled never sends notification emails
+	EmailNotificationsDisabled = "disabled"
+	// EmailNotificationsDaily sends the unread notifications in a daily digest
+	EmailNotificationsDaily = "daily"
+	// EmailNotificationsWeekly sends the unread notifications in a weekly digest
+	EmailNotificationsWeekly = "weekly"
+)
+
+// IsValidEmailNotificationsPreference returns true if preference is a known
+// email notifications preference.
+func IsValidEmailNotificationsPreference(preference string) bool {
+	switch preference {
+	case EmailNotificationsEnabled, EmailNotificationsOnMention, EmailNotificationsDisabled,
+		EmailNotificationsDaily, EmailNotificationsWeekly:
+		return true
+	}
+	return false
+}
+
 var (
 	// ErrUserNotKeyOwner user does not own this key error
 	ErrUserNotKeyOwner = errors.New("User does not own this public key")
@@ -131,7 +157,9 @@ type User struct {
 	Members     []*User `xorm:"-"`
 
 	// Preferences
-	DiffViewStyle string `xorm:"NOT NULL DEFAULT ''"`
+	DiffViewStyle                string         `xorm:"NOT NULL DEFAULT ''"`
+	EmailNotificationsPreference string         `xorm:"VARCHAR(20) NOT NULL DEFAULT 'enabled'"`
+	LastDigestUnix               util.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 }
 
 // BeforeUpdate is invoked from XORM before updating this object.
@@ -168,6 +196,74 @@ func (u *User) UpdateDiffViewStyle(style string) error {
 	return UpdateUserCols(u, "diff_view_style")
 }
 
+// EmailNotifications returns the user's email notification preference,
+// defaulting to EmailNotificationsEnabled.
+func (u *User) EmailNotifications() string {
+	if len(u.EmailNotificationsPreference) == 0 {
+		return EmailNotificationsEnabled
+	}
+	return u.EmailNotificationsPreference
+}
+
+// IsEmailNotificationsDigest returns true if the user wants to receive
+// their notifications in a daily or weekly digest mail.
+func (u *User) IsEmailNotificationsDigest() bool {
+	switch u.EmailNotifications() {
+	case EmailNotificationsDaily, EmailNotificationsWeekly:
+		return true
+	}
+	return false
+}
+
+// SetEmailNotifications sets the user's email notification preference.
+func (u *User) SetEmailNotifications(preference string) error {
+	if !IsValidEmailNotificationsPreference(preference) {
+		return fmt.Errorf("invalid email notifications preference: %s", preference)
+	}
+	u.EmailNotificationsPreference = preference
+	if u.IsEmailNotificationsDigest() && u.LastDigestUnix == 0 {
+		// Do not send the whole notification history in the first digest.
+		u.LastDigestUnix = util.TimeStampNow()
+	}
+	return UpdateUserCols(u, "email_notifications_preference", "last_digest_unix")
+}
+
+// UnsubscribeToken returns a signed token which allows the user to disable
+// their email notifications without signing in.
+func (u *User) UnsubscribeToken() string {
+	return fmt.Sprintf("%d.%s", u.ID, u.unsubscribeSignature())
+}
+
+// UnsubscribeURL returns the one-click unsubscribe link of the user.
+func (u *User) UnsubscribeURL() string {
+	return setting.AppURL + "user/unsubscribe/" + u.UnsubscribeToken()
+}
+
+// unsubscribeSignature is invalidated when the user changes their password,
+// since Rands is regenerated at that time.
+func (u *User) unsubscribeSignature() string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	mac.Write([]byte(fmt.Sprintf("unsubscribe:%d:%s", u.ID, u.Rands)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetUserByUnsubscribeToken returns the user the given unsubscribe token was
+// signed for.
+func GetUserByUnsubscribeToken(token string) (*User, error) {
+	fields := strings.SplitN(token, ".", 2)
+	if len(fields) != 2 {
+		return nil, ErrUserNotExist{0, "", 0}
+	}
+	u, err := GetUserByID(com.StrTo(fields[0]).MustInt64())
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(u.unsubscribeSignature()), []byte(fields[1])) {
+		return nil, ErrUserNotExist{0, "", 0}
+	}
+	return u, nil
+}
+
 // getEmail returns an noreply email, if the user has set to keep his
 // email address private, otherwise the primary email address.
 func (u *User) getEmail() string {
@@ -278,9 +374,30 @@ func (u *User) GenerateActivateCode() string {
 	return u.GenerateEmailActivateCode(u.Email)
 }
 
-// CustomAvatarPath returns user custom avatar file path.
-func (u *User) CustomAvatarPath() string {
-	return filepath.Join(setting.AvatarUploadPath, u.Avatar)
+// CustomAvatarRelativePath returns user custom avatar relative path inside the avatars storage.
+func (u *User) CustomAvatarRelativePath() string {
+	return u.Avatar
+}
+
+// CustomAvatarExists returns true if the custom avatar of the user is present in the avatars storage.
+func (u *User) CustomAvatarExists() bool {
+	if len(u.Avatar) == 0 {
+		return false
+	}
+	_, err := storage.Avatars.Stat(u.CustomAvatarRelativePath())
+	return err == nil
+}
+
+// saveAvatar encodes the image as png and stores it as custom avatar of the user.
+func (u *User) saveAvatar(img image.Image) error {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return fmt.Errorf("Encode: %v", err)
+	}
+	if _, err := storage.Avatars.Save(u.CustomAvatarRelativePath(), buf); err != nil {
+		return fmt.Errorf("Save: %v", err)
+	}
+	return nil
 }
 
 // GenerateRandomAvatar generates a random avatar for user.
@@ -303,23 +420,14 @@ func (u *User) generateRandomAvatar(e Engine) error {
 	if u.Avatar == "" {
 		u.Avatar = fmt.Sprintf("%d", u.ID)
 	}
-	if err = os.MkdirAll(filepath.Dir(u.CustomAvatarPath()), os.ModePerm); err != nil {
-		return fmt.Errorf("MkdirAll: %v", err)
-	}
-	fw, err := os.Create(u.CustomAvatarPath())
-	if err != nil {
-		return fmt.Errorf("Create: %v", err)
+	if err = u.saveAvatar(img); err != nil {
+		return err
 	}
-	defer fw.Close()
 
 	if _, err := e.ID(u.ID).Cols("avatar").Update(u); err != nil {
 		return err
 	}
 
-	if err = png.Encode(fw, img); err != nil {
-		return fmt.Errorf("Encode: %v", err)
-	}
-
 	log.Info("New random avatar created: %d", u.ID)
 	return nil
 }
@@ -333,12 +441,12 @@ func (u *User) SizedRelAvatarLink(size int) string {
 
 	switch {
 	case u.UseCustomAvatar:
-		if !com.IsFile(u.CustomAvatarPath()) {
+		if !u.CustomAvatarExists() {
 			return base.DefaultAvatarLink()
 		}
 		return setting.AppSubURL + "/avatars/" + u.Avatar
 	case setting.DisableGravatar, setting.OfflineMode:
-		if !com.IsFile(u.CustomAvatarPath()) {
+		if !u.CustomAvatarExists() {
 			if err := u.GenerateRandomAvatar(); err != nil {
 				log.Error(3, "GenerateRandomAvatar: %v", err)
 			}
@@ -450,18 +558,8 @@ func (u *User) UploadAvatar(data []byte) error {
 		return fmt.Errorf("updateUser: %v", err)
 	}
 
-	if err := os.MkdirAll(setting.AvatarUploadPath, os.ModePerm); err != nil {
-		return fmt.Errorf("Failed to create dir %s: %v", setting.AvatarUploadPath, err)
-	}
-
-	fw, err := os.Create(u.CustomAvatarPath())
-	if err != nil {
-		return fmt.Errorf("Create: %v", err)
-	}
-	defer fw.Close()
-
-	if err = png.Encode(fw, m); err != nil {
-		return fmt.Errorf("Encode: %v", err)
+	if err = u.saveAvatar(m); err != nil {
+		return err
 	}
 
 	return sess.Commit()
@@ -469,10 +567,10 @@ func (u *User) UploadAvatar(data []byte) error {
 
 // DeleteAvatar deletes the user's custom avatar.
 func (u *User) DeleteAvatar() error {
-	log.Trace("DeleteAvatar[%d]: %s", u.ID, u.CustomAvatarPath())
+	log.Trace("DeleteAvatar[%d]: %s", u.ID, u.CustomAvatarRelativePath())
 	if len(u.Avatar) > 0 {
-		if err := os.Remove(u.CustomAvatarPath()); err != nil {
-			return fmt.Errorf("Failed to remove %s: %v", u.CustomAvatarPath(), err)
+		if err := storage.Avatars.Delete(u.CustomAvatarRelativePath()); err != nil {
+			return fmt.Errorf("Failed to remove %s: %v", u.CustomAvatarRelativePath(), err)
 		}
 	}
 
@@ -963,6 +1061,7 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&Collaboration{UserID: u.ID},
 		&Access{UserID: u.ID},
 		&Watch{UserID: u.ID},
+		&Notification{UserID: u.ID},
 		&Star{UID: u.ID},
 		&Follow{UserID: u.ID},
 		&Follow{FollowID: u.ID},
@@ -975,6 +1074,10 @@ func deleteUser(e *xorm.Session, u *User) error {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = deleteOAuth2ByUserID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteOAuth2ByUserID: %v", err)
+	}
+
 	// ***** START: PublicKey *****
 	keys := make([]*PublicKey, 0, 10)
 	if err = e.Find(&keys, &PublicKey{OwnerID: u.ID}); err != nil {
@@ -991,7 +1094,7 @@ func deleteUser(e *xorm.Session, u *User) error {
 	// ***** END: PublicKey *****
 
 	// Clear assignee.
-	if _, err = e.Exec("UPDATE `issue` SET assignee_id=0 WHERE assignee_id=?", u.ID); err != nil {
+	if err = clearAssigneeByUserID(e, u.ID); err != nil {
 		return fmt.Errorf("clear assignee: %v", err)
 	}
 
@@ -1015,11 +1118,9 @@ func deleteUser(e *xorm.Session, u *User) error {
 	}
 
 	if len(u.Avatar) > 0 {
-		avatarPath := u.CustomAvatarPath()
-		if com.IsExist(avatarPath) {
-			if err := os.Remove(avatarPath); err != nil {
-				return fmt.Errorf("Failed to remove %s: %v", avatarPath, err)
-			}
+		avatarPath := u.CustomAvatarRelativePath()
+		if err := storage.Avatars.Delete(avatarPath); err != nil {
+			return fmt.Errorf("Failed to remove %s: %v", avatarPath, err)
 		}
 	}
 
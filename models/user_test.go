@@ -147,6 +147,44 @@ func TestHashPasswordDeterministic(t *testing.T) {
 	}
 }
 
+func TestSetEmailNotifications(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.Equal(t, EmailNotificationsEnabled, user.EmailNotifications())
+
+	assert.Error(t, user.SetEmailNotifications("unknown"))
+
+	assert.NoError(t, user.SetEmailNotifications(EmailNotificationsOnMention))
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.Equal(t, EmailNotificationsOnMention, user.EmailNotifications())
+	assert.False(t, user.IsEmailNotificationsDigest())
+	assert.EqualValues(t, 0, user.LastDigestUnix)
+
+	// the first digest does not include the notification history
+	assert.NoError(t, user.SetEmailNotifications(EmailNotificationsWeekly))
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.True(t, user.IsEmailNotificationsDigest())
+	assert.NotZero(t, user.LastDigestUnix)
+}
+
+func TestGetUserByUnsubscribeToken(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	u, err := GetUserByUnsubscribeToken(user.UnsubscribeToken())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, u.ID)
+
+	// a token of a user is not valid for another one
+	other := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	forged := "2." + other.unsubscribeSignature()
+	_, err = GetUserByUnsubscribeToken(forged)
+	assert.True(t, IsErrUserNotExist(err))
+
+	_, err = GetUserByUnsubscribeToken("invalid")
+	assert.True(t, IsErrUserNotExist(err))
+}
+
 func BenchmarkHashPassword(b *testing.B) {
 	// BenchmarkHashPassword ensures that it takes a reasonable amount of time
 	// to hash a password - in order to protect from brute-force attacks.
@@ -10,14 +10,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"strings"
 	"time"
 
+	"code.gitea.io/git"
 	"code.gitea.io/gitea/modules/httplib"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/sync"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/webhook"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/Unknwon/com"
@@ -66,10 +69,18 @@ func IsValidHookContentType(name string) bool {
 
 // HookEvents is a set of web hook events
 type HookEvents struct {
-	Create      bool `json:"create"`
-	Push        bool `json:"push"`
-	PullRequest bool `json:"pull_request"`
-	Repository  bool `json:"repository"`
+	Create            bool `json:"create"`
+	Delete            bool `json:"delete"`
+	Fork              bool `json:"fork"`
+	Issues            bool `json:"issues"`
+	IssueComment      bool `json:"issue_comment"`
+	Push              bool `json:"push"`
+	PullRequest       bool `json:"pull_request"`
+	PullRequestReview bool `json:"pull_request_review"`
+	Repository        bool `json:"repository"`
+	Release           bool `json:"release"`
+	Label             bool `json:"label"`
+	Milestone         bool `json:"milestone"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -107,6 +118,14 @@ type Webhook struct {
 	Meta         string     `xorm:"TEXT"` // store hook-specific attributes
 	LastStatus   HookStatus // Last delivery status
 
+	// IsSystemWebhook is set on the hooks of the admin which fire for every repository.
+	// The admin hooks without it, the default hooks, are copied into each new repository.
+	IsSystemWebhook bool `xorm:"NOT NULL DEFAULT false"`
+
+	// IsFailing is set once a hook task has been given up on, and cleared by the next successful delivery.
+	IsFailing        bool           `xorm:"INDEX NOT NULL DEFAULT false"`
+	FailingSinceUnix util.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+
 	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix util.TimeStamp `xorm:"INDEX updated"`
 }
@@ -137,6 +156,15 @@ func (w *Webhook) GetDiscordHook() *DiscordMeta {
 	return s
 }
 
+// GetMattermostHook returns mattermost metadata
+func (w *Webhook) GetMattermostHook() *MattermostMeta {
+	s := &MattermostMeta{}
+	if err := json.Unmarshal([]byte(w.Meta), s); err != nil {
+		log.Error(4, "webhook.GetMattermostHook(%d): %v", w.ID, err)
+	}
+	return s
+}
+
 // History returns history of webhook by given conditions.
 func (w *Webhook) History(page int) ([]*HookTask, error) {
 	return HookTasks(w.ID, page)
@@ -155,6 +183,30 @@ func (w *Webhook) HasCreateEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Create)
 }
 
+// HasDeleteEvent returns true if hook enabled delete event.
+func (w *Webhook) HasDeleteEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Delete)
+}
+
+// HasForkEvent returns true if hook enabled fork event.
+func (w *Webhook) HasForkEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Fork)
+}
+
+// HasIssuesEvent returns true if hook enabled issues event.
+func (w *Webhook) HasIssuesEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Issues)
+}
+
+// HasIssueCommentEvent returns true if hook enabled issue_comment event.
+func (w *Webhook) HasIssueCommentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.IssueComment)
+}
+
 // HasPushEvent returns true if hook enabled push event.
 func (w *Webhook) HasPushEvent() bool {
 	return w.PushOnly || w.SendEverything ||
@@ -167,23 +219,74 @@ func (w *Webhook) HasPullRequestEvent() bool {
 		(w.ChooseEvents && w.HookEvents.PullRequest)
 }
 
+// HasPullRequestReviewEvent returns true if hook enabled pull request review event.
+func (w *Webhook) HasPullRequestReviewEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.PullRequestReview)
+}
+
 // HasRepositoryEvent returns if hook enabled repository event.
 func (w *Webhook) HasRepositoryEvent() bool {
 	return w.SendEverything ||
 		(w.ChooseEvents && w.HookEvents.Repository)
 }
 
+// HasReleaseEvent returns if hook enabled release event.
+func (w *Webhook) HasReleaseEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Release)
+}
+
+// HasLabelEvent returns if hook enabled label event.
+func (w *Webhook) HasLabelEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Label)
+}
+
+// HasMilestoneEvent returns if hook enabled milestone event.
+func (w *Webhook) HasMilestoneEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Milestone)
+}
+
+// HasEvent returns true if hook enabled the given event.
+func (w *Webhook) HasEvent(event HookEventType) bool {
+	switch event {
+	case HookEventCreate:
+		return w.HasCreateEvent()
+	case HookEventDelete:
+		return w.HasDeleteEvent()
+	case HookEventFork:
+		return w.HasForkEvent()
+	case HookEventIssues:
+		return w.HasIssuesEvent()
+	case HookEventIssueComment:
+		return w.HasIssueCommentEvent()
+	case HookEventPush:
+		return w.HasPushEvent()
+	case HookEventPullRequest:
+		return w.HasPullRequestEvent()
+	case HookEventPullRequestReview:
+		return w.HasPullRequestReviewEvent()
+	case HookEventRepository:
+		return w.HasRepositoryEvent()
+	case HookEventRelease:
+		return w.HasReleaseEvent()
+	case HookEventLabel:
+		return w.HasLabelEvent()
+	case HookEventMilestone:
+		return w.HasMilestoneEvent()
+	}
+	return false
+}
+
 // EventsArray returns an array of hook events
 func (w *Webhook) EventsArray() []string {
-	events := make([]string, 0, 3)
-	if w.HasCreateEvent() {
-		events = append(events, "create")
-	}
-	if w.HasPushEvent() {
-		events = append(events, "push")
-	}
-	if w.HasPullRequestEvent() {
-		events = append(events, "pull_request")
+	events := make([]string, 0, len(AllHookEvents))
+	for _, event := range AllHookEvents {
+		if w.HasEvent(event) {
+			events = append(events, string(event))
+		}
 	}
 	return events
 }
@@ -265,6 +368,71 @@ func GetWebhooksByOrgID(orgID int64) (ws []*Webhook, err error) {
 	return ws, err
 }
 
+// GetSystemWebhooks returns all the webhooks which fire for every repository.
+func GetSystemWebhooks() ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 5)
+	return webhooks, x.
+		Where("repo_id=? AND org_id=?", 0, 0).
+		And("is_system_webhook=?", true).
+		Find(&webhooks)
+}
+
+func getActiveSystemWebhooks(e Engine) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 5)
+	return webhooks, e.
+		Where("repo_id=? AND org_id=?", 0, 0).
+		And("is_system_webhook=?", true).
+		And("is_active=?", true).
+		Find(&webhooks)
+}
+
+// GetDefaultWebhooks returns all the webhooks which are copied into new repositories.
+func GetDefaultWebhooks() ([]*Webhook, error) {
+	return getDefaultWebhooks(x)
+}
+
+func getDefaultWebhooks(e Engine) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 5)
+	return webhooks, e.
+		Where("repo_id=? AND org_id=?", 0, 0).
+		And("is_system_webhook=?", false).
+		Find(&webhooks)
+}
+
+// GetSystemOrDefaultWebhook returns the admin webhook by given ID.
+func GetSystemOrDefaultWebhook(id int64) (*Webhook, error) {
+	w := new(Webhook)
+	has, err := x.
+		Where("id=? AND repo_id=? AND org_id=?", id, 0, 0).
+		Get(w)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrWebhookNotExist{id}
+	}
+	return w, nil
+}
+
+// copyDefaultWebhooksToRepo creates a webhook of the repository for each default webhook.
+func copyDefaultWebhooksToRepo(e Engine, repoID int64) error {
+	ws, err := getDefaultWebhooks(e)
+	if err != nil {
+		return fmt.Errorf("getDefaultWebhooks: %v", err)
+	}
+
+	for _, w := range ws {
+		w.ID = 0
+		w.RepoID = repoID
+		w.LastStatus = HookStatusNone
+		w.IsFailing = false
+		w.FailingSinceUnix = 0
+		if _, err = e.Insert(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UpdateWebhook updates information of webhook.
 func UpdateWebhook(w *Webhook) error {
 	_, err := x.ID(w.ID).AllCols().Update(w)
@@ -273,10 +441,39 @@ func UpdateWebhook(w *Webhook) error {
 
 // UpdateWebhookLastStatus updates last status of webhook.
 func UpdateWebhookLastStatus(w *Webhook) error {
-	_, err := x.ID(w.ID).Cols("last_status").Update(w)
+	_, err := x.ID(w.ID).Cols("last_status", "is_failing", "failing_since_unix").Update(w)
 	return err
 }
 
+// GetFailingWebhooks returns all webhooks whose deliveries have been given up on.
+func GetFailingWebhooks() ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 10)
+	return webhooks, x.
+		Where("is_failing=?", true).
+		Desc("failing_since_unix").
+		Find(&webhooks)
+}
+
+// SettingsLink returns the link to the settings page of the webhook.
+func (w *Webhook) SettingsLink() (string, error) {
+	if w.IsSystemWebhook {
+		return fmt.Sprintf("%s/admin/system-hooks/%d", setting.AppSubURL, w.ID), nil
+	} else if w.RepoID == 0 && w.OrgID == 0 {
+		return fmt.Sprintf("%s/admin/default-hooks/%d", setting.AppSubURL, w.ID), nil
+	} else if w.RepoID > 0 {
+		repo, err := GetRepositoryByID(w.RepoID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s/settings/hooks/%d", repo.Link(), w.ID), nil
+	}
+	org, err := GetUserByID(w.OrgID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/org/%s/settings/hooks/%d", setting.AppSubURL, org.Name, w.ID), nil
+}
+
 // deleteWebhook uses argument bean as query condition,
 // ID must be specified and do not assign unnecessary fields.
 func deleteWebhook(bean *Webhook) (err error) {
@@ -313,6 +510,27 @@ func DeleteWebhookByOrgID(orgID, id int64) error {
 	})
 }
 
+// DeleteDefaultSystemWebhook deletes the admin webhook by given ID.
+func DeleteDefaultSystemWebhook(id int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if count, err := sess.
+		Where("id=? AND repo_id=? AND org_id=?", id, 0, 0).
+		Delete(new(Webhook)); err != nil {
+		return err
+	} else if count == 0 {
+		return ErrWebhookNotExist{ID: id}
+	} else if _, err = sess.Delete(&HookTask{HookID: id}); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
 //   ___ ___                __   ___________              __
 //  /   |   \  ____   ____ |  | _\__    ___/____    _____|  | __
 // /    ~    \/  _ \ /  _ \|  |/ / |    |  \__  \  /  ___/  |/ /
@@ -330,14 +548,18 @@ const (
 	GITEA
 	DISCORD
 	DINGTALK
+	MSTEAMS
+	MATTERMOST
 )
 
 var hookTaskTypes = map[string]HookTaskType{
-	"gitea":    GITEA,
-	"gogs":     GOGS,
-	"slack":    SLACK,
-	"discord":  DISCORD,
-	"dingtalk": DINGTALK,
+	"gitea":      GITEA,
+	"gogs":       GOGS,
+	"slack":      SLACK,
+	"discord":    DISCORD,
+	"dingtalk":   DINGTALK,
+	"msteams":    MSTEAMS,
+	"mattermost": MATTERMOST,
 }
 
 // ToHookTaskType returns HookTaskType by given name.
@@ -358,6 +580,10 @@ func (t HookTaskType) Name() string {
 		return "discord"
 	case DINGTALK:
 		return "dingtalk"
+	case MSTEAMS:
+		return "msteams"
+	case MATTERMOST:
+		return "mattermost"
 	}
 	return ""
 }
@@ -373,12 +599,36 @@ type HookEventType string
 
 // Types of hook events
 const (
-	HookEventCreate      HookEventType = "create"
-	HookEventPush        HookEventType = "push"
-	HookEventPullRequest HookEventType = "pull_request"
-	HookEventRepository  HookEventType = "repository"
+	HookEventCreate            HookEventType = "create"
+	HookEventDelete            HookEventType = "delete"
+	HookEventFork              HookEventType = "fork"
+	HookEventIssues            HookEventType = "issues"
+	HookEventIssueComment      HookEventType = "issue_comment"
+	HookEventPush              HookEventType = "push"
+	HookEventPullRequest       HookEventType = "pull_request"
+	HookEventPullRequestReview HookEventType = "pull_request_review"
+	HookEventRepository        HookEventType = "repository"
+	HookEventRelease           HookEventType = "release"
+	HookEventLabel             HookEventType = "label"
+	HookEventMilestone         HookEventType = "milestone"
 )
 
+// AllHookEvents contains all the types of hook events
+var AllHookEvents = []HookEventType{
+	HookEventCreate,
+	HookEventDelete,
+	HookEventFork,
+	HookEventIssues,
+	HookEventIssueComment,
+	HookEventPush,
+	HookEventPullRequest,
+	HookEventPullRequestReview,
+	HookEventRepository,
+	HookEventRelease,
+	HookEventLabel,
+	HookEventMilestone,
+}
+
 // HookRequest represents hook task request information.
 type HookRequest struct {
 	Headers map[string]string `json:"headers"`
@@ -408,6 +658,10 @@ type HookTask struct {
 	Delivered       int64
 	DeliveredString string `xorm:"-"`
 
+	// Retry info.
+	Attempts        int            `xorm:"NOT NULL DEFAULT 0"`
+	NextAttemptUnix util.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+
 	// History info.
 	IsSucceed       bool
 	RequestContent  string        `xorm:"TEXT"`
@@ -439,6 +693,15 @@ func (t *HookTask) AfterLoad() {
 	if err := json.Unmarshal([]byte(t.RequestContent), t.RequestInfo); err != nil {
 		log.Error(3, "Unmarshal[%d]: %v", t.ID, err)
 	}
+
+	if len(t.ResponseContent) == 0 {
+		return
+	}
+
+	t.ResponseInfo = &HookResponse{}
+	if err := json.Unmarshal([]byte(t.ResponseContent), t.ResponseInfo); err != nil {
+		log.Error(3, "Unmarshal[%d]: %v", t.ID, err)
+	}
 }
 
 func (t *HookTask) simpleMarshalJSON(v interface{}) string {
@@ -459,6 +722,80 @@ func HookTasks(hookID int64, page int) ([]*HookTask, error) {
 		Find(&tasks)
 }
 
+// GetHookTaskByUUID returns the hook task of the webhook with the given delivery UUID.
+func GetHookTaskByUUID(hookID int64, uuid string) (*HookTask, error) {
+	t := &HookTask{
+		HookID: hookID,
+		UUID:   uuid,
+	}
+	has, err := x.Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHookTaskNotExist{HookID: hookID, UUID: uuid}
+	}
+	return t, nil
+}
+
+// RedeliverHookTask creates a new hook task delivering the payload of the given one
+// to the current settings of its webhook.
+func RedeliverHookTask(w *Webhook, t *HookTask) (*HookTask, error) {
+	redelivery := &HookTask{
+		RepoID:         t.RepoID,
+		HookID:         w.ID,
+		UUID:           gouuid.NewV4().String(),
+		Type:           w.HookTaskType,
+		URL:            w.URL,
+		PayloadContent: t.PayloadContent,
+		ContentType:    w.ContentType,
+		EventType:      t.EventType,
+		IsSSL:          w.IsSSL,
+	}
+	if _, err := x.Insert(redelivery); err != nil {
+		return nil, err
+	}
+	return redelivery, nil
+}
+
+// NewTestPushPayload returns a fake push event of the given commit, or of a fake
+// commit if it is nil, to test the deliveries of a webhook.
+func NewTestPushPayload(repo *Repository, commit *git.Commit, doer *User) *api.PushPayload {
+	if commit == nil {
+		ghost := NewGhostUser()
+		commit = &git.Commit{
+			ID:            git.MustIDFromString(git.EmptySHA),
+			Author:        ghost.NewGitSig(),
+			Committer:     ghost.NewGitSig(),
+			CommitMessage: "This is a fake commit",
+		}
+	}
+
+	apiUser := doer.APIFormat()
+	return &api.PushPayload{
+		Ref:    git.BranchPrefix + repo.DefaultBranch,
+		Before: commit.ID.String(),
+		After:  commit.ID.String(),
+		Commits: []*api.PayloadCommit{
+			{
+				ID:      commit.ID.String(),
+				Message: commit.Message(),
+				URL:     repo.HTMLURL() + "/commit/" + commit.ID.String(),
+				Author: &api.PayloadUser{
+					Name:  commit.Author.Name,
+					Email: commit.Author.Email,
+				},
+				Committer: &api.PayloadUser{
+					Name:  commit.Committer.Name,
+					Email: commit.Committer.Email,
+				},
+			},
+		},
+		Repo:   repo.APIFormat(AccessModeNone),
+		Pusher: apiUser,
+		Sender: apiUser,
+	}
+}
+
 // CreateHookTask creates a new hook task,
 // it handles conversion from Payload to PayloadContent.
 func CreateHookTask(t *HookTask) error {
@@ -488,23 +825,8 @@ func PrepareWebhook(w *Webhook, repo *Repository, event HookEventType, p api.Pay
 }
 
 func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType, p api.Payloader) error {
-	switch event {
-	case HookEventCreate:
-		if !w.HasCreateEvent() {
-			return nil
-		}
-	case HookEventPush:
-		if !w.HasPushEvent() {
-			return nil
-		}
-	case HookEventPullRequest:
-		if !w.HasPullRequestEvent() {
-			return nil
-		}
-	case HookEventRepository:
-		if !w.HasRepositoryEvent() {
-			return nil
-		}
+	if !w.HasEvent(event) {
+		return nil
 	}
 
 	var payloader api.Payloader
@@ -526,8 +848,18 @@ func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType,
 		if err != nil {
 			return fmt.Errorf("GetDingtalkPayload: %v", err)
 		}
+	case MSTEAMS:
+		payloader, err = GetMSTeamsPayload(p, event, w.Meta)
+		if err != nil {
+			return fmt.Errorf("GetMSTeamsPayload: %v", err)
+		}
+	case MATTERMOST:
+		payloader, err = GetMattermostPayload(p, event, w.Meta)
+		if err != nil {
+			return fmt.Errorf("GetMattermostPayload: %v", err)
+		}
 	default:
-		p.SetSecret(w.Secret)
+		// The secret is never sent, deliveries are signed with it instead.
 		payloader = p
 	}
 
@@ -567,6 +899,13 @@ func prepareWebhooks(e Engine, repo *Repository, event HookEventType, p api.Payl
 		ws = append(ws, orgHooks...)
 	}
 
+	// Add any admin-defined system webhooks
+	systemHooks, err := getActiveSystemWebhooks(e)
+	if err != nil {
+		return fmt.Errorf("getActiveSystemWebhooks: %v", err)
+	}
+	ws = append(ws, systemHooks...)
+
 	if len(ws) == 0 {
 		return nil
 	}
@@ -579,8 +918,61 @@ func prepareWebhooks(e Engine, repo *Repository, event HookEventType, p api.Payl
 	return nil
 }
 
+// hookTaskBackoff returns the delay before the next delivery attempt of a hook task
+// which has failed the given number of times.
+func hookTaskBackoff(attempts int) time.Duration {
+	backoff := setting.Webhook.RetryBackoff
+	for i := 1; i < attempts && backoff < setting.Webhook.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > setting.Webhook.MaxBackoff {
+		backoff = setting.Webhook.MaxBackoff
+	}
+	return backoff
+}
+
+// finishAttempt marks the hook task as delivered when the attempt succeeded or when it
+// was the last allowed one, otherwise it schedules the next attempt. It returns true
+// when the delivery has been given up.
+func (t *HookTask) finishAttempt() (givenUp bool) {
+	t.Delivered = time.Now().UnixNano()
+	if t.IsSucceed {
+		t.IsDelivered = true
+		log.Trace("Hook delivered: %s", t.UUID)
+	} else if t.Attempts >= setting.Webhook.MaxAttempts {
+		t.IsDelivered = true
+		givenUp = true
+		log.Warn("Hook delivery failed after %d attempts, giving up: %s", t.Attempts, t.UUID)
+	} else {
+		t.NextAttemptUnix = util.TimeStampNow().AddDuration(hookTaskBackoff(t.Attempts))
+		log.Trace("Hook delivery failed (attempt %d), retrying later: %s", t.Attempts, t.UUID)
+	}
+	return givenUp
+}
+
+// deliver makes one delivery attempt of the hook task. The task is marked as delivered
+// when the attempt succeeds or when it was the last allowed one, otherwise the next
+// attempt is scheduled.
 func (t *HookTask) deliver() {
-	t.IsDelivered = true
+	w, err := GetWebhookByID(t.HookID)
+	if err != nil {
+		log.Error(5, "GetWebhookByID [%d]: %v", t.HookID, err)
+		if IsErrWebhookNotExist(err) {
+			// The webhook is gone, there is nothing left to deliver to.
+			t.IsDelivered = true
+			return
+		}
+		// Any other error counts as a failed attempt, so the task is retried with backoff
+		// and eventually given up instead of being retried forever.
+		t.Attempts++
+		t.ResponseInfo = &HookResponse{
+			Headers: map[string]string{},
+			Body:    fmt.Sprintf("Get webhook: %v", err),
+		}
+		t.finishAttempt()
+		return
+	}
+	t.Attempts++
 
 	timeout := time.Duration(setting.Webhook.DeliverTimeout) * time.Second
 	req := httplib.Post(t.URL).SetTimeout(timeout, timeout).
@@ -592,11 +984,24 @@ func (t *HookTask) deliver() {
 		HeaderWithSensitiveCase("X-GitHub-Event", string(t.EventType)).
 		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: setting.Webhook.SkipTLSVerify})
 
+	var body string
 	switch t.ContentType {
 	case ContentTypeJSON:
-		req = req.Header("Content-Type", "application/json").Body(t.PayloadContent)
+		req = req.Header("Content-Type", "application/json")
+		body = t.PayloadContent
 	case ContentTypeForm:
-		req.Param("payload", t.PayloadContent)
+		req = req.Header("Content-Type", "application/x-www-form-urlencoded")
+		body = "payload=" + url.QueryEscape(t.PayloadContent)
+	}
+	req = req.Body(body)
+
+	// Sign the raw body so receivers can check the delivery comes from us and was not altered.
+	// Receivers written for Gogs, which no longer get the secret in the payload, check X-Gogs-Signature.
+	if len(w.Secret) > 0 {
+		signature := webhook.Sign(w.Secret, []byte(body))
+		req = req.Header(webhook.SignatureHeader, signature).
+			Header(webhook.GogsSignatureHeader, signature).
+			Header(webhook.GitHubSignatureHeader, webhook.GitHubSignature(w.Secret, []byte(body)))
 	}
 
 	// Record delivery information.
@@ -612,25 +1017,21 @@ func (t *HookTask) deliver() {
 	}
 
 	defer func() {
-		t.Delivered = time.Now().UnixNano()
-		if t.IsSucceed {
-			log.Trace("Hook delivered: %s", t.UUID)
-		} else {
-			log.Trace("Hook delivery failed: %s", t.UUID)
-		}
+		givenUp := t.finishAttempt()
 
 		// Update webhook last delivery status.
-		w, err := GetWebhookByID(t.HookID)
-		if err != nil {
-			log.Error(5, "GetWebhookByID: %v", err)
-			return
-		}
 		if t.IsSucceed {
 			w.LastStatus = HookStatusSucceed
+			w.IsFailing = false
+			w.FailingSinceUnix = 0
 		} else {
 			w.LastStatus = HookStatusFail
+			if givenUp && !w.IsFailing {
+				w.IsFailing = true
+				w.FailingSinceUnix = util.TimeStampNow()
+			}
 		}
-		if err = UpdateWebhookLastStatus(w); err != nil {
+		if err := UpdateWebhookLastStatus(w); err != nil {
 			log.Error(5, "UpdateWebhookLastStatus: %v", err)
 			return
 		}
@@ -658,47 +1059,80 @@ func (t *HookTask) deliver() {
 	t.ResponseInfo.Body = string(p)
 }
 
-// DeliverHooks checks and delivers undelivered hooks.
-// TODO: shoot more hooks at same time.
-func DeliverHooks() {
+var (
+	// hookTaskQueue feeds the due hook tasks to the delivery workers.
+	hookTaskQueue chan *HookTask
+	// hookTasksInDelivery holds the IDs of the hook tasks which are queued or being delivered,
+	// so a task is never handed to two workers at the same time.
+	hookTasksInDelivery = sync.NewStatusTable()
+)
+
+// queueDueHookTasks hands the undelivered hook tasks whose next attempt is due to the
+// delivery workers. All repositories are considered if repoID is 0.
+func queueDueHookTasks(repoID int64) {
 	tasks := make([]*HookTask, 0, 10)
-	err := x.Where("is_delivered=?", false).Find(&tasks)
-	if err != nil {
-		log.Error(4, "DeliverHooks: %v", err)
+	sess := x.Where("is_delivered=? AND next_attempt_unix<=?", false, util.TimeStampNow())
+	if repoID > 0 {
+		sess.And("repo_id=?", repoID)
+	}
+	if err := sess.Asc("id").Find(&tasks); err != nil {
+		log.Error(4, "Get hook tasks [repo_id: %d]: %v", repoID, err)
 		return
 	}
 
-	// Update hook task status.
 	for _, t := range tasks {
-		t.deliver()
+		if !hookTasksInDelivery.StartIfNotRunning(com.ToStr(t.ID)) {
+			continue
+		}
+
+		// Never wait for busy workers, that would also block everyone adding to HookQueue.
+		// The remaining tasks are still due and are picked up again by the next pass.
+		select {
+		case hookTaskQueue <- t:
+		default:
+			hookTasksInDelivery.Stop(com.ToStr(t.ID))
+			return
+		}
+	}
+}
 
+// deliverHookTasks delivers the hook tasks it receives from the queue until the queue is closed.
+func deliverHookTasks() {
+	for t := range hookTaskQueue {
+		t.deliver()
 		if err := UpdateHookTask(t); err != nil {
 			log.Error(4, "UpdateHookTask [%d]: %v", t.ID, err)
 		}
+		hookTasksInDelivery.Stop(com.ToStr(t.ID))
 	}
+}
 
-	// Start listening on new hook requests.
-	for repoIDStr := range HookQueue.Queue() {
-		log.Trace("DeliverHooks [repo_id: %v]", repoIDStr)
-		HookQueue.Remove(repoIDStr)
+// DeliverHooks starts the delivery workers and keeps handing them undelivered hook tasks,
+// either when new tasks are queued for a repository or when failed deliveries are due for a retry.
+func DeliverHooks() {
+	hookTaskQueue = make(chan *HookTask, setting.Webhook.QueueLength)
+	for i := 0; i < setting.Webhook.DeliverWorkers; i++ {
+		go deliverHookTasks()
+	}
 
-		repoID, err := com.StrTo(repoIDStr).Int64()
-		if err != nil {
-			log.Error(4, "Invalid repo ID: %s", repoIDStr)
-			continue
-		}
+	queueDueHookTasks(0)
 
-		tasks = make([]*HookTask, 0, 5)
-		if err := x.Where("repo_id=? AND is_delivered=?", repoID, false).Find(&tasks); err != nil {
-			log.Error(4, "Get repository [%s] hook tasks: %v", repoID, err)
-			continue
-		}
-		for _, t := range tasks {
-			t.deliver()
-			if err := UpdateHookTask(t); err != nil {
-				log.Error(4, "UpdateHookTask [%d]: %v", t.ID, err)
+	ticker := time.NewTicker(setting.Webhook.RetryBackoff)
+	defer ticker.Stop()
+	for {
+		select {
+		case repoIDStr := <-HookQueue.Queue():
+			log.Trace("DeliverHooks [repo_id: %v]", repoIDStr)
+			HookQueue.Remove(repoIDStr)
+
+			repoID, err := com.StrTo(repoIDStr).Int64()
+			if err != nil {
+				log.Error(4, "Invalid repo ID: %s", repoIDStr)
 				continue
 			}
+			queueDueHookTasks(repoID)
+		case <-ticker.C:
+			queueDueHookTasks(0)
 		}
 	}
 }
@@ -118,8 +118,12 @@ func getDingtalkPullRequestPayload(p *api.PullRequestPayload) (*DingtalkPayload,
 		title = fmt.Sprintf("[%s] Pull request edited: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
 		text = p.PullRequest.Body
 	case api.HookIssueAssigned:
+		assigneeNames := make([]string, len(p.PullRequest.Assignees))
+		for i, assignee := range p.PullRequest.Assignees {
+			assigneeNames[i] = assignee.UserName
+		}
 		title = fmt.Sprintf("[%s] Pull request assigned to %s: #%d %s", p.Repository.FullName,
-			p.PullRequest.Assignee.UserName, p.Index, p.PullRequest.Title)
+			strings.Join(assigneeNames, ", "), p.Index, p.PullRequest.Title)
 		text = p.PullRequest.Body
 	case api.HookIssueUnassigned:
 		title = fmt.Sprintf("[%s] Pull request unassigned: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
@@ -178,6 +182,157 @@ func getDingtalkRepositoryPayload(p *api.RepositoryPayload) (*DingtalkPayload, e
 	return nil, nil
 }
 
+func newDingtalkActionCard(title, text, singleTitle, singleURL string) *DingtalkPayload {
+	return &DingtalkPayload{
+		MsgType: "actionCard",
+		ActionCard: dingtalk.ActionCard{
+			Text:        text,
+			Title:       title,
+			HideAvatar:  "0",
+			SingleTitle: singleTitle,
+			SingleURL:   singleURL,
+		},
+	}
+}
+
+func getDingtalkDeletePayload(p *api.DeletePayload) (*DingtalkPayload, error) {
+	// deleted tag/branch
+	refName := git.RefEndName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName)
+
+	return newDingtalkActionCard(title, title, "view repository", p.Repo.HTMLURL), nil
+}
+
+func getDingtalkForkPayload(p *api.ForkPayload) (*DingtalkPayload, error) {
+	title := fmt.Sprintf("%s is forked to %s", p.Repo.FullName, p.Forkee.FullName)
+
+	return newDingtalkActionCard(title, title, "view forked repository", p.Forkee.HTMLURL), nil
+}
+
+func getDingtalkIssuesPayload(p *api.IssuePayload) (*DingtalkPayload, error) {
+	var text, title string
+	switch p.Action {
+	case api.HookIssueOpened:
+		title = fmt.Sprintf("[%s] Issue opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueClosed:
+		title = fmt.Sprintf("[%s] Issue closed: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueReOpened:
+		title = fmt.Sprintf("[%s] Issue re-opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueEdited:
+		title = fmt.Sprintf("[%s] Issue edited: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueAssigned:
+		assigneeNames := make([]string, len(p.Issue.Assignees))
+		for i, assignee := range p.Issue.Assignees {
+			assigneeNames[i] = assignee.UserName
+		}
+		title = fmt.Sprintf("[%s] Issue assigned to %s: #%d %s", p.Repository.FullName,
+			strings.Join(assigneeNames, ", "), p.Index, p.Issue.Title)
+	case api.HookIssueUnassigned:
+		title = fmt.Sprintf("[%s] Issue unassigned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueLabelUpdated:
+		title = fmt.Sprintf("[%s] Issue labels updated: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueLabelCleared:
+		title = fmt.Sprintf("[%s] Issue labels cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueMilestoned:
+		title = fmt.Sprintf("[%s] Issue milestoned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	case api.HookIssueDemilestoned:
+		title = fmt.Sprintf("[%s] Issue milestone cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+	}
+	text = p.Issue.Body
+
+	return newDingtalkActionCard(title, text, "view issue",
+		fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index)), nil
+}
+
+func getDingtalkIssueCommentPayload(p *api.IssueCommentPayload) (*DingtalkPayload, error) {
+	var text, title, url string
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		title = fmt.Sprintf("[%s] New comment on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+	case api.HookIssueCommentEdited:
+		title = fmt.Sprintf("[%s] Comment edited on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+	case api.HookIssueCommentDeleted:
+		title = fmt.Sprintf("[%s] Comment deleted on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = title
+		url = fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+	}
+
+	return newDingtalkActionCard(title, text, "view issue comment", url), nil
+}
+
+func getDingtalkReleasePayload(p *api.ReleasePayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Action {
+	case api.HookReleasePublished:
+		title = fmt.Sprintf("[%s] Release published: %s", p.Repository.FullName, p.Release.TagName)
+	case api.HookReleaseUpdated:
+		title = fmt.Sprintf("[%s] Release updated: %s", p.Repository.FullName, p.Release.TagName)
+	case api.HookReleaseDeleted:
+		title = fmt.Sprintf("[%s] Release deleted: %s", p.Repository.FullName, p.Release.TagName)
+	}
+
+	return newDingtalkActionCard(title, title, "view release",
+		p.Repository.HTMLURL+"/src/tag/"+p.Release.TagName), nil
+}
+
+func getDingtalkLabelPayload(p *LabelPayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Action {
+	case HookLabelCreated:
+		title = fmt.Sprintf("[%s] Label created: %s", p.Repository.FullName, p.Label.Name)
+	case HookLabelEdited:
+		title = fmt.Sprintf("[%s] Label edited: %s", p.Repository.FullName, p.Label.Name)
+	case HookLabelDeleted:
+		title = fmt.Sprintf("[%s] Label deleted: %s", p.Repository.FullName, p.Label.Name)
+	}
+
+	return newDingtalkActionCard(title, title, "view labels", p.Repository.HTMLURL+"/labels"), nil
+}
+
+func getDingtalkMilestonePayload(p *MilestonePayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Action {
+	case HookMilestoneCreated:
+		title = fmt.Sprintf("[%s] Milestone created: %s", p.Repository.FullName, p.Milestone.Title)
+	case HookMilestoneEdited:
+		title = fmt.Sprintf("[%s] Milestone edited: %s", p.Repository.FullName, p.Milestone.Title)
+	case HookMilestoneClosed:
+		title = fmt.Sprintf("[%s] Milestone closed: %s", p.Repository.FullName, p.Milestone.Title)
+	case HookMilestoneOpened:
+		title = fmt.Sprintf("[%s] Milestone re-opened: %s", p.Repository.FullName, p.Milestone.Title)
+	case HookMilestoneDeleted:
+		title = fmt.Sprintf("[%s] Milestone deleted: %s", p.Repository.FullName, p.Milestone.Title)
+	}
+
+	text := p.Milestone.Description
+	if len(text) == 0 {
+		text = title
+	}
+	return newDingtalkActionCard(title, text, "view milestones", p.Repository.HTMLURL+"/milestones"), nil
+}
+
+func getDingtalkPullRequestReviewPayload(p *PullRequestReviewPayload) (*DingtalkPayload, error) {
+	var title string
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		title = fmt.Sprintf("[%s] Pull request approved: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+	case api.ReviewStateRequestChanges:
+		title = fmt.Sprintf("[%s] Pull request changes requested: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+	default:
+		title = fmt.Sprintf("[%s] Pull request reviewed: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+	}
+
+	text := p.Review.Body
+	if len(text) == 0 {
+		text = title
+	}
+	return newDingtalkActionCard(title, text, "view review", p.Review.HTMLURL), nil
+}
+
 // GetDingtalkPayload converts a ding talk webhook into a DingtalkPayload
 func GetDingtalkPayload(p api.Payloader, event HookEventType, meta string) (*DingtalkPayload, error) {
 	s := new(DingtalkPayload)
@@ -185,12 +340,28 @@ func GetDingtalkPayload(p api.Payloader, event HookEventType, meta string) (*Din
 	switch event {
 	case HookEventCreate:
 		return getDingtalkCreatePayload(p.(*api.CreatePayload))
+	case HookEventDelete:
+		return getDingtalkDeletePayload(p.(*api.DeletePayload))
+	case HookEventFork:
+		return getDingtalkForkPayload(p.(*api.ForkPayload))
+	case HookEventIssues:
+		return getDingtalkIssuesPayload(p.(*api.IssuePayload))
+	case HookEventIssueComment:
+		return getDingtalkIssueCommentPayload(p.(*api.IssueCommentPayload))
 	case HookEventPush:
 		return getDingtalkPushPayload(p.(*api.PushPayload))
 	case HookEventPullRequest:
 		return getDingtalkPullRequestPayload(p.(*api.PullRequestPayload))
+	case HookEventPullRequestReview:
+		return getDingtalkPullRequestReviewPayload(p.(*PullRequestReviewPayload))
 	case HookEventRepository:
 		return getDingtalkRepositoryPayload(p.(*api.RepositoryPayload))
+	case HookEventRelease:
+		return getDingtalkReleasePayload(p.(*api.ReleasePayload))
+	case HookEventLabel:
+		return getDingtalkLabelPayload(p.(*LabelPayload))
+	case HookEventMilestone:
+		return getDingtalkMilestonePayload(p.(*MilestonePayload))
 	}
 
 	return s, nil
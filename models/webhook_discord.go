@@ -191,8 +191,12 @@ func getDiscordPullRequestPayload(p *api.PullRequestPayload, meta *DiscordMeta)
 		text = p.PullRequest.Body
 		color = warnColor
 	case api.HookIssueAssigned:
+		assigneeNames := make([]string, len(p.PullRequest.Assignees))
+		for i, assignee := range p.PullRequest.Assignees {
+			assigneeNames[i] = assignee.UserName
+		}
 		title = fmt.Sprintf("[%s] Pull request assigned to %s: #%d %s", p.Repository.FullName,
-			p.PullRequest.Assignee.UserName, p.Index, p.PullRequest.Title)
+			strings.Join(assigneeNames, ", "), p.Index, p.PullRequest.Title)
 		text = p.PullRequest.Body
 		color = successColor
 	case api.HookIssueUnassigned:
@@ -263,6 +267,278 @@ func getDiscordRepositoryPayload(p *api.RepositoryPayload, meta *DiscordMeta) (*
 	}, nil
 }
 
+func discordAuthor(sender *api.User) DiscordEmbedAuthor {
+	return DiscordEmbedAuthor{
+		Name:    sender.UserName,
+		URL:     setting.AppURL + sender.UserName,
+		IconURL: sender.AvatarURL,
+	}
+}
+
+func getDiscordDeletePayload(p *api.DeletePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	// deleted tag/branch
+	refName := git.RefEndName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName)
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:  title,
+				URL:    p.Repo.HTMLURL,
+				Color:  warnColor,
+				Author: discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordForkPayload(p *api.ForkPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	title := fmt.Sprintf("%s is forked to %s", p.Repo.FullName, p.Forkee.FullName)
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:  title,
+				URL:    p.Forkee.HTMLURL,
+				Color:  successColor,
+				Author: discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordIssuesPayload(p *api.IssuePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var text, title string
+	var color int
+	switch p.Action {
+	case api.HookIssueOpened:
+		title = fmt.Sprintf("[%s] Issue opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueClosed:
+		title = fmt.Sprintf("[%s] Issue closed: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = failedColor
+		text = p.Issue.Body
+	case api.HookIssueReOpened:
+		title = fmt.Sprintf("[%s] Issue re-opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueEdited:
+		title = fmt.Sprintf("[%s] Issue edited: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueAssigned:
+		assigneeNames := make([]string, len(p.Issue.Assignees))
+		for i, assignee := range p.Issue.Assignees {
+			assigneeNames[i] = assignee.UserName
+		}
+		title = fmt.Sprintf("[%s] Issue assigned to %s: #%d %s", p.Repository.FullName,
+			strings.Join(assigneeNames, ", "), p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = successColor
+	case api.HookIssueUnassigned:
+		title = fmt.Sprintf("[%s] Issue unassigned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueLabelUpdated:
+		title = fmt.Sprintf("[%s] Issue labels updated: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueLabelCleared:
+		title = fmt.Sprintf("[%s] Issue labels cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueMilestoned:
+		title = fmt.Sprintf("[%s] Issue milestoned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueDemilestoned:
+		title = fmt.Sprintf("[%s] Issue milestone cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index),
+				Color:       color,
+				Author:      discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordIssueCommentPayload(p *api.IssueCommentPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var text, title, url string
+	var color int
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		title = fmt.Sprintf("[%s] New comment on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+		color = successColor
+	case api.HookIssueCommentEdited:
+		title = fmt.Sprintf("[%s] Comment edited on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+		color = warnColor
+	case api.HookIssueCommentDeleted:
+		title = fmt.Sprintf("[%s] Comment deleted on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		url = fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+		color = failedColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         url,
+				Color:       color,
+				Author:      discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordReleasePayload(p *api.ReleasePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title string
+	var color int
+	switch p.Action {
+	case api.HookReleasePublished:
+		title = fmt.Sprintf("[%s] Release published: %s", p.Repository.FullName, p.Release.TagName)
+		color = successColor
+	case api.HookReleaseUpdated:
+		title = fmt.Sprintf("[%s] Release updated: %s", p.Repository.FullName, p.Release.TagName)
+		color = warnColor
+	case api.HookReleaseDeleted:
+		title = fmt.Sprintf("[%s] Release deleted: %s", p.Repository.FullName, p.Release.TagName)
+		color = failedColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: p.Release.Note,
+				URL:         p.Repository.HTMLURL + "/src/tag/" + p.Release.TagName,
+				Color:       color,
+				Author:      discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordLabelPayload(p *LabelPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title string
+	var color int
+	switch p.Action {
+	case HookLabelCreated:
+		title = fmt.Sprintf("[%s] Label created: %s", p.Repository.FullName, p.Label.Name)
+		color = successColor
+	case HookLabelEdited:
+		title = fmt.Sprintf("[%s] Label edited: %s", p.Repository.FullName, p.Label.Name)
+		color = warnColor
+	case HookLabelDeleted:
+		title = fmt.Sprintf("[%s] Label deleted: %s", p.Repository.FullName, p.Label.Name)
+		color = failedColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:  title,
+				URL:    p.Repository.HTMLURL + "/labels",
+				Color:  color,
+				Author: discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordMilestonePayload(p *MilestonePayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title, text string
+	var color int
+	switch p.Action {
+	case HookMilestoneCreated:
+		title = fmt.Sprintf("[%s] Milestone created: %s", p.Repository.FullName, p.Milestone.Title)
+		text = p.Milestone.Description
+		color = successColor
+	case HookMilestoneEdited:
+		title = fmt.Sprintf("[%s] Milestone edited: %s", p.Repository.FullName, p.Milestone.Title)
+		text = p.Milestone.Description
+		color = warnColor
+	case HookMilestoneClosed:
+		title = fmt.Sprintf("[%s] Milestone closed: %s", p.Repository.FullName, p.Milestone.Title)
+		color = successColor
+	case HookMilestoneOpened:
+		title = fmt.Sprintf("[%s] Milestone re-opened: %s", p.Repository.FullName, p.Milestone.Title)
+		color = warnColor
+	case HookMilestoneDeleted:
+		title = fmt.Sprintf("[%s] Milestone deleted: %s", p.Repository.FullName, p.Milestone.Title)
+		color = failedColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         p.Repository.HTMLURL + "/milestones",
+				Color:       color,
+				Author:      discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
+func getDiscordPullRequestReviewPayload(p *PullRequestReviewPayload, meta *DiscordMeta) (*DiscordPayload, error) {
+	var title string
+	var color int
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		title = fmt.Sprintf("[%s] Pull request approved: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+		color = successColor
+	case api.ReviewStateRequestChanges:
+		title = fmt.Sprintf("[%s] Pull request changes requested: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+		color = failedColor
+	default:
+		title = fmt.Sprintf("[%s] Pull request reviewed: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+		color = warnColor
+	}
+
+	return &DiscordPayload{
+		Username:  meta.Username,
+		AvatarURL: meta.IconURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: p.Review.Body,
+				URL:         p.Review.HTMLURL,
+				Color:       color,
+				Author:      discordAuthor(p.Sender),
+			},
+		},
+	}, nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (*DiscordPayload, error) {
 	s := new(DiscordPayload)
@@ -275,12 +551,28 @@ func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (*Disc
 	switch event {
 	case HookEventCreate:
 		return getDiscordCreatePayload(p.(*api.CreatePayload), discord)
+	case HookEventDelete:
+		return getDiscordDeletePayload(p.(*api.DeletePayload), discord)
+	case HookEventFork:
+		return getDiscordForkPayload(p.(*api.ForkPayload), discord)
+	case HookEventIssues:
+		return getDiscordIssuesPayload(p.(*api.IssuePayload), discord)
+	case HookEventIssueComment:
+		return getDiscordIssueCommentPayload(p.(*api.IssueCommentPayload), discord)
 	case HookEventPush:
 		return getDiscordPushPayload(p.(*api.PushPayload), discord)
 	case HookEventPullRequest:
 		return getDiscordPullRequestPayload(p.(*api.PullRequestPayload), discord)
+	case HookEventPullRequestReview:
+		return getDiscordPullRequestReviewPayload(p.(*PullRequestReviewPayload), discord)
 	case HookEventRepository:
 		return getDiscordRepositoryPayload(p.(*api.RepositoryPayload), discord)
+	case HookEventRelease:
+		return getDiscordReleasePayload(p.(*api.ReleasePayload), discord)
+	case HookEventLabel:
+		return getDiscordLabelPayload(p.(*LabelPayload), discord)
+	case HookEventMilestone:
+		return getDiscordMilestonePayload(p.(*MilestonePayload), discord)
 	}
 
 	return s, nil
@@ -0,0 +1,386 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// MattermostMeta contains the mattermost metadata
+type MattermostMeta struct {
+	Channel  string `json:"channel"`
+	Username string `json:"username"`
+	IconURL  string `json:"icon_url"`
+	Color    string `json:"color"`
+}
+
+// MattermostPayload represents the payload of a mattermost incoming webhook
+type MattermostPayload struct {
+	Text        string                 `json:"text"`
+	Channel     string                 `json:"channel,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	IconURL     string                 `json:"icon_url,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+}
+
+// MattermostAttachment contains the mattermost message attachment
+type MattermostAttachment struct {
+	Fallback   string `json:"fallback"`
+	Color      string `json:"color"`
+	AuthorName string `json:"author_name"`
+	AuthorLink string `json:"author_link"`
+	AuthorIcon string `json:"author_icon"`
+	Title      string `json:"title"`
+	TitleLink  string `json:"title_link"`
+	Text       string `json:"text"`
+}
+
+// SetSecret sets the mattermost secret
+func (p *MattermostPayload) SetSecret(_ string) {}
+
+// JSONPayload Marshals the MattermostPayload to json
+func (p *MattermostPayload) JSONPayload() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// MattermostLinkFormatter creates a markdown link, which is what mattermost renders
+func MattermostLinkFormatter(url string, text string) string {
+	return fmt.Sprintf("[%s](%s)", strings.Replace(text, "]", "\\]", -1), url)
+}
+
+// MattermostLinkToRef mattermost-formatter link to a repo ref
+func MattermostLinkToRef(repoURL, ref string) string {
+	refName := git.RefEndName(ref)
+	switch {
+	case strings.HasPrefix(ref, git.BranchPrefix):
+		return MattermostLinkFormatter(repoURL+"/src/branch/"+refName, refName)
+	case strings.HasPrefix(ref, git.TagPrefix):
+		return MattermostLinkFormatter(repoURL+"/src/tag/"+refName, refName)
+	default:
+		return MattermostLinkFormatter(repoURL+"/src/commit/"+refName, refName)
+	}
+}
+
+// newMattermostPayload returns a payload with text as message and, when title or
+// attachmentText is given, an attachment authored by the sender of the event
+func newMattermostPayload(meta *MattermostMeta, sender *api.User, text, title, titleLink, attachmentText string) *MattermostPayload {
+	payload := &MattermostPayload{
+		Text:     text,
+		Channel:  meta.Channel,
+		Username: meta.Username,
+		IconURL:  meta.IconURL,
+	}
+	if len(title) > 0 || len(attachmentText) > 0 {
+		payload.Attachments = []MattermostAttachment{{
+			Fallback:   text,
+			Color:      meta.Color,
+			AuthorName: sender.UserName,
+			AuthorLink: setting.AppURL + sender.UserName,
+			AuthorIcon: sender.AvatarURL,
+			Title:      title,
+			TitleLink:  titleLink,
+			Text:       attachmentText,
+		}}
+	}
+	return payload
+}
+
+func getMattermostCreatePayload(p *api.CreatePayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	repoLink := MattermostLinkFormatter(p.Repo.HTMLURL, p.Repo.FullName)
+	refLink := MattermostLinkToRef(p.Repo.HTMLURL, p.Ref)
+	text := fmt.Sprintf("[%s:%s] %s created by %s", repoLink, refLink, p.RefType, p.Sender.UserName)
+
+	return newMattermostPayload(meta, p.Sender, text, "", "", ""), nil
+}
+
+func getMattermostDeletePayload(p *api.DeletePayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	repoLink := MattermostLinkFormatter(p.Repo.HTMLURL, p.Repo.FullName)
+	text := fmt.Sprintf("[%s:%s] %s deleted by %s", repoLink, git.RefEndName(p.Ref), p.RefType, p.Sender.UserName)
+
+	return newMattermostPayload(meta, p.Sender, text, "", "", ""), nil
+}
+
+func getMattermostForkPayload(p *api.ForkPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	baseLink := MattermostLinkFormatter(p.Repo.HTMLURL, p.Repo.FullName)
+	forkLink := MattermostLinkFormatter(p.Forkee.HTMLURL, p.Forkee.FullName)
+	text := fmt.Sprintf("%s is forked to %s", baseLink, forkLink)
+
+	return newMattermostPayload(meta, p.Sender, text, "", "", ""), nil
+}
+
+func getMattermostPushPayload(p *api.PushPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	var commitDesc string
+	if len(p.Commits) == 1 {
+		commitDesc = "1 new commit"
+	} else {
+		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+	}
+	if len(p.CompareURL) > 0 {
+		commitDesc = MattermostLinkFormatter(p.CompareURL, commitDesc)
+	}
+
+	repoLink := MattermostLinkFormatter(p.Repo.HTMLURL, p.Repo.FullName)
+	branchLink := MattermostLinkToRef(p.Repo.HTMLURL, p.Ref)
+	text := fmt.Sprintf("[%s:%s] %s pushed by %s", repoLink, branchLink, commitDesc, p.Pusher.UserName)
+
+	commitLines := make([]string, len(p.Commits))
+	for i, commit := range p.Commits {
+		var authorName string
+		if commit.Author != nil {
+			authorName = " - " + commit.Author.Name
+		}
+		commitLines[i] = fmt.Sprintf("%s: %s", MattermostLinkFormatter(commit.URL, commit.ID[:7]),
+			strings.Split(commit.Message, "\n")[0]) + authorName
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, "", "", strings.Join(commitLines, "\n")), nil
+}
+
+func getMattermostIssuesPayload(p *api.IssuePayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	issueURL := fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index)
+	issueTitle := fmt.Sprintf("#%d %s", p.Index, p.Issue.Title)
+	titleLink := MattermostLinkFormatter(issueURL, issueTitle)
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueOpened:
+		text = fmt.Sprintf("[%s] Issue submitted by %s", p.Repository.FullName, senderLink)
+		title = issueTitle
+		attachmentText = p.Issue.Body
+	case api.HookIssueClosed:
+		text = fmt.Sprintf("[%s] Issue closed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueReOpened:
+		text = fmt.Sprintf("[%s] Issue re-opened: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueEdited:
+		text = fmt.Sprintf("[%s] Issue edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		title = issueTitle
+		attachmentText = p.Issue.Body
+	case api.HookIssueAssigned:
+		assigneeLinks := make([]string, len(p.Issue.Assignees))
+		for i, assignee := range p.Issue.Assignees {
+			assigneeLinks[i] = MattermostLinkFormatter(setting.AppURL+assignee.UserName, assignee.UserName)
+		}
+		text = fmt.Sprintf("[%s] Issue assigned to %s: %s by %s", p.Repository.FullName,
+			strings.Join(assigneeLinks, ", "), titleLink, senderLink)
+	case api.HookIssueUnassigned:
+		text = fmt.Sprintf("[%s] Issue unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelUpdated:
+		text = fmt.Sprintf("[%s] Issue labels updated: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelCleared:
+		text = fmt.Sprintf("[%s] Issue labels cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueMilestoned:
+		text = fmt.Sprintf("[%s] Issue milestoned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueDemilestoned:
+		text = fmt.Sprintf("[%s] Issue milestone cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, title, issueURL, attachmentText), nil
+}
+
+func getMattermostIssueCommentPayload(p *api.IssueCommentPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	issueTitle := fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title)
+	titleLink := MattermostLinkFormatter(fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index), issueTitle)
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		text = fmt.Sprintf("[%s] New comment on %s by %s", p.Repository.FullName, titleLink, senderLink)
+		title = issueTitle
+		attachmentText = p.Comment.Body
+	case api.HookIssueCommentEdited:
+		text = fmt.Sprintf("[%s] Comment edited on %s by %s", p.Repository.FullName, titleLink, senderLink)
+		title = issueTitle
+		attachmentText = p.Comment.Body
+	case api.HookIssueCommentDeleted:
+		text = fmt.Sprintf("[%s] Comment deleted on %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, title, p.Comment.HTMLURL, attachmentText), nil
+}
+
+func getMattermostPullRequestPayload(p *api.PullRequestPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	prTitle := fmt.Sprintf("#%d %s", p.Index, p.PullRequest.Title)
+	titleLink := MattermostLinkFormatter(p.PullRequest.HTMLURL, prTitle)
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueOpened:
+		text = fmt.Sprintf("[%s] Pull request submitted by %s", p.Repository.FullName, senderLink)
+		title = prTitle
+		attachmentText = p.PullRequest.Body
+	case api.HookIssueClosed:
+		if p.PullRequest.HasMerged {
+			text = fmt.Sprintf("[%s] Pull request merged: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		} else {
+			text = fmt.Sprintf("[%s] Pull request closed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		}
+	case api.HookIssueReOpened:
+		text = fmt.Sprintf("[%s] Pull request re-opened: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueEdited:
+		text = fmt.Sprintf("[%s] Pull request edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		title = prTitle
+		attachmentText = p.PullRequest.Body
+	case api.HookIssueAssigned:
+		assigneeLinks := make([]string, len(p.PullRequest.Assignees))
+		for i, assignee := range p.PullRequest.Assignees {
+			assigneeLinks[i] = MattermostLinkFormatter(setting.AppURL+assignee.UserName, assignee.UserName)
+		}
+		text = fmt.Sprintf("[%s] Pull request assigned to %s: %s by %s", p.Repository.FullName,
+			strings.Join(assigneeLinks, ", "), titleLink, senderLink)
+	case api.HookIssueUnassigned:
+		text = fmt.Sprintf("[%s] Pull request unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelUpdated:
+		text = fmt.Sprintf("[%s] Pull request labels updated: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelCleared:
+		text = fmt.Sprintf("[%s] Pull request labels cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueSynchronized:
+		text = fmt.Sprintf("[%s] Pull request synchronized: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, title, p.PullRequest.HTMLURL, attachmentText), nil
+}
+
+func getMattermostPullRequestReviewPayload(p *PullRequestReviewPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	prTitle := fmt.Sprintf("#%d %s", p.PullRequest.Index, p.PullRequest.Title)
+	titleLink := MattermostLinkFormatter(p.Review.HTMLURL, prTitle)
+	var text string
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		text = fmt.Sprintf("[%s] Pull request approved: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.ReviewStateRequestChanges:
+		text = fmt.Sprintf("[%s] Pull request changes requested: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	default:
+		text = fmt.Sprintf("[%s] Pull request reviewed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, "", p.Review.HTMLURL, p.Review.Body), nil
+}
+
+func getMattermostRepositoryPayload(p *api.RepositoryPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	var text string
+	switch p.Action {
+	case api.HookRepoCreated:
+		text = fmt.Sprintf("[%s] Repository created by %s",
+			MattermostLinkFormatter(p.Repository.HTMLURL, p.Repository.FullName), senderLink)
+	case api.HookRepoDeleted:
+		text = fmt.Sprintf("[%s] Repository deleted by %s", p.Repository.FullName, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, "", "", ""), nil
+}
+
+func getMattermostReleasePayload(p *api.ReleasePayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	repoLink := MattermostLinkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	tagURL := p.Repository.HTMLURL + "/src/tag/" + p.Release.TagName
+	refLink := MattermostLinkFormatter(tagURL, p.Release.TagName)
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookReleasePublished:
+		text = fmt.Sprintf("[%s] Release %s published by %s", repoLink, refLink, senderLink)
+		title = p.Release.Title
+		attachmentText = p.Release.Note
+	case api.HookReleaseUpdated:
+		text = fmt.Sprintf("[%s] Release %s updated by %s", repoLink, refLink, senderLink)
+		title = p.Release.Title
+		attachmentText = p.Release.Note
+	case api.HookReleaseDeleted:
+		text = fmt.Sprintf("[%s] Release %s deleted by %s", repoLink, p.Release.TagName, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, title, tagURL, attachmentText), nil
+}
+
+func getMattermostLabelPayload(p *LabelPayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	labelLink := MattermostLinkFormatter(p.Repository.HTMLURL+"/labels", p.Label.Name)
+	var text string
+	switch p.Action {
+	case HookLabelCreated:
+		text = fmt.Sprintf("[%s] Label %s created by %s", p.Repository.FullName, labelLink, senderLink)
+	case HookLabelEdited:
+		text = fmt.Sprintf("[%s] Label %s edited by %s", p.Repository.FullName, labelLink, senderLink)
+	case HookLabelDeleted:
+		text = fmt.Sprintf("[%s] Label %s deleted by %s", p.Repository.FullName, p.Label.Name, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, "", "", ""), nil
+}
+
+func getMattermostMilestonePayload(p *MilestonePayload, meta *MattermostMeta) (*MattermostPayload, error) {
+	senderLink := MattermostLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	milestonesURL := p.Repository.HTMLURL + "/milestones"
+	milestoneLink := MattermostLinkFormatter(milestonesURL, p.Milestone.Title)
+	var text, attachmentText string
+	switch p.Action {
+	case HookMilestoneCreated:
+		text = fmt.Sprintf("[%s] Milestone %s created by %s", p.Repository.FullName, milestoneLink, senderLink)
+		attachmentText = p.Milestone.Description
+	case HookMilestoneEdited:
+		text = fmt.Sprintf("[%s] Milestone %s edited by %s", p.Repository.FullName, milestoneLink, senderLink)
+		attachmentText = p.Milestone.Description
+	case HookMilestoneClosed:
+		text = fmt.Sprintf("[%s] Milestone %s closed by %s", p.Repository.FullName, milestoneLink, senderLink)
+	case HookMilestoneOpened:
+		text = fmt.Sprintf("[%s] Milestone %s re-opened by %s", p.Repository.FullName, milestoneLink, senderLink)
+	case HookMilestoneDeleted:
+		text = fmt.Sprintf("[%s] Milestone %s deleted by %s", p.Repository.FullName, p.Milestone.Title, senderLink)
+	}
+
+	return newMattermostPayload(meta, p.Sender, text, "", milestonesURL, attachmentText), nil
+}
+
+// GetMattermostPayload converts a mattermost webhook into a MattermostPayload
+func GetMattermostPayload(p api.Payloader, event HookEventType, meta string) (*MattermostPayload, error) {
+	s := new(MattermostPayload)
+
+	mattermost := &MattermostMeta{}
+	if err := json.Unmarshal([]byte(meta), &mattermost); err != nil {
+		return s, errors.New("GetMattermostPayload meta json:" + err.Error())
+	}
+
+	switch event {
+	case HookEventCreate:
+		return getMattermostCreatePayload(p.(*api.CreatePayload), mattermost)
+	case HookEventDelete:
+		return getMattermostDeletePayload(p.(*api.DeletePayload), mattermost)
+	case HookEventFork:
+		return getMattermostForkPayload(p.(*api.ForkPayload), mattermost)
+	case HookEventIssues:
+		return getMattermostIssuesPayload(p.(*api.IssuePayload), mattermost)
+	case HookEventIssueComment:
+		return getMattermostIssueCommentPayload(p.(*api.IssueCommentPayload), mattermost)
+	case HookEventPush:
+		return getMattermostPushPayload(p.(*api.PushPayload), mattermost)
+	case HookEventPullRequest:
+		return getMattermostPullRequestPayload(p.(*api.PullRequestPayload), mattermost)
+	case HookEventPullRequestReview:
+		return getMattermostPullRequestReviewPayload(p.(*PullRequestReviewPayload), mattermost)
+	case HookEventRepository:
+		return getMattermostRepositoryPayload(p.(*api.RepositoryPayload), mattermost)
+	case HookEventRelease:
+		return getMattermostReleasePayload(p.(*api.ReleasePayload), mattermost)
+	case HookEventLabel:
+		return getMattermostLabelPayload(p.(*LabelPayload), mattermost)
+	case HookEventMilestone:
+		return getMattermostMilestonePayload(p.(*MilestonePayload), mattermost)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMattermostMeta = `{"channel":"town-square","username":"Gitea","icon_url":"http://localhost:3000/img/favicon.png","color":"#dd4b39"}`
+
+func TestMattermostLinkFormatter(t *testing.T) {
+	assert.Equal(t, "[text](http://example.com)", MattermostLinkFormatter("http://example.com", "text"))
+	assert.Equal(t, "[[WIP\\] text](http://example.com)", MattermostLinkFormatter("http://example.com", "[WIP] text"))
+}
+
+func TestGetMattermostPayload_InvalidMeta(t *testing.T) {
+	_, err := GetMattermostPayload(testHookPushPayload(), HookEventPush, "{")
+	assert.Error(t, err)
+}
+
+func TestGetMattermostPayload_Push(t *testing.T) {
+	pl, err := GetMattermostPayload(testHookPushPayload(), HookEventPush, testMattermostMeta)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "town-square", pl.Channel)
+	assert.Equal(t, "Gitea", pl.Username)
+	assert.Equal(t, "http://localhost:3000/img/favicon.png", pl.IconURL)
+	assert.Equal(t, "[[test/repo](http://localhost:3000/test/repo):[master](http://localhost:3000/test/repo/src/branch/master)] "+
+		"[2 new commits](http://localhost:3000/test/repo/compare/2020558...2020558) pushed by user1", pl.Text)
+	if assert.Len(t, pl.Attachments, 1) {
+		attachment := pl.Attachments[0]
+		assert.Equal(t, "#dd4b39", attachment.Color)
+		assert.Equal(t, "user1", attachment.AuthorName)
+		assert.Equal(t, "http://localhost:3000/avatars/1", attachment.AuthorIcon)
+		assert.Equal(t, "[2020558](http://localhost:3000/test/repo/commit/2020558fe2e34debb818a514715839cabd25e778): commit message - user1\n"+
+			"[2020558](http://localhost:3000/test/repo/commit/2020558fe2e34debb818a514715839cabd25e779): second commit - user1", attachment.Text)
+	}
+}
+
+func TestGetMattermostPayload_Issues(t *testing.T) {
+	pl, err := GetMattermostPayload(testHookIssuePayload(api.HookIssueOpened), HookEventIssues, testMattermostMeta)
+	assert.NoError(t, err)
+	assert.Contains(t, pl.Text, "[test/repo] Issue submitted by ")
+	if assert.Len(t, pl.Attachments, 1) {
+		assert.Equal(t, "#2 crash", pl.Attachments[0].Title)
+		assert.Equal(t, "http://localhost:3000/test/repo/issues/2", pl.Attachments[0].TitleLink)
+		assert.Equal(t, "issue body", pl.Attachments[0].Text)
+	}
+
+	pl, err = GetMattermostPayload(testHookIssuePayload(api.HookIssueClosed), HookEventIssues, testMattermostMeta)
+	assert.NoError(t, err)
+	assert.Contains(t, pl.Text, "[test/repo] Issue closed: [#2 crash](http://localhost:3000/test/repo/issues/2) by ")
+	assert.Empty(t, pl.Attachments)
+}
+
+func TestGetMattermostPayload_Milestone(t *testing.T) {
+	p := &MilestonePayload{
+		Action:     HookMilestoneCreated,
+		Milestone:  &api.Milestone{Title: "v1.0", Description: "first release"},
+		Repository: testHookRepository(),
+		Sender:     testHookSender(),
+	}
+	pl, err := GetMattermostPayload(p, HookEventMilestone, testMattermostMeta)
+	assert.NoError(t, err)
+	assert.Contains(t, pl.Text, "[test/repo] Milestone [v1.0](http://localhost:3000/test/repo/milestones) created by ")
+	if assert.Len(t, pl.Attachments, 1) {
+		assert.Equal(t, "first release", pl.Attachments[0].Text)
+	}
+}
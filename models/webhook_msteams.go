@@ -0,0 +1,410 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+type (
+	// MSTeamsFact for Fact Structure
+	MSTeamsFact struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	// MSTeamsSection is a MessageCard section
+	MSTeamsSection struct {
+		ActivityTitle    string        `json:"activityTitle"`
+		ActivitySubtitle string        `json:"activitySubtitle"`
+		ActivityImage    string        `json:"activityImage"`
+		Facts            []MSTeamsFact `json:"facts"`
+		Text             string        `json:"text"`
+	}
+
+	// MSTeamsAction is an action (creates buttons, links etc)
+	MSTeamsAction struct {
+		Type    string                `json:"@type"`
+		Name    string                `json:"name"`
+		Targets []MSTeamsActionTarget `json:"targets,omitempty"`
+	}
+
+	// MSTeamsActionTarget is the actual link to follow, etc
+	MSTeamsActionTarget struct {
+		Os  string `json:"os"`
+		URI string `json:"uri"`
+	}
+
+	// MSTeamsPayload is the parent object of a Microsoft Teams connector MessageCard
+	MSTeamsPayload struct {
+		Type            string           `json:"@type"`
+		Context         string           `json:"@context"`
+		ThemeColor      string           `json:"themeColor"`
+		Title           string           `json:"title"`
+		Summary         string           `json:"summary"`
+		Sections        []MSTeamsSection `json:"sections"`
+		PotentialAction []MSTeamsAction  `json:"potentialAction"`
+	}
+)
+
+// SetSecret sets the MSTeams secret
+func (p *MSTeamsPayload) SetSecret(_ string) {}
+
+// JSONPayload Marshals the MSTeamsPayload to json
+func (p *MSTeamsPayload) JSONPayload() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// newMSTeamsPayload returns a MessageCard with a single section describing the event
+// and a button opening actionURL
+func newMSTeamsPayload(repo *api.Repository, sender *api.User, title, text, actionURL string, color int) *MSTeamsPayload {
+	facts := []MSTeamsFact{{
+		Name:  "Repository:",
+		Value: repo.FullName,
+	}}
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%x", color),
+		Title:      title,
+		Summary:    title,
+		Sections: []MSTeamsSection{{
+			ActivityTitle:    sender.FullName,
+			ActivitySubtitle: sender.UserName,
+			ActivityImage:    sender.AvatarURL,
+			Text:             text,
+			Facts:            facts,
+		}},
+		PotentialAction: []MSTeamsAction{{
+			Type: "OpenUri",
+			Name: "View in Gitea",
+			Targets: []MSTeamsActionTarget{{
+				Os:  "default",
+				URI: actionURL,
+			}},
+		}},
+	}
+}
+
+func getMSTeamsCreatePayload(p *api.CreatePayload) (*MSTeamsPayload, error) {
+	// created tag/branch
+	refName := git.RefEndName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s created", p.Repo.FullName, p.RefType, refName)
+
+	return newMSTeamsPayload(p.Repo, p.Sender, title, "", p.Repo.HTMLURL+"/src/"+refName, successColor), nil
+}
+
+func getMSTeamsDeletePayload(p *api.DeletePayload) (*MSTeamsPayload, error) {
+	// deleted tag/branch
+	refName := git.RefEndName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName)
+
+	return newMSTeamsPayload(p.Repo, p.Sender, title, "", p.Repo.HTMLURL, warnColor), nil
+}
+
+func getMSTeamsForkPayload(p *api.ForkPayload) (*MSTeamsPayload, error) {
+	title := fmt.Sprintf("%s is forked to %s", p.Repo.FullName, p.Forkee.FullName)
+
+	return newMSTeamsPayload(p.Repo, p.Sender, title, "", p.Forkee.HTMLURL, successColor), nil
+}
+
+func getMSTeamsPushPayload(p *api.PushPayload) (*MSTeamsPayload, error) {
+	var (
+		branchName = git.RefEndName(p.Ref)
+		commitDesc string
+	)
+
+	var titleLink string
+	if len(p.Commits) == 1 {
+		commitDesc = "1 new commit"
+		titleLink = p.Commits[0].URL
+	} else {
+		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+		titleLink = p.CompareURL
+	}
+	if titleLink == "" {
+		titleLink = p.Repo.HTMLURL + "/src/" + branchName
+	}
+
+	title := fmt.Sprintf("[%s:%s] %s", p.Repo.FullName, branchName, commitDesc)
+
+	var text string
+	// for each commit, generate a line of text
+	for i, commit := range p.Commits {
+		var authorName string
+		if commit.Author != nil {
+			authorName = " - " + commit.Author.Name
+		}
+		text += fmt.Sprintf("[%s](%s) %s", commit.ID[:7], commit.URL,
+			strings.TrimRight(commit.Message, "\r\n")) + authorName
+		// add linebreak to each commit but the last
+		if i < len(p.Commits)-1 {
+			text += "\n\n"
+		}
+	}
+
+	return newMSTeamsPayload(p.Repo, p.Sender, title, text, titleLink, successColor), nil
+}
+
+func getMSTeamsIssuesPayload(p *api.IssuePayload) (*MSTeamsPayload, error) {
+	var text, title string
+	var color int
+	switch p.Action {
+	case api.HookIssueOpened:
+		title = fmt.Sprintf("[%s] Issue opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueClosed:
+		title = fmt.Sprintf("[%s] Issue closed: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = failedColor
+	case api.HookIssueReOpened:
+		title = fmt.Sprintf("[%s] Issue re-opened: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = warnColor
+	case api.HookIssueEdited:
+		title = fmt.Sprintf("[%s] Issue edited: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		text = p.Issue.Body
+		color = warnColor
+	case api.HookIssueAssigned:
+		assigneeNames := make([]string, len(p.Issue.Assignees))
+		for i, assignee := range p.Issue.Assignees {
+			assigneeNames[i] = assignee.UserName
+		}
+		title = fmt.Sprintf("[%s] Issue assigned to %s: #%d %s", p.Repository.FullName,
+			strings.Join(assigneeNames, ", "), p.Index, p.Issue.Title)
+		color = successColor
+	case api.HookIssueUnassigned:
+		title = fmt.Sprintf("[%s] Issue unassigned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = warnColor
+	case api.HookIssueLabelUpdated:
+		title = fmt.Sprintf("[%s] Issue labels updated: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = warnColor
+	case api.HookIssueLabelCleared:
+		title = fmt.Sprintf("[%s] Issue labels cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = warnColor
+	case api.HookIssueMilestoned:
+		title = fmt.Sprintf("[%s] Issue milestoned: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = warnColor
+	case api.HookIssueDemilestoned:
+		title = fmt.Sprintf("[%s] Issue milestone cleared: #%d %s", p.Repository.FullName, p.Index, p.Issue.Title)
+		color = warnColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, text,
+		fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index), color), nil
+}
+
+func getMSTeamsIssueCommentPayload(p *api.IssueCommentPayload) (*MSTeamsPayload, error) {
+	var text, title, url string
+	var color int
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		title = fmt.Sprintf("[%s] New comment on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+		color = successColor
+	case api.HookIssueCommentEdited:
+		title = fmt.Sprintf("[%s] Comment edited on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		text = p.Comment.Body
+		url = p.Comment.HTMLURL
+		color = warnColor
+	case api.HookIssueCommentDeleted:
+		title = fmt.Sprintf("[%s] Comment deleted on #%d %s", p.Repository.FullName, p.Issue.Index, p.Issue.Title)
+		url = fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+		color = failedColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, text, url, color), nil
+}
+
+func getMSTeamsPullRequestPayload(p *api.PullRequestPayload) (*MSTeamsPayload, error) {
+	var text, title string
+	var color int
+	switch p.Action {
+	case api.HookIssueOpened:
+		title = fmt.Sprintf("[%s] Pull request opened: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		text = p.PullRequest.Body
+		color = warnColor
+	case api.HookIssueClosed:
+		if p.PullRequest.HasMerged {
+			title = fmt.Sprintf("[%s] Pull request merged: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+			color = successColor
+		} else {
+			title = fmt.Sprintf("[%s] Pull request closed: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+			color = failedColor
+		}
+	case api.HookIssueReOpened:
+		title = fmt.Sprintf("[%s] Pull request re-opened: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = warnColor
+	case api.HookIssueEdited:
+		title = fmt.Sprintf("[%s] Pull request edited: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		text = p.PullRequest.Body
+		color = warnColor
+	case api.HookIssueAssigned:
+		assigneeNames := make([]string, len(p.PullRequest.Assignees))
+		for i, assignee := range p.PullRequest.Assignees {
+			assigneeNames[i] = assignee.UserName
+		}
+		title = fmt.Sprintf("[%s] Pull request assigned to %s: #%d %s", p.Repository.FullName,
+			strings.Join(assigneeNames, ", "), p.Index, p.PullRequest.Title)
+		color = successColor
+	case api.HookIssueUnassigned:
+		title = fmt.Sprintf("[%s] Pull request unassigned: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = warnColor
+	case api.HookIssueLabelUpdated:
+		title = fmt.Sprintf("[%s] Pull request labels updated: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = warnColor
+	case api.HookIssueLabelCleared:
+		title = fmt.Sprintf("[%s] Pull request labels cleared: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = warnColor
+	case api.HookIssueSynchronized:
+		title = fmt.Sprintf("[%s] Pull request synchronized: #%d %s", p.Repository.FullName, p.Index, p.PullRequest.Title)
+		color = successColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, text, p.PullRequest.HTMLURL, color), nil
+}
+
+func getMSTeamsPullRequestReviewPayload(p *PullRequestReviewPayload) (*MSTeamsPayload, error) {
+	var title string
+	var color int
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		title = fmt.Sprintf("[%s] Pull request approved: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+		color = successColor
+	case api.ReviewStateRequestChanges:
+		title = fmt.Sprintf("[%s] Pull request changes requested: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+		color = failedColor
+	default:
+		title = fmt.Sprintf("[%s] Pull request reviewed: #%d %s", p.Repository.FullName, p.PullRequest.Index, p.PullRequest.Title)
+		color = warnColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, p.Review.Body, p.Review.HTMLURL, color), nil
+}
+
+func getMSTeamsRepositoryPayload(p *api.RepositoryPayload) (*MSTeamsPayload, error) {
+	var title, url string
+	var color int
+	switch p.Action {
+	case api.HookRepoCreated:
+		title = fmt.Sprintf("[%s] Repository created", p.Repository.FullName)
+		url = p.Repository.HTMLURL
+		color = successColor
+	case api.HookRepoDeleted:
+		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
+		url = setting.AppURL + p.Repository.Owner.UserName
+		color = failedColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, "", url, color), nil
+}
+
+func getMSTeamsReleasePayload(p *api.ReleasePayload) (*MSTeamsPayload, error) {
+	var title string
+	var color int
+	switch p.Action {
+	case api.HookReleasePublished:
+		title = fmt.Sprintf("[%s] Release published: %s", p.Repository.FullName, p.Release.TagName)
+		color = successColor
+	case api.HookReleaseUpdated:
+		title = fmt.Sprintf("[%s] Release updated: %s", p.Repository.FullName, p.Release.TagName)
+		color = warnColor
+	case api.HookReleaseDeleted:
+		title = fmt.Sprintf("[%s] Release deleted: %s", p.Repository.FullName, p.Release.TagName)
+		color = failedColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, p.Release.Note,
+		p.Repository.HTMLURL+"/src/tag/"+p.Release.TagName, color), nil
+}
+
+func getMSTeamsLabelPayload(p *LabelPayload) (*MSTeamsPayload, error) {
+	var title string
+	var color int
+	switch p.Action {
+	case HookLabelCreated:
+		title = fmt.Sprintf("[%s] Label created: %s", p.Repository.FullName, p.Label.Name)
+		color = successColor
+	case HookLabelEdited:
+		title = fmt.Sprintf("[%s] Label edited: %s", p.Repository.FullName, p.Label.Name)
+		color = warnColor
+	case HookLabelDeleted:
+		title = fmt.Sprintf("[%s] Label deleted: %s", p.Repository.FullName, p.Label.Name)
+		color = failedColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, "", p.Repository.HTMLURL+"/labels", color), nil
+}
+
+func getMSTeamsMilestonePayload(p *MilestonePayload) (*MSTeamsPayload, error) {
+	var title string
+	var color int
+	switch p.Action {
+	case HookMilestoneCreated:
+		title = fmt.Sprintf("[%s] Milestone created: %s", p.Repository.FullName, p.Milestone.Title)
+		color = successColor
+	case HookMilestoneEdited:
+		title = fmt.Sprintf("[%s] Milestone edited: %s", p.Repository.FullName, p.Milestone.Title)
+		color = warnColor
+	case HookMilestoneClosed:
+		title = fmt.Sprintf("[%s] Milestone closed: %s", p.Repository.FullName, p.Milestone.Title)
+		color = failedColor
+	case HookMilestoneOpened:
+		title = fmt.Sprintf("[%s] Milestone re-opened: %s", p.Repository.FullName, p.Milestone.Title)
+		color = warnColor
+	case HookMilestoneDeleted:
+		title = fmt.Sprintf("[%s] Milestone deleted: %s", p.Repository.FullName, p.Milestone.Title)
+		color = failedColor
+	}
+
+	return newMSTeamsPayload(p.Repository, p.Sender, title, p.Milestone.Description,
+		p.Repository.HTMLURL+"/milestones", color), nil
+}
+
+// GetMSTeamsPayload converts a MSTeams webhook into a MSTeamsPayload
+func GetMSTeamsPayload(p api.Payloader, event HookEventType, meta string) (*MSTeamsPayload, error) {
+	s := new(MSTeamsPayload)
+
+	switch event {
+	case HookEventCreate:
+		return getMSTeamsCreatePayload(p.(*api.CreatePayload))
+	case HookEventDelete:
+		return getMSTeamsDeletePayload(p.(*api.DeletePayload))
+	case HookEventFork:
+		return getMSTeamsForkPayload(p.(*api.ForkPayload))
+	case HookEventIssues:
+		return getMSTeamsIssuesPayload(p.(*api.IssuePayload))
+	case HookEventIssueComment:
+		return getMSTeamsIssueCommentPayload(p.(*api.IssueCommentPayload))
+	case HookEventPush:
+		return getMSTeamsPushPayload(p.(*api.PushPayload))
+	case HookEventPullRequest:
+		return getMSTeamsPullRequestPayload(p.(*api.PullRequestPayload))
+	case HookEventPullRequestReview:
+		return getMSTeamsPullRequestReviewPayload(p.(*PullRequestReviewPayload))
+	case HookEventRepository:
+		return getMSTeamsRepositoryPayload(p.(*api.RepositoryPayload))
+	case HookEventRelease:
+		return getMSTeamsReleasePayload(p.(*api.ReleasePayload))
+	case HookEventLabel:
+		return getMSTeamsLabelPayload(p.(*LabelPayload))
+	case HookEventMilestone:
+		return getMSTeamsMilestonePayload(p.(*MilestonePayload))
+	}
+
+	return s, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testHookRepository() *api.Repository {
+	return &api.Repository{
+		FullName: "test/repo",
+		HTMLURL:  "http://localhost:3000/test/repo",
+		Owner:    &api.User{UserName: "test"},
+	}
+}
+
+func testHookSender() *api.User {
+	return &api.User{
+		UserName:  "user1",
+		FullName:  "User One",
+		AvatarURL: "http://localhost:3000/avatars/1",
+	}
+}
+
+func testHookPushPayload() *api.PushPayload {
+	return &api.PushPayload{
+		Ref:        "refs/heads/master",
+		CompareURL: "http://localhost:3000/test/repo/compare/2020558...2020558",
+		Commits: []*api.PayloadCommit{
+			{
+				ID:      "2020558fe2e34debb818a514715839cabd25e778",
+				Message: "commit message",
+				URL:     "http://localhost:3000/test/repo/commit/2020558fe2e34debb818a514715839cabd25e778",
+				Author:  &api.PayloadUser{Name: "user1"},
+			},
+			{
+				ID:      "2020558fe2e34debb818a514715839cabd25e779",
+				Message: "second commit\n\nwith a body",
+				URL:     "http://localhost:3000/test/repo/commit/2020558fe2e34debb818a514715839cabd25e779",
+				Author:  &api.PayloadUser{Name: "user1"},
+			},
+		},
+		Repo:   testHookRepository(),
+		Pusher: testHookSender(),
+		Sender: testHookSender(),
+	}
+}
+
+func testHookIssuePayload(action api.HookIssueAction) *api.IssuePayload {
+	return &api.IssuePayload{
+		Action: action,
+		Index:  2,
+		Issue: &api.Issue{
+			Title: "crash",
+			Body:  "issue body",
+		},
+		Repository: testHookRepository(),
+		Sender:     testHookSender(),
+	}
+}
+
+func TestGetMSTeamsPayload_Push(t *testing.T) {
+	pl, err := GetMSTeamsPayload(testHookPushPayload(), HookEventPush, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "MessageCard", pl.Type)
+	assert.Equal(t, "https://schema.org/extensions", pl.Context)
+	assert.Equal(t, "[test/repo:master] 2 new commits", pl.Title)
+	assert.Equal(t, pl.Title, pl.Summary)
+	if assert.Len(t, pl.Sections, 1) {
+		section := pl.Sections[0]
+		assert.Equal(t, "User One", section.ActivityTitle)
+		assert.Equal(t, "user1", section.ActivitySubtitle)
+		assert.Equal(t, "http://localhost:3000/avatars/1", section.ActivityImage)
+		assert.Equal(t, []MSTeamsFact{{Name: "Repository:", Value: "test/repo"}}, section.Facts)
+		assert.Equal(t, "[2020558](http://localhost:3000/test/repo/commit/2020558fe2e34debb818a514715839cabd25e778) commit message - user1\n\n"+
+			"[2020558](http://localhost:3000/test/repo/commit/2020558fe2e34debb818a514715839cabd25e779) second commit\n\nwith a body - user1", section.Text)
+	}
+	if assert.Len(t, pl.PotentialAction, 1) {
+		assert.Equal(t, "OpenUri", pl.PotentialAction[0].Type)
+		assert.Equal(t, []MSTeamsActionTarget{{Os: "default", URI: "http://localhost:3000/test/repo/compare/2020558...2020558"}},
+			pl.PotentialAction[0].Targets)
+	}
+}
+
+func TestGetMSTeamsPayload_Issues(t *testing.T) {
+	pl, err := GetMSTeamsPayload(testHookIssuePayload(api.HookIssueOpened), HookEventIssues, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[test/repo] Issue opened: #2 crash", pl.Title)
+	assert.Equal(t, "ffd930", pl.ThemeColor)
+	assert.Equal(t, "issue body", pl.Sections[0].Text)
+	assert.Equal(t, "http://localhost:3000/test/repo/issues/2", pl.PotentialAction[0].Targets[0].URI)
+
+	pl, err = GetMSTeamsPayload(testHookIssuePayload(api.HookIssueClosed), HookEventIssues, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[test/repo] Issue closed: #2 crash", pl.Title)
+	assert.Equal(t, "ff3232", pl.ThemeColor)
+}
+
+func TestGetMSTeamsPayload_PullRequest(t *testing.T) {
+	p := &api.PullRequestPayload{
+		Action: api.HookIssueClosed,
+		Index:  3,
+		PullRequest: &api.PullRequest{
+			Title:     "fix crash",
+			HTMLURL:   "http://localhost:3000/test/repo/pulls/3",
+			HasMerged: true,
+		},
+		Repository: testHookRepository(),
+		Sender:     testHookSender(),
+	}
+	pl, err := GetMSTeamsPayload(p, HookEventPullRequest, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[test/repo] Pull request merged: #3 fix crash", pl.Title)
+	assert.Equal(t, "1ac600", pl.ThemeColor)
+	assert.Equal(t, "http://localhost:3000/test/repo/pulls/3", pl.PotentialAction[0].Targets[0].URI)
+}
+
+func TestGetMSTeamsPayload_Label(t *testing.T) {
+	p := &LabelPayload{
+		Action:     HookLabelDeleted,
+		Label:      &api.Label{Name: "bug"},
+		Repository: testHookRepository(),
+		Sender:     testHookSender(),
+	}
+	pl, err := GetMSTeamsPayload(p, HookEventLabel, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[test/repo] Label deleted: bug", pl.Title)
+	assert.Equal(t, "http://localhost:3000/test/repo/labels", pl.PotentialAction[0].Targets[0].URI)
+}
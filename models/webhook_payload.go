@@ -0,0 +1,94 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+var (
+	_ api.Payloader = &LabelPayload{}
+	_ api.Payloader = &MilestonePayload{}
+	_ api.Payloader = &PullRequestReviewPayload{}
+)
+
+// HookLabelAction represents the action that triggered a label event.
+type HookLabelAction string
+
+// Possible actions of a label event
+const (
+	HookLabelCreated HookLabelAction = "created"
+	HookLabelEdited  HookLabelAction = "edited"
+	HookLabelDeleted HookLabelAction = "deleted"
+)
+
+// LabelPayload represents the payload of a label event.
+type LabelPayload struct {
+	Action     HookLabelAction `json:"action"`
+	Label      *api.Label      `json:"label"`
+	Repository *api.Repository `json:"repository"`
+	Sender     *api.User       `json:"sender"`
+}
+
+// SetSecret is a no-op, the secret is not part of the payload as deliveries are signed with it.
+func (p *LabelPayload) SetSecret(_ string) {}
+
+// JSONPayload marshals the LabelPayload to json.
+func (p *LabelPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookMilestoneAction represents the action that triggered a milestone event.
+type HookMilestoneAction string
+
+// Possible actions of a milestone event
+const (
+	HookMilestoneCreated HookMilestoneAction = "created"
+	HookMilestoneEdited  HookMilestoneAction = "edited"
+	HookMilestoneClosed  HookMilestoneAction = "closed"
+	HookMilestoneOpened  HookMilestoneAction = "opened"
+	HookMilestoneDeleted HookMilestoneAction = "deleted"
+)
+
+// MilestonePayload represents the payload of a milestone event.
+type MilestonePayload struct {
+	Action     HookMilestoneAction `json:"action"`
+	Milestone  *api.Milestone      `json:"milestone"`
+	Repository *api.Repository     `json:"repository"`
+	Sender     *api.User           `json:"sender"`
+}
+
+// SetSecret is a no-op, the secret is not part of the payload as deliveries are signed with it.
+func (p *MilestonePayload) SetSecret(_ string) {}
+
+// JSONPayload marshals the MilestonePayload to json.
+func (p *MilestonePayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookReviewAction represents the action that triggered a pull request review event.
+type HookReviewAction string
+
+// HookReviewSubmitted is the action of a submitted pull request review
+const HookReviewSubmitted HookReviewAction = "submitted"
+
+// PullRequestReviewPayload represents the payload of a pull request review event.
+type PullRequestReviewPayload struct {
+	Action      HookReviewAction `json:"action"`
+	Review      *api.PullReview  `json:"review"`
+	PullRequest *api.PullRequest `json:"pull_request"`
+	Repository  *api.Repository  `json:"repository"`
+	Sender      *api.User        `json:"sender"`
+}
+
+// SetSecret is a no-op, the secret is not part of the payload as deliveries are signed with it.
+func (p *PullRequestReviewPayload) SetSecret(_ string) {}
+
+// JSONPayload marshals the PullRequestReviewPayload to json.
+func (p *PullRequestReviewPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
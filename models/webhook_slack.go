@@ -172,9 +172,12 @@ func getSlackPullRequestPayload(p *api.PullRequestPayload, slack *SlackMeta) (*S
 		text = fmt.Sprintf("[%s] Pull request edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
 		attachmentText = SlackTextFormatter(p.PullRequest.Body)
 	case api.HookIssueAssigned:
+		assigneeLinks := make([]string, len(p.PullRequest.Assignees))
+		for i, assignee := range p.PullRequest.Assignees {
+			assigneeLinks[i] = SlackLinkFormatter(setting.AppURL+assignee.UserName, assignee.UserName)
+		}
 		text = fmt.Sprintf("[%s] Pull request assigned to %s: %s by %s", p.Repository.FullName,
-			SlackLinkFormatter(setting.AppURL+p.PullRequest.Assignee.UserName, p.PullRequest.Assignee.UserName),
-			titleLink, senderLink)
+			strings.Join(assigneeLinks, ", "), titleLink, senderLink)
 	case api.HookIssueUnassigned:
 		text = fmt.Sprintf("[%s] Pull request unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
 	case api.HookIssueLabelUpdated:
@@ -222,6 +225,212 @@ func getSlackRepositoryPayload(p *api.RepositoryPayload, slack *SlackMeta) (*Sla
 	}, nil
 }
 
+func getSlackDeletePayload(p *api.DeletePayload, slack *SlackMeta) (*SlackPayload, error) {
+	// deleted tag/branch
+	refName := git.RefEndName(p.Ref)
+	repoLink := SlackLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	text := fmt.Sprintf("[%s:%s] %s deleted by %s", repoLink, refName, p.RefType, p.Sender.UserName)
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackForkPayload(p *api.ForkPayload, slack *SlackMeta) (*SlackPayload, error) {
+	baseLink := SlackLinkFormatter(p.Repo.HTMLURL, p.Repo.FullName)
+	forkLink := SlackLinkFormatter(p.Forkee.HTMLURL, p.Forkee.FullName)
+	text := fmt.Sprintf("%s is forked to %s", baseLink, forkLink)
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackIssuesPayload(p *api.IssuePayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	titleLink := SlackLinkFormatter(fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index),
+		fmt.Sprintf("#%d %s", p.Index, p.Issue.Title))
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueOpened:
+		text = fmt.Sprintf("[%s] Issue submitted by %s", p.Repository.FullName, senderLink)
+		title = titleLink
+		attachmentText = SlackTextFormatter(p.Issue.Body)
+	case api.HookIssueClosed:
+		text = fmt.Sprintf("[%s] Issue closed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueReOpened:
+		text = fmt.Sprintf("[%s] Issue re-opened: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueEdited:
+		text = fmt.Sprintf("[%s] Issue edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		attachmentText = SlackTextFormatter(p.Issue.Body)
+	case api.HookIssueAssigned:
+		assigneeLinks := make([]string, len(p.Issue.Assignees))
+		for i, assignee := range p.Issue.Assignees {
+			assigneeLinks[i] = SlackLinkFormatter(setting.AppURL+assignee.UserName, assignee.UserName)
+		}
+		text = fmt.Sprintf("[%s] Issue assigned to %s: %s by %s", p.Repository.FullName,
+			strings.Join(assigneeLinks, ", "), titleLink, senderLink)
+	case api.HookIssueUnassigned:
+		text = fmt.Sprintf("[%s] Issue unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelUpdated:
+		text = fmt.Sprintf("[%s] Issue labels updated: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueLabelCleared:
+		text = fmt.Sprintf("[%s] Issue labels cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueMilestoned:
+		text = fmt.Sprintf("[%s] Issue milestoned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HookIssueDemilestoned:
+		text = fmt.Sprintf("[%s] Issue milestone cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Title: title,
+			Text:  attachmentText,
+		}},
+	}, nil
+}
+
+func getSlackIssueCommentPayload(p *api.IssueCommentPayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	titleLink := SlackLinkFormatter(fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index),
+		fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title))
+	var text, title, attachmentText string
+	switch p.Action {
+	case api.HookIssueCommentCreated:
+		text = fmt.Sprintf("[%s] New comment created by %s", p.Repository.FullName, senderLink)
+		title = SlackLinkFormatter(p.Comment.HTMLURL, fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title))
+		attachmentText = SlackTextFormatter(p.Comment.Body)
+	case api.HookIssueCommentEdited:
+		text = fmt.Sprintf("[%s] Comment edited on %s by %s", p.Repository.FullName, titleLink, senderLink)
+		title = SlackLinkFormatter(p.Comment.HTMLURL, fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title))
+		attachmentText = SlackTextFormatter(p.Comment.Body)
+	case api.HookIssueCommentDeleted:
+		text = fmt.Sprintf("[%s] Comment deleted on %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Title: title,
+			Text:  attachmentText,
+		}},
+	}, nil
+}
+
+func getSlackReleasePayload(p *api.ReleasePayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	repoLink := SlackLinkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	refLink := SlackLinkFormatter(p.Repository.HTMLURL+"/src/tag/"+p.Release.TagName, p.Release.TagName)
+	var text string
+	switch p.Action {
+	case api.HookReleasePublished:
+		text = fmt.Sprintf("[%s] Release %s published by %s", repoLink, refLink, senderLink)
+	case api.HookReleaseUpdated:
+		text = fmt.Sprintf("[%s] Release %s updated by %s", repoLink, refLink, senderLink)
+	case api.HookReleaseDeleted:
+		text = fmt.Sprintf("[%s] Release %s deleted by %s", repoLink, refLink, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackLabelPayload(p *LabelPayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	labelLink := SlackLinkFormatter(p.Repository.HTMLURL+"/labels", p.Label.Name)
+	var text string
+	switch p.Action {
+	case HookLabelCreated:
+		text = fmt.Sprintf("[%s] Label %s created by %s", p.Repository.FullName, labelLink, senderLink)
+	case HookLabelEdited:
+		text = fmt.Sprintf("[%s] Label %s edited by %s", p.Repository.FullName, labelLink, senderLink)
+	case HookLabelDeleted:
+		text = fmt.Sprintf("[%s] Label %s deleted by %s", p.Repository.FullName, SlackTextFormatter(p.Label.Name), senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+	}, nil
+}
+
+func getSlackMilestonePayload(p *MilestonePayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	milestoneLink := SlackLinkFormatter(p.Repository.HTMLURL+"/milestones", p.Milestone.Title)
+	var text, attachmentText string
+	switch p.Action {
+	case HookMilestoneCreated:
+		text = fmt.Sprintf("[%s] Milestone %s created by %s", p.Repository.FullName, milestoneLink, senderLink)
+		attachmentText = SlackTextFormatter(p.Milestone.Description)
+	case HookMilestoneEdited:
+		text = fmt.Sprintf("[%s] Milestone %s edited by %s", p.Repository.FullName, milestoneLink, senderLink)
+		attachmentText = SlackTextFormatter(p.Milestone.Description)
+	case HookMilestoneClosed:
+		text = fmt.Sprintf("[%s] Milestone %s closed by %s", p.Repository.FullName, milestoneLink, senderLink)
+	case HookMilestoneOpened:
+		text = fmt.Sprintf("[%s] Milestone %s re-opened by %s", p.Repository.FullName, milestoneLink, senderLink)
+	case HookMilestoneDeleted:
+		text = fmt.Sprintf("[%s] Milestone %s deleted by %s", p.Repository.FullName, SlackTextFormatter(p.Milestone.Title), senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Text:  attachmentText,
+		}},
+	}, nil
+}
+
+func getSlackPullRequestReviewPayload(p *PullRequestReviewPayload, slack *SlackMeta) (*SlackPayload, error) {
+	senderLink := SlackLinkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName)
+	titleLink := SlackLinkFormatter(p.Review.HTMLURL, fmt.Sprintf("#%d %s", p.PullRequest.Index, p.PullRequest.Title))
+	var text string
+	switch p.Review.State {
+	case api.ReviewStateApproved:
+		text = fmt.Sprintf("[%s] Pull request approved: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.ReviewStateRequestChanges:
+		text = fmt.Sprintf("[%s] Pull request changes requested: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	default:
+		text = fmt.Sprintf("[%s] Pull request reviewed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	return &SlackPayload{
+		Channel:  slack.Channel,
+		Text:     text,
+		Username: slack.Username,
+		IconURL:  slack.IconURL,
+		Attachments: []SlackAttachment{{
+			Color: slack.Color,
+			Text:  SlackTextFormatter(p.Review.Body),
+		}},
+	}, nil
+}
+
 // GetSlackPayload converts a slack webhook into a SlackPayload
 func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (*SlackPayload, error) {
 	s := new(SlackPayload)
@@ -234,12 +443,28 @@ func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (*SlackP
 	switch event {
 	case HookEventCreate:
 		return getSlackCreatePayload(p.(*api.CreatePayload), slack)
+	case HookEventDelete:
+		return getSlackDeletePayload(p.(*api.DeletePayload), slack)
+	case HookEventFork:
+		return getSlackForkPayload(p.(*api.ForkPayload), slack)
+	case HookEventIssues:
+		return getSlackIssuesPayload(p.(*api.IssuePayload), slack)
+	case HookEventIssueComment:
+		return getSlackIssueCommentPayload(p.(*api.IssueCommentPayload), slack)
 	case HookEventPush:
 		return getSlackPushPayload(p.(*api.PushPayload), slack)
 	case HookEventPullRequest:
 		return getSlackPullRequestPayload(p.(*api.PullRequestPayload), slack)
+	case HookEventPullRequestReview:
+		return getSlackPullRequestReviewPayload(p.(*PullRequestReviewPayload), slack)
 	case HookEventRepository:
 		return getSlackRepositoryPayload(p.(*api.RepositoryPayload), slack)
+	case HookEventRelease:
+		return getSlackReleasePayload(p.(*api.ReleasePayload), slack)
+	case HookEventLabel:
+		return getSlackLabelPayload(p.(*LabelPayload), slack)
+	case HookEventMilestone:
+		return getSlackMilestonePayload(p.(*MilestonePayload), slack)
 	}
 
 	return s, nil
@@ -6,8 +6,14 @@ package models
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/webhook"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -73,12 +79,25 @@ func TestWebhook_UpdateEvent(t *testing.T) {
 }
 
 func TestWebhook_EventsArray(t *testing.T) {
-	assert.Equal(t, []string{"create", "push", "pull_request"},
+	assert.Equal(t, []string{"create", "delete", "fork", "issues", "issue_comment", "push",
+		"pull_request", "pull_request_review", "repository", "release", "label", "milestone"},
 		(&Webhook{
 			HookEvent: &HookEvent{SendEverything: true},
 		}).EventsArray(),
 	)
 
+	assert.Equal(t, []string{"issues", "label"},
+		(&Webhook{
+			HookEvent: &HookEvent{
+				ChooseEvents: true,
+				HookEvents: HookEvents{
+					Issues: true,
+					Label:  true,
+				},
+			},
+		}).EventsArray(),
+	)
+
 	assert.Equal(t, []string{"push"},
 		(&Webhook{
 			HookEvent: &HookEvent{PushOnly: true},
@@ -193,6 +212,82 @@ func TestDeleteWebhookByOrgID(t *testing.T) {
 	assert.True(t, IsErrWebhookNotExist(err))
 }
 
+func TestSystemAndDefaultWebhooks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	systemHook := &Webhook{
+		URL:             "www.example.com/system",
+		ContentType:     ContentTypeJSON,
+		Events:          `{"push_only":true,"send_everything":false,"choose_events":false,"events":{"create":false,"push":true,"pull_request":false}}`,
+		IsActive:        true,
+		IsSystemWebhook: true,
+	}
+	defaultHook := &Webhook{
+		URL:         "www.example.com/default",
+		ContentType: ContentTypeJSON,
+		Events:      `{"push_only":true,"send_everything":false,"choose_events":false,"events":{"create":false,"push":true,"pull_request":false}}`,
+		IsActive:    true,
+	}
+	assert.NoError(t, CreateWebhook(systemHook))
+	assert.NoError(t, CreateWebhook(defaultHook))
+
+	hooks, err := GetSystemWebhooks()
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, systemHook.ID, hooks[0].ID)
+	}
+
+	hooks, err = GetDefaultWebhooks()
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, defaultHook.ID, hooks[0].ID)
+	}
+
+	hook, err := GetSystemOrDefaultWebhook(defaultHook.ID)
+	assert.NoError(t, err)
+	assert.False(t, hook.IsSystemWebhook)
+
+	_, err = GetSystemOrDefaultWebhook(1)
+	assert.True(t, IsErrWebhookNotExist(err))
+}
+
+func TestCopyDefaultWebhooksToRepo(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defaultHook := &Webhook{
+		URL:         "www.example.com/default",
+		ContentType: ContentTypeJSON,
+		Events:      `{"push_only":true,"send_everything":false,"choose_events":false,"events":{"create":false,"push":true,"pull_request":false}}`,
+		IsActive:    true,
+	}
+	assert.NoError(t, CreateWebhook(defaultHook))
+
+	assert.NoError(t, copyDefaultWebhooksToRepo(x, 2))
+	hooks, err := GetWebhooksByRepoID(2)
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.NotEqual(t, defaultHook.ID, hooks[0].ID)
+		assert.Equal(t, defaultHook.URL, hooks[0].URL)
+	}
+	AssertExistsAndLoadBean(t, &Webhook{ID: defaultHook.ID})
+}
+
+func TestDeleteDefaultSystemWebhook(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	systemHook := &Webhook{
+		URL:             "www.example.com/system",
+		ContentType:     ContentTypeJSON,
+		Events:          `{"push_only":true,"send_everything":false,"choose_events":false,"events":{"create":false,"push":true,"pull_request":false}}`,
+		IsSystemWebhook: true,
+	}
+	assert.NoError(t, CreateWebhook(systemHook))
+	assert.NoError(t, DeleteDefaultSystemWebhook(systemHook.ID))
+	AssertNotExistsBean(t, &Webhook{ID: systemHook.ID})
+
+	// repository webhooks can't be deleted this way
+	err := DeleteDefaultSystemWebhook(1)
+	assert.True(t, IsErrWebhookNotExist(err))
+	AssertExistsAndLoadBean(t, &Webhook{ID: 1})
+}
+
 func TestToHookTaskType(t *testing.T) {
 	assert.Equal(t, GOGS, ToHookTaskType("gogs"))
 	assert.Equal(t, SLACK, ToHookTaskType("slack"))
@@ -225,6 +320,31 @@ func TestHookTasks(t *testing.T) {
 	assert.Len(t, hookTasks, 0)
 }
 
+func TestGetHookTaskByUUID(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	hookTask, err := GetHookTaskByUUID(1, "uuid1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), hookTask.ID)
+
+	_, err = GetHookTaskByUUID(2, "uuid1")
+	assert.True(t, IsErrHookTaskNotExist(err))
+	_, err = GetHookTaskByUUID(1, "nonexistent")
+	assert.True(t, IsErrHookTaskNotExist(err))
+}
+
+func TestRedeliverHookTask(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	hook := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	hookTask := AssertExistsAndLoadBean(t, &HookTask{ID: 1}).(*HookTask)
+
+	redelivery, err := RedeliverHookTask(hook, hookTask)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hookTask.UUID, redelivery.UUID)
+	assert.Equal(t, hook.URL, redelivery.URL)
+	assert.False(t, redelivery.IsDelivered)
+	AssertExistsAndLoadBean(t, &HookTask{ID: redelivery.ID, HookID: 1, RepoID: 1})
+}
+
 func TestCreateHookTask(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	hookTask := &HookTask{
@@ -267,6 +387,168 @@ func TestPrepareWebhooks(t *testing.T) {
 	}
 }
 
-// TODO TestHookTask_deliver
+func TestPrepareWebhooksUnsubscribedEvent(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	hookTask := &HookTask{RepoID: repo.ID, HookID: 1, EventType: HookEventLabel}
+	AssertNotExistsBean(t, hookTask)
+	assert.NoError(t, PrepareWebhooks(repo, HookEventLabel, &LabelPayload{}))
+	// webhook 1 only subscribes to push events
+	AssertNotExistsBean(t, hookTask)
+}
+
+func TestHookTaskBackoff(t *testing.T) {
+	defer func(backoff, maxBackoff time.Duration) {
+		setting.Webhook.RetryBackoff = backoff
+		setting.Webhook.MaxBackoff = maxBackoff
+	}(setting.Webhook.RetryBackoff, setting.Webhook.MaxBackoff)
+	setting.Webhook.RetryBackoff = time.Minute
+	setting.Webhook.MaxBackoff = 10 * time.Minute
+
+	assert.Equal(t, time.Minute, hookTaskBackoff(1))
+	assert.Equal(t, 2*time.Minute, hookTaskBackoff(2))
+	assert.Equal(t, 4*time.Minute, hookTaskBackoff(3))
+	assert.Equal(t, 8*time.Minute, hookTaskBackoff(4))
+	assert.Equal(t, 10*time.Minute, hookTaskBackoff(5))
+	assert.Equal(t, 10*time.Minute, hookTaskBackoff(50))
+}
+
+func TestHookTask_deliver(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	defer func(maxAttempts int) {
+		setting.Webhook.MaxAttempts = maxAttempts
+	}(setting.Webhook.MaxAttempts)
+	setting.Webhook.MaxAttempts = 2
+
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	task := &HookTask{
+		RepoID:         1,
+		HookID:         1,
+		URL:            server.URL,
+		ContentType:    ContentTypeJSON,
+		EventType:      HookEventPush,
+		PayloadContent: "{}",
+	}
+
+	// the first failure schedules a retry
+	task.deliver()
+	assert.False(t, task.IsSucceed)
+	assert.False(t, task.IsDelivered)
+	assert.EqualValues(t, 1, task.Attempts)
+	assert.True(t, task.NextAttemptUnix > 0)
+	hook := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	assert.EqualValues(t, HookStatusFail, hook.LastStatus)
+	assert.False(t, hook.IsFailing)
+
+	// the last allowed attempt gives up and marks the webhook as failing
+	task.deliver()
+	assert.False(t, task.IsSucceed)
+	assert.True(t, task.IsDelivered)
+	assert.EqualValues(t, 2, task.Attempts)
+	hook = AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	assert.True(t, hook.IsFailing)
+	assert.True(t, hook.FailingSinceUnix > 0)
+
+	// a successful delivery clears the failing state
+	status = http.StatusOK
+	task = &HookTask{
+		RepoID:         1,
+		HookID:         1,
+		URL:            server.URL,
+		ContentType:    ContentTypeJSON,
+		EventType:      HookEventPush,
+		PayloadContent: "{}",
+	}
+	task.deliver()
+	assert.True(t, task.IsSucceed)
+	assert.True(t, task.IsDelivered)
+	hook = AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	assert.EqualValues(t, HookStatusSucceed, hook.LastStatus)
+	assert.False(t, hook.IsFailing)
+}
+
+func TestHookTask_finishAttempt(t *testing.T) {
+	defer func(maxAttempts int) {
+		setting.Webhook.MaxAttempts = maxAttempts
+	}(setting.Webhook.MaxAttempts)
+	setting.Webhook.MaxAttempts = 2
+
+	// a failed attempt, e.g. because the webhook could not be loaded, is retried later
+	task := &HookTask{Attempts: 1}
+	assert.False(t, task.finishAttempt())
+	assert.False(t, task.IsDelivered)
+	assert.True(t, task.NextAttemptUnix > 0)
+
+	task.Attempts++
+	assert.True(t, task.finishAttempt())
+	assert.True(t, task.IsDelivered)
+}
+
+func TestHookTask_deliverMissingWebhook(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	task := &HookTask{RepoID: 1, HookID: 999}
+	task.deliver()
+	assert.True(t, task.IsDelivered)
+	assert.EqualValues(t, 0, task.Attempts)
+}
+
+func TestHookTask_deliverSigned(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer server.Close()
+
+	hook := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	hook.Secret = "secret"
+	assert.NoError(t, UpdateWebhook(hook))
+
+	task := &HookTask{
+		RepoID:         1,
+		HookID:         1,
+		URL:            server.URL,
+		ContentType:    ContentTypeForm,
+		EventType:      HookEventPush,
+		PayloadContent: `{"ref":"refs/heads/master"}`,
+	}
+	task.deliver()
+	assert.True(t, task.IsSucceed)
+	assert.Equal(t, "payload=%7B%22ref%22%3A%22refs%2Fheads%2Fmaster%22%7D", string(body))
+	assert.Equal(t, webhook.Sign("secret", body), header.Get(webhook.SignatureHeader))
+	assert.Equal(t, webhook.Sign("secret", body), header.Get(webhook.GogsSignatureHeader))
+	assert.Equal(t, webhook.GitHubSignature("secret", body), header.Get(webhook.GitHubSignatureHeader))
+}
 
 // TODO TestDeliverHooks
+
+func TestQueueDueHookTasks_busyWorkers(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(queue chan *HookTask) {
+		hookTaskQueue = queue
+	}(hookTaskQueue)
+
+	// no worker is receiving, the due task must be left for the next pass
+	hookTaskQueue = make(chan *HookTask)
+	queueDueHookTasks(1)
+	assert.False(t, hookTasksInDelivery.IsRunning("1"))
+
+	hookTaskQueue = make(chan *HookTask, 1)
+	queueDueHookTasks(1)
+	assert.True(t, hookTasksInDelivery.IsRunning("1"))
+	if assert.Len(t, hookTaskQueue, 1) {
+		assert.EqualValues(t, 1, (<-hookTaskQueue).ID)
+	}
+	hookTasksInDelivery.Stop("1")
+}
@@ -44,7 +44,7 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 			auHead := ctx.Req.Header.Get("Authorization")
 			if len(auHead) > 0 {
 				auths := strings.Fields(auHead)
-				if len(auths) == 2 && auths[0] == "token" {
+				if len(auths) == 2 && (auths[0] == "token" || strings.ToLower(auths[0]) == "bearer") {
 					tokenSHA = auths[1]
 				}
 			}
@@ -52,9 +52,16 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 
 		// Let's see if token is valid.
 		if len(tokenSHA) > 0 {
+			// Access tokens issued to OAuth2 applications are signed JWTs.
+			if strings.Contains(tokenSHA, ".") {
+				return checkOAuth2AccessToken(ctx, tokenSHA)
+			}
+
 			t, err := models.GetAccessTokenBySHA(tokenSHA)
 			if err != nil {
-				if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) {
+				if models.IsErrAccessTokenExpired(err) {
+					log.Trace("GetAccessTokenBySHA: %v", err)
+				} else if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) {
 					log.Error(4, "GetAccessTokenBySHA: %v", err)
 				}
 				return 0
@@ -63,6 +70,8 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 			if err = models.UpdateAccessToken(t); err != nil {
 				log.Error(4, "UpdateAccessToken: %v", err)
 			}
+			// Keep the token around so its scopes can be checked later on.
+			ctx.Data["AccessToken"] = t
 			return t.UID
 		}
 	}
@@ -76,6 +85,27 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 	return 0
 }
 
+// checkOAuth2AccessToken returns the id of the user an OAuth2 access token has been issued for.
+func checkOAuth2AccessToken(ctx *macaron.Context, accessToken string) int64 {
+	if !setting.OAuth2.Enable {
+		return 0
+	}
+	uid, grant, err := models.GetUserIDByOAuth2AccessToken(accessToken)
+	if err != nil {
+		log.Error(4, "GetUserIDByOAuth2AccessToken: %v", err)
+		return 0
+	} else if uid == 0 {
+		return 0
+	}
+	// Restrict the token to the scopes the user has granted to the application.
+	ctx.Data["AccessToken"] = &models.AccessToken{
+		UID:    uid,
+		Name:   "oauth2",
+		Scopes: grant.AccessTokenScopes(),
+	}
+	return uid
+}
+
 // SignedInUser returns the user object of signed user.
 // It returns a bool value to indicate whether user uses basic auth or not.
 func SignedInUser(ctx *macaron.Context, sess session.Store) (*models.User, bool) {
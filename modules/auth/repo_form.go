@@ -96,6 +96,14 @@ type RepoSettingForm struct {
 	MirrorAddress string
 	Private       bool
 	EnablePrune   bool
+	Topics        string
+
+	// Push mirror settings
+	PushMirrorID       int64
+	PushMirrorAddress  string
+	PushMirrorUsername string
+	PushMirrorPassword string
+	PushMirrorInterval string
 
 	// Advanced settings
 	EnableWiki                       bool
@@ -113,6 +121,7 @@ type RepoSettingForm struct {
 	PullsAllowSquash                 bool
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
+	EnableDependencyCheck            bool
 }
 
 // Validate validates the fields
@@ -129,10 +138,17 @@ func (f *RepoSettingForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 
 // ProtectBranchForm form for changing protected branch settings
 type ProtectBranchForm struct {
-	Protected       bool
-	EnableWhitelist bool
-	WhitelistUsers  string
-	WhitelistTeams  string
+	Protected            bool
+	EnablePush           bool
+	EnableWhitelist      bool
+	WhitelistUsers       string
+	WhitelistTeams       string
+	EnableMergeWhitelist bool
+	MergeWhitelistUsers  string
+	MergeWhitelistTeams  string
+	RequiredApprovals    int64 `binding:"Range(0,100)"`
+	EnableStatusCheck    bool
+	StatusCheckContexts  string
 }
 
 // Validate validates the fields
@@ -149,12 +165,20 @@ func (f *ProtectBranchForm) Validate(ctx *macaron.Context, errs binding.Errors)
 
 // WebhookForm form for changing web hook
 type WebhookForm struct {
-	Events      string
-	Create      bool
-	Push        bool
-	PullRequest bool
-	Repository  bool
-	Active      bool
+	Events            string
+	Create            bool
+	Delete            bool
+	Fork              bool
+	Issues            bool
+	IssueComment      bool
+	Push              bool
+	PullRequest       bool
+	PullRequestReview bool
+	Repository        bool
+	Release           bool
+	Label             bool
+	Milestone         bool
+	Active            bool
 }
 
 // PushOnly if the hook will be triggered when push
@@ -237,6 +261,32 @@ func (f *NewDingtalkHookForm) Validate(ctx *macaron.Context, errs binding.Errors
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// NewMSTeamsHookForm form for creating MS Teams hook
+type NewMSTeamsHookForm struct {
+	PayloadURL string `binding:"Required;ValidUrl"`
+	WebhookForm
+}
+
+// Validate validates the fields
+func (f *NewMSTeamsHookForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// NewMattermostHookForm form for creating mattermost hook
+type NewMattermostHookForm struct {
+	PayloadURL string `binding:"Required;ValidUrl"`
+	Channel    string
+	Username   string
+	IconURL    string
+	Color      string
+	WebhookForm
+}
+
+// Validate validates the fields
+func (f *NewMattermostHookForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -248,9 +298,9 @@ func (f *NewDingtalkHookForm) Validate(ctx *macaron.Context, errs binding.Errors
 type CreateIssueForm struct {
 	Title       string `binding:"Required;MaxSize(255)"`
 	LabelIDs    string `form:"label_ids"`
+	AssigneeIDs string `form:"assignee_ids"`
 	Ref         string `form:"ref"`
 	MilestoneID int64
-	AssigneeID  int64
 	Content     string
 	Files       []string
 }
@@ -282,6 +332,17 @@ func (f *ReactionForm) Validate(ctx *macaron.Context, errs binding.Errors) bindi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// IssueDependencyForm form for adding and removing issue dependencies
+type IssueDependencyForm struct {
+	Reference string `binding:"Required;MaxSize(255)"`
+	Type      string `binding:"Required;In(blocked_by,blocks)"`
+}
+
+// Validate validates the fields
+func (f *IssueDependencyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //    _____  .__.__                   __
 //   /     \ |__|  |   ____   _______/  |_  ____   ____   ____
 //  /  \ /  \|  |  | _/ __ \ /  ___/\   __\/  _ \ /    \_/ __ \
@@ -349,6 +410,45 @@ func (f *MergePullRequestForm) Validate(ctx *macaron.Context, errs binding.Error
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// CodeCommentForm form for adding a line comment to a pull request review
+type CodeCommentForm struct {
+	Content   string `binding:"Required"`
+	TreePath  string `form:"path" binding:"Required"`
+	Line      int64
+	CommitSHA string `form:"commit_id"`
+}
+
+// Validate validates the fields
+func (f *CodeCommentForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// SubmitReviewForm form for submitting a finished pull request review
+type SubmitReviewForm struct {
+	Content  string
+	Type     string `binding:"Required;In(approve,comment,reject)"`
+	CommitID string
+}
+
+// Validate validates the fields
+func (f *SubmitReviewForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ReviewType returns the review type the form was submitted with
+func (f SubmitReviewForm) ReviewType() models.ReviewType {
+	switch f.Type {
+	case "approve":
+		return models.ReviewTypeApprove
+	case "comment":
+		return models.ReviewTypeComment
+	case "reject":
+		return models.ReviewTypeReject
+	default:
+		return models.ReviewTypeUnknown
+	}
+}
+
 // __________       .__
 // \______   \ ____ |  |   ____ _____    ______ ____
 //  |       _// __ \|  | _/ __ \\__  \  /  ___// __ \
@@ -509,3 +609,17 @@ type AddTimeManuallyForm struct {
 func (f *AddTimeManuallyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// RepoSubscriptionForm form for choosing the events a repository watcher is notified of
+type RepoSubscriptionForm struct {
+	Issues       bool
+	PullRequests bool
+	Comments     bool
+	Releases     bool
+	Pushes       bool
+}
+
+// Validate validates the fields
+func (f *RepoSubscriptionForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
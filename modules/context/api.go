@@ -75,6 +75,18 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 	})
 }
 
+// TokenHasScope returns true if the request is not authenticated by an access
+// token, or if the token has been granted at least one of the given scopes.
+func (ctx *APIContext) TokenHasScope(scopes ...models.AccessTokenScope) bool {
+	return ctx.AccessToken == nil || ctx.AccessToken.HasAnyScope(scopes...)
+}
+
+// TokenCanAccessRepo returns true if the request is not authenticated by an
+// access token, or if the token is not restricted from the given repository.
+func (ctx *APIContext) TokenCanAccessRepo(repo *models.Repository) bool {
+	return ctx.AccessToken == nil || ctx.AccessToken.CanAccessRepo(repo)
+}
+
 // SetLinkHeader sets pagination link header by given total number and page size.
 func (ctx *APIContext) SetLinkHeader(total, pageSize int) {
 	page := paginater.New(total, pageSize, ctx.QueryInt("page"), 0)
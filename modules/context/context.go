@@ -39,6 +39,7 @@ type Context struct {
 	User        *models.User
 	IsSigned    bool
 	IsBasicAuth bool
+	AccessToken *models.AccessToken // Token used to sign in, if any
 
 	Repo *Repository
 	Org  *Organization
@@ -214,6 +215,7 @@ func Contexter() macaron.Handler {
 		ctx.User, ctx.IsBasicAuth = auth.SignedInUser(ctx.Context, ctx.Session)
 
 		if ctx.User != nil {
+			ctx.AccessToken, _ = ctx.Data["AccessToken"].(*models.AccessToken)
 			ctx.IsSigned = true
 			ctx.Data["IsSigned"] = ctx.IsSigned
 			ctx.Data["SignedUser"] = ctx.User
@@ -245,6 +247,7 @@ func Contexter() macaron.Handler {
 		ctx.Data["ShowFooterVersion"] = setting.ShowFooterVersion
 		ctx.Data["EnableSwaggerEndpoint"] = setting.API.EnableSwaggerEndpoint
 		ctx.Data["EnableOpenIDSignIn"] = setting.Service.EnableOpenIDSignIn
+		ctx.Data["EnableOAuth2"] = setting.OAuth2.Enable
 
 		c.Map(ctx)
 	}
@@ -100,7 +100,7 @@ func (r *Repository) CanUseTimetracker(issue *models.Issue, user *models.User) b
 	// 1. Is timetracker enabled
 	// 2. Is the user a contributor, admin, poster or assignee and do the repository policies require this?
 	return r.Repository.IsTimetrackerEnabled() && (!r.Repository.AllowOnlyContributorsToTrackTime() ||
-		r.IsWriter() || issue.IsPoster(user.ID) || issue.AssigneeID == user.ID)
+		r.IsWriter() || issue.IsPoster(user.ID) || issue.IsAssignee(user.ID))
 }
 
 // GetCommitsCount returns cached commit count for current view
@@ -375,7 +375,8 @@ func RepoAssignment() macaron.Handler {
 		ctx.Data["Owner"] = ctx.Repo.Repository.Owner
 		ctx.Data["IsRepositoryOwner"] = ctx.Repo.IsOwner()
 		ctx.Data["IsRepositoryAdmin"] = ctx.Repo.IsAdmin()
-		ctx.Data["IsRepositoryWriter"] = ctx.Repo.IsWriter()
+		// Archived repositories are read-only, hide all controls that change them.
+		ctx.Data["IsRepositoryWriter"] = ctx.Repo.IsWriter() && !repo.IsArchived
 
 		if ctx.Data["CanSignedUserFork"], err = ctx.Repo.Repository.CanUserFork(ctx.User); err != nil {
 			ctx.ServerError("CanUserFork", err)
@@ -429,7 +430,7 @@ func RepoAssignment() macaron.Handler {
 		}
 
 		// People who have push access or have forked repository can propose a new pull request.
-		if ctx.Repo.IsWriter() || (ctx.IsSigned && ctx.User.HasForkedRepo(ctx.Repo.Repository.ID)) {
+		if !repo.IsArchived && (ctx.Repo.IsWriter() || (ctx.IsSigned && ctx.User.HasForkedRepo(ctx.Repo.Repository.ID))) {
 			// Pull request is allowed if this is a fork repository
 			// and base repository accepts pull requests.
 			if repo.BaseRepo != nil && repo.BaseRepo.AllowsPulls() {
@@ -644,6 +645,18 @@ func RepoRefByType(refType RepoRefType) macaron.Handler {
 	}
 }
 
+// RepoMustNotBeArchived returns a macaron middleware that rejects changes to archived repositories
+func RepoMustNotBeArchived() macaron.Handler {
+	return func(ctx *Context) {
+		if ctx.Repo.Repository.IsArchived {
+			ctx.NotFound("RepoMustNotBeArchived", models.ErrRepoArchived{
+				ID:   ctx.Repo.Repository.ID,
+				Name: ctx.Repo.Repository.Name,
+			})
+		}
+	}
+}
+
 // RequireRepoAdmin returns a macaron middleware for requiring repository admin permission
 func RequireRepoAdmin() macaron.Handler {
 	return func(ctx *Context) {
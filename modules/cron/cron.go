@@ -88,6 +88,17 @@ func NewContext() {
 			go models.RemoveOldDeletedBranches()
 		}
 	}
+	if setting.Cron.NotificationDigest.Enabled {
+		entry, err = c.AddFunc("Send notification digests", setting.Cron.NotificationDigest.Schedule, models.SendNotificationDigests)
+		if err != nil {
+			log.Fatal(4, "Cron[Send notification digests]: %v", err)
+		}
+		if setting.Cron.NotificationDigest.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go models.SendNotificationDigests()
+		}
+	}
 	c.Start()
 }
 
@@ -69,6 +69,17 @@ func NewLfsJwtSecret() (string, error) {
 	return JWTSecretBase64, nil
 }
 
+// NewJwtSecret generate a new value intended to be used for signing JSON web tokens.
+func NewJwtSecret() (string, error) {
+	JWTSecretBytes := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, JWTSecretBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(JWTSecretBytes), nil
+}
+
 // NewSecretKey generate a new value intended to be used by SECRET_KEY.
 func NewSecretKey() (string, error) {
 	secretKey, err := GetRandomString(64)
@@ -6,9 +6,10 @@ import (
 	"errors"
 	"io"
 	"os"
-	"path/filepath"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
 )
 
 var (
@@ -16,68 +17,59 @@ var (
 	errSizeMismatch = errors.New("Content size does not match")
 )
 
-// ContentStore provides a simple file system based storage.
+// ContentStore provides a simple object storage based store.
 type ContentStore struct {
-	BasePath string
+	storage.ObjectStorage
+}
+
+// NewContentStore returns a content store backed by the configured lfs storage.
+func NewContentStore() *ContentStore {
+	return &ContentStore{ObjectStorage: storage.LFS}
 }
 
 // Get takes a Meta object and retrieves the content from the store, returning
 // it as an io.Reader. If fromByte > 0, the reader starts from that byte
 func (s *ContentStore) Get(meta *models.LFSMetaObject, fromByte int64) (io.ReadCloser, error) {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
-
-	f, err := os.Open(path)
+	f, err := s.Open(meta.RelativePath())
 	if err != nil {
 		return nil, err
 	}
 	if fromByte > 0 {
-		_, err = f.Seek(fromByte, os.SEEK_CUR)
+		if _, err = f.Seek(fromByte, os.SEEK_CUR); err != nil {
+			f.Close()
+			return nil, err
+		}
 	}
-	return f, err
+	return f, nil
 }
 
 // Put takes a Meta object and an io.Reader and writes the content to the store.
 func (s *ContentStore) Put(meta *models.LFSMetaObject, r io.Reader) error {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
-	tmpPath := path + ".tmp"
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0750); err != nil {
-		return err
-	}
-
-	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0640)
-	if err != nil {
-		return err
-	}
-	defer os.Remove(tmpPath)
+	p := meta.RelativePath()
 
 	hash := sha256.New()
-	hw := io.MultiWriter(hash, file)
-
-	written, err := io.Copy(hw, r)
+	written, err := s.Save(p, io.TeeReader(r, hash))
 	if err != nil {
-		file.Close()
 		return err
 	}
-	file.Close()
 
 	if written != meta.Size {
-		return errSizeMismatch
+		err = errSizeMismatch
+	} else if hex.EncodeToString(hash.Sum(nil)) != meta.Oid {
+		err = errHashMismatch
 	}
-
-	shaStr := hex.EncodeToString(hash.Sum(nil))
-	if shaStr != meta.Oid {
-		return errHashMismatch
+	if err != nil {
+		if delErr := s.Delete(p); delErr != nil {
+			log.Error(4, "Cleaning up invalid LFS object %s: %v", p, delErr)
+		}
+		return err
 	}
-
-	return os.Rename(tmpPath, path)
+	return nil
 }
 
 // Exists returns true if the object exists in the content store.
 func (s *ContentStore) Exists(meta *models.LFSMetaObject) bool {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if _, err := s.Stat(meta.RelativePath()); os.IsNotExist(err) {
 		return false
 	}
 	return true
@@ -85,9 +77,7 @@ func (s *ContentStore) Exists(meta *models.LFSMetaObject) bool {
 
 // Verify returns true if the object exists in the content store and size is correct.
 func (s *ContentStore) Verify(meta *models.LFSMetaObject) (bool, error) {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
-
-	fi, err := os.Stat(path)
+	fi, err := s.Stat(meta.RelativePath())
 	if os.IsNotExist(err) || err == nil && fi.Size() != meta.Size {
 		return false, nil
 	} else if err != nil {
@@ -96,11 +86,3 @@ func (s *ContentStore) Verify(meta *models.LFSMetaObject) (bool, error) {
 
 	return true, nil
 }
-
-func transformKey(key string) string {
-	if len(key) < 5 {
-		return key
-	}
-
-	return filepath.Join(key[0:2], key[2:4], key[4:])
-}
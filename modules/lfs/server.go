@@ -151,7 +151,7 @@ func getContentHandler(ctx *context.Context) {
 		}
 	}
 
-	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+	contentStore := NewContentStore()
 	content, err := contentStore.Get(meta, fromByte)
 	if err != nil {
 		writeStatus(ctx, 404)
@@ -228,7 +228,7 @@ func PostHandler(ctx *context.Context) {
 	ctx.Resp.Header().Set("Content-Type", metaMediaType)
 
 	sentStatus := 202
-	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+	contentStore := NewContentStore()
 	if meta.Existing && contentStore.Exists(meta) {
 		sentStatus = 200
 	}
@@ -276,7 +276,7 @@ func BatchHandler(ctx *context.Context) {
 			return
 		}
 
-		contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+		contentStore := NewContentStore()
 
 		meta, err := repository.GetLFSMetaObjectByOid(object.Oid)
 		if err == nil && contentStore.Exists(meta) { // Object is found and exists
@@ -309,7 +309,7 @@ func PutHandler(ctx *context.Context) {
 		return
 	}
 
-	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+	contentStore := NewContentStore()
 	if err := contentStore.Put(meta, ctx.Req.Body().ReadCloser()); err != nil {
 		ctx.Resp.WriteHeader(500)
 		fmt.Fprintf(ctx.Resp, `{"message":"%s"}`, err)
@@ -341,7 +341,7 @@ func VerifyHandler(ctx *context.Context) {
 		return
 	}
 
-	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+	contentStore := NewContentStore()
 	ok, err := contentStore.Verify(meta)
 	if err != nil {
 		ctx.Resp.WriteHeader(500)
@@ -486,12 +486,15 @@ func authenticate(ctx *context.Context, repository *models.Repository, authoriza
 		return accessCheck
 	}
 
-	user, repo, opStr, err := parseToken(authorization)
+	user, repo, token, opStr, err := parseToken(authorization)
 	if err != nil {
 		return false
 	}
 	ctx.User = user
 	if opStr == "basic" {
+		if token != nil && !token.AllowsRepoAccess(repository, accessMode) {
+			return false
+		}
 		accessCheck, _ := models.HasAccess(ctx.User.ID, repository, accessMode)
 		return accessCheck
 	}
@@ -504,9 +507,11 @@ func authenticate(ctx *context.Context, repository *models.Repository, authoriza
 	return false
 }
 
-func parseToken(authorization string) (*models.User, *models.Repository, string, error) {
+// parseToken returns the user and repository of a JWT, or the user and, if the
+// password is a personal access token, the token of a basic auth header.
+func parseToken(authorization string) (*models.User, *models.Repository, *models.AccessToken, string, error) {
 	if authorization == "" {
-		return nil, nil, "unknown", fmt.Errorf("No token")
+		return nil, nil, nil, "unknown", fmt.Errorf("No token")
 	}
 	if strings.HasPrefix(authorization, "Bearer ") {
 		token, err := jwt.Parse(authorization[7:], func(t *jwt.Token) (interface{}, error) {
@@ -516,57 +521,63 @@ func parseToken(authorization string) (*models.User, *models.Repository, string,
 			return setting.LFS.JWTSecretBytes, nil
 		})
 		if err != nil {
-			return nil, nil, "unknown", err
+			return nil, nil, nil, "unknown", err
 		}
 		claims, claimsOk := token.Claims.(jwt.MapClaims)
 		if !token.Valid || !claimsOk {
-			return nil, nil, "unknown", fmt.Errorf("Token claim invalid")
+			return nil, nil, nil, "unknown", fmt.Errorf("Token claim invalid")
 		}
 		opStr, ok := claims["op"].(string)
 		if !ok {
-			return nil, nil, "unknown", fmt.Errorf("Token operation invalid")
+			return nil, nil, nil, "unknown", fmt.Errorf("Token operation invalid")
 		}
 		repoID, ok := claims["repo"].(float64)
 		if !ok {
-			return nil, nil, opStr, fmt.Errorf("Token repository id invalid")
+			return nil, nil, nil, opStr, fmt.Errorf("Token repository id invalid")
 		}
 		r, err := models.GetRepositoryByID(int64(repoID))
 		if err != nil {
-			return nil, nil, opStr, err
+			return nil, nil, nil, opStr, err
 		}
 		userID, ok := claims["user"].(float64)
 		if !ok {
-			return nil, r, opStr, fmt.Errorf("Token user id invalid")
+			return nil, r, nil, opStr, fmt.Errorf("Token user id invalid")
 		}
 		u, err := models.GetUserByID(int64(userID))
 		if err != nil {
-			return nil, r, opStr, err
+			return nil, r, nil, opStr, err
 		}
-		return u, r, opStr, nil
+		return u, r, nil, opStr, nil
 	}
 
 	if strings.HasPrefix(authorization, "Basic ") {
 		c, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, "Basic "))
 		if err != nil {
-			return nil, nil, "basic", err
+			return nil, nil, nil, "basic", err
 		}
 		cs := string(c)
 		i := strings.IndexByte(cs, ':')
 		if i < 0 {
-			return nil, nil, "basic", fmt.Errorf("Basic auth invalid")
+			return nil, nil, nil, "basic", fmt.Errorf("Basic auth invalid")
 		}
 		user, password := cs[:i], cs[i+1:]
 		u, err := models.GetUserByName(user)
 		if err != nil {
-			return nil, nil, "basic", err
+			return nil, nil, nil, "basic", err
 		}
-		if !u.ValidatePassword(password) {
-			return nil, nil, "basic", fmt.Errorf("Basic auth failed")
+		if u.ValidatePassword(password) {
+			return u, nil, nil, "basic", nil
+		}
+
+		// The password may be a personal access token of the user.
+		token, err := models.GetAccessTokenBySHA(password)
+		if err != nil || token.UID != u.ID {
+			return nil, nil, nil, "basic", fmt.Errorf("Basic auth failed")
 		}
-		return u, nil, "basic", nil
+		return u, nil, token, "basic", nil
 	}
 
-	return nil, nil, "unknown", fmt.Errorf("Token not found")
+	return nil, nil, nil, "unknown", fmt.Errorf("Token not found")
 }
 
 func requireAuth(ctx *context.Context) {
@@ -0,0 +1,182 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/jaytaylor/html2text"
+	"golang.org/x/net/html/charset"
+)
+
+// Message is an incoming message
+type Message struct {
+	From    string // address of the sender
+	Subject string
+	Text    string // plain text body, an HTML only body is converted to text
+	// IsAutoReply is true for bounces, vacation replies and other automatic responses
+	IsAutoReply bool
+}
+
+type header interface {
+	Get(key string) string
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// ParseMessage parses a RFC 5322 message
+func ParseMessage(data []byte) (*Message, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := new(Message)
+	if from, err := mail.ParseAddress(m.Header.Get("From")); err == nil {
+		msg.From = from.Address
+	}
+	if msg.Subject, err = wordDecoder.DecodeHeader(m.Header.Get("Subject")); err != nil {
+		msg.Subject = m.Header.Get("Subject")
+	}
+	msg.IsAutoReply = isAutoReply(m.Header)
+
+	text, isHTML, err := readText(m.Header, m.Body)
+	if err != nil {
+		return nil, err
+	}
+	if isHTML {
+		if text, err = html2text.FromString(text); err != nil {
+			return nil, err
+		}
+	}
+	msg.Text = text
+	return msg, nil
+}
+
+// isAutoReply detects automatic responses, see RFC 3834
+func isAutoReply(h mail.Header) bool {
+	if autoSubmitted := strings.ToLower(h.Get("Auto-Submitted")); len(autoSubmitted) > 0 && autoSubmitted != "no" {
+		return true
+	}
+	switch strings.ToLower(h.Get("Precedence")) {
+	case "bulk", "junk", "list", "auto_reply":
+		return true
+	}
+	return len(h.Get("X-Autoreply")) > 0 || len(h.Get("X-Autorespond")) > 0
+}
+
+// readText returns the plain text part of a body, or its HTML part if there
+// is no plain text one
+func readText(h header, body io.Reader) (text string, isHTML bool, err error) {
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	switch strings.ToLower(h.Get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return readMultipartText(multipart.NewReader(body, params["boundary"]))
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", false, nil
+	}
+
+	if label, ok := params["charset"]; ok && !strings.EqualFold(label, "utf-8") {
+		if body, err = charset.NewReaderLabel(label, body); err != nil {
+			return "", false, err
+		}
+	}
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", false, err
+	}
+	return string(content), mediaType == "text/html", nil
+}
+
+func readMultipartText(mr *multipart.Reader) (string, bool, error) {
+	var html string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", false, err
+		}
+		if disposition, _, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition")); disposition == "attachment" {
+			continue
+		}
+
+		text, isHTML, err := readText(part.Header, part)
+		if err != nil {
+			return "", false, err
+		}
+		if len(text) == 0 {
+			continue
+		}
+		if !isHTML {
+			return text, false, nil
+		}
+		if len(html) == 0 {
+			html = text
+		}
+	}
+	return html, len(html) > 0, nil
+}
+
+var (
+	// "On Mon, Jan 1, 2018 at 10:00 AM, Someone <someone@example.com> wrote:",
+	// which some clients wrap on two lines
+	quoteHeaderPattern = regexp.MustCompile(`(?s)^On\s.+\swrote:$`)
+	// "-----Original Message-----" of Outlook and "-------- Forwarded Message --------"
+	separatorPattern = regexp.MustCompile(`^-{3,}\s*[\w ]+\s*-{3,}$`)
+	// "Sent from my iPhone"
+	mobileSignaturePattern = regexp.MustCompile(`^Sent from my [\w ]+$`)
+)
+
+// StripReply removes the quoted text and the signature of a reply
+func StripReply(text string) string {
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+
+	end := len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if line == "-- " || trimmed == "--" || // signature delimiter, see RFC 3676
+			separatorPattern.MatchString(trimmed) ||
+			mobileSignaturePattern.MatchString(trimmed) ||
+			quoteHeaderPattern.MatchString(trimmed) ||
+			(i+1 < len(lines) && strings.HasPrefix(trimmed, "On ") &&
+				quoteHeaderPattern.MatchString(trimmed+" "+strings.TrimSpace(lines[i+1]))) {
+			end = i
+			break
+		}
+	}
+	lines = lines[:end]
+
+	// drop the quoted text at the bottom of the reply, but keep inline answers
+	for len(lines) > 0 {
+		last := strings.TrimSpace(lines[len(lines)-1])
+		if len(last) > 0 && !strings.HasPrefix(last, ">") {
+			break
+		}
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMessage(t *testing.T) {
+	msg, err := ParseMessage([]byte(strings.Replace(`From: User Two <user2@example.com>
+Subject: =?UTF-8?Q?Re:_[repo1]_issue1_=E2=9C=94?=
+MIME-Version: 1.0
+Content-Type: multipart/alternative; boundary="boundary"
+
+--boundary
+Content-Type: text/plain; charset=UTF-8
+Content-Transfer-Encoding: quoted-printable
+
+Looks good to me =E2=9C=94
+
+On Mon, Jan 1, 2018 at 10:00 AM, Gitea <gitea@example.com> wrote:
+> A comment
+--boundary
+Content-Type: text/html; charset=UTF-8
+
+<p>Looks good to me</p>
+--boundary--
+`, "\n", "\r\n", -1)))
+	assert.NoError(t, err)
+	assert.Equal(t, "user2@example.com", msg.From)
+	assert.Equal(t, "Re: [repo1] issue1 ✔", msg.Subject)
+	assert.False(t, msg.IsAutoReply)
+	assert.Equal(t, "Looks good to me ✔", StripReply(msg.Text))
+
+	// HTML only
+	msg, err = ParseMessage([]byte("From: user2@example.com\r\nContent-Type: text/html\r\nAuto-Submitted: auto-replied\r\n\r\n<p>Out of office</p>\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Out of office", strings.TrimSpace(msg.Text))
+	assert.True(t, msg.IsAutoReply)
+}
+
+func TestStripReply(t *testing.T) {
+	for _, test := range []struct {
+		text     string
+		expected string
+	}{
+		{"Thanks!", "Thanks!"},
+		{"Thanks!\n\nOn Mon, Jan 1, 2018 at 10:00 AM, Gitea <gitea@example.com> wrote:\n> A comment", "Thanks!"},
+		// quote header wrapped on two lines
+		{"Thanks!\n\nOn Mon, Jan 1, 2018 at 10:00 AM, Gitea\n<gitea@example.com> wrote:\n> A comment", "Thanks!"},
+		{"Thanks!\n-- \nUser Two\nExample Inc.", "Thanks!"},
+		{"Thanks!\n\nSent from my iPhone", "Thanks!"},
+		{"Thanks!\r\n\r\n-----Original Message-----\r\nFrom: Gitea", "Thanks!"},
+		{"Thanks!\n\n> A comment\n> on two lines", "Thanks!"},
+		// inline answers are kept
+		{"> A question?\nAn answer.", "> A question?\nAn answer."},
+		{"unsubscribe\n\nOn Mon, Jan 1, 2018 at 10:00 AM, Gitea <gitea@example.com> wrote:\n> A comment", "unsubscribe"},
+		{"On Mon, Jan 1, 2018 at 10:00 AM, Gitea <gitea@example.com> wrote:\n> A comment", ""},
+	} {
+		assert.Equal(t, test.expected, StripReply(test.text), test.text)
+	}
+}
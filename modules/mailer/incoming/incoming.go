@@ -0,0 +1,147 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// unsubscribeCommand is the reply which unsubscribes the sender from the issue
+const unsubscribeCommand = "unsubscribe"
+
+// Listen starts the incoming mail server in the background
+func Listen() {
+	l, err := net.Listen("tcp", setting.IncomingEmail.ListenAddr)
+	if err != nil {
+		log.Fatal(4, "Failed to listen for incoming mail on %s: %v", setting.IncomingEmail.ListenAddr, err)
+	}
+
+	srv := &Server{
+		Handler:        replyHandler{},
+		Hostname:       setting.Domain,
+		UseLMTP:        setting.IncomingEmail.UseLMTP,
+		MaxMessageSize: setting.IncomingEmail.MaxMessageSize,
+	}
+	go func() {
+		if err := srv.Serve(l); err != nil {
+			log.Error(4, "Incoming mail server stopped: %v", err)
+		}
+	}()
+}
+
+// ParseReplyToken returns the reply token of a reply-to address,
+// see setting.IncomingEmail.ReplyToAddress
+func ParseReplyToken(address string) (string, bool) {
+	pattern := setting.IncomingEmail.ReplyToAddress
+	i := strings.Index(pattern, setting.IncomingEmailTokenPlaceholder)
+	if i < 0 {
+		return "", false
+	}
+	prefix, suffix := pattern[:i], pattern[i+len(setting.IncomingEmailTokenPlaceholder):]
+
+	address = strings.ToLower(address)
+	if len(address) <= len(prefix)+len(suffix) ||
+		!strings.HasPrefix(address, strings.ToLower(prefix)) ||
+		!strings.HasSuffix(address, strings.ToLower(suffix)) {
+		return "", false
+	}
+	return address[len(prefix) : len(address)-len(suffix)], true
+}
+
+// replyHandler creates comments from the replies to notification emails
+type replyHandler struct{}
+
+func (replyHandler) AcceptRecipient(rcpt string) error {
+	if _, ok := ParseReplyToken(rcpt); !ok {
+		return Reject("Unknown recipient")
+	}
+	return nil
+}
+
+func (replyHandler) Deliver(from, rcpt string, data []byte) error {
+	token, ok := ParseReplyToken(rcpt)
+	if !ok {
+		return Reject("Unknown recipient")
+	}
+
+	msg, err := ParseMessage(data)
+	if err != nil {
+		return Reject("Invalid message: %v", err)
+	}
+	if msg.IsAutoReply {
+		log.Trace("Incoming mail: ignoring automatic reply from %s", msg.From)
+		return nil
+	}
+
+	issue, doer, err := models.GetIssueByReplyToken(token)
+	if err != nil {
+		if models.IsErrInvalidReplyToken(err) {
+			return Reject("Invalid reply address")
+		}
+		return fmt.Errorf("GetIssueByReplyToken: %v", err)
+	}
+	if err = checkSender(doer, msg.From); err != nil {
+		return err
+	}
+	if has, err := models.HasAccess(doer.ID, issue.Repo, models.AccessModeRead); err != nil {
+		return fmt.Errorf("HasAccess: %v", err)
+	} else if !has {
+		return Reject("You no longer have access to this repository")
+	}
+
+	content := StripReply(msg.Text)
+	if strings.EqualFold(content, unsubscribeCommand) {
+		if err = models.CreateOrUpdateIssueWatch(doer.ID, issue.ID, false); err != nil {
+			return fmt.Errorf("CreateOrUpdateIssueWatch: %v", err)
+		}
+		log.Trace("Incoming mail: %s unsubscribed from issue %d", doer.Name, issue.ID)
+		return nil
+	}
+	if len(content) == 0 {
+		return Reject("The reply is empty")
+	}
+	// archived repositories are read-only, just like on the web and the API
+	if issue.Repo.IsArchived {
+		return Reject("The repository is archived and does not accept comments")
+	}
+
+	comment, err := models.CreateIssueComment(doer, issue.Repo, issue, content, nil)
+	if err != nil {
+		return fmt.Errorf("CreateIssueComment: %v", err)
+	}
+	notification.Service.NotifyComment(issue, comment, doer.ID)
+
+	log.Trace("Comment created by email: %d/%d/%d", issue.RepoID, issue.ID, comment.ID)
+	return nil
+}
+
+// checkSender makes sure the reply was sent by the user the reply-to address was
+// signed for, and not forwarded to someone else
+func checkSender(doer *models.User, from string) error {
+	if !doer.IsActive || doer.ProhibitLogin {
+		return Reject("Your account is not allowed to comment")
+	}
+
+	if strings.EqualFold(doer.Email, from) {
+		return nil
+	}
+	emails, err := models.GetEmailAddresses(doer.ID)
+	if err != nil {
+		return fmt.Errorf("GetEmailAddresses: %v", err)
+	}
+	for _, email := range emails {
+		if email.IsActivated && strings.EqualFold(email.Email, from) {
+			return nil
+		}
+	}
+	return Reject("The sender does not match the reply address")
+}
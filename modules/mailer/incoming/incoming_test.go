@@ -0,0 +1,34 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReplyToken(t *testing.T) {
+	setting.IncomingEmail.ReplyToAddress = "incoming+%{token}@example.com"
+
+	token, ok := ParseReplyToken("incoming+1.2.abcdef@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "1.2.abcdef", token)
+
+	token, ok = ParseReplyToken("Incoming+1.2.ABCDEF@Example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "1.2.abcdef", token)
+
+	for _, address := range []string{
+		"incoming+@example.com",
+		"incoming+1.2.abcdef@example.org",
+		"someone@example.com",
+	} {
+		_, ok = ParseReplyToken(address)
+		assert.False(t, ok, address)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	commandTimeout = 5 * time.Minute
+	maxRecipients  = 100
+)
+
+// Handler processes the messages delivered to a Server
+type Handler interface {
+	// AcceptRecipient returns an error if the server must not accept mail for the address
+	AcceptRecipient(rcpt string) error
+	// Deliver processes a message delivered to an accepted recipient
+	Deliver(from, rcpt string, data []byte) error
+}
+
+// RejectError is returned by a Handler when the message or the recipient must
+// be rejected permanently, any other error is reported as a temporary failure
+// so that the client tries again later.
+type RejectError struct {
+	Message string
+}
+
+func (err *RejectError) Error() string {
+	return err.Message
+}
+
+// Reject returns a RejectError with the given message
+func Reject(format string, args ...interface{}) error {
+	return &RejectError{fmt.Sprintf(format, args...)}
+}
+
+// Server is a minimal SMTP (RFC 5321) or LMTP (RFC 2033) server, meant to
+// receive mail from a local MTA
+type Server struct {
+	Handler        Handler
+	Hostname       string
+	UseLMTP        bool
+	MaxMessageSize int64
+}
+
+// Serve accepts the connections on the listener until it is closed
+func (srv *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.serveConn(conn)
+	}
+}
+
+type session struct {
+	srv   *Server
+	conn  *textproto.Conn
+	helo  bool
+	from  string
+	rcpts []string
+}
+
+func (srv *Server) serveConn(c net.Conn) {
+	defer c.Close()
+
+	s := &session{srv: srv, conn: textproto.NewConn(c)}
+	if srv.UseLMTP {
+		s.reply(220, "%s LMTP Gitea", srv.Hostname)
+	} else {
+		s.reply(220, "%s ESMTP Gitea", srv.Hostname)
+	}
+
+	for {
+		c.SetDeadline(time.Now().Add(commandTimeout))
+		line, err := s.conn.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				log.Trace("Incoming mail: %v", err)
+			}
+			return
+		}
+
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch strings.ToUpper(verb) {
+		case "HELO", "EHLO":
+			if srv.UseLMTP {
+				s.reply(500, "5.5.1 Use LHLO")
+				continue
+			}
+			s.hello(strings.ToUpper(verb) == "EHLO")
+		case "LHLO":
+			if !srv.UseLMTP {
+				s.reply(500, "5.5.1 Command not recognized")
+				continue
+			}
+			s.hello(true)
+		case "MAIL":
+			s.mail(arg)
+		case "RCPT":
+			s.rcpt(arg)
+		case "DATA":
+			s.data()
+		case "RSET":
+			s.reset()
+			s.reply(250, "2.0.0 OK")
+		case "NOOP":
+			s.reply(250, "2.0.0 OK")
+		case "VRFY":
+			s.reply(252, "2.5.0 Cannot verify user")
+		case "QUIT":
+			s.reply(221, "2.0.0 Bye")
+			return
+		default:
+			s.reply(500, "5.5.1 Command not recognized")
+		}
+	}
+}
+
+func (s *session) reply(code int, format string, args ...interface{}) {
+	if err := s.conn.PrintfLine("%d %s", code, fmt.Sprintf(format, args...)); err != nil {
+		log.Trace("Incoming mail: %v", err)
+	}
+}
+
+func (s *session) reset() {
+	s.from = ""
+	s.rcpts = nil
+}
+
+func (s *session) hello(extended bool) {
+	s.reset()
+	s.helo = true
+	if !extended {
+		s.reply(250, "%s", s.srv.Hostname)
+		return
+	}
+	s.conn.PrintfLine("250-%s", s.srv.Hostname)
+	s.conn.PrintfLine("250-8BITMIME")
+	s.conn.PrintfLine("250-ENHANCEDSTATUSCODES")
+	s.reply(250, "SIZE %d", s.srv.MaxMessageSize)
+}
+
+// parsePath returns the address of a "FROM:<address> [parameters]" or
+// "TO:<address> [parameters]" argument
+func parsePath(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	arg = strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(arg, "<") {
+		return "", false
+	}
+	end := strings.IndexByte(arg, '>')
+	if end < 0 {
+		return "", false
+	}
+	return arg[1:end], true
+}
+
+func (s *session) mail(arg string) {
+	if !s.helo {
+		s.reply(503, "5.5.1 Send hello first")
+		return
+	} else if len(s.from) > 0 {
+		s.reply(503, "5.5.1 Sender already specified")
+		return
+	}
+
+	from, ok := parsePath(arg, "FROM:")
+	if !ok {
+		s.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+		return
+	}
+	// the null reverse-path of bounces is accepted, the handler decides what to do with them
+	if len(from) == 0 {
+		from = "<>"
+	}
+	s.from = from
+	s.reply(250, "2.1.0 OK")
+}
+
+func (s *session) rcpt(arg string) {
+	if len(s.from) == 0 {
+		s.reply(503, "5.5.1 Need MAIL command")
+		return
+	} else if len(s.rcpts) >= maxRecipients {
+		s.reply(452, "4.5.3 Too many recipients")
+		return
+	}
+
+	rcpt, ok := parsePath(arg, "TO:")
+	if !ok || len(rcpt) == 0 {
+		s.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+		return
+	}
+	if err := s.srv.Handler.AcceptRecipient(rcpt); err != nil {
+		s.replyError(err, "5.1.1")
+		return
+	}
+	s.rcpts = append(s.rcpts, rcpt)
+	s.reply(250, "2.1.5 OK")
+}
+
+func (s *session) data() {
+	if len(s.rcpts) == 0 {
+		s.reply(503, "5.5.1 Need RCPT command")
+		return
+	}
+	s.reply(354, "End data with <CR><LF>.<CR><LF>")
+
+	r := s.conn.DotReader()
+	data, err := ioutil.ReadAll(io.LimitReader(r, s.srv.MaxMessageSize+1))
+	if err != nil {
+		log.Trace("Incoming mail: %v", err)
+		return
+	}
+	defer s.reset()
+
+	if int64(len(data)) > s.srv.MaxMessageSize {
+		// the rest of the message must be read before replying
+		io.Copy(ioutil.Discard, r)
+		for i := 0; i < s.numDataReplies(); i++ {
+			s.reply(552, "5.3.4 Message too big")
+		}
+		return
+	}
+
+	errs := make([]error, len(s.rcpts))
+	for i, rcpt := range s.rcpts {
+		errs[i] = s.srv.Handler.Deliver(s.from, rcpt, data)
+	}
+
+	if s.srv.UseLMTP {
+		// LMTP replies with the delivery status of each recipient
+		for _, err := range errs {
+			s.replyDelivery(err)
+		}
+		return
+	}
+	for _, err := range errs {
+		if err != nil {
+			s.replyDelivery(err)
+			return
+		}
+	}
+	s.replyDelivery(nil)
+}
+
+// numDataReplies returns the number of replies expected after DATA, since
+// LMTP replies once for each recipient
+func (s *session) numDataReplies() int {
+	if s.srv.UseLMTP {
+		return len(s.rcpts)
+	}
+	return 1
+}
+
+func (s *session) replyDelivery(err error) {
+	if err == nil {
+		s.reply(250, "2.0.0 OK")
+		return
+	}
+	s.replyError(err, "5.7.1")
+}
+
+func (s *session) replyError(err error, status string) {
+	if rejected, ok := err.(*RejectError); ok {
+		s.reply(550, "%s %s", status, rejected.Message)
+		return
+	}
+	log.Error(4, "Incoming mail: %v", err)
+	s.reply(451, "4.3.0 Temporary failure, please try again later")
+}
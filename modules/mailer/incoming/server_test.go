@@ -0,0 +1,162 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package incoming
+
+import (
+	"errors"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type delivery struct {
+	from, rcpt string
+	data       string
+}
+
+// stubHandler accepts mail for the addresses of the example.com domain, and
+// fails the delivery to the ones starting with "fail"
+type stubHandler struct {
+	sync.Mutex
+	deliveries []delivery
+}
+
+func (h *stubHandler) AcceptRecipient(rcpt string) error {
+	if !strings.HasSuffix(rcpt, "@example.com") {
+		return Reject("Unknown recipient")
+	}
+	return nil
+}
+
+func (h *stubHandler) Deliver(from, rcpt string, data []byte) error {
+	if strings.HasPrefix(rcpt, "fail") {
+		return errors.New("delivery failed")
+	}
+	h.Lock()
+	defer h.Unlock()
+	h.deliveries = append(h.deliveries, delivery{from, rcpt, string(data)})
+	return nil
+}
+
+func startTestServer(t *testing.T, useLMTP bool) (*stubHandler, string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	handler := new(stubHandler)
+	srv := &Server{
+		Handler:        handler,
+		Hostname:       "gitea.example.com",
+		UseLMTP:        useLMTP,
+		MaxMessageSize: 1024,
+	}
+	go srv.Serve(l)
+	return handler, l.Addr().String(), func() { l.Close() }
+}
+
+const testMessage = "From: user2@example.com\r\nSubject: Re: issue1\r\n\r\nHello\r\n"
+
+func TestServer_SMTP(t *testing.T) {
+	handler, addr, stop := startTestServer(t, false)
+	defer stop()
+
+	assert.NoError(t, smtp.SendMail(addr, nil, "user2@example.com", []string{"incoming+token@example.com"}, []byte(testMessage)))
+	if assert.Len(t, handler.deliveries, 1) {
+		assert.Equal(t, "user2@example.com", handler.deliveries[0].from)
+		assert.Equal(t, "incoming+token@example.com", handler.deliveries[0].rcpt)
+		// the line endings are normalized by the dot reader
+		assert.Equal(t, strings.Replace(testMessage, "\r\n", "\n", -1), handler.deliveries[0].data)
+	}
+
+	err := smtp.SendMail(addr, nil, "user2@example.com", []string{"someone@example.org"}, []byte(testMessage))
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "550"))
+	}
+
+	err = smtp.SendMail(addr, nil, "user2@example.com", []string{"fail@example.com"}, []byte(testMessage))
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "451"))
+	}
+
+	err = smtp.SendMail(addr, nil, "user2@example.com", []string{"incoming+token@example.com"}, []byte(strings.Repeat("a", 2048)))
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "552"))
+	}
+	assert.Len(t, handler.deliveries, 1)
+}
+
+func TestServer_LMTP(t *testing.T) {
+	handler, addr, stop := startTestServer(t, true)
+	defer stop()
+
+	conn, err := textproto.Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	expect := func(code int) {
+		_, _, err := conn.ReadResponse(code)
+		assert.NoError(t, err)
+	}
+	send := func(code int, format string, args ...interface{}) {
+		assert.NoError(t, conn.PrintfLine(format, args...))
+		expect(code)
+	}
+
+	expect(220)
+	send(500, "EHLO client.example.com")
+	send(250, "LHLO client.example.com")
+	send(250, "MAIL FROM:<user2@example.com>")
+	send(250, "RCPT TO:<incoming+token@example.com>")
+	send(250, "RCPT TO:<fail@example.com>")
+	send(354, "DATA")
+
+	w := conn.DotWriter()
+	_, err = w.Write([]byte(testMessage))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// one reply for each recipient
+	expect(250)
+	expect(451)
+	send(221, "QUIT")
+
+	if assert.Len(t, handler.deliveries, 1) {
+		assert.Equal(t, "incoming+token@example.com", handler.deliveries[0].rcpt)
+	}
+}
+
+func TestServer_BadSequence(t *testing.T) {
+	_, addr, stop := startTestServer(t, false)
+	defer stop()
+
+	conn, err := textproto.Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	for _, step := range []struct {
+		command string
+		code    int
+	}{
+		{"", 220},
+		{"MAIL FROM:<user2@example.com>", 503},
+		{"HELO client.example.com", 250},
+		{"RCPT TO:<incoming+token@example.com>", 503},
+		{"MAIL FROM:user2@example.com", 501},
+		{"MAIL FROM:<user2@example.com>", 250},
+		{"DATA", 503},
+		{"RSET", 250},
+		{"UNKNOWN", 500},
+	} {
+		if len(step.command) > 0 {
+			assert.NoError(t, conn.PrintfLine("%s", step.command))
+		}
+		_, _, err := conn.ReadResponse(step.code)
+		assert.NoError(t, err, step.command)
+	}
+}
@@ -7,23 +7,65 @@ package notification
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
 )
 
+// Event is an event users may be notified of
+type Event interface {
+	// Notify creates or updates the notifications of the event
+	Notify() error
+}
+
 type (
 	notificationService struct {
-		issueQueue chan issueNotificationOpts
+		queue chan Event
+	}
+
+	// IssueEvent is a new or updated issue
+	IssueEvent struct {
+		Issue                *models.Issue
+		NotificationAuthorID int64
+	}
+
+	// PullRequestEvent is a new or updated pull request
+	PullRequestEvent struct {
+		Issue                *models.Issue
+		NotificationAuthorID int64
+	}
+
+	// CommentEvent is a new comment on an issue or a pull request
+	CommentEvent struct {
+		Issue                *models.Issue
+		Comment              *models.Comment
+		NotificationAuthorID int64
+	}
+
+	// ReleaseEvent is a published release
+	ReleaseEvent struct {
+		Release              *models.Release
+		NotificationAuthorID int64
 	}
 
-	issueNotificationOpts struct {
-		issue                *models.Issue
-		notificationAuthorID int64
+	// PushEvent is a push to the default branch of a repository
+	PushEvent struct {
+		Repository           *models.Repository
+		CommitID             string
+		NotificationAuthorID int64
+	}
+
+	// MentionEvent is a mention of users in an issue, a pull request or a comment
+	MentionEvent struct {
+		Issue                *models.Issue
+		Comment              *models.Comment // nil if the users are mentioned in the issue itself
+		Mentions             []string
+		NotificationAuthorID int64
 	}
 )
 
 var (
 	// Service is the notification service
 	Service = &notificationService{
-		issueQueue: make(chan issueNotificationOpts, 100),
+		queue: make(chan Event, 100),
 	}
 )
 
@@ -32,19 +74,86 @@ func init() {
 }
 
 func (ns *notificationService) Run() {
-	for {
-		select {
-		case opts := <-ns.issueQueue:
-			if err := models.CreateOrUpdateIssueNotifications(opts.issue, opts.notificationAuthorID); err != nil {
-				log.Error(4, "Was unable to create issue notification: %v", err)
-			}
+	for event := range ns.queue {
+		if err := event.Notify(); err != nil {
+			log.Error(4, "Was unable to create notifications of %T: %v", event, err)
 		}
 	}
 }
 
+// Notify queues the creation of the notifications of an event
+func (ns *notificationService) Notify(event Event) {
+	ns.queue <- event
+}
+
+// NotifyNewIssue notifies of a new issue or pull request, and of the users mentioned in it
+func (ns *notificationService) NotifyNewIssue(issue *models.Issue, notificationAuthorID int64) {
+	ns.NotifyIssue(issue, notificationAuthorID)
+	ns.Notify(&MentionEvent{issue, nil, markup.FindAllMentions(issue.Content), notificationAuthorID})
+}
+
+// NotifyIssue notifies of an updated issue or pull request
 func (ns *notificationService) NotifyIssue(issue *models.Issue, notificationAuthorID int64) {
-	ns.issueQueue <- issueNotificationOpts{
-		issue,
-		notificationAuthorID,
+	if issue.IsPull {
+		ns.Notify(&PullRequestEvent{issue, notificationAuthorID})
+	} else {
+		ns.Notify(&IssueEvent{issue, notificationAuthorID})
+	}
+}
+
+// NotifyComment notifies of a new comment on an issue or a pull request, and
+// of the users mentioned in it
+func (ns *notificationService) NotifyComment(issue *models.Issue, comment *models.Comment, notificationAuthorID int64) {
+	ns.Notify(&CommentEvent{issue, comment, notificationAuthorID})
+	ns.Notify(&MentionEvent{issue, comment, markup.FindAllMentions(comment.Content), notificationAuthorID})
+}
+
+// NotifyRelease notifies of a published release
+func (ns *notificationService) NotifyRelease(rel *models.Release, notificationAuthorID int64) {
+	if rel.IsDraft {
+		return
+	}
+	ns.Notify(&ReleaseEvent{rel, notificationAuthorID})
+}
+
+// NotifyPush notifies of a push to the default branch of a repository
+func (ns *notificationService) NotifyPush(repo *models.Repository, branch, commitID string, notificationAuthorID int64) {
+	if branch != repo.DefaultBranch {
+		return
+	}
+	ns.Notify(&PushEvent{repo, commitID, notificationAuthorID})
+}
+
+// Notify implements Event
+func (e *IssueEvent) Notify() error {
+	return models.CreateOrUpdateIssueNotifications(e.Issue, e.NotificationAuthorID)
+}
+
+// Notify implements Event
+func (e *PullRequestEvent) Notify() error {
+	return models.CreateOrUpdateIssueNotifications(e.Issue, e.NotificationAuthorID)
+}
+
+// Notify implements Event
+func (e *CommentEvent) Notify() error {
+	return models.CreateOrUpdateCommentNotifications(e.Issue, e.Comment.ID, e.NotificationAuthorID)
+}
+
+// Notify implements Event
+func (e *ReleaseEvent) Notify() error {
+	return models.CreateOrUpdateReleaseNotifications(e.Release, e.NotificationAuthorID)
+}
+
+// Notify implements Event
+func (e *PushEvent) Notify() error {
+	return models.CreatePushNotifications(e.Repository, e.CommitID, e.NotificationAuthorID)
+}
+
+// Notify implements Event
+func (e *MentionEvent) Notify() error {
+	var commentID int64
+	if e.Comment != nil {
+		commentID = e.Comment.ID
 	}
+	return models.CreateOrUpdateMentionNotifications(e.Issue, commentID, e.Mentions, e.NotificationAuthorID)
 }
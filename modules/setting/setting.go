@@ -176,11 +176,19 @@ var (
 		SkipTLSVerify  bool
 		Types          []string
 		PagingNum      int
+		DeliverWorkers int
+		MaxAttempts    int
+		RetryBackoff   time.Duration
+		MaxBackoff     time.Duration
 	}{
 		QueueLength:    1000,
 		DeliverTimeout: 5,
 		SkipTLSVerify:  false,
 		PagingNum:      10,
+		DeliverWorkers: 5,
+		MaxAttempts:    5,
+		RetryBackoff:   time.Minute,
+		MaxBackoff:     time.Hour,
 	}
 
 	// Repository settings
@@ -402,6 +410,11 @@ var (
 			Schedule   string
 			OlderThan  time.Duration
 		} `ini:"cron.deleted_branches_cleanup"`
+		NotificationDigest struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.notification_digest"`
 	}{
 		UpdateMirror: struct {
 			Enabled    bool
@@ -467,6 +480,15 @@ var (
 			Schedule:   "@every 24h",
 			OlderThan:  24 * time.Hour,
 		},
+		NotificationDigest: struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		}{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
 	}
 
 	// Git settings
@@ -515,9 +537,24 @@ var (
 	API = struct {
 		EnableSwaggerEndpoint bool
 		MaxResponseItems      int
+		MaxBlobSize           int64
 	}{
 		EnableSwaggerEndpoint: true,
 		MaxResponseItems:      50,
+		MaxBlobSize:           10485760,
+	}
+
+	// OAuth2 provider settings
+	OAuth2 = struct {
+		Enable                     bool
+		AccessTokenExpirationTime  int64
+		RefreshTokenExpirationTime int64
+		JWTSecretBase64            string `ini:"JWT_SECRET"`
+		JWTSecretBytes             []byte `ini:"-"`
+	}{
+		Enable:                     true,
+		AccessTokenExpirationTime:  3600,
+		RefreshTokenExpirationTime: 730,
 	}
 
 	// I18n settings
@@ -1064,8 +1101,45 @@ func NewContext() {
 		log.Fatal(4, "Failed to map Git settings: %v", err)
 	} else if err = Cfg.Section("api").MapTo(&API); err != nil {
 		log.Fatal(4, "Failed to map API settings: %v", err)
+	} else if err = Cfg.Section("oauth2").MapTo(&OAuth2); err != nil {
+		log.Fatal(4, "Failed to map OAuth2 settings: %v", err)
 	}
 
+	if OAuth2.Enable {
+		OAuth2.JWTSecretBytes = make([]byte, 32)
+		n, err := base64.RawURLEncoding.Decode(OAuth2.JWTSecretBytes, []byte(OAuth2.JWTSecretBase64))
+
+		if err != nil || n != 32 {
+			OAuth2.JWTSecretBase64, err = generate.NewJwtSecret()
+			if err != nil {
+				log.Fatal(4, "Error generating JWT Secret for custom config: %v", err)
+				return
+			}
+			OAuth2.JWTSecretBytes, _ = base64.RawURLEncoding.DecodeString(OAuth2.JWTSecretBase64)
+
+			// Save secret
+			cfg := ini.Empty()
+			if com.IsFile(CustomConf) {
+				// Keeps custom settings if there is already something.
+				if err := cfg.Append(CustomConf); err != nil {
+					log.Error(4, "Failed to load custom conf '%s': %v", CustomConf, err)
+				}
+			}
+
+			cfg.Section("oauth2").Key("JWT_SECRET").SetValue(OAuth2.JWTSecretBase64)
+
+			if err := os.MkdirAll(filepath.Dir(CustomConf), os.ModePerm); err != nil {
+				log.Fatal(4, "Failed to create '%s': %v", CustomConf, err)
+			}
+			if err := cfg.SaveTo(CustomConf); err != nil {
+				log.Fatal(4, "Error saving generated JWT Secret to custom config: %v", err)
+				return
+			}
+		}
+	}
+
+	newStorageService()
+
 	sec = Cfg.Section("mirror")
 	Mirror.MinInterval = sec.Key("MIN_INTERVAL").MustDuration(10 * time.Minute)
 	Mirror.DefaultInterval = sec.Key("DEFAULT_INTERVAL").MustDuration(8 * time.Hour)
@@ -1151,6 +1225,7 @@ var Service struct {
 	DefaultAllowCreateOrganization          bool
 	DefaultEnableTimetracking               bool
 	DefaultAllowOnlyContributorsToTrackTime bool
+	DefaultEnableDependencyCheck            bool
 	NoReplyAddress                          string
 
 	// OpenID settings
@@ -1174,6 +1249,7 @@ func newService() {
 	Service.DefaultAllowCreateOrganization = sec.Key("DEFAULT_ALLOW_CREATE_ORGANIZATION").MustBool(true)
 	Service.DefaultEnableTimetracking = sec.Key("DEFAULT_ENABLE_TIMETRACKING").MustBool(true)
 	Service.DefaultAllowOnlyContributorsToTrackTime = sec.Key("DEFAULT_ALLOW_ONLY_CONTRIBUTORS_TO_TRACK_TIME").MustBool(true)
+	Service.DefaultEnableDependencyCheck = sec.Key("DEFAULT_ENABLE_DEPENDENCY_CHECK").MustBool(true)
 	Service.NoReplyAddress = sec.Key("NO_REPLY_ADDRESS").MustString("noreply.example.org")
 
 	sec = Cfg.Section("openid")
@@ -1442,8 +1518,24 @@ type Mailer struct {
 var (
 	// MailService the global mailer
 	MailService *Mailer
+
+	// IncomingEmail represents the settings of the incoming mail service,
+	// which lets users reply to notification emails to comment on issues
+	IncomingEmail = struct {
+		Enabled        bool
+		ReplyToAddress string // must contain IncomingEmailTokenPlaceholder
+		ListenAddr     string
+		UseLMTP        bool
+		MaxMessageSize int64
+	}{
+		ListenAddr:     "127.0.0.1:2525",
+		MaxMessageSize: 10 << 20,
+	}
 )
 
+// IncomingEmailTokenPlaceholder is replaced by the reply token in IncomingEmail.ReplyToAddress
+const IncomingEmailTokenPlaceholder = "%{token}"
+
 func newMailService() {
 	sec := Cfg.Section("mailer")
 	// Check mailer setting.
@@ -1515,13 +1607,52 @@ func newNotifyMailService() {
 	log.Info("Notify Mail Service Enabled")
 }
 
+func newIncomingEmailService() {
+	sec := Cfg.Section("email.incoming")
+	if !sec.Key("ENABLED").MustBool() {
+		return
+	} else if !Service.EnableNotifyMail {
+		log.Warn("Incoming Email Service: Notify Mail Service is not enabled")
+		return
+	}
+
+	IncomingEmail.ReplyToAddress = sec.Key("REPLY_TO_ADDRESS").String()
+	if strings.Count(IncomingEmail.ReplyToAddress, IncomingEmailTokenPlaceholder) != 1 {
+		log.Fatal(4, "email.incoming.REPLY_TO_ADDRESS must contain %s once: %s", IncomingEmailTokenPlaceholder, IncomingEmail.ReplyToAddress)
+	}
+	if _, err := mail.ParseAddress(strings.Replace(IncomingEmail.ReplyToAddress, IncomingEmailTokenPlaceholder, "token", 1)); err != nil {
+		log.Fatal(4, "Invalid email.incoming.REPLY_TO_ADDRESS (%s): %v", IncomingEmail.ReplyToAddress, err)
+	}
+	IncomingEmail.ListenAddr = sec.Key("LISTEN_ADDR").MustString(IncomingEmail.ListenAddr)
+	IncomingEmail.UseLMTP = sec.Key("USE_LMTP").MustBool()
+	IncomingEmail.MaxMessageSize = sec.Key("MAX_MESSAGE_SIZE").MustInt64(IncomingEmail.MaxMessageSize)
+	IncomingEmail.Enabled = true
+	log.Info("Incoming Email Service Enabled")
+}
+
 func newWebhookService() {
 	sec := Cfg.Section("webhook")
 	Webhook.QueueLength = sec.Key("QUEUE_LENGTH").MustInt(1000)
 	Webhook.DeliverTimeout = sec.Key("DELIVER_TIMEOUT").MustInt(5)
 	Webhook.SkipTLSVerify = sec.Key("SKIP_TLS_VERIFY").MustBool()
-	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "dingtalk"}
+	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "dingtalk", "msteams", "mattermost"}
 	Webhook.PagingNum = sec.Key("PAGING_NUM").MustInt(10)
+	Webhook.DeliverWorkers = sec.Key("DELIVER_WORKERS").MustInt(5)
+	if Webhook.DeliverWorkers < 1 {
+		Webhook.DeliverWorkers = 1
+	}
+	Webhook.MaxAttempts = sec.Key("MAX_ATTEMPTS").MustInt(5)
+	if Webhook.MaxAttempts < 1 {
+		Webhook.MaxAttempts = 1
+	}
+	Webhook.RetryBackoff = sec.Key("RETRY_BACKOFF").MustDuration(time.Minute)
+	if Webhook.RetryBackoff < time.Second {
+		Webhook.RetryBackoff = time.Second
+	}
+	Webhook.MaxBackoff = sec.Key("MAX_BACKOFF").MustDuration(time.Hour)
+	if Webhook.MaxBackoff < Webhook.RetryBackoff {
+		Webhook.MaxBackoff = Webhook.RetryBackoff
+	}
 }
 
 // NewServices initializes the services
@@ -1534,5 +1665,6 @@ func NewServices() {
 	newMailService()
 	newRegisterMailService()
 	newNotifyMailService()
+	newIncomingEmailService()
 	newWebhookService()
 }
@@ -0,0 +1,85 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path"
+	"path/filepath"
+)
+
+// enumerates all the storage types
+const (
+	LocalStorageType = "local"
+	MinioStorageType = "minio"
+)
+
+// MinioStorageConfig represents the configuration of a minio (or any other S3 compatible) storage
+type MinioStorageConfig struct {
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string
+	Location        string
+	BasePath        string
+	UseSSL          bool
+}
+
+// Storage represents the configuration of an object storage
+type Storage struct {
+	Type  string
+	Path  string
+	Minio MinioStorageConfig
+}
+
+// Storage settings
+var (
+	AttachmentStorage  Storage
+	LFSStorage         Storage
+	AvatarStorage      Storage
+	RepoArchiveStorage Storage
+)
+
+// getStorage reads the storage configuration of the given name from the section
+// [storage.<name>], every key falls back to the shared [storage] section.
+func getStorage(name, defaultPath string) Storage {
+	sec := Cfg.Section("storage." + name)
+	def := Cfg.Section("storage")
+	get := func(key, defaultValue string) string {
+		if sec.HasKey(key) {
+			return sec.Key(key).String()
+		}
+		return def.Key(key).MustString(defaultValue)
+	}
+
+	storage := Storage{
+		Type: get("STORAGE_TYPE", LocalStorageType),
+		Path: sec.Key("PATH").MustString(defaultPath),
+	}
+	if !filepath.IsAbs(storage.Path) {
+		storage.Path = path.Join(AppWorkPath, storage.Path)
+	}
+
+	storage.Minio = MinioStorageConfig{
+		Endpoint:        get("MINIO_ENDPOINT", "localhost:9000"),
+		AccessKeyID:     get("MINIO_ACCESS_KEY_ID", ""),
+		SecretAccessKey: get("MINIO_SECRET_ACCESS_KEY", ""),
+		Bucket:          get("MINIO_BUCKET", "gitea"),
+		Location:        get("MINIO_LOCATION", "us-east-1"),
+		BasePath:        get("MINIO_BASE_PATH", name+"/"),
+	}
+	if sec.HasKey("MINIO_USE_SSL") {
+		storage.Minio.UseSSL = sec.Key("MINIO_USE_SSL").MustBool(false)
+	} else {
+		storage.Minio.UseSSL = def.Key("MINIO_USE_SSL").MustBool(false)
+	}
+	return storage
+}
+
+func newStorageService() {
+	AttachmentStorage = getStorage("attachments", AttachmentPath)
+	LFSStorage = getStorage("lfs", LFS.ContentPath)
+	AvatarStorage = getStorage("avatars", AvatarUploadPath)
+	RepoArchiveStorage = getStorage("repo-archive", path.Join(AppDataPath, "repo-archive"))
+}
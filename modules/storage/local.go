@@ -0,0 +1,117 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var _ ObjectStorage = &LocalStorage{}
+
+// LocalStorage represents a storage in a directory of the local disk
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage returns a local storage rooted at the given directory, which is created if necessary
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Dir returns the root directory of the storage
+func (l *LocalStorage) Dir() string {
+	return l.dir
+}
+
+// fullPath converts a storage path to a path on the disk, the path can never point outside of the storage directory
+func (l *LocalStorage) fullPath(p string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(path.Clean("/"+p)))
+}
+
+// Open opens a file
+func (l *LocalStorage) Open(p string) (Object, error) {
+	return os.Open(l.fullPath(p))
+}
+
+// Save writes the content of r to the file, the file is replaced atomically
+func (l *LocalStorage) Save(p string, r io.Reader) (int64, error) {
+	fullPath := l.fullPath(p)
+	if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
+		return 0, err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(fullPath), ".tmp-")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := tmp.Name()
+
+	written, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	if err = os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	return written, nil
+}
+
+// Stat returns the info of the file
+func (l *LocalStorage) Stat(p string) (os.FileInfo, error) {
+	return os.Stat(l.fullPath(p))
+}
+
+// Delete deletes a file
+func (l *LocalStorage) Delete(p string) error {
+	if err := os.Remove(l.fullPath(p)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// IterateObjects iterates across the files below a directory of the storage
+func (l *LocalStorage) IterateObjects(dir string, fn func(path string, obj Object) error) error {
+	root := l.dir
+	if len(dir) > 0 {
+		root = l.fullPath(dir)
+	}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		relPath, err := filepath.Rel(l.dir, p)
+		if err != nil {
+			return err
+		}
+		obj, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer obj.Close()
+		return fn(filepath.ToSlash(relPath), obj)
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "storage")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := NewLocalStorage(filepath.Join(dir, "objects"))
+	assert.NoError(t, err)
+
+	written, err := l.Save("a/b/c", strings.NewReader("content"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, written)
+
+	// paths must not escape the storage directory
+	_, err = l.Save("../../escaped", strings.NewReader("content"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "escaped"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, l.Delete("a/b/c"))
+	assert.NoError(t, l.Delete("escaped"))
+
+	testObjectStorage(t, l)
+}
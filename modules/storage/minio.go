@@ -0,0 +1,181 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/minio/minio-go"
+)
+
+var (
+	_ ObjectStorage = &MinioStorage{}
+	_ Object        = &minioObject{}
+)
+
+// MinioStorage represents a storage in a bucket of a minio (or any other S3 compatible) server
+type MinioStorage struct {
+	client   *minio.Client
+	bucket   string
+	basePath string
+}
+
+// NewMinioStorage returns a minio storage, the bucket is created if it does not exist yet
+func NewMinioStorage(cfg setting.MinioStorageConfig) (*MinioStorage, error) {
+	client, err := minio.New(cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(cfg.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err = client.MakeBucket(cfg.Bucket, cfg.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MinioStorage{
+		client:   client,
+		bucket:   cfg.Bucket,
+		basePath: cfg.BasePath,
+	}, nil
+}
+
+func (m *MinioStorage) buildMinioPath(p string) string {
+	return strings.TrimPrefix(path.Join(m.basePath, path.Clean("/"+p)), "/")
+}
+
+// convertMinioErr maps a missing object to os.ErrNotExist
+func convertMinioErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return os.ErrNotExist
+	}
+	return err
+}
+
+// Open opens an object
+func (m *MinioStorage) Open(p string) (Object, error) {
+	obj, err := m.client.GetObject(m.bucket, m.buildMinioPath(p), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, convertMinioErr(err)
+	}
+	// GetObject does not contact the server, make sure the object actually exists
+	if _, err = obj.Stat(); err != nil {
+		obj.Close()
+		return nil, convertMinioErr(err)
+	}
+	return &minioObject{obj}, nil
+}
+
+// Save uploads the content of r as an object
+func (m *MinioStorage) Save(p string, r io.Reader) (int64, error) {
+	return m.client.PutObject(
+		m.bucket,
+		m.buildMinioPath(p),
+		r,
+		-1,
+		minio.PutObjectOptions{ContentType: "application/octet-stream"},
+	)
+}
+
+// Stat returns the info of an object
+func (m *MinioStorage) Stat(p string) (os.FileInfo, error) {
+	info, err := m.client.StatObject(m.bucket, m.buildMinioPath(p), minio.StatObjectOptions{})
+	if err != nil {
+		return nil, convertMinioErr(err)
+	}
+	return &minioFileInfo{info}, nil
+}
+
+// Delete deletes an object
+func (m *MinioStorage) Delete(p string) error {
+	return convertMinioErr(m.client.RemoveObject(m.bucket, m.buildMinioPath(p)))
+}
+
+// IterateObjects iterates across the objects below a directory of the storage
+func (m *MinioStorage) IterateObjects(dir string, fn func(path string, obj Object) error) error {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	basePrefix := m.buildMinioPath("/")
+	if len(basePrefix) > 0 {
+		basePrefix += "/"
+	}
+	// the trailing slash keeps e.g. "1/" from matching the objects of "10/"
+	prefix := m.buildMinioPath(dir)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+	for info := range m.client.ListObjectsV2(m.bucket, prefix, true, doneCh) {
+		if info.Err != nil {
+			return info.Err
+		}
+		if err := func() error {
+			obj, err := m.client.GetObject(m.bucket, info.Key, minio.GetObjectOptions{})
+			if err != nil {
+				return convertMinioErr(err)
+			}
+			defer obj.Close()
+			return fn(strings.TrimPrefix(info.Key, basePrefix), &minioObject{obj})
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type minioObject struct {
+	*minio.Object
+}
+
+// Stat returns the info of the object
+func (m *minioObject) Stat() (os.FileInfo, error) {
+	info, err := m.Object.Stat()
+	if err != nil {
+		return nil, convertMinioErr(err)
+	}
+	return &minioFileInfo{info}, nil
+}
+
+// minioFileInfo implements os.FileInfo for an object of a bucket
+type minioFileInfo struct {
+	minio.ObjectInfo
+}
+
+func (m *minioFileInfo) Name() string {
+	return path.Base(m.ObjectInfo.Key)
+}
+
+func (m *minioFileInfo) Size() int64 {
+	return m.ObjectInfo.Size
+}
+
+func (m *minioFileInfo) Mode() os.FileMode {
+	return os.ModePerm
+}
+
+func (m *minioFileInfo) ModTime() time.Time {
+	return m.LastModified
+}
+
+func (m *minioFileInfo) IsDir() bool {
+	return strings.HasSuffix(m.ObjectInfo.Key, "/")
+}
+
+func (m *minioFileInfo) Sys() interface{} {
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"os"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinioStorage(t *testing.T) {
+	endpoint := os.Getenv("TEST_MINIO_ENDPOINT")
+	if len(endpoint) == 0 {
+		t.Skip("TEST_MINIO_ENDPOINT not set")
+		return
+	}
+
+	m, err := NewMinioStorage(setting.MinioStorageConfig{
+		Endpoint:        endpoint,
+		AccessKeyID:     "123456",
+		SecretAccessKey: "12345678",
+		Bucket:          "gitea",
+		Location:        "us-east-1",
+		BasePath:        "storage-test/",
+	})
+	assert.NoError(t, err)
+
+	// remove the objects left over by a previous run
+	assert.NoError(t, m.IterateObjects("", func(p string, obj Object) error {
+		return m.Delete(p)
+	}))
+
+	testObjectStorage(t, m)
+}
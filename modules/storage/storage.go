@@ -0,0 +1,87 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Object represents an object stored in an ObjectStorage
+type Object interface {
+	io.ReadCloser
+	io.Seeker
+	Stat() (os.FileInfo, error)
+}
+
+// ObjectStorage represents a storage of named objects, e.g. a directory on the local disk or a bucket of an S3 compatible server.
+// All paths are relative to the root of the storage and use forward slashes.
+type ObjectStorage interface {
+	// Open opens the object for reading, it returns an error satisfying os.IsNotExist if the object does not exist
+	Open(path string) (Object, error)
+	// Save stores the content of r as the object of the given path and returns the number of bytes written
+	Save(path string, r io.Reader) (int64, error)
+	// Stat returns the file info of the object, it returns an error satisfying os.IsNotExist if the object does not exist
+	Stat(path string) (os.FileInfo, error)
+	// Delete removes the object, deleting an object which does not exist is not an error
+	Delete(path string) error
+	// IterateObjects calls fn for every object below the given directory, or for every object in the storage if dir is empty.
+	// The paths passed to fn are relative to the root of the storage.
+	IterateObjects(dir string, fn func(path string, obj Object) error) error
+}
+
+// Copy copies the object srcPath of src to dstPath of dst
+func Copy(dst ObjectStorage, dstPath string, src ObjectStorage, srcPath string) (int64, error) {
+	f, err := src.Open(srcPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return dst.Save(dstPath, f)
+}
+
+// NewStorage creates the object storage described by the given configuration
+func NewStorage(cfg setting.Storage) (ObjectStorage, error) {
+	switch cfg.Type {
+	case setting.LocalStorageType:
+		return NewLocalStorage(cfg.Path)
+	case setting.MinioStorageType:
+		return NewMinioStorage(cfg.Minio)
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", cfg.Type)
+	}
+}
+
+var (
+	// Attachments represents attachments storage
+	Attachments ObjectStorage
+	// LFS represents lfs storage
+	LFS ObjectStorage
+	// Avatars represents user avatars storage
+	Avatars ObjectStorage
+	// RepoArchives represents repository archives storage
+	RepoArchives ObjectStorage
+)
+
+// Init initializes all the storages according to the configuration
+func Init() (err error) {
+	if Attachments, err = NewStorage(setting.AttachmentStorage); err != nil {
+		return fmt.Errorf("attachments storage: %v", err)
+	}
+	if LFS, err = NewStorage(setting.LFSStorage); err != nil {
+		return fmt.Errorf("lfs storage: %v", err)
+	}
+	if Avatars, err = NewStorage(setting.AvatarStorage); err != nil {
+		return fmt.Errorf("avatars storage: %v", err)
+	}
+	if RepoArchives, err = NewStorage(setting.RepoArchiveStorage); err != nil {
+		return fmt.Errorf("repo archives storage: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testObjectStorage checks the behavior shared by all the ObjectStorage implementations
+func testObjectStorage(t *testing.T, s ObjectStorage) {
+	for _, p := range []string{"1/a", "1/b/c", "10/a", "2/a"} {
+		written, err := s.Save(p, strings.NewReader("content of "+p))
+		assert.NoError(t, err)
+		assert.EqualValues(t, len("content of "+p), written)
+	}
+
+	fi, err := s.Stat("1/b/c")
+	assert.NoError(t, err)
+	assert.EqualValues(t, len("content of 1/b/c"), fi.Size())
+	_, err = s.Stat("1/none")
+	assert.True(t, os.IsNotExist(err))
+
+	obj, err := s.Open("1/b/c")
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(obj)
+	assert.NoError(t, err)
+	assert.NoError(t, obj.Close())
+	assert.Equal(t, "content of 1/b/c", string(content))
+	_, err = s.Open("1/none")
+	assert.True(t, os.IsNotExist(err))
+
+	iterate := func(dir string) []string {
+		var paths []string
+		assert.NoError(t, s.IterateObjects(dir, func(p string, obj Object) error {
+			paths = append(paths, p)
+			return nil
+		}))
+		sort.Strings(paths)
+		return paths
+	}
+	assert.Equal(t, []string{"1/a", "1/b/c", "10/a", "2/a"}, iterate(""))
+	// the objects of "10" are not below "1"
+	assert.Equal(t, []string{"1/a", "1/b/c"}, iterate("1"))
+	assert.Equal(t, []string{"1/b/c"}, iterate("1/b"))
+	assert.Empty(t, iterate("3"))
+
+	for _, p := range iterate("1") {
+		assert.NoError(t, s.Delete(p))
+	}
+	assert.NoError(t, s.Delete("1/a"))
+	assert.Equal(t, []string{"10/a", "2/a"}, iterate(""))
+}
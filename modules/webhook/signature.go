@@ -0,0 +1,79 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package webhook provides the signing of webhook deliveries and a helper
+// for receivers to verify them.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// SignatureHeader is the header holding the hex encoded HMAC-SHA256 of the delivery body
+	SignatureHeader = "X-Gitea-Signature"
+	// GitHubSignatureHeader is the GitHub compatible header holding "sha256=" followed by the signature
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+	// GogsSignatureHeader is the Gogs compatible header holding the same signature as SignatureHeader
+	GogsSignatureHeader = "X-Gogs-Signature"
+
+	githubSignaturePrefix = "sha256="
+)
+
+// ErrInvalidSignature is returned when a delivery is not signed or its signature does not match
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the hex encoded HMAC-SHA256 of the body using the webhook secret as key
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GitHubSignature returns the signature in the format of the X-Hub-Signature-256 header
+func GitHubSignature(secret string, body []byte) string {
+	return githubSignaturePrefix + Sign(secret, body)
+}
+
+// VerifySignature reports whether the signature, as sent in the X-Gitea-Signature,
+// X-Gogs-Signature or X-Hub-Signature-256 header, matches the body.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, githubSignaturePrefix))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// VerifyRequest reads and verifies the body of a webhook delivery against its signature headers.
+// The body is returned and also left readable on the request.
+func VerifyRequest(req *http.Request, secret string) ([]byte, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	signature := req.Header.Get(SignatureHeader)
+	if len(signature) == 0 {
+		signature = req.Header.Get(GogsSignatureHeader)
+	}
+	if len(signature) == 0 {
+		signature = req.Header.Get(GitHubSignatureHeader)
+	}
+	if len(signature) == 0 || !VerifySignature(secret, body, signature) {
+		return nil, ErrInvalidSignature
+	}
+	return body, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// Test vector from RFC 4231, test case 2
+	assert.Equal(t,
+		"5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		Sign("Jefe", []byte("what do ya want for nothing?")))
+	assert.Equal(t,
+		"sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		GitHubSignature("Jefe", []byte("what do ya want for nothing?")))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	signature := Sign("secret", body)
+
+	assert.True(t, VerifySignature("secret", body, signature))
+	assert.True(t, VerifySignature("secret", body, GitHubSignature("secret", body)))
+	assert.False(t, VerifySignature("other", body, signature))
+	assert.False(t, VerifySignature("secret", []byte(`{}`), signature))
+	assert.False(t, VerifySignature("secret", body, "not hex"))
+	assert.False(t, VerifySignature("secret", body, ""))
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := `{"ref":"refs/heads/master"}`
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, Sign("secret", []byte(body)))
+	read, err := VerifyRequest(req, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(read))
+	// the body can still be read by the handler
+	read, err = ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(read))
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(GitHubSignatureHeader, GitHubSignature("secret", []byte(body)))
+	_, err = VerifyRequest(req, "secret")
+	assert.NoError(t, err)
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(GogsSignatureHeader, Sign("secret", []byte(body)))
+	_, err = VerifyRequest(req, "secret")
+	assert.NoError(t, err)
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, Sign("other", []byte(body)))
+	_, err = VerifyRequest(req, "secret")
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	_, err = VerifyRequest(req, "secret")
+	assert.Equal(t, ErrInvalidSignature, err)
+}
@@ -179,81 +179,86 @@ function initCommentForm() {
     initBranchSelector();
     initCommentPreviewTab($('.comment.form'));
 
-    // Labels
-    var $list = $('.ui.labels.list');
-    var $noSelect = $list.find('.no-select');
-    var $labelMenu = $('.select-label .menu');
-    var hasLabelUpdateAction = $labelMenu.data('action') == 'update';
+    function initListSubmits(selector, outerSelector) {
+        var $list = $('.ui.' + outerSelector + '.list');
+        var $noSelect = $list.find('.no-select');
+        var $listMenu = $('.' + selector + ' .menu');
+        var hasUpdateAction = $listMenu.data('action') == 'update';
 
-    $('.select-label').dropdown('setting', 'onHide', function(){
-        if (hasLabelUpdateAction) {
-            location.reload();
-        }
-    });
-
-    $labelMenu.find('.item:not(.no-select)').click(function () {
-        if ($(this).hasClass('checked')) {
-            $(this).removeClass('checked');
-            $(this).find('.octicon').removeClass('octicon-check');
-            if (hasLabelUpdateAction) {
-                updateIssuesMeta(
-                    $labelMenu.data('update-url'),
-                    "detach",
-                    $labelMenu.data('issue-id'),
-                    $(this).data('id')
-                );
-            }
-        } else {
-            $(this).addClass('checked');
-            $(this).find('.octicon').addClass('octicon-check');
-            if (hasLabelUpdateAction) {
-                updateIssuesMeta(
-                    $labelMenu.data('update-url'),
-                    "attach",
-                    $labelMenu.data('issue-id'),
-                    $(this).data('id')
-                );
+        $('.' + selector).dropdown('setting', 'onHide', function(){
+            if (hasUpdateAction) {
+                location.reload();
             }
-        }
+        });
 
-        var labelIds = [];
-        $(this).parent().find('.item').each(function () {
+        $listMenu.find('.item:not(.no-select)').click(function () {
             if ($(this).hasClass('checked')) {
-                labelIds.push($(this).data('id'));
-                $($(this).data('id-selector')).removeClass('hide');
+                $(this).removeClass('checked');
+                $(this).find('.octicon').removeClass('octicon-check');
+                if (hasUpdateAction) {
+                    updateIssuesMeta(
+                        $listMenu.data('update-url'),
+                        "detach",
+                        $listMenu.data('issue-id'),
+                        $(this).data('id')
+                    );
+                }
             } else {
-                $($(this).data('id-selector')).addClass('hide');
+                $(this).addClass('checked');
+                $(this).find('.octicon').addClass('octicon-check');
+                if (hasUpdateAction) {
+                    updateIssuesMeta(
+                        $listMenu.data('update-url'),
+                        "attach",
+                        $listMenu.data('issue-id'),
+                        $(this).data('id')
+                    );
+                }
             }
-        });
-        if (labelIds.length == 0) {
-            $noSelect.removeClass('hide');
-        } else {
-            $noSelect.addClass('hide');
-        }
-        $($(this).parent().data('id')).val(labelIds.join(","));
-        return false;
-    });
-    $labelMenu.find('.no-select.item').click(function () {
-        if (hasLabelUpdateAction) {
-            updateIssuesMeta(
-                $labelMenu.data('update-url'),
-                "clear",
-                $labelMenu.data('issue-id'),
-                ""
-            );
-        }
 
-        $(this).parent().find('.item').each(function () {
-            $(this).removeClass('checked');
-            $(this).find('.octicon').removeClass('octicon-check');
+            var listIds = [];
+            $(this).parent().find('.item').each(function () {
+                if ($(this).hasClass('checked')) {
+                    listIds.push($(this).data('id'));
+                    $($(this).data('id-selector')).removeClass('hide');
+                } else {
+                    $($(this).data('id-selector')).addClass('hide');
+                }
+            });
+            if (listIds.length == 0) {
+                $noSelect.removeClass('hide');
+            } else {
+                $noSelect.addClass('hide');
+            }
+            $($(this).parent().data('id')).val(listIds.join(","));
+            return false;
         });
+        $listMenu.find('.no-select.item').click(function () {
+            if (hasUpdateAction) {
+                updateIssuesMeta(
+                    $listMenu.data('update-url'),
+                    "clear",
+                    $listMenu.data('issue-id'),
+                    ""
+                );
+            }
 
-        $list.find('.item').each(function () {
-            $(this).addClass('hide');
+            $(this).parent().find('.item').each(function () {
+                $(this).removeClass('checked');
+                $(this).find('.octicon').removeClass('octicon-check');
+            });
+
+            $list.find('.item').each(function () {
+                $(this).addClass('hide');
+            });
+            $noSelect.removeClass('hide');
+            $($(this).parent().data('id')).val('');
         });
-        $noSelect.removeClass('hide');
-        $($(this).parent().data('id')).val('');
-    });
+    }
+
+    // Labels and assignees
+    initListSubmits('select-label', 'labels');
+    initListSubmits('select-assignees', 'assignees');
 
     function selectItem(select_id, input_id) {
         var $menu = $(select_id + ' .menu');
@@ -280,10 +285,6 @@ function initCommentForm() {
                     $list.find('.selected').html('<a class="item" href=' + $(this).data('href') + '>' +
                         $(this).text() + '</a>');
                     break;
-                case '#assignee_id':
-                    $list.find('.selected').html('<a class="item" href=' + $(this).data('href') + '>' +
-                        '<img class="ui avatar image" src=' + $(this).data('avatar') + '>' +
-                        $(this).text() + '</a>');
             }
             $('.ui' + select_id + '.list .no-select').addClass('hide');
             $(input_id).val($(this).data('id'));
@@ -309,9 +310,8 @@ function initCommentForm() {
         });
     }
 
-    // Milestone and assignee
+    // Milestone
     selectItem('.select-milestone', '#milestone_id');
-    selectItem('.select-assignee', '#assignee_id');
 }
 
 function initInstall() {
@@ -1351,6 +1351,23 @@ function searchRepositories() {
     });
 }
 
+function initCodeCommentForm() {
+    if ($('.code-comment-form').length === 0) {
+        return;
+    }
+
+    // clicking a line number of the new version selects the line to comment on
+    $('.code-diff .lines-num-new span').click(function () {
+        var line = $(this).text().trim();
+        if (line === '') {
+            return;
+        }
+        var $form = $(this).closest('.diff-file-box').find('.code-comment-form');
+        $form.find('input[name=line]').val(line);
+        $form.find('textarea[name=content]').focus();
+    });
+}
+
 function initCodeView() {
     if ($('.code-view .linenums').length > 0) {
         $(document).on('click', '.lines-num span', function (e) {
@@ -1586,6 +1603,7 @@ $(document).ready(function () {
     initWebhook();
     initAdmin();
     initCodeView();
+    initCodeCommentForm();
     initVueApp();
     initTeamSettings();
     initCtrlEnterSubmit();
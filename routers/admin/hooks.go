@@ -0,0 +1,99 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplHooks base.TplName = "admin/hooks"
+)
+
+// failingHook is a failing webhook along with the link to its settings page
+type failingHook struct {
+	*models.Webhook
+	Link string
+}
+
+// Hooks shows the system and default webhooks, and the webhooks which keep failing
+func Hooks(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.hooks")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminHooks"] = true
+
+	systemHooks, err := models.GetSystemWebhooks()
+	if err != nil {
+		ctx.ServerError("GetSystemWebhooks", err)
+		return
+	}
+	ctx.Data["SystemHooks"] = systemHooks
+	ctx.Data["SystemHooksMenu"] = map[string]string{
+		"Label": ctx.Tr("repo.settings.add_webhook"),
+		"Link":  setting.AppSubURL + "/admin/system-hooks",
+	}
+
+	defaultHooks, err := models.GetDefaultWebhooks()
+	if err != nil {
+		ctx.ServerError("GetDefaultWebhooks", err)
+		return
+	}
+	ctx.Data["DefaultHooks"] = defaultHooks
+	ctx.Data["DefaultHooksMenu"] = map[string]string{
+		"Label": ctx.Tr("repo.settings.add_webhook"),
+		"Link":  setting.AppSubURL + "/admin/default-hooks",
+	}
+
+	ws, err := models.GetFailingWebhooks()
+	if err != nil {
+		ctx.ServerError("GetFailingWebhooks", err)
+		return
+	}
+
+	hooks := make([]*failingHook, 0, len(ws))
+	for _, w := range ws {
+		link, err := w.SettingsLink()
+		if err != nil {
+			ctx.ServerError("SettingsLink", err)
+			return
+		}
+		hooks = append(hooks, &failingHook{Webhook: w, Link: link})
+	}
+	ctx.Data["Hooks"] = hooks
+	ctx.Data["Total"] = len(hooks)
+
+	ctx.HTML(200, tplHooks)
+}
+
+// HooksContext marks the pages managing the system webhooks, or the default
+// webhooks, as admin pages for the shared webhook handlers
+func HooksContext(isSystemWebhook bool) func(ctx *context.Context) {
+	return func(ctx *context.Context) {
+		ctx.Data["PageIsAdmin"] = true
+		ctx.Data["PageIsAdminHooks"] = true
+		ctx.Data["IsSystemWebhook"] = isSystemWebhook
+	}
+}
+
+// RedirectToHooks redirects to the page listing all the admin webhooks
+func RedirectToHooks(ctx *context.Context) {
+	ctx.Redirect(setting.AppSubURL + "/admin/hooks")
+}
+
+// DeleteDefaultOrSystemWebhook deletes a system or default webhook
+func DeleteDefaultOrSystemWebhook(ctx *context.Context) {
+	if err := models.DeleteDefaultSystemWebhook(ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteDefaultSystemWebhook: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.webhook_deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/admin/hooks",
+	})
+}
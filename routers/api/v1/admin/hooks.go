@@ -0,0 +1,169 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// isSystemWebhookType returns whether the `type` query parameter selects the
+// system webhooks (the default) or the default webhooks
+func isSystemWebhookType(ctx *context.APIContext) bool {
+	return ctx.Query("type") != "default"
+}
+
+// ListHooks list the system or default webhooks
+func ListHooks(ctx *context.APIContext) {
+	// swagger:operation GET /admin/hooks admin adminListHooks
+	// ---
+	// summary: List the system webhooks, or the default webhooks copied into new repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: kind of webhooks to list, either "system" (the default) or "default"
+	//   type: string
+	//   enum: [system, default]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookList"
+	isSystemWebhook := isSystemWebhookType(ctx)
+
+	var hooks []*models.Webhook
+	var err error
+	if isSystemWebhook {
+		hooks, err = models.GetSystemWebhooks()
+	} else {
+		hooks, err = models.GetDefaultWebhooks()
+	}
+	if err != nil {
+		ctx.Error(500, "GetSystemOrDefaultWebhooks", err)
+		return
+	}
+
+	hooksLink := utils.AdminHooksLink(isSystemWebhook)
+	apiHooks := make([]*api.Hook, len(hooks))
+	for i := range hooks {
+		apiHooks[i] = convert.ToHook(hooksLink, hooks[i])
+	}
+	ctx.JSON(200, &apiHooks)
+}
+
+// GetHook get a system or default webhook by id
+func GetHook(ctx *context.APIContext) {
+	// swagger:operation GET /admin/hooks/{id} admin adminGetHook
+	// ---
+	// summary: Get a system or default webhook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to get
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	hook, err := utils.GetAdminHook(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	ctx.JSON(200, convert.ToHook(utils.AdminHooksLink(hook.IsSystemWebhook), hook))
+}
+
+// CreateHook create a system or default webhook
+func CreateHook(ctx *context.APIContext, form api.CreateHookOption) {
+	// swagger:operation POST /admin/hooks admin adminCreateHook
+	// ---
+	// summary: Create a system or default webhook
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: kind of webhook to create, either "system" (the default) or "default"
+	//   type: string
+	//   enum: [system, default]
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateHookOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Hook"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if !utils.CheckCreateHookOption(ctx, &form) {
+		return
+	}
+	utils.AddAdminHook(ctx, &form, isSystemWebhookType(ctx))
+}
+
+// EditHook modify a system or default webhook
+func EditHook(ctx *context.APIContext, form api.EditHookOption) {
+	// swagger:operation PATCH /admin/hooks/{id} admin adminEditHook
+	// ---
+	// summary: Update a system or default webhook
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to update
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	utils.EditAdminHook(ctx, &form, ctx.ParamsInt64(":id"))
+}
+
+// DeleteHook delete a system or default webhook
+func DeleteHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/hooks/{id} admin adminDeleteHook
+	// ---
+	// summary: Delete a system or default webhook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to delete
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if err := models.DeleteDefaultSystemWebhook(ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "DeleteDefaultSystemWebhook", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
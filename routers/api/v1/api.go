@@ -53,6 +53,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/routers/api/v1/admin"
 	"code.gitea.io/gitea/routers/api/v1/misc"
+	"code.gitea.io/gitea/routers/api/v1/notify"
 	"code.gitea.io/gitea/routers/api/v1/org"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
@@ -109,7 +110,12 @@ func repoAssignment() macaron.Handler {
 		}
 		repo.Owner = owner
 
-		if ctx.IsSigned && ctx.User.IsAdmin {
+		if !ctx.TokenCanAccessRepo(repo) {
+			ctx.Status(404)
+			return
+		}
+
+		if ctx.IsSigned && ctx.User.IsAdmin && ctx.TokenHasScope(models.AccessTokenScopeAdmin) {
 			ctx.Repo.AccessMode = models.AccessModeOwner
 		} else {
 			mode, err := models.AccessLevel(utils.UserID(ctx), repo)
@@ -129,6 +135,79 @@ func repoAssignment() macaron.Handler {
 	}
 }
 
+// requiredTokenScopes returns the access token scopes of which at least one is
+// needed to call the endpoint with given method and path.
+func requiredTokenScopes(method, path string) []models.AccessTokenScope {
+	if i := strings.Index(path, "/api/v1/"); i >= 0 {
+		path = path[i+len("/api/v1/"):]
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	isRead := method == "GET" || method == "HEAD"
+	repoScopes := []models.AccessTokenScope{models.AccessTokenScopeRepo}
+	if isRead {
+		repoScopes = []models.AccessTokenScope{models.AccessTokenScopeRepoRead}
+	}
+	issueScopes := []models.AccessTokenScope{models.AccessTokenScopeIssue}
+	if isRead {
+		issueScopes = []models.AccessTokenScope{models.AccessTokenScopeIssue, models.AccessTokenScopeRepoRead}
+	}
+
+	switch parts[0] {
+	case "admin":
+		return []models.AccessTokenScope{models.AccessTokenScopeAdmin}
+	case "notifications":
+		return []models.AccessTokenScope{models.AccessTokenScopeNotification}
+	case "repos", "repositories":
+		// repos/:username/:reponame/issues/...
+		if len(parts) > 3 {
+			switch parts[3] {
+			case "pulls":
+				// creating, editing and merging a pull request writes to the repository,
+				// only the metadata of a pull request (e.g. reviews) is issue-like
+				if !isRead && (len(parts) <= 5 || parts[5] == "merge") {
+					return repoScopes
+				}
+				return issueScopes
+			case "issues", "labels", "milestones", "times":
+				return issueScopes
+			case "notifications":
+				return []models.AccessTokenScope{models.AccessTokenScopeNotification}
+			}
+		}
+		return repoScopes
+	case "user", "users":
+		// user/repos, users/:username/repos
+		if parts[len(parts)-1] == "repos" {
+			return repoScopes
+		} else if parts[len(parts)-1] == "orgs" {
+			return []models.AccessTokenScope{models.AccessTokenScopeOrg}
+		} else if len(parts) > 1 && parts[1] == "times" {
+			return issueScopes
+		}
+		return []models.AccessTokenScope{models.AccessTokenScopeUser}
+	case "org", "orgs", "teams":
+		// org/:org/repos, orgs/:orgname/repos
+		if parts[0] != "teams" && len(parts) == 3 && parts[2] == "repos" {
+			return repoScopes
+		}
+		return []models.AccessTokenScope{models.AccessTokenScopeOrg}
+	}
+	return nil
+}
+
+// reqTokenScope rejects requests authenticated by an access token which has
+// not been granted the scope required by the endpoint.
+func reqTokenScope() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		scopes := requiredTokenScopes(ctx.Req.Method, ctx.Req.URL.Path)
+		if len(scopes) > 0 && !ctx.TokenHasScope(scopes...) {
+			ctx.Error(403, "", "Token does not have the required scope")
+			return
+		}
+	}
+}
+
 // Contexter middleware already checks token for user sign in process.
 func reqToken() macaron.Handler {
 	return func(ctx *context.Context) {
@@ -166,6 +245,33 @@ func reqRepoWriter() macaron.Handler {
 	}
 }
 
+func reqRepoAdmin() macaron.Handler {
+	return func(ctx *context.Context) {
+		if !ctx.Repo.IsAdmin() {
+			ctx.Error(403)
+			return
+		}
+	}
+}
+
+func reqRepoNotArchived() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.Repo.Repository.IsArchived {
+			ctx.Error(403, "", "Repository is archived")
+			return
+		}
+	}
+}
+
+func reqRepoNotBare() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.Repo.Repository.IsBare {
+			ctx.Status(404)
+			return
+		}
+	}
+}
+
 func reqOrgMembership() macaron.Handler {
 	return func(ctx *context.APIContext) {
 		var orgID int64
@@ -266,6 +372,13 @@ func mustEnableIssues(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableWiki(ctx *context.APIContext) {
+	if !ctx.Repo.Repository.UnitEnabled(models.UnitTypeWiki) {
+		ctx.Status(404)
+		return
+	}
+}
+
 func mustAllowPulls(ctx *context.Context) {
 	if !ctx.Repo.Repository.AllowsPulls() {
 		ctx.Status(404)
@@ -365,6 +478,16 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
 		}, reqToken())
 
+		// Notifications
+		m.Group("/notifications", func() {
+			m.Combo("").
+				Get(notify.ListNotifications).
+				Put(notify.ReadNotifications)
+			m.Combo("/threads/:id").
+				Get(notify.GetThread).
+				Patch(notify.ReadThread)
+		}, reqToken())
+
 		// Repositories
 		m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 
@@ -372,6 +495,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/search", repo.Search)
 		})
 
+		m.Get("/topics/search", repo.TopicSearch)
+
 		m.Combo("/repositories/:id", reqToken()).Get(repo.GetByID)
 
 		m.Group("/repos", func() {
@@ -379,12 +504,19 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 			m.Group("/:username/:reponame", func() {
 				m.Combo("").Get(repo.Get).Delete(reqToken(), repo.Delete)
+				m.Combo("/archived", reqToken(), reqRepoAdmin()).
+					Put(repo.Archive).
+					Delete(repo.Unarchive)
 				m.Group("/hooks", func() {
 					m.Combo("").Get(repo.ListHooks).
 						Post(bind(api.CreateHookOption{}), repo.CreateHook)
 					m.Combo("/:id").Get(repo.GetHook).
 						Patch(bind(api.EditHookOption{}), repo.EditHook).
 						Delete(repo.DeleteHook)
+					m.Get("/:id/deliveries", repo.ListHookDeliveries)
+					m.Get("/:id/deliveries/:uuid", repo.GetHookDelivery)
+					m.Post("/:id/deliveries/:uuid/redeliver", repo.RedeliverHook)
+					m.Post("/:id/tests", repo.TestHook)
 				}, reqToken(), reqRepoWriter())
 				m.Group("/collaborators", func() {
 					m.Get("", repo.ListCollaborators)
@@ -392,14 +524,31 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Put(bind(api.AddCollaboratorOption{}), repo.AddCollaborator).
 						Delete(repo.DeleteCollaborator)
 				}, reqToken())
+				m.Combo("/notifications", reqToken()).
+					Get(notify.ListRepoNotifications).
+					Put(notify.ReadRepoNotifications)
 				m.Get("/raw/*", context.RepoRefByType(context.RepoRefAny), repo.GetRawFile)
 				m.Get("/archive/*", repo.GetArchive)
+				m.Group("/contents", func() {
+					m.Get("", repo.GetContents)
+					m.Combo("/*").Get(repo.GetContents).
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.CreateFileOptions{}), repo.CreateFile).
+						Put(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.UpdateFileOptions{}), repo.UpdateFile).
+						Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.DeleteFileOptions{}), repo.DeleteFile)
+				}, context.ReferencesGitRepo())
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), bind(api.CreateForkOption{}), repo.CreateFork)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", context.RepoRefByType(context.RepoRefBranch), repo.GetBranch)
 				})
+				m.Group("/branch_protections", func() {
+					m.Combo("").Get(repo.ListBranchProtections).
+						Post(bind(api.CreateBranchProtectionOption{}), repo.CreateBranchProtection)
+					m.Combo("/*").Get(repo.GetBranchProtection).
+						Patch(bind(api.EditBranchProtectionOption{}), repo.EditBranchProtection).
+						Delete(repo.DeleteBranchProtection)
+				}, reqToken(), reqRepoAdmin(), context.ReferencesGitRepo())
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
 						Post(bind(api.CreateKeyOption{}), repo.CreateDeployKey)
@@ -413,51 +562,65 @@ func RegisterRoutes(m *macaron.Macaron) {
 				}, mustEnableIssues)
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
-						Post(reqToken(), bind(api.CreateIssueOption{}), repo.CreateIssue)
+						Post(reqToken(), reqRepoNotArchived(), bind(api.CreateIssueOption{}), repo.CreateIssue)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
-						m.Combo("/:id", reqToken()).
+						m.Combo("/:id", reqToken(), reqRepoNotArchived()).
 							Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
 							Delete(repo.DeleteIssueComment)
+						m.Combo("/:id/reactions").Get(repo.GetCommentReactions).
+							Post(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.PostCommentReaction).
+							Delete(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.DeleteCommentReaction)
 					})
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetIssue).
-							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
+							Patch(reqToken(), reqRepoNotArchived(), bind(api.EditIssueOption{}), repo.EditIssue)
 
 						m.Group("/comments", func() {
 							m.Combo("").Get(repo.ListIssueComments).
-								Post(reqToken(), bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
-							m.Combo("/:id", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
+								Post(reqToken(), reqRepoNotArchived(), bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
+							m.Combo("/:id", reqToken(), reqRepoNotArchived()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
 
 						m.Group("/labels", func() {
 							m.Combo("").Get(repo.ListIssueLabels).
-								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
-								Put(reqToken(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
-								Delete(reqToken(), repo.ClearIssueLabels)
-							m.Delete("/:id", reqToken(), repo.DeleteIssueLabel)
+								Post(reqToken(), reqRepoNotArchived(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
+								Put(reqToken(), reqRepoNotArchived(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
+								Delete(reqToken(), reqRepoNotArchived(), repo.ClearIssueLabels)
+							m.Delete("/:id", reqToken(), reqRepoNotArchived(), repo.DeleteIssueLabel)
 						})
 
 						m.Group("/times", func() {
 							m.Combo("").Get(repo.ListTrackedTimes).
-								Post(reqToken(), bind(api.AddTimeOption{}), repo.AddTime)
+								Post(reqToken(), reqRepoNotArchived(), bind(api.AddTimeOption{}), repo.AddTime)
 						})
+
+						m.Combo("/reactions").Get(repo.GetIssueReactions).
+							Post(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.PostIssueReaction).
+							Delete(reqToken(), reqRepoNotArchived(), bind(api.EditReactionOption{}), repo.DeleteIssueReaction)
+
+						m.Combo("/dependencies").Get(repo.ListIssueDependencies).
+							Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.IssueMeta{}), repo.CreateIssueDependency).
+							Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.IssueMeta{}), repo.RemoveIssueDependency)
+						m.Combo("/blocks").Get(repo.ListIssueBlocks).
+							Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.IssueMeta{}), repo.CreateIssueBlocking).
+							Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.IssueMeta{}), repo.RemoveIssueBlocking)
 					})
 				}, mustEnableIssues)
 				m.Group("/labels", func() {
 					m.Combo("").Get(repo.ListLabels).
-						Post(reqToken(), bind(api.CreateLabelOption{}), repo.CreateLabel)
+						Post(reqToken(), reqRepoNotArchived(), bind(api.CreateLabelOption{}), repo.CreateLabel)
 					m.Combo("/:id").Get(repo.GetLabel).
-						Patch(reqToken(), bind(api.EditLabelOption{}), repo.EditLabel).
-						Delete(reqToken(), repo.DeleteLabel)
+						Patch(reqToken(), reqRepoNotArchived(), bind(api.EditLabelOption{}), repo.EditLabel).
+						Delete(reqToken(), reqRepoNotArchived(), repo.DeleteLabel)
 				})
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
-						Post(reqToken(), reqRepoWriter(), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
 					m.Combo("/:id").Get(repo.GetMilestone).
-						Patch(reqToken(), reqRepoWriter(), bind(api.EditMilestoneOption{}), repo.EditMilestone).
-						Delete(reqToken(), reqRepoWriter(), repo.DeleteMilestone)
+						Patch(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.EditMilestoneOption{}), repo.EditMilestone).
+						Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.DeleteMilestone)
 				})
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
@@ -468,41 +631,84 @@ func RegisterRoutes(m *macaron.Macaron) {
 				})
 				m.Group("/releases", func() {
 					m.Combo("").Get(repo.ListReleases).
-						Post(reqToken(), reqRepoWriter(), context.ReferencesGitRepo(), bind(api.CreateReleaseOption{}), repo.CreateRelease)
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), context.ReferencesGitRepo(), bind(api.CreateReleaseOption{}), repo.CreateRelease)
 					m.Group("/:id", func() {
 						m.Combo("").Get(repo.GetRelease).
-							Patch(reqToken(), reqRepoWriter(), context.ReferencesGitRepo(), bind(api.EditReleaseOption{}), repo.EditRelease).
-							Delete(reqToken(), reqRepoWriter(), repo.DeleteRelease)
+							Patch(reqToken(), reqRepoWriter(), reqRepoNotArchived(), context.ReferencesGitRepo(), bind(api.EditReleaseOption{}), repo.EditRelease).
+							Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.DeleteRelease)
 						m.Group("/assets", func() {
 							m.Combo("").Get(repo.ListReleaseAttachments).
-								Post(reqToken(), reqRepoWriter(), repo.CreateReleaseAttachment)
+								Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.CreateReleaseAttachment)
 							m.Combo("/:asset").Get(repo.GetReleaseAttachment).
-								Patch(reqToken(), reqRepoWriter(), bind(api.EditAttachmentOptions{}), repo.EditReleaseAttachment).
-								Delete(reqToken(), reqRepoWriter(), repo.DeleteReleaseAttachment)
+								Patch(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.EditAttachmentOptions{}), repo.EditReleaseAttachment).
+								Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.DeleteReleaseAttachment)
 						})
 					})
 				})
 				m.Post("/mirror-sync", reqToken(), reqRepoWriter(), repo.MirrorSync)
+				m.Group("/push_mirrors", func() {
+					m.Combo("").Get(repo.ListPushMirrors).
+						Post(bind(api.CreatePushMirrorOption{}), repo.CreatePushMirror)
+					m.Group("/:id", func() {
+						m.Combo("").Get(repo.GetPushMirror).
+							Delete(repo.DeletePushMirror)
+						m.Post("/sync", repo.SyncPushMirror)
+					})
+				}, reqToken(), reqRepoAdmin())
+				m.Group("/topics", func() {
+					m.Combo("").Get(repo.ListTopics).
+						Put(reqToken(), reqRepoAdmin(), bind(api.RepoTopicOptions{}), repo.UpdateTopics)
+					m.Combo("/:topic", reqToken(), reqRepoAdmin()).
+						Put(repo.AddTopic).
+						Delete(repo.DeleteTopic)
+				})
 				m.Get("/editorconfig/:filename", context.RepoRef(), repo.GetEditorconfig)
 				m.Group("/pulls", func() {
 					m.Combo("").Get(bind(api.ListPullRequestsOptions{}), repo.ListPullRequests).
-						Post(reqToken(), reqRepoWriter(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetPullRequest).
-							Patch(reqToken(), reqRepoWriter(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
+							Patch(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
-							Post(reqToken(), reqRepoWriter(), bind(auth.MergePullRequestForm{}), repo.MergePullRequest)
+							Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(auth.MergePullRequestForm{}), repo.MergePullRequest)
+						m.Group("/reviews", func() {
+							m.Combo("").Get(repo.ListPullReviews).
+								Post(reqToken(), reqRepoNotArchived(), bind(api.CreatePullReviewOptions{}), repo.CreatePullReview)
+							m.Group("/:id", func() {
+								m.Get("", repo.GetPullReview)
+								m.Get("/comments", repo.GetPullReviewComments)
+							})
+						})
 					})
 
 				}, mustAllowPulls, context.ReferencesGitRepo())
 				m.Group("/statuses", func() {
 					m.Combo("/:sha").Get(repo.GetCommitStatuses).
-						Post(reqToken(), reqRepoWriter(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
 				})
 				m.Group("/commits/:ref", func() {
 					m.Get("/status", repo.GetCombinedCommitStatusByRef)
 					m.Get("/statuses", repo.GetCommitStatusesByRef)
 				})
+				m.Group("/wiki/pages", func() {
+					m.Combo("").Get(repo.ListWikiPages).
+						Post(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.CreateWikiPageOptions{}), repo.CreateWikiPage)
+					m.Combo("/:pageName").Get(repo.GetWikiPage).
+						Patch(reqToken(), reqRepoWriter(), reqRepoNotArchived(), bind(api.EditWikiPageOptions{}), repo.EditWikiPage).
+						Delete(reqToken(), reqRepoWriter(), reqRepoNotArchived(), repo.DeleteWikiPage)
+					m.Get("/:pageName/revisions", repo.ListWikiPageRevisions)
+				}, mustEnableWiki)
+				m.Group("", func() {
+					m.Get("/commits", repo.ListCommits)
+					m.Get("/tags", repo.ListTags)
+					m.Group("/git", func() {
+						m.Get("/commits/:sha", repo.GetSingleCommit)
+						m.Get("/trees/:sha", repo.GetTree)
+						m.Get("/blobs/:sha", repo.GetBlob)
+						m.Get("/refs", repo.ListGitRefs)
+						m.Get("/refs/*", repo.ListGitRefs)
+					})
+				}, reqRepoNotBare(), context.ReferencesGitRepo())
 			}, repoAssignment())
 		})
 
@@ -532,6 +738,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:id").Get(org.GetHook).
 					Patch(reqOrgOwnership(), bind(api.EditHookOption{}), org.EditHook).
 					Delete(reqOrgOwnership(), org.DeleteHook)
+				m.Get("/:id/deliveries", reqOrgOwnership(), org.ListHookDeliveries)
+				m.Get("/:id/deliveries/:uuid", reqOrgOwnership(), org.GetHookDelivery)
+				m.Post("/:id/deliveries/:uuid/redeliver", reqOrgOwnership(), org.RedeliverHook)
+				m.Post("/:id/tests", reqOrgOwnership(), org.TestHook)
 			}, reqToken(), reqOrgMembership())
 		}, orgAssignment(true))
 		m.Group("/teams/:teamid", func() {
@@ -570,6 +780,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 				})
 			})
+			m.Group("/hooks", func() {
+				m.Combo("").Get(admin.ListHooks).
+					Post(bind(api.CreateHookOption{}), admin.CreateHook)
+				m.Combo("/:id").Get(admin.GetHook).
+					Patch(bind(api.EditHookOption{}), admin.EditHook).
+					Delete(admin.DeleteHook)
+			})
 		}, reqAdmin())
-	}, context.APIContexter())
+	}, context.APIContexter(), reqTokenScope())
 }
@@ -0,0 +1,36 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredTokenScopes(t *testing.T) {
+	repo := []models.AccessTokenScope{models.AccessTokenScopeRepo}
+	issueWrite := []models.AccessTokenScope{models.AccessTokenScopeIssue}
+	issueRead := []models.AccessTokenScope{models.AccessTokenScopeIssue, models.AccessTokenScopeRepoRead}
+
+	for _, kase := range []struct {
+		Method   string
+		Path     string
+		Expected []models.AccessTokenScope
+	}{
+		{"GET", "/api/v1/repos/user2/repo1/pulls", issueRead},
+		{"GET", "/api/v1/repos/user2/repo1/pulls/2/merge", issueRead},
+		{"POST", "/api/v1/repos/user2/repo1/pulls", repo},
+		{"PATCH", "/api/v1/repos/user2/repo1/pulls/2", repo},
+		{"POST", "/api/v1/repos/user2/repo1/pulls/2/merge", repo},
+		{"POST", "/api/v1/repos/user2/repo1/pulls/2/reviews", issueWrite},
+		{"POST", "/api/v1/repos/user2/repo1/issues/1/comments", issueWrite},
+		{"GET", "/api/v1/user/repos", []models.AccessTokenScope{models.AccessTokenScopeRepoRead}},
+	} {
+		assert.Equal(t, kase.Expected, requiredTokenScopes(kase.Method, kase.Path), "%s %s", kase.Method, kase.Path)
+	}
+}
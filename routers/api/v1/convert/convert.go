@@ -6,6 +6,7 @@ package convert
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Unknwon/com"
 
@@ -36,51 +37,110 @@ func ToBranch(repo *models.Repository, b *models.Branch, c *git.Commit) *api.Bra
 
 // ToCommit convert a commit to api.PayloadCommit
 func ToCommit(repo *models.Repository, c *git.Commit) *api.PayloadCommit {
-	authorUsername := ""
-	if author, err := models.GetUserByEmail(c.Author.Email); err == nil {
-		authorUsername = author.Name
-	} else if !models.IsErrUserNotExist(err) {
-		log.Error(4, "GetUserByEmail: %v", err)
+	return &api.PayloadCommit{
+		ID:      c.ID.String(),
+		Message: c.Message(),
+		URL:     util.URLJoin(repo.Link(), "commit", c.ID.String()),
+		Author: &api.PayloadUser{
+			Name:     c.Author.Name,
+			Email:    c.Author.Email,
+			UserName: getUserNameByEmail(c.Author.Email),
+		},
+		Committer: &api.PayloadUser{
+			Name:     c.Committer.Name,
+			Email:    c.Committer.Email,
+			UserName: getUserNameByEmail(c.Committer.Email),
+		},
+		Timestamp:    c.Author.When,
+		Verification: toCommitVerification(c),
 	}
+}
 
-	committerUsername := ""
-	if committer, err := models.GetUserByEmail(c.Committer.Email); err == nil {
-		committerUsername = committer.Name
-	} else if !models.IsErrUserNotExist(err) {
-		log.Error(4, "GetUserByEmail: %v", err)
+// ToGitCommit convert a commit to api.Commit
+func ToGitCommit(repo *models.Repository, c *git.Commit) *api.Commit {
+	parents := make([]*api.CommitMeta, c.ParentCount())
+	for i := range parents {
+		id, _ := c.ParentID(i)
+		parents[i] = &api.CommitMeta{
+			URL: repo.APIURL() + "/git/commits/" + id.String(),
+			SHA: id.String(),
+		}
 	}
 
-	verif := models.ParseCommitWithSignature(c)
-	var signature, payload string
-	if c.Signature != nil {
-		signature = c.Signature.Signature
-		payload = c.Signature.Payload
+	return &api.Commit{
+		URL:     repo.APIURL() + "/git/commits/" + c.ID.String(),
+		SHA:     c.ID.String(),
+		HTMLURL: repo.HTMLURL() + "/commit/" + c.ID.String(),
+		Message: c.Message(),
+		Author: &api.CommitUser{
+			Name:     c.Author.Name,
+			Email:    c.Author.Email,
+			UserName: getUserNameByEmail(c.Author.Email),
+			Date:     c.Author.When,
+		},
+		Committer: &api.CommitUser{
+			Name:     c.Committer.Name,
+			Email:    c.Committer.Email,
+			UserName: getUserNameByEmail(c.Committer.Email),
+			Date:     c.Committer.When,
+		},
+		Tree: &api.CommitMeta{
+			URL: repo.APIURL() + "/git/trees/" + c.Tree.ID.String(),
+			SHA: c.Tree.ID.String(),
+		},
+		Parents:      parents,
+		Verification: toCommitVerification(c),
 	}
+}
 
-	return &api.PayloadCommit{
+// ToWikiCommit convert a commit of a wiki to api.WikiCommit
+func ToWikiCommit(c *git.Commit) *api.WikiCommit {
+	return &api.WikiCommit{
 		ID:      c.ID.String(),
 		Message: c.Message(),
-		URL:     util.URLJoin(repo.Link(), "commit", c.ID.String()),
-		Author: &api.PayloadUser{
+		Author: &api.CommitUser{
 			Name:     c.Author.Name,
 			Email:    c.Author.Email,
-			UserName: authorUsername,
+			UserName: getUserNameByEmail(c.Author.Email),
+			Date:     c.Author.When,
 		},
-		Committer: &api.PayloadUser{
+		Committer: &api.CommitUser{
 			Name:     c.Committer.Name,
 			Email:    c.Committer.Email,
-			UserName: committerUsername,
-		},
-		Timestamp: c.Author.When,
-		Verification: &api.PayloadCommitVerification{
-			Verified:  verif.Verified,
-			Reason:    verif.Reason,
-			Signature: signature,
-			Payload:   payload,
+			UserName: getUserNameByEmail(c.Committer.Email),
+			Date:     c.Committer.When,
 		},
 	}
 }
 
+// getUserNameByEmail returns the name of the user owning the email, if any
+func getUserNameByEmail(email string) string {
+	user, err := models.GetUserByEmail(email)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			log.Error(4, "GetUserByEmail: %v", err)
+		}
+		return ""
+	}
+	return user.Name
+}
+
+func toCommitVerification(c *git.Commit) *api.PayloadCommitVerification {
+	verif := models.ParseCommitWithSignature(c)
+	var signature, payload string
+	if c.Signature != nil {
+		signature = c.Signature.Signature
+		payload = c.Signature.Payload
+	}
+
+	return &api.PayloadCommitVerification{
+		Verified:  verif.Verified,
+		Reason:    verif.Reason,
+		Signature: signature,
+		Payload:   payload,
+	}
+}
+
 // ToPublicKey convert models.PublicKey to api.PublicKey
 func ToPublicKey(apiLink string, key *models.PublicKey) *api.PublicKey {
 	return &api.PublicKey{
@@ -139,7 +199,7 @@ func ToGPGKeyEmail(email *models.EmailAddress) *api.GPGKeyEmail {
 }
 
 // ToHook convert models.Webhook to api.Hook
-func ToHook(repoLink string, w *models.Webhook) *api.Hook {
+func ToHook(hooksLink string, w *models.Webhook) *api.Hook {
 	config := map[string]string{
 		"url":          w.URL,
 		"content_type": w.ContentType.Name(),
@@ -150,12 +210,18 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 		config["username"] = s.Username
 		config["icon_url"] = s.IconURL
 		config["color"] = s.Color
+	} else if w.HookTaskType == models.MATTERMOST {
+		s := w.GetMattermostHook()
+		config["channel"] = s.Channel
+		config["username"] = s.Username
+		config["icon_url"] = s.IconURL
+		config["color"] = s.Color
 	}
 
 	return &api.Hook{
 		ID:      w.ID,
 		Type:    w.HookTaskType.Name(),
-		URL:     fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		URL:     fmt.Sprintf("%s/%d", hooksLink, w.ID),
 		Active:  w.IsActive,
 		Config:  config,
 		Events:  w.EventsArray(),
@@ -164,6 +230,38 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	}
 }
 
+// ToHookDelivery convert models.HookTask to api.HookDelivery
+func ToHookDelivery(t *models.HookTask) *api.HookDelivery {
+	delivery := &api.HookDelivery{
+		ID:          t.ID,
+		UUID:        t.UUID,
+		Event:       string(t.EventType),
+		URL:         t.URL,
+		IsDelivered: t.IsDelivered,
+		IsSucceed:   t.IsSucceed,
+		Attempts:    t.Attempts,
+		Request: &api.HookDeliveryRequest{
+			Headers: map[string]string{},
+			Body:    t.PayloadContent,
+		},
+	}
+	if t.Delivered > 0 {
+		delivered := time.Unix(0, t.Delivered)
+		delivery.Delivered = &delivered
+	}
+	if t.RequestInfo != nil {
+		delivery.Request.Headers = t.RequestInfo.Headers
+	}
+	if t.ResponseInfo != nil {
+		delivery.Response = &api.HookDeliveryResponse{
+			Status:  t.ResponseInfo.Status,
+			Headers: t.ResponseInfo.Headers,
+			Body:    t.ResponseInfo.Body,
+		}
+	}
+	return delivery
+}
+
 // ToDeployKey convert models.DeployKey to api.DeployKey
 func ToDeployKey(apiLink string, key *models.DeployKey) *api.DeployKey {
 	return &api.DeployKey{
@@ -0,0 +1,252 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"time"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+)
+
+// parseTimeQuery parses the RFC 3339 timestamp of the query parameter `name`,
+// it returns 0 if the parameter is absent. If invalid, writes to `ctx`
+// accordingly and returns false.
+func parseTimeQuery(ctx *context.APIContext, name string) (int64, bool) {
+	value := ctx.Query(name)
+	if len(value) == 0 {
+		return 0, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		ctx.Error(422, "", "invalid "+name+": "+err.Error())
+		return 0, false
+	}
+	return t.Unix(), true
+}
+
+// getFindNotificationOptions returns the filters of the notifications of the
+// signed user given in the query. If invalid, writes to `ctx` accordingly
+// and returns nil.
+func getFindNotificationOptions(ctx *context.APIContext) *models.FindNotificationOptions {
+	since, ok := parseTimeQuery(ctx, "since")
+	if !ok {
+		return nil
+	}
+	before, ok := parseTimeQuery(ctx, "before")
+	if !ok {
+		return nil
+	}
+
+	opts := &models.FindNotificationOptions{
+		UserID:            ctx.User.ID,
+		Participating:     ctx.QueryBool("participating"),
+		UpdatedAfterUnix:  since,
+		UpdatedBeforeUnix: before,
+		Page:              ctx.QueryInt("page"),
+		PageSize:          convert.ToCorrectPageSize(ctx.QueryInt("limit")),
+	}
+	if !ctx.QueryBool("all") {
+		opts.Status = []models.NotificationStatus{models.NotificationStatusUnread, models.NotificationStatusPinned}
+	}
+	return opts
+}
+
+// listNotifications writes the notifications matching `opts` to `ctx`
+func listNotifications(ctx *context.APIContext, opts *models.FindNotificationOptions) {
+	notifications, err := models.FindNotifications(opts)
+	if err != nil {
+		ctx.Error(500, "FindNotifications", err)
+		return
+	}
+
+	apiNotifications := make([]*api.NotificationThread, len(notifications))
+	for i, n := range notifications {
+		if err = n.LoadAttributes(); err != nil {
+			ctx.Error(500, "LoadAttributes", err)
+			return
+		}
+		apiNotifications[i] = n.APIFormat()
+	}
+	ctx.JSON(200, &apiNotifications)
+}
+
+// readNotifications marks the unread notifications matching `opts` and last
+// updated before the `last_read_at` query parameter as read
+func readNotifications(ctx *context.APIContext, opts *models.FindNotificationOptions) {
+	lastReadAt, ok := parseTimeQuery(ctx, "last_read_at")
+	if !ok {
+		return
+	}
+	if lastReadAt == 0 {
+		lastReadAt = time.Now().Unix()
+	}
+	opts.Status = []models.NotificationStatus{models.NotificationStatusUnread}
+	opts.UpdatedBeforeUnix = lastReadAt
+
+	if err := models.UpdateNotificationsStatus(ctx.User, opts, models.NotificationStatusRead); err != nil {
+		ctx.Error(500, "UpdateNotificationsStatus", err)
+		return
+	}
+	ctx.Status(205)
+}
+
+// ListNotifications list the notifications of the signed user
+func ListNotifications(ctx *context.APIContext) {
+	// swagger:operation GET /notifications notification notifyGetList
+	// ---
+	// summary: List the notification threads of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: all
+	//   in: query
+	//   description: if true, also list the notifications marked as read
+	//   type: boolean
+	// - name: participating
+	//   in: query
+	//   description: if true, only list the notifications of issues and pull requests
+	//                the user posted, commented or explicitly watches
+	//   type: boolean
+	// - name: since
+	//   in: query
+	//   description: only list the notifications updated at or after the given time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only list the notifications updated at or before the given time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opts := getFindNotificationOptions(ctx)
+	if opts == nil {
+		return
+	}
+	listNotifications(ctx, opts)
+}
+
+// ReadNotifications mark all the notifications of the signed user as read
+func ReadNotifications(ctx *context.APIContext) {
+	// swagger:operation PUT /notifications notification notifyReadList
+	// ---
+	// summary: Mark the notification threads of the authenticated user as read
+	// parameters:
+	// - name: last_read_at
+	//   in: query
+	//   description: only mark the notifications updated at or before the given time as read,
+	//                in RFC 3339 format. Defaults to the current time.
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "205":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	readNotifications(ctx, &models.FindNotificationOptions{})
+}
+
+// ListRepoNotifications list the notifications of the signed user in a repository
+func ListRepoNotifications(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/notifications notification notifyGetRepoList
+	// ---
+	// summary: List the notification threads of the authenticated user in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: all
+	//   in: query
+	//   description: if true, also list the notifications marked as read
+	//   type: boolean
+	// - name: participating
+	//   in: query
+	//   description: if true, only list the notifications of issues and pull requests
+	//                the user posted, commented or explicitly watches
+	//   type: boolean
+	// - name: since
+	//   in: query
+	//   description: only list the notifications updated at or after the given time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only list the notifications updated at or before the given time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThreadList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opts := getFindNotificationOptions(ctx)
+	if opts == nil {
+		return
+	}
+	opts.RepoID = ctx.Repo.Repository.ID
+	listNotifications(ctx, opts)
+}
+
+// ReadRepoNotifications mark all the notifications of the signed user in a repository as read
+func ReadRepoNotifications(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/notifications notification notifyReadRepoList
+	// ---
+	// summary: Mark the notification threads of the authenticated user in a repository as read
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: last_read_at
+	//   in: query
+	//   description: only mark the notifications updated at or before the given time as read,
+	//                in RFC 3339 format. Defaults to the current time.
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "205":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	readNotifications(ctx, &models.FindNotificationOptions{RepoID: ctx.Repo.Repository.ID})
+}
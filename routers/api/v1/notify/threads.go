@@ -0,0 +1,111 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// getThread returns the notification given by the `:id` param. If it does
+// not exist or belongs to another user, writes to `ctx` accordingly and
+// returns nil.
+func getThread(ctx *context.APIContext) *models.Notification {
+	n, err := models.GetNotificationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrNotificationNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetNotificationByID", err)
+		}
+		return nil
+	}
+	if n.UserID != ctx.User.ID {
+		ctx.Status(403)
+		return nil
+	}
+	if err = n.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return nil
+	}
+	return n
+}
+
+// GetThread get a notification thread of the signed user
+func GetThread(ctx *context.APIContext) {
+	// swagger:operation GET /notifications/threads/{id} notification notifyGetThread
+	// ---
+	// summary: Get a notification thread
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the notification thread
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationThread"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	n := getThread(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, n.APIFormat())
+}
+
+// ReadThread change the status of a notification thread of the signed user
+func ReadThread(ctx *context.APIContext) {
+	// swagger:operation PATCH /notifications/threads/{id} notification notifyReadThread
+	// ---
+	// summary: Mark a notification thread as read, unread or pinned
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the notification thread
+	//   type: integer
+	//   required: true
+	// - name: to-status
+	//   in: query
+	//   description: status to mark the notification thread as, defaults to "read"
+	//   type: string
+	//   enum: [read, unread, pinned]
+	// responses:
+	//   "205":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	n := getThread(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var status models.NotificationStatus
+	switch ctx.Query("to-status") {
+	case "", "read":
+		status = models.NotificationStatusRead
+	case "unread":
+		status = models.NotificationStatusUnread
+	case "pinned":
+		status = models.NotificationStatusPinned
+	default:
+		ctx.Error(422, "", "invalid to-status: "+ctx.Query("to-status"))
+		return
+	}
+
+	if err := models.SetNotificationStatus(n.ID, ctx.User, status); err != nil {
+		ctx.Error(500, "SetNotificationStatus", err)
+		return
+	}
+	ctx.Status(205)
+}
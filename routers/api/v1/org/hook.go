@@ -31,7 +31,7 @@ func ListHooks(ctx *context.APIContext) {
 	}
 	hooks := make([]*api.Hook, len(orgHooks))
 	for i, hook := range orgHooks {
-		hooks[i] = convert.ToHook(org.HomeLink(), hook)
+		hooks[i] = convert.ToHook(org.HomeLink()+"/settings/hooks", hook)
 	}
 	ctx.JSON(200, hooks)
 }
@@ -52,7 +52,7 @@ func GetHook(ctx *context.APIContext) {
 	if err != nil {
 		return
 	}
-	ctx.JSON(200, convert.ToHook(org.HomeLink(), hook))
+	ctx.JSON(200, convert.ToHook(org.HomeLink()+"/settings/hooks", hook))
 }
 
 // CreateHook create a hook for an organization
@@ -111,3 +111,156 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(204)
 }
+
+// ListHookDeliveries list the deliveries of a hook of an organization
+func ListHookDeliveries(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/hooks/{id}/deliveries organization orgListHookDeliveries
+	// ---
+	// summary: List the deliveries of a hook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookDeliveryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	hook, err := utils.GetOrgHook(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.ListHookDeliveries(ctx, hook)
+}
+
+// GetHookDelivery get a delivery of a hook of an organization
+func GetHookDelivery(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/hooks/{id}/deliveries/{uuid} organization orgGetHookDelivery
+	// ---
+	// summary: Get the request and response of a delivery of a hook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the delivery
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookDelivery"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	hook, err := utils.GetOrgHook(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.GetHookDelivery(ctx, hook)
+}
+
+// RedeliverHook deliver again the payload of a delivery of a hook of an organization
+func RedeliverHook(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/{id}/deliveries/{uuid}/redeliver organization orgRedeliverHook
+	// ---
+	// summary: Deliver again the payload of a delivery of a hook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   required: true
+	// - name: uuid
+	//   in: path
+	//   description: uuid of the delivery
+	//   type: string
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/HookDelivery"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	hook, err := utils.GetOrgHook(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.RedeliverHook(ctx, hook)
+}
+
+// TestHook send a fake push event to a hook of an organization, using one of
+// the repositories of the organization
+func TestHook(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/{id}/tests organization orgTestHook
+	// ---
+	// summary: Test a push webhook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	org := ctx.Org.Organization
+	hook, err := utils.GetOrgHook(ctx, org.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	if err = org.GetRepositories(1, 1); err != nil {
+		ctx.Error(500, "GetRepositories", err)
+		return
+	} else if len(org.Repos) == 0 {
+		ctx.Error(422, "", "Organization has no repository to test the hook with")
+		return
+	}
+	utils.TestHook(ctx, hook, org.Repos[0])
+}
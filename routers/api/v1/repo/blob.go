@@ -0,0 +1,83 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// GetBlob get a blob of a repository
+func GetBlob(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/blobs/{sha} repository repoGetBlob
+	// ---
+	// summary: Get a blob of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the blob
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitBlobResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	sha := ctx.Params(":sha")
+	blob, err := ctx.Repo.GitRepo.GetBlob(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetBlob", err)
+		}
+		return
+	}
+
+	// the whole content is encoded into the response, refuse to load huge blobs into memory
+	if blob.Size() > setting.API.MaxBlobSize {
+		ctx.Error(403, "", fmt.Sprintf("blob is larger than %d bytes", setting.API.MaxBlobSize))
+		return
+	}
+
+	r, err := blob.Data()
+	if err != nil {
+		ctx.Error(500, "Data", err)
+		return
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, setting.API.MaxBlobSize))
+	if err != nil {
+		ctx.Error(500, "ReadAll", err)
+		return
+	}
+
+	ctx.JSON(200, &api.GitBlobResponse{
+		URL:      ctx.Repo.Repository.APIURL() + "/git/blobs/" + blob.ID.String(),
+		SHA:      blob.ID.String(),
+		Size:     int64(len(data)),
+		Encoding: "base64",
+		Content:  base64.StdEncoding.EncodeToString(data),
+	})
+}
@@ -0,0 +1,465 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListBranchProtections list the branch protections of a repository
+func ListBranchProtections(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branch_protections repository repoListBranchProtection
+	// ---
+	// summary: List branch protections for a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionList"
+	protectBranches, err := ctx.Repo.Repository.GetProtectedBranches()
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranches", err)
+		return
+	}
+
+	apiProtectBranches := make([]*api.BranchProtection, len(protectBranches))
+	for i := range protectBranches {
+		if apiProtectBranches[i], err = toBranchProtection(protectBranches[i]); err != nil {
+			ctx.Error(500, "toBranchProtection", err)
+			return
+		}
+	}
+	ctx.JSON(200, &apiProtectBranches)
+}
+
+// GetBranchProtection get the protection of a branch
+func GetBranchProtection(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branch_protections/{name} repository repoGetBranchProtection
+	// ---
+	// summary: Get the protection of a branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the protected branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtection"
+	protectBranch := getBranchProtection(ctx)
+	if ctx.Written() {
+		return
+	}
+	writeBranchProtection(ctx, 200, protectBranch)
+}
+
+// CreateBranchProtection protect a branch
+func CreateBranchProtection(ctx *context.APIContext, form api.CreateBranchProtectionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/branch_protections repository repoCreateBranchProtection
+	// ---
+	// summary: Protect a branch
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateBranchProtectionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	repo := ctx.Repo.Repository
+	branchName := strings.TrimSpace(form.BranchName)
+	if repo.IsBare || !ctx.Repo.GitRepo.IsBranchExist(branchName) {
+		ctx.Error(422, "", fmt.Sprintf("branch does not exist: %s", branchName))
+		return
+	}
+
+	protectBranch, err := models.GetProtectedBranchBy(repo.ID, branchName)
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return
+	} else if protectBranch != nil {
+		ctx.Error(422, "", fmt.Sprintf("branch is already protected: %s", branchName))
+		return
+	}
+
+	if !checkRequiredApprovals(ctx, form.RequiredApprovals) {
+		return
+	}
+	opts := models.WhitelistOptions{
+		UserIDs:      getWhitelistUserIDs(ctx, form.PushWhitelistUsernames),
+		TeamIDs:      getWhitelistTeamIDs(ctx, form.PushWhitelistTeams),
+		MergeUserIDs: getWhitelistUserIDs(ctx, form.MergeWhitelistUsernames),
+		MergeTeamIDs: getWhitelistTeamIDs(ctx, form.MergeWhitelistTeams),
+	}
+	if ctx.Written() {
+		return
+	}
+
+	protectBranch = &models.ProtectedBranch{
+		RepoID:               repo.ID,
+		BranchName:           branchName,
+		CanPush:              form.EnablePush,
+		EnableWhitelist:      form.EnablePushWhitelist,
+		EnableMergeWhitelist: form.EnableMergeWhitelist,
+		RequiredApprovals:    form.RequiredApprovals,
+		EnableStatusCheck:    form.EnableStatusCheck,
+		StatusCheckContexts:  cleanStatusCheckContexts(form.StatusCheckContexts),
+	}
+	if err = models.UpdateProtectBranch(repo, protectBranch, opts); err != nil {
+		ctx.Error(500, "UpdateProtectBranch", err)
+		return
+	}
+
+	writeBranchProtection(ctx, 201, protectBranch)
+}
+
+// EditBranchProtection edit the protection of a branch
+func EditBranchProtection(ctx *context.APIContext, form api.EditBranchProtectionOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/branch_protections/{name} repository repoEditBranchProtection
+	// ---
+	// summary: Edit the protection of a branch, only the given fields are changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the protected branch
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditBranchProtectionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	protectBranch := getBranchProtection(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.EnablePush != nil {
+		protectBranch.CanPush = *form.EnablePush
+	}
+	if form.EnablePushWhitelist != nil {
+		protectBranch.EnableWhitelist = *form.EnablePushWhitelist
+	}
+	if form.EnableMergeWhitelist != nil {
+		protectBranch.EnableMergeWhitelist = *form.EnableMergeWhitelist
+	}
+	if form.RequiredApprovals != nil {
+		if !checkRequiredApprovals(ctx, *form.RequiredApprovals) {
+			return
+		}
+		protectBranch.RequiredApprovals = *form.RequiredApprovals
+	}
+	if form.EnableStatusCheck != nil {
+		protectBranch.EnableStatusCheck = *form.EnableStatusCheck
+	}
+	if form.StatusCheckContexts != nil {
+		protectBranch.StatusCheckContexts = cleanStatusCheckContexts(form.StatusCheckContexts)
+	}
+
+	// the whitelists which are not given are kept
+	opts := models.WhitelistOptions{
+		UserIDs:      protectBranch.WhitelistUserIDs,
+		TeamIDs:      protectBranch.WhitelistTeamIDs,
+		MergeUserIDs: protectBranch.MergeWhitelistUserIDs,
+		MergeTeamIDs: protectBranch.MergeWhitelistTeamIDs,
+	}
+	if form.PushWhitelistUsernames != nil {
+		opts.UserIDs = getWhitelistUserIDs(ctx, form.PushWhitelistUsernames)
+	}
+	if form.PushWhitelistTeams != nil {
+		opts.TeamIDs = getWhitelistTeamIDs(ctx, form.PushWhitelistTeams)
+	}
+	if form.MergeWhitelistUsernames != nil {
+		opts.MergeUserIDs = getWhitelistUserIDs(ctx, form.MergeWhitelistUsernames)
+	}
+	if form.MergeWhitelistTeams != nil {
+		opts.MergeTeamIDs = getWhitelistTeamIDs(ctx, form.MergeWhitelistTeams)
+	}
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, opts); err != nil {
+		ctx.Error(500, "UpdateProtectBranch", err)
+		return
+	}
+
+	writeBranchProtection(ctx, 200, protectBranch)
+}
+
+// DeleteBranchProtection remove the protection of a branch
+func DeleteBranchProtection(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/branch_protections/{name} repository repoDeleteBranchProtection
+	// ---
+	// summary: Remove the protection of a branch
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the protected branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	protectBranch := getBranchProtection(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := ctx.Repo.Repository.DeleteProtectedBranch(protectBranch.ID); err != nil {
+		ctx.Error(500, "DeleteProtectedBranch", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+func getBranchProtection(ctx *context.APIContext) *models.ProtectedBranch {
+	protectBranch, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, ctx.Params("*"))
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranchBy", err)
+		return nil
+	} else if protectBranch == nil {
+		ctx.Status(404)
+		return nil
+	}
+	return protectBranch
+}
+
+func checkRequiredApprovals(ctx *context.APIContext, approvals int64) bool {
+	if approvals < 0 || approvals > 100 {
+		ctx.Error(422, "", "required_approvals must be between 0 and 100")
+		return false
+	}
+	return true
+}
+
+// getWhitelistUserIDs returns the IDs of the users, who must have write access
+// to the repository
+func getWhitelistUserIDs(ctx *context.APIContext, names []string) []int64 {
+	if ctx.Written() {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		user, err := models.GetUserByName(name)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", fmt.Sprintf("user does not exist: %s", name))
+			} else {
+				ctx.Error(500, "GetUserByName", err)
+			}
+			return nil
+		}
+
+		if has, err := models.HasAccess(user.ID, ctx.Repo.Repository, models.AccessModeWrite); err != nil {
+			ctx.Error(500, "HasAccess", err)
+			return nil
+		} else if !has {
+			ctx.Error(422, "", fmt.Sprintf("user does not have write access: %s", name))
+			return nil
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// getWhitelistTeamIDs returns the IDs of the teams of the organization owning
+// the repository, which must have write access to it
+func getWhitelistTeamIDs(ctx *context.APIContext, names []string) []int64 {
+	if ctx.Written() || len(names) == 0 {
+		return []int64{}
+	}
+	if !ctx.Repo.Owner.IsOrganization() {
+		ctx.Error(422, "", "teams can only be whitelisted in repositories of organizations")
+		return nil
+	}
+
+	teams, err := models.GetTeamsWithAccessToRepo(ctx.Repo.Owner.ID, ctx.Repo.Repository.ID, models.AccessModeWrite)
+	if err != nil {
+		ctx.Error(500, "GetTeamsWithAccessToRepo", err)
+		return nil
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		var team *models.Team
+		for i := range teams {
+			if teams[i].LowerName == strings.ToLower(name) {
+				team = teams[i]
+				break
+			}
+		}
+		if team == nil || !team.HasWriteAccess() {
+			ctx.Error(422, "", fmt.Sprintf("team does not exist or does not have write access: %s", name))
+			return nil
+		}
+		ids = append(ids, team.ID)
+	}
+	return ids
+}
+
+// cleanStatusCheckContexts drops empty and duplicate status check contexts
+func cleanStatusCheckContexts(contexts []string) []string {
+	result := make([]string, 0, len(contexts))
+	seen := make(map[string]bool, len(contexts))
+	for _, context := range contexts {
+		context = strings.TrimSpace(context)
+		if len(context) == 0 || seen[context] {
+			continue
+		}
+		seen[context] = true
+		result = append(result, context)
+	}
+	return result
+}
+
+func writeBranchProtection(ctx *context.APIContext, status int, protectBranch *models.ProtectedBranch) {
+	apiProtectBranch, err := toBranchProtection(protectBranch)
+	if err != nil {
+		ctx.Error(500, "toBranchProtection", err)
+		return
+	}
+	ctx.JSON(status, apiProtectBranch)
+}
+
+func toBranchProtection(protectBranch *models.ProtectedBranch) (*api.BranchProtection, error) {
+	pushUsers, err := getUserNames(protectBranch.WhitelistUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	mergeUsers, err := getUserNames(protectBranch.MergeWhitelistUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	pushTeams, err := getTeamNames(protectBranch.WhitelistTeamIDs)
+	if err != nil {
+		return nil, err
+	}
+	mergeTeams, err := getTeamNames(protectBranch.MergeWhitelistTeamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := protectBranch.StatusCheckContexts
+	if contexts == nil {
+		contexts = []string{}
+	}
+	return &api.BranchProtection{
+		BranchName:              protectBranch.BranchName,
+		EnablePush:              protectBranch.CanPush,
+		EnablePushWhitelist:     protectBranch.EnableWhitelist,
+		PushWhitelistUsernames:  pushUsers,
+		PushWhitelistTeams:      pushTeams,
+		EnableMergeWhitelist:    protectBranch.EnableMergeWhitelist,
+		MergeWhitelistUsernames: mergeUsers,
+		MergeWhitelistTeams:     mergeTeams,
+		RequiredApprovals:       protectBranch.RequiredApprovals,
+		EnableStatusCheck:       protectBranch.EnableStatusCheck,
+		StatusCheckContexts:     contexts,
+		Created:                 protectBranch.CreatedUnix.AsTime(),
+		Updated:                 protectBranch.UpdatedUnix.AsTime(),
+	}, nil
+}
+
+func getUserNames(ids []int64) ([]string, error) {
+	users, err := models.GetUsersByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(users))
+	for i := range users {
+		names[i] = users[i].Name
+	}
+	return names, nil
+}
+
+func getTeamNames(ids []int64) ([]string, error) {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		team, err := models.GetTeamByID(id)
+		if err != nil {
+			if err == models.ErrTeamNotExist {
+				continue
+			}
+			return nil, err
+		}
+		names = append(names, team.Name)
+	}
+	return names, nil
+}
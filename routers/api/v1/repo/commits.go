@@ -0,0 +1,142 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"container/list"
+	"fmt"
+
+	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+)
+
+// GetSingleCommit get a commit of a repository
+func GetSingleCommit(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/commits/{sha} repository repoGetSingleCommit
+	// ---
+	// summary: Get a single commit from a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: the commit hash
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Commit"
+	commit, err := ctx.Repo.GitRepo.GetCommit(ctx.Params(":sha"))
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetCommit", err)
+		}
+		return
+	}
+
+	ctx.JSON(200, convert.ToGitCommit(ctx.Repo.Repository, commit))
+}
+
+// ListCommits list the commits of a repository
+func ListCommits(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/commits repository repoListCommits
+	// ---
+	// summary: List the commits of a repository, from the most recent one
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: query
+	//   description: "branch, tag or commit to start listing commits from, default: the default branch of the repository"
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: only list the commits changing this file or directory
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of the results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitList"
+	ref := ctx.QueryTrim("sha")
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := getCommitByRef(ctx.Repo.GitRepo, ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "getCommitByRef", err)
+		}
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	var (
+		count   int64
+		commits *list.List
+	)
+	treePath := cleanTreePath(ctx.Query("path"))
+	if len(treePath) == 0 {
+		if count, err = commit.CommitsCount(); err != nil {
+			ctx.Error(500, "CommitsCount", err)
+			return
+		}
+		if commits, err = commit.CommitsByRange(page); err != nil {
+			ctx.Error(500, "CommitsByRange", err)
+			return
+		}
+	} else {
+		if count, err = ctx.Repo.GitRepo.FileCommitsCount(commit.ID.String(), treePath); err != nil {
+			ctx.Error(500, "FileCommitsCount", err)
+			return
+		}
+		if commits, err = ctx.Repo.GitRepo.CommitsByFileAndRange(commit.ID.String(), treePath, page); err != nil {
+			ctx.Error(500, "CommitsByFileAndRange", err)
+			return
+		}
+	}
+
+	apiCommits := make([]*api.Commit, 0, commits.Len())
+	for e := commits.Front(); e != nil; e = e.Next() {
+		apiCommits = append(apiCommits, convert.ToGitCommit(ctx.Repo.Repository, e.Value.(*git.Commit)))
+	}
+
+	ctx.SetLinkHeader(int(count), git.CommitsRangeSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.JSON(200, &apiCommits)
+}
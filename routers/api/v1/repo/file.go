@@ -5,10 +5,18 @@
 package repo
 
 import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
 	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/convert"
 	"code.gitea.io/gitea/routers/repo"
 )
 
@@ -140,3 +148,506 @@ func GetEditorconfig(ctx *context.APIContext) {
 	}
 	ctx.JSON(200, def)
 }
+
+// GetContents get the metadata and content of a file, or the entries of a directory
+func GetContents(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/contents/{filepath} repository repoGetContents
+	// ---
+	// summary: Get the metadata and content of a file, or the entries of a directory
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file or directory, the root directory if empty
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "name of the branch, tag or commit, default: the default branch of the repository"
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContentsResponse"
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	ref := ctx.QueryTrim("ref")
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := getCommitByRef(ctx.Repo.GitRepo, ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "getCommitByRef", err)
+		}
+		return
+	}
+
+	treePath := cleanTreePath(ctx.Params("*"))
+	if len(treePath) > 0 {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.Status(404)
+			} else {
+				ctx.Error(500, "GetTreeEntryByPath", err)
+			}
+			return
+		}
+
+		if !entry.IsDir() {
+			contents, err := toContentsResponse(ctx.Repo.Repository, commit, ref, treePath, entry, true)
+			if err != nil {
+				ctx.Error(500, "toContentsResponse", err)
+				return
+			}
+			ctx.JSON(200, contents)
+			return
+		}
+	}
+
+	tree, err := commit.SubTree(treePath)
+	if err != nil {
+		ctx.Error(500, "SubTree", err)
+		return
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		ctx.Error(500, "ListEntries", err)
+		return
+	}
+
+	contents := make([]*api.ContentsResponse, len(entries))
+	for i, entry := range entries {
+		if contents[i], err = toContentsResponse(ctx.Repo.Repository, commit, ref, path.Join(treePath, entry.Name()), entry, false); err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+	}
+	ctx.JSON(200, &contents)
+}
+
+// CreateFile create a file in a repository
+func CreateFile(ctx *context.APIContext, form api.CreateFileOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/contents/{filepath} repository repoCreateFile
+	// ---
+	// summary: Create a file in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to create
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateFileOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	treePath := cleanTreePath(ctx.Params("*"))
+	oldBranch, newBranch, commit := prepareFileCommit(ctx, treePath, form.Branch, form.NewBranch)
+	if ctx.Written() {
+		return
+	}
+
+	if _, err := commit.GetTreeEntryByPath(treePath); err == nil {
+		ctx.Error(422, "", fmt.Sprintf("file already exists: %s", treePath))
+		return
+	} else if !git.IsErrNotExist(err) {
+		ctx.Error(500, "GetTreeEntryByPath", err)
+		return
+	}
+
+	content, err := base64.StdEncoding.DecodeString(form.Content)
+	if err != nil {
+		ctx.Error(422, "", fmt.Sprintf("invalid base64 content: %v", err))
+		return
+	}
+
+	message := strings.TrimSpace(form.Message)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.add", treePath)
+	}
+
+	if err = ctx.Repo.Repository.UpdateRepoFile(ctx.User, models.UpdateRepoFileOptions{
+		LastCommitID: commit.ID.String(),
+		OldBranch:    oldBranch,
+		NewBranch:    newBranch,
+		OldTreeName:  treePath,
+		NewTreeName:  treePath,
+		Message:      message,
+		Content:      string(content),
+		IsNewFile:    true,
+	}); err != nil {
+		if models.IsErrRepoFileAlreadyExist(err) {
+			ctx.Error(422, "", fmt.Sprintf("file already exists: %s", treePath))
+		} else {
+			ctx.Error(500, "UpdateRepoFile", err)
+		}
+		return
+	}
+
+	writeFileResponse(ctx, 201, newBranch, treePath)
+}
+
+// UpdateFile update a file in a repository
+func UpdateFile(ctx *context.APIContext, form api.UpdateFileOptions) {
+	// swagger:operation PUT /repos/{owner}/{repo}/contents/{filepath} repository repoUpdateFile
+	// ---
+	// summary: Update a file in a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to update
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateFileOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	treePath := cleanTreePath(ctx.Params("*"))
+	oldBranch, newBranch, commit := prepareFileCommit(ctx, treePath, form.Branch, form.NewBranch)
+	if ctx.Written() {
+		return
+	}
+	if !checkFileSHA(ctx, commit, treePath, form.SHA) {
+		return
+	}
+
+	content, err := base64.StdEncoding.DecodeString(form.Content)
+	if err != nil {
+		ctx.Error(422, "", fmt.Sprintf("invalid base64 content: %v", err))
+		return
+	}
+
+	message := strings.TrimSpace(form.Message)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.update", treePath)
+	}
+
+	if err = ctx.Repo.Repository.UpdateRepoFile(ctx.User, models.UpdateRepoFileOptions{
+		LastCommitID: commit.ID.String(),
+		OldBranch:    oldBranch,
+		NewBranch:    newBranch,
+		OldTreeName:  treePath,
+		NewTreeName:  treePath,
+		Message:      message,
+		Content:      string(content),
+		SHA:          form.SHA,
+	}); err != nil {
+		if models.IsErrSHADoesNotMatch(err) {
+			ctx.Error(409, "", err)
+		} else {
+			ctx.Error(500, "UpdateRepoFile", err)
+		}
+		return
+	}
+
+	writeFileResponse(ctx, 200, newBranch, treePath)
+}
+
+// DeleteFile delete a file from a repository
+func DeleteFile(ctx *context.APIContext, form api.DeleteFileOptions) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/contents/{filepath} repository repoDeleteFile
+	// ---
+	// summary: Delete a file from a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to delete
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/DeleteFileOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	treePath := cleanTreePath(ctx.Params("*"))
+	oldBranch, newBranch, commit := prepareFileCommit(ctx, treePath, form.Branch, form.NewBranch)
+	if ctx.Written() {
+		return
+	}
+	if !checkFileSHA(ctx, commit, treePath, form.SHA) {
+		return
+	}
+
+	message := strings.TrimSpace(form.Message)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.delete", treePath)
+	}
+
+	if err := ctx.Repo.Repository.DeleteRepoFile(ctx.User, models.DeleteRepoFileOptions{
+		LastCommitID: commit.ID.String(),
+		OldBranch:    oldBranch,
+		NewBranch:    newBranch,
+		TreePath:     treePath,
+		Message:      message,
+		SHA:          form.SHA,
+	}); err != nil {
+		if models.IsErrSHADoesNotMatch(err) {
+			ctx.Error(409, "", err)
+		} else {
+			ctx.Error(500, "DeleteRepoFile", err)
+		}
+		return
+	}
+
+	writeFileResponse(ctx, 200, newBranch, "")
+}
+
+// cleanTreePath returns the path relative to the root of the repository,
+// without any ".." element
+func cleanTreePath(treePath string) string {
+	return strings.Trim(path.Clean("/"+treePath), "/")
+}
+
+// getCommitByRef returns the commit of a branch, a tag or a commit ID
+func getCommitByRef(gitRepo *git.Repository, ref string) (*git.Commit, error) {
+	if gitRepo.IsBranchExist(ref) {
+		return gitRepo.GetBranchCommit(ref)
+	} else if gitRepo.IsTagExist(ref) {
+		return gitRepo.GetTagCommit(ref)
+	}
+	return gitRepo.GetCommit(ref)
+}
+
+// prepareFileCommit checks that the doer can commit a change of treePath to the
+// branch, or to a new branch based on it, and returns the names of both branches
+// with the current commit of the base branch
+func prepareFileCommit(ctx *context.APIContext, treePath, branch, newBranch string) (string, string, *git.Commit) {
+	repo := ctx.Repo.Repository
+	if !repo.CanEnableEditor() {
+		ctx.Error(403, "", "Repository cannot be edited")
+		return "", "", nil
+	} else if repo.IsBare {
+		ctx.Error(422, "", "Repository is empty")
+		return "", "", nil
+	} else if len(treePath) == 0 {
+		ctx.Error(422, "", "File path cannot be empty")
+		return "", "", nil
+	}
+
+	if len(branch) == 0 {
+		branch = repo.DefaultBranch
+	}
+	if len(newBranch) == 0 {
+		newBranch = branch
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetBranchCommit(branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Error(404, "", fmt.Sprintf("branch does not exist: %s", branch))
+		} else {
+			ctx.Error(500, "GetBranchCommit", err)
+		}
+		return "", "", nil
+	}
+
+	if newBranch != branch {
+		if ctx.Repo.GitRepo.IsBranchExist(newBranch) {
+			ctx.Error(422, "", fmt.Sprintf("branch already exists: %s", newBranch))
+			return "", "", nil
+		}
+	} else if protected, err := repo.IsProtectedBranch(branch, ctx.User); err != nil {
+		ctx.Error(500, "IsProtectedBranch", err)
+		return "", "", nil
+	} else if protected {
+		ctx.Error(403, "", fmt.Sprintf("branch is protected: %s", branch))
+		return "", "", nil
+	}
+
+	// the parent directories of the file must not be files
+	var parentPath string
+	parents := strings.Split(treePath, "/")
+	for _, part := range parents[:len(parents)-1] {
+		parentPath = path.Join(parentPath, part)
+		entry, err := commit.GetTreeEntryByPath(parentPath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				break
+			}
+			ctx.Error(500, "GetTreeEntryByPath", err)
+			return "", "", nil
+		}
+		if !entry.IsDir() {
+			ctx.Error(422, "", fmt.Sprintf("%s is not a directory", parentPath))
+			return "", "", nil
+		}
+	}
+
+	return branch, newBranch, commit
+}
+
+// checkFileSHA makes sure the file to change exists and has the blob SHA
+// given by the client
+func checkFileSHA(ctx *context.APIContext, commit *git.Commit, treePath, sha string) bool {
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetTreeEntryByPath", err)
+		}
+		return false
+	}
+
+	if entry.IsDir() || entry.IsSubModule() {
+		ctx.Error(422, "", fmt.Sprintf("%s is not a file", treePath))
+		return false
+	} else if len(sha) == 0 {
+		ctx.Error(422, "", "sha is required")
+		return false
+	} else if sha != entry.ID.String() {
+		ctx.Error(409, "", models.ErrSHADoesNotMatch{Path: treePath, GivenSHA: sha, CurrentSHA: entry.ID.String()})
+		return false
+	}
+	return true
+}
+
+// writeFileResponse writes the file and the commit of a change made to the branch,
+// the file is omitted if treePath is empty
+func writeFileResponse(ctx *context.APIContext, status int, branch, treePath string) {
+	commit, err := ctx.Repo.GitRepo.GetBranchCommit(branch)
+	if err != nil {
+		ctx.Error(500, "GetBranchCommit", err)
+		return
+	}
+
+	resp := &api.FileResponse{
+		Commit: convert.ToCommit(ctx.Repo.Repository, commit),
+	}
+	if len(treePath) > 0 {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			ctx.Error(500, "GetTreeEntryByPath", err)
+			return
+		}
+		if resp.Content, err = toContentsResponse(ctx.Repo.Repository, commit, branch, treePath, entry, false); err != nil {
+			ctx.Error(500, "toContentsResponse", err)
+			return
+		}
+	}
+	ctx.JSON(status, resp)
+}
+
+func toContentsResponse(repo *models.Repository, commit *git.Commit, ref, treePath string, entry *git.TreeEntry, withContent bool) (*api.ContentsResponse, error) {
+	contents := &api.ContentsResponse{
+		Name:    entry.Name(),
+		Path:    treePath,
+		SHA:     entry.ID.String(),
+		URL:     repo.APIURL() + "/contents/" + treePath + "?ref=" + ref,
+		HTMLURL: repo.HTMLURL() + "/src/commit/" + commit.ID.String() + "/" + treePath,
+	}
+
+	switch {
+	case entry.IsDir():
+		contents.Type = "dir"
+	case entry.IsSubModule():
+		contents.Type = "submodule"
+	case entry.IsLink():
+		contents.Type = "symlink"
+	default:
+		contents.Type = "file"
+	}
+	if contents.Type != "file" && contents.Type != "symlink" {
+		return contents, nil
+	}
+
+	contents.Size = entry.Size()
+	contents.DownloadURL = repo.HTMLURL() + "/raw/commit/" + commit.ID.String() + "/" + treePath
+	if !withContent {
+		return contents, nil
+	}
+
+	r, err := entry.Blob().Data()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	contents.Encoding = "base64"
+	contents.Content = base64.StdEncoding.EncodeToString(data)
+	return contents, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListGitRefs list the references of a repository
+func ListGitRefs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/refs repository repoListAllGitRefs
+	// ---
+	// summary: Get the references of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReferenceList"
+
+	// swagger:operation GET /repos/{owner}/{repo}/git/refs/{ref} repository repoListGitRefs
+	// ---
+	// summary: Get the references of a repository starting with a prefix
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: part of the reference name after "refs/", e.g. "heads/master" or "tags"
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReferenceList"
+	prefix := ctx.Params("*")
+	refs, err := models.GetGitReferences(ctx.Repo.GitRepo, "refs/"+prefix)
+	if err != nil {
+		ctx.Error(500, "GetGitReferences", err)
+		return
+	}
+	if len(prefix) > 0 && len(refs) == 0 {
+		ctx.Status(404)
+		return
+	}
+
+	apiRefs := make([]*api.Reference, len(refs))
+	for i, ref := range refs {
+		apiRefs[i] = &api.Reference{
+			Ref: ref.Name,
+			URL: ctx.Repo.Repository.APIURL() + "/git/" + ref.Name,
+			Object: &api.GitObject{
+				Type: ref.ObjectType,
+				SHA:  ref.ObjectID,
+			},
+		}
+		if ref.ObjectType == "commit" {
+			apiRefs[i].Object.URL = ctx.Repo.Repository.APIURL() + "/git/commits/" + ref.ObjectID
+		}
+	}
+	ctx.JSON(200, &apiRefs)
+}
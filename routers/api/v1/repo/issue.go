@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/indexer"
+	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 )
@@ -171,29 +172,35 @@ func CreateIssue(ctx *context.APIContext, form api.CreateIssueOption) {
 		Content:  form.Body,
 	}
 
+	var assigneeIDs []int64
+	var err error
 	if ctx.Repo.IsWriter() {
-		if len(form.Assignee) > 0 {
-			assignee, err := models.GetUserByName(form.Assignee)
-			if err != nil {
-				if models.IsErrUserNotExist(err) {
-					ctx.Error(422, "", fmt.Sprintf("Assignee does not exist: [name: %s]", form.Assignee))
-				} else {
-					ctx.Error(500, "GetUserByName", err)
-				}
-				return
+		issue.MilestoneID = form.Milestone
+		assigneeIDs, err = models.MakeIDsFromAPIAssigneesToAdd(form.Assignee, form.Assignees)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", fmt.Sprintf("Assignee does not exist: %v", err))
+			} else {
+				ctx.Error(500, "MakeIDsFromAPIAssigneesToAdd", err)
 			}
-			issue.AssigneeID = assignee.ID
+			return
 		}
-		issue.MilestoneID = form.Milestone
 	} else {
+		// setting labels and assignees is not allowed if user is not a writer
 		form.Labels = nil
 	}
 
-	if err := models.NewIssue(ctx.Repo.Repository, issue, form.Labels, nil); err != nil {
+	if err := models.NewIssue(ctx.Repo.Repository, issue, form.Labels, assigneeIDs, nil); err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(422, "", fmt.Sprintf("Assignee does not exist: %v", err))
+			return
+		}
 		ctx.Error(500, "NewIssue", err)
 		return
 	}
 
+	notification.Service.NotifyNewIssue(issue, ctx.User.ID)
+
 	if form.Closed {
 		if err := issue.ChangeStatus(ctx.User, ctx.Repo.Repository, true); err != nil {
 			ctx.Error(500, "ChangeStatus", err)
@@ -202,7 +209,6 @@ func CreateIssue(ctx *context.APIContext, form api.CreateIssueOption) {
 	}
 
 	// Refetch from database to assign some automatic values
-	var err error
 	issue, err = models.GetIssueByID(issue.ID)
 	if err != nil {
 		ctx.Error(500, "GetIssueByID", err)
@@ -243,6 +249,8 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Issue"
+	//   "412":
+	//     "$ref": "#/responses/error"
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrIssueNotExist(err) {
@@ -265,25 +273,20 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 		issue.Content = *form.Body
 	}
 
-	if ctx.Repo.IsWriter() && form.Assignee != nil &&
-		(issue.Assignee == nil || issue.Assignee.LowerName != strings.ToLower(*form.Assignee)) {
-		if len(*form.Assignee) == 0 {
-			issue.AssigneeID = 0
-		} else {
-			assignee, err := models.GetUserByName(*form.Assignee)
-			if err != nil {
-				if models.IsErrUserNotExist(err) {
-					ctx.Error(422, "", fmt.Sprintf("assignee does not exist: [name: %s]", *form.Assignee))
-				} else {
-					ctx.Error(500, "GetUserByName", err)
-				}
-				return
-			}
-			issue.AssigneeID = assignee.ID
+	// Update the assignees if either the deprecated single assignee or the list
+	// of assignees was passed, an empty value clears all assignees.
+	if ctx.Repo.IsWriter() && (form.Assignee != nil || form.Assignees != nil) {
+		oneAssignee := ""
+		if form.Assignee != nil {
+			oneAssignee = *form.Assignee
 		}
 
-		if err = models.UpdateIssueUserByAssignee(issue); err != nil {
-			ctx.Error(500, "UpdateIssueUserByAssignee", err)
+		if err = models.UpdateAPIAssignee(issue, oneAssignee, form.Assignees, ctx.User); err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(422, "", fmt.Sprintf("Assignee does not exist: %v", err))
+			} else {
+				ctx.Error(500, "UpdateAPIAssignee", err)
+			}
 			return
 		}
 	}
@@ -303,6 +306,14 @@ func EditIssue(ctx *context.APIContext, form api.EditIssueOption) {
 	}
 	if form.State != nil {
 		if err = issue.ChangeStatus(ctx.User, ctx.Repo.Repository, api.StateClosed == api.StateType(*form.State)); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				msg := "cannot close this issue because it still has open dependencies"
+				if hidden := err.(models.ErrDependenciesLeft).NumHidden; hidden > 0 {
+					msg += fmt.Sprintf(", %d of which are in repositories you cannot access", hidden)
+				}
+				ctx.Error(412, "", msg)
+				return
+			}
 			ctx.Error(500, "ChangeStatus", err)
 			return
 		}
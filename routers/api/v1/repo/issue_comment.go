@@ -11,6 +11,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
 )
 
 // ListIssueComments list all the comments of an issue
@@ -65,6 +66,11 @@ func ListIssueComments(ctx *context.APIContext) {
 		return
 	}
 
+	if err = models.CommentList(comments).LoadReactions(); err != nil {
+		ctx.Error(500, "LoadReactions", err)
+		return
+	}
+
 	apiComments := make([]*api.Comment, len(comments))
 	for i := range comments {
 		apiComments[i] = comments[i].APIFormat()
@@ -112,6 +118,11 @@ func ListRepoIssueComments(ctx *context.APIContext) {
 		return
 	}
 
+	if err = models.CommentList(comments).LoadReactions(); err != nil {
+		ctx.Error(500, "LoadReactions", err)
+		return
+	}
+
 	apiComments := make([]*api.Comment, len(comments))
 	for i := range comments {
 		apiComments[i] = comments[i].APIFormat()
@@ -163,6 +174,8 @@ func CreateIssueComment(ctx *context.APIContext, form api.CreateIssueCommentOpti
 		return
 	}
 
+	notification.Service.NotifyComment(issue, comment, ctx.User.ID)
+
 	ctx.JSON(201, comment.APIFormat())
 }
 
@@ -261,11 +274,16 @@ func editIssueComment(ctx *context.APIContext, form api.EditIssueCommentOption)
 		return
 	}
 
+	oldContent := comment.Content
 	comment.Content = form.Body
-	if err := models.UpdateComment(comment); err != nil {
+	if err := models.UpdateComment(ctx.User, comment, oldContent); err != nil {
 		ctx.Error(500, "UpdateComment", err)
 		return
 	}
+	if err := comment.LoadReactions(); err != nil {
+		ctx.Error(500, "LoadReactions", err)
+		return
+	}
 	ctx.JSON(200, comment.APIFormat())
 }
 
@@ -348,7 +366,7 @@ func deleteIssueComment(ctx *context.APIContext) {
 		return
 	}
 
-	if err = models.DeleteComment(comment); err != nil {
+	if err = models.DeleteComment(ctx.User, comment); err != nil {
 		ctx.Error(500, "DeleteCommentByID", err)
 		return
 	}
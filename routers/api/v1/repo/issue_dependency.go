@@ -0,0 +1,349 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListIssueDependencies list the issues blocking an issue
+func ListIssueDependencies(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/dependencies issue issueListIssueDependencies
+	// ---
+	// summary: List the issues blocking an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	issue := getDependentIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issues, err := issue.BlockedBy(ctx.User)
+	if err != nil {
+		ctx.Error(500, "BlockedBy", err)
+		return
+	}
+	writeDependencyIssues(ctx, issues)
+}
+
+// CreateIssueDependency make an issue blocked by another issue
+func CreateIssueDependency(ctx *context.APIContext, form api.IssueMeta) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/dependencies issue issueCreateIssueDependency
+	// ---
+	// summary: Make an issue blocked by another issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the blocked issue
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getDependentIssue(ctx)
+	dep := getIssueFromMeta(ctx, form, models.AccessModeRead)
+	if ctx.Written() {
+		return
+	}
+	createIssueDependency(ctx, issue, dep, dep)
+}
+
+// RemoveIssueDependency remove an issue from the issues blocking an issue
+func RemoveIssueDependency(ctx *context.APIContext, form api.IssueMeta) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/dependencies issue issueRemoveIssueDependency
+	// ---
+	// summary: Remove an issue from the issues blocking an issue
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the blocked issue
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	issue := getDependentIssue(ctx)
+	dep := getIssueFromMeta(ctx, form, models.AccessModeRead)
+	if ctx.Written() {
+		return
+	}
+	removeIssueDependency(ctx, issue, dep)
+}
+
+// ListIssueBlocks list the issues blocked by an issue
+func ListIssueBlocks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/blocks issue issueListBlocks
+	// ---
+	// summary: List the issues blocked by an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	issue := getDependentIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issues, err := issue.Blocking(ctx.User)
+	if err != nil {
+		ctx.Error(500, "Blocking", err)
+		return
+	}
+	writeDependencyIssues(ctx, issues)
+}
+
+// CreateIssueBlocking make an issue block another issue
+func CreateIssueBlocking(ctx *context.APIContext, form api.IssueMeta) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/blocks issue issueCreateIssueBlocking
+	// ---
+	// summary: Make an issue block another issue, which requires write access to the blocked issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the blocking issue
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getDependentIssue(ctx)
+	blocked := getIssueFromMeta(ctx, form, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+	createIssueDependency(ctx, blocked, issue, blocked)
+}
+
+// RemoveIssueBlocking remove an issue from the issues blocked by an issue
+func RemoveIssueBlocking(ctx *context.APIContext, form api.IssueMeta) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/blocks issue issueRemoveIssueBlocking
+	// ---
+	// summary: Remove an issue from the issues blocked by an issue, which requires write access to the blocked issue
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the blocking issue
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	issue := getDependentIssue(ctx)
+	blocked := getIssueFromMeta(ctx, form, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+	removeIssueDependency(ctx, blocked, issue)
+}
+
+func getDependentIssue(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	return issue
+}
+
+// getIssueFromMeta returns the issue described by form, which is looked up in the
+// repository of the request if no repository is given. Issues the user doesn't have
+// the given access to are treated as not existing.
+func getIssueFromMeta(ctx *context.APIContext, form api.IssueMeta, mode models.AccessMode) *models.Issue {
+	if ctx.Written() {
+		return nil
+	}
+
+	repo := ctx.Repo.Repository
+	if len(form.Owner) > 0 || len(form.Name) > 0 {
+		var err error
+		repo, err = models.GetRepositoryByOwnerAndName(form.Owner, form.Name)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.Status(404)
+			} else {
+				ctx.Error(500, "GetRepositoryByOwnerAndName", err)
+			}
+			return nil
+		}
+	}
+
+	if has, err := models.HasAccess(ctx.User.ID, repo, mode); err != nil {
+		ctx.Error(500, "HasAccess", err)
+		return nil
+	} else if !has || !ctx.TokenCanAccessRepo(repo) {
+		ctx.Status(404)
+		return nil
+	}
+
+	issue, err := models.GetIssueByIndex(repo.ID, form.Index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	return issue
+}
+
+// createIssueDependency makes issue blocked by dep and responds with the other issue
+// of the relation
+func createIssueDependency(ctx *context.APIContext, issue, dep, other *models.Issue) {
+	if err := models.CreateIssueDependency(ctx.User, issue, dep); err != nil {
+		if models.IsErrDependencyExists(err) {
+			ctx.Error(422, "", "dependency already exists")
+		} else if models.IsErrCircularDependency(err) {
+			ctx.Error(422, "", "dependencies cannot be circular")
+		} else {
+			ctx.Error(500, "CreateIssueDependency", err)
+		}
+		return
+	}
+	ctx.JSON(201, other.APIFormat())
+}
+
+func removeIssueDependency(ctx *context.APIContext, issue, dep *models.Issue) {
+	if err := models.RemoveIssueDependency(ctx.User, issue, dep); err != nil {
+		if models.IsErrDependencyNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "RemoveIssueDependency", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
+
+func writeDependencyIssues(ctx *context.APIContext, issues models.IssueList) {
+	apiIssues := make([]*api.Issue, len(issues))
+	for i := range issues {
+		apiIssues[i] = issues[i].APIFormat()
+	}
+	ctx.JSON(200, &apiIssues)
+}
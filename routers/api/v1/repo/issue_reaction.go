@@ -0,0 +1,369 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// GetIssueReactions list the reactions of an issue
+func GetIssueReactions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/reactions issue issueGetIssueReactions
+	// ---
+	// summary: Get the reactions of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReactionList"
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reactions, err := models.FindIssueReactions(issue)
+	if err != nil {
+		ctx.Error(500, "FindIssueReactions", err)
+		return
+	}
+	writeReactions(ctx, reactions)
+}
+
+// PostIssueReaction adds a reaction to an issue
+func PostIssueReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/reactions issue issuePostIssueReaction
+	// ---
+	// summary: Add a reaction to an issue
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Reaction"
+	//   "201":
+	//     "$ref": "#/responses/Reaction"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	createReaction(ctx, &models.ReactionOptions{
+		Type:  form.Content,
+		Doer:  ctx.User,
+		Issue: issue,
+	})
+}
+
+// DeleteIssueReaction removes a reaction from an issue
+func DeleteIssueReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/reactions issue issueDeleteIssueReaction
+	// ---
+	// summary: Remove a reaction from an issue
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getReactionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	deleteReaction(ctx, &models.ReactionOptions{
+		Type:  form.Content,
+		Doer:  ctx.User,
+		Issue: issue,
+	})
+}
+
+// GetCommentReactions list the reactions of a comment
+func GetCommentReactions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/reactions issue issueGetCommentReactions
+	// ---
+	// summary: Get the reactions of a comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReactionList"
+	comment, _ := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reactions, err := models.FindCommentReactions(comment)
+	if err != nil {
+		ctx.Error(500, "FindCommentReactions", err)
+		return
+	}
+	writeReactions(ctx, reactions)
+}
+
+// PostCommentReaction adds a reaction to a comment
+func PostCommentReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/reactions issue issuePostCommentReaction
+	// ---
+	// summary: Add a reaction to a comment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Reaction"
+	//   "201":
+	//     "$ref": "#/responses/Reaction"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	comment, issue := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	createReaction(ctx, &models.ReactionOptions{
+		Type:    form.Content,
+		Doer:    ctx.User,
+		Issue:   issue,
+		Comment: comment,
+	})
+}
+
+// DeleteCommentReaction removes a reaction from a comment
+func DeleteCommentReaction(ctx *context.APIContext, form api.EditReactionOption) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/comments/{id}/reactions issue issueDeleteCommentReaction
+	// ---
+	// summary: Remove a reaction from a comment
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	comment, issue := getReactionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	deleteReaction(ctx, &models.ReactionOptions{
+		Type:    form.Content,
+		Doer:    ctx.User,
+		Issue:   issue,
+		Comment: comment,
+	})
+}
+
+func getReactionIssue(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	return issue
+}
+
+// getReactionComment returns the comment of the request and its issue, if
+// the comment belongs to the repository of the request
+func getReactionComment(ctx *context.APIContext) (*models.Comment, *models.Issue) {
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrCommentNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetCommentByID", err)
+		}
+		return nil, nil
+	}
+
+	issue, err := models.GetIssueByID(comment.IssueID)
+	if err != nil {
+		ctx.Error(500, "GetIssueByID", err)
+		return nil, nil
+	}
+	if issue.RepoID != ctx.Repo.Repository.ID || comment.Type != models.CommentTypeComment {
+		ctx.Status(404)
+		return nil, nil
+	}
+	return comment, issue
+}
+
+func writeReactions(ctx *context.APIContext, reactions models.ReactionList) {
+	if _, err := reactions.LoadUsers(); err != nil {
+		ctx.Error(500, "LoadUsers", err)
+		return
+	}
+
+	apiReactions := make([]*api.Reaction, len(reactions))
+	for i := range reactions {
+		apiReactions[i] = reactions[i].APIFormat()
+	}
+	ctx.JSON(200, &apiReactions)
+}
+
+func createReaction(ctx *context.APIContext, opts *models.ReactionOptions) {
+	if !models.IsValidReaction(opts.Type) {
+		ctx.Error(422, "", fmt.Sprintf("invalid reaction: %s", opts.Type))
+		return
+	}
+
+	reaction, err := models.CreateReaction(opts)
+	if err != nil {
+		if !models.IsErrReactionAlreadyExist(err) {
+			ctx.Error(500, "CreateReaction", err)
+			return
+		}
+
+		// reacting twice is not an error, the existing reaction is returned
+		if reaction, err = models.GetReaction(opts); err != nil {
+			ctx.Error(500, "GetReaction", err)
+			return
+		}
+		reaction.User = ctx.User
+		ctx.JSON(200, reaction.APIFormat())
+		return
+	}
+
+	reaction.User = ctx.User
+	ctx.JSON(201, reaction.APIFormat())
+}
+
+func deleteReaction(ctx *context.APIContext, opts *models.ReactionOptions) {
+	if !models.IsValidReaction(opts.Type) {
+		ctx.Error(422, "", fmt.Sprintf("invalid reaction: %s", opts.Type))
+		return
+	}
+
+	if err := models.DeleteReaction(opts); err != nil {
+		ctx.Error(500, "DeleteReaction", err)
+		return
+	}
+	ctx.Status(204)
+}
@@ -132,7 +132,7 @@ func CreateLabel(ctx *context.APIContext, form api.CreateLabelOption) {
 		Color:  form.Color,
 		RepoID: ctx.Repo.Repository.ID,
 	}
-	if err := models.NewLabel(label); err != nil {
+	if err := models.NewLabel(ctx.User, label); err != nil {
 		ctx.Error(500, "NewLabel", err)
 		return
 	}
@@ -192,7 +192,7 @@ func EditLabel(ctx *context.APIContext, form api.EditLabelOption) {
 	if form.Color != nil {
 		label.Color = *form.Color
 	}
-	if err := models.UpdateLabel(label); err != nil {
+	if err := models.UpdateLabel(ctx.User, label); err != nil {
 		ctx.ServerError("UpdateLabel", err)
 		return
 	}
@@ -228,7 +228,7 @@ func DeleteLabel(ctx *context.APIContext) {
 		return
 	}
 
-	if err := models.DeleteLabel(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+	if err := models.DeleteLabel(ctx.User, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
 		ctx.Error(500, "DeleteLabel", err)
 		return
 	}
@@ -125,7 +125,7 @@ func CreateMilestone(ctx *context.APIContext, form api.CreateMilestoneOption) {
 		DeadlineUnix: util.TimeStamp(form.Deadline.Unix()),
 	}
 
-	if err := models.NewMilestone(milestone); err != nil {
+	if err := models.NewMilestone(ctx.User, milestone); err != nil {
 		ctx.Error(500, "NewMilestone", err)
 		return
 	}
@@ -179,7 +179,7 @@ func EditMilestone(ctx *context.APIContext, form api.EditMilestoneOption) {
 		milestone.DeadlineUnix = util.TimeStamp(form.Deadline.Unix())
 	}
 
-	if err := models.UpdateMilestone(milestone); err != nil {
+	if err := models.UpdateMilestone(ctx.User, milestone); err != nil {
 		ctx.ServerError("UpdateMilestone", err)
 		return
 	}
@@ -210,7 +210,7 @@ func DeleteMilestone(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
-	if err := models.DeleteMilestoneByRepoID(ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+	if err := models.DeleteMilestoneByRepoID(ctx.User, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
 		ctx.Error(500, "DeleteMilestoneByRepoID", err)
 		return
 	}
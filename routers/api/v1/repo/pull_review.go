@@ -0,0 +1,289 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// getPullIssue returns the issue of the pull request identified by the :index parameter
+func getPullIssue(ctx *context.APIContext) *models.Issue {
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+	if err = pr.LoadIssue(); err != nil {
+		ctx.Error(500, "LoadIssue", err)
+		return nil
+	}
+	pr.Issue.PullRequest = pr
+	pr.Issue.Repo = ctx.Repo.Repository
+	return pr.Issue
+}
+
+// getPullReview returns the published review identified by the :id parameter
+func getPullReview(ctx *context.APIContext, issue *models.Issue) *models.Review {
+	review, err := models.GetReviewByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrReviewNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetReviewByID", err)
+		}
+		return nil
+	}
+	if review.IssueID != issue.ID || review.Type == models.ReviewTypePending {
+		ctx.Status(404)
+		return nil
+	}
+	review.Issue = issue
+	if err = review.LoadAttributes(); err != nil {
+		ctx.Error(500, "LoadAttributes", err)
+		return nil
+	}
+	if err = review.LoadCodeComments(); err != nil {
+		ctx.Error(500, "LoadCodeComments", err)
+		return nil
+	}
+	return review
+}
+
+// ListPullReviews lists all published reviews of a pull request
+func ListPullReviews(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews repository repoListPullReviews
+	// ---
+	// summary: List all reviews for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewList"
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reviews, err := models.GetReviewsByIssueID(issue.ID)
+	if err != nil {
+		ctx.Error(500, "GetReviewsByIssueID", err)
+		return
+	}
+
+	apiReviews := make([]*api.PullReview, len(reviews))
+	for i := range reviews {
+		reviews[i].Issue = issue
+		if err = reviews[i].LoadAttributes(); err != nil {
+			ctx.Error(500, "LoadAttributes", err)
+			return
+		}
+		if err = reviews[i].LoadCodeComments(); err != nil {
+			ctx.Error(500, "LoadCodeComments", err)
+			return
+		}
+		apiReviews[i] = reviews[i].APIFormat()
+	}
+	ctx.JSON(200, &apiReviews)
+}
+
+// GetPullReview gets a single review of a pull request
+func GetPullReview(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repository repoGetPullReview
+	// ---
+	// summary: Get a specific review for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReview"
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	review := getPullReview(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
+
+// GetPullReviewComments lists the code comments of a review
+func GetPullReviewComments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments repository repoGetPullReviewComments
+	// ---
+	// summary: Get the code comments of a pull review
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewCommentList"
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	review := getPullReview(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
+	apiComments := make([]*api.PullReviewComment, len(review.CodeComments))
+	for i := range review.CodeComments {
+		apiComments[i] = review.CodeComments[i].APIFormatCodeComment()
+	}
+	ctx.JSON(200, &apiComments)
+}
+
+// CreatePullReview creates and submits a review of a pull request
+func CreatePullReview(ctx *context.APIContext, form api.CreatePullReviewOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/reviews repository repoCreatePullReview
+	// ---
+	// summary: Create a review for a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreatePullReviewOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReview"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := getPullIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reviewType := models.ReviewTypeFromAPIState(form.Event)
+	if reviewType == models.ReviewTypeUnknown {
+		ctx.Error(422, "", "invalid review event")
+		return
+	}
+	if (reviewType == models.ReviewTypeApprove || reviewType == models.ReviewTypeReject) &&
+		issue.IsPoster(ctx.User.ID) {
+		ctx.Error(422, "", "cannot approve or request changes on your own pull request")
+		return
+	}
+
+	commitID := form.CommitID
+	if len(commitID) == 0 {
+		var err error
+		commitID, err = ctx.Repo.GitRepo.GetRefCommitID(issue.PullRequest.GetGitRefName())
+		if err != nil {
+			ctx.Error(500, "GetRefCommitID", err)
+			return
+		}
+	}
+
+	comments := make([]*models.CreateCodeCommentOptions, 0, len(form.Comments))
+	for _, c := range form.Comments {
+		comments = append(comments, &models.CreateCodeCommentOptions{
+			Content:  c.Body,
+			TreePath: c.Path,
+			LineNum:  c.NewLineNum,
+		})
+	}
+
+	review, err := models.CreatePullReview(ctx.User, ctx.Repo.Repository, issue, reviewType, form.Body, commitID, comments)
+	if err != nil {
+		if models.IsErrReviewEmpty(err) {
+			ctx.Error(422, "", err.Error())
+			return
+		}
+		ctx.Error(500, "CreatePullReview", err)
+		return
+	}
+
+	review.CodeComments = nil
+	if err = review.LoadCodeComments(); err != nil {
+		ctx.Error(500, "LoadCodeComments", err)
+		return
+	}
+	ctx.JSON(200, review.APIFormat())
+}
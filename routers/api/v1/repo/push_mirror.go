@@ -0,0 +1,226 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// getPushMirror returns the push mirror identified by the :id parameter
+func getPushMirror(ctx *context.APIContext) *models.PushMirror {
+	m, err := models.GetPushMirrorByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrPushMirrorNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetPushMirrorByID", err)
+		}
+		return nil
+	}
+	if m.RepoID != ctx.Repo.Repository.ID {
+		ctx.Status(404)
+		return nil
+	}
+	m.Repo = ctx.Repo.Repository
+	return m
+}
+
+// ListPushMirrors lists the push mirrors of a repository
+func ListPushMirrors(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/push_mirrors repository repoListPushMirrors
+	// ---
+	// summary: List a repository's push mirrors
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushMirrorList"
+	mirrors, err := models.GetPushMirrorsByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(500, "GetPushMirrorsByRepoID", err)
+		return
+	}
+
+	apiMirrors := make([]*api.PushMirror, len(mirrors))
+	for i := range mirrors {
+		apiMirrors[i] = mirrors[i].APIFormat()
+	}
+	ctx.JSON(200, &apiMirrors)
+}
+
+// GetPushMirror gets a push mirror of a repository
+func GetPushMirror(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/push_mirrors/{id} repository repoGetPushMirror
+	// ---
+	// summary: Get a push mirror of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the push mirror
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushMirror"
+	m := getPushMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(200, m.APIFormat())
+}
+
+// CreatePushMirror adds a push mirror to a repository
+func CreatePushMirror(ctx *context.APIContext, form api.CreatePushMirrorOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/push_mirrors repository repoCreatePushMirror
+	// ---
+	// summary: Add a push mirror to a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePushMirrorOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PushMirror"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	var interval time.Duration
+	if len(form.Interval) > 0 {
+		var err error
+		interval, err = time.ParseDuration(form.Interval)
+		if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+			ctx.Error(422, "", "invalid push mirror interval")
+			return
+		}
+	}
+
+	addr, err := models.ParsePushMirrorAddr(form.RemoteAddress, form.RemoteUsername, form.RemotePassword)
+	if err != nil {
+		ctx.Error(422, "", "invalid push mirror address")
+		return
+	}
+
+	m, err := models.CreatePushMirror(ctx.Repo.Repository, addr, interval)
+	if err != nil {
+		ctx.Error(500, "CreatePushMirror", err)
+		return
+	}
+	go models.PushMirrorQueue.Add(m.ID)
+	ctx.JSON(201, m.APIFormat())
+}
+
+// DeletePushMirror removes a push mirror from a repository
+func DeletePushMirror(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/push_mirrors/{id} repository repoDeletePushMirror
+	// ---
+	// summary: Remove a push mirror from a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the push mirror
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	m := getPushMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeletePushMirror(m); err != nil {
+		ctx.Error(500, "DeletePushMirror", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// SyncPushMirror adds a push mirror to the sync queue
+func SyncPushMirror(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/push_mirrors/{id}/sync repository repoSyncPushMirror
+	// ---
+	// summary: Push a repository to one of its push mirrors
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the push mirror
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/empty"
+	m := getPushMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+	go models.PushMirrorQueue.Add(m.ID)
+	ctx.Status(200)
+}
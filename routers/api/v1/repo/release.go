@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/notification"
 )
 
 // GetRelease get a single release of a repository
@@ -156,6 +157,7 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 			}
 			return
 		}
+		notification.Service.NotifyRelease(rel, ctx.User.ID)
 	} else {
 		if !rel.IsTag {
 			ctx.Status(409)
@@ -171,10 +173,11 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 		rel.Repo = ctx.Repo.Repository
 		rel.Publisher = ctx.User
 
-		if err = models.UpdateRelease(ctx.Repo.GitRepo, rel, nil); err != nil {
+		if err = models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, nil); err != nil {
 			ctx.ServerError("UpdateRelease", err)
 			return
 		}
+		notification.Service.NotifyRelease(rel, ctx.User.ID)
 	}
 	ctx.JSON(201, rel.APIFormat())
 }
@@ -239,16 +242,20 @@ func EditRelease(ctx *context.APIContext, form api.EditReleaseOption) {
 	if len(form.Note) > 0 {
 		rel.Note = form.Note
 	}
+	wasDraft := rel.IsDraft
 	if form.IsDraft != nil {
 		rel.IsDraft = *form.IsDraft
 	}
 	if form.IsPrerelease != nil {
 		rel.IsPrerelease = *form.IsPrerelease
 	}
-	if err := models.UpdateRelease(ctx.Repo.GitRepo, rel, nil); err != nil {
+	if err := models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, nil); err != nil {
 		ctx.Error(500, "UpdateRelease", err)
 		return
 	}
+	if wasDraft {
+		notification.Service.NotifyRelease(rel, ctx.User.ID)
+	}
 
 	rel, err = models.GetReleaseByID(id)
 	if err != nil {
@@ -31,6 +31,10 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: keyword
 	//   type: string
+	// - name: topic
+	//   in: query
+	//   description: Limit search to repositories with keyword as topic
+	//   type: boolean
 	// - name: uid
 	//   in: query
 	//   description: search only for repos that the user with the given id owns or contributes to
@@ -52,6 +56,10 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: if `uid` is given, search only for repos that the user owns
 	//   type: boolean
+	// - name: archived
+	//   in: query
+	//   description: if given, search only for archived (true) or non-archived (false) repos
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/SearchResults"
@@ -59,6 +67,7 @@ func Search(ctx *context.APIContext) {
 	//     "$ref": "#/responses/validationError"
 	opts := &models.SearchRepoOptions{
 		Keyword:     strings.Trim(ctx.Query("q"), " "),
+		TopicOnly:   ctx.QueryBool("topic"),
 		OwnerID:     ctx.QueryInt64("uid"),
 		Page:        ctx.QueryInt("page"),
 		PageSize:    convert.ToCorrectPageSize(ctx.QueryInt("limit")),
@@ -69,6 +78,10 @@ func Search(ctx *context.APIContext) {
 		opts.Collaborate = util.OptionalBoolFalse
 	}
 
+	if len(ctx.Query("archived")) > 0 {
+		opts.Archived = util.OptionalBoolOf(ctx.QueryBool("archived"))
+	}
+
 	var mode = ctx.Query("mode")
 	switch mode {
 	case "source":
@@ -124,6 +137,15 @@ func Search(ctx *context.APIContext) {
 		}
 	}
 
+	// Restricted tokens must not even reveal the repositories they can't access,
+	// so the restriction is part of the query and of the total count.
+	if ctx.AccessToken != nil {
+		if ctx.AccessToken.HasScope(models.AccessTokenScopePublicOnly) {
+			opts.Private = false
+		}
+		opts.RepoIDs = ctx.AccessToken.RepoIDs
+	}
+
 	repos, count, err := models.SearchRepositoryByName(opts)
 	if err != nil {
 		ctx.JSON(500, api.SearchError{
@@ -138,8 +160,8 @@ func Search(ctx *context.APIContext) {
 		userID = ctx.User.ID
 	}
 
-	results := make([]*api.Repository, len(repos))
-	for i, repo := range repos {
+	results := make([]*api.Repository, 0, len(repos))
+	for _, repo := range repos {
 		if err = repo.GetOwner(); err != nil {
 			ctx.JSON(500, api.SearchError{
 				OK:    false,
@@ -153,8 +175,9 @@ func Search(ctx *context.APIContext) {
 				OK:    false,
 				Error: err.Error(),
 			})
+			return
 		}
-		results[i] = repo.APIFormat(accessMode)
+		results = append(results, repo.APIFormat(accessMode))
 	}
 
 	ctx.SetLinkHeader(int(count), setting.API.MaxResponseItems)
@@ -413,7 +436,7 @@ func GetByID(ctx *context.APIContext) {
 	if err != nil {
 		ctx.Error(500, "AccessLevel", err)
 		return
-	} else if access < models.AccessModeRead {
+	} else if access < models.AccessModeRead || !ctx.TokenCanAccessRepo(repo) {
 		ctx.Status(404)
 		return
 	}
@@ -470,6 +493,73 @@ func Delete(ctx *context.APIContext) {
 	ctx.Status(204)
 }
 
+// Archive marks a repository as archived
+func Archive(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/archived repository repoArchive
+	// ---
+	// summary: Archive a repository, making it read-only
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to archive
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to archive
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	setArchiveRepoState(ctx, true)
+}
+
+// Unarchive removes the archived state of a repository
+func Unarchive(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/archived repository repoUnarchive
+	// ---
+	// summary: Un-archive a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to un-archive
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to un-archive
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	setArchiveRepoState(ctx, false)
+}
+
+func setArchiveRepoState(ctx *context.APIContext, isArchived bool) {
+	repo := ctx.Repo.Repository
+	if isArchived && repo.IsMirror {
+		ctx.Error(422, "", "Mirror repositories can not be archived")
+		return
+	}
+
+	if repo.IsArchived != isArchived {
+		if err := repo.SetArchiveRepoState(isArchived); err != nil {
+			ctx.Error(500, "SetArchiveRepoState", err)
+			return
+		}
+		log.Trace("Repository archived state changed to %t: %s/%s", isArchived, ctx.Repo.Owner.Name, repo.Name)
+	}
+	ctx.JSON(200, repo.APIFormat(ctx.Repo.AccessMode))
+}
+
 // MirrorSync adds a mirrored repository to the sync queue
 func MirrorSync(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/mirror-sync repository repoMirrorSync
@@ -0,0 +1,59 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListTags list the tags of a repository
+func ListTags(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tags repository repoListTags
+	// ---
+	// summary: List a repository's tags
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagList"
+	tags, err := ctx.Repo.GitRepo.GetTags()
+	if err != nil {
+		ctx.Error(500, "GetTags", err)
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	apiTags := make([]*api.Tag, len(tags))
+	for i, name := range tags {
+		commitID, err := ctx.Repo.GitRepo.GetTagCommitID(name)
+		if err != nil {
+			ctx.Error(500, "GetTagCommitID", err)
+			return
+		}
+		apiTags[i] = &api.Tag{
+			Name: name,
+			Commit: &api.CommitMeta{
+				URL: repo.APIURL() + "/git/commits/" + commitID,
+				SHA: commitID,
+			},
+			ZipballURL: repo.HTMLURL() + "/archive/" + name + ".zip",
+			TarballURL: repo.HTMLURL() + "/archive/" + name + ".tar.gz",
+		}
+	}
+	ctx.JSON(200, &apiTags)
+}
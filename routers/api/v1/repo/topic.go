@@ -0,0 +1,236 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// ListTopics returns list of current topics for repo
+func ListTopics(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/topics repository repoListTopics
+	// ---
+	// summary: Get list of topics that a repository has
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TopicNames"
+	topics, err := models.FindTopics(&models.FindTopicOptions{
+		RepoID: ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.Error(500, "FindTopics", err)
+		return
+	}
+
+	topicNames := make([]string, len(topics))
+	for i, topic := range topics {
+		topicNames[i] = topic.Name
+	}
+	ctx.JSON(200, &api.TopicName{
+		TopicNames: topicNames,
+	})
+}
+
+// UpdateTopics replaces the topics of a repository
+func UpdateTopics(ctx *context.APIContext, form api.RepoTopicOptions) {
+	// swagger:operation PUT /repos/{owner}/{repo}/topics repository repoUpdateTopics
+	// ---
+	// summary: Replace list of topics for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RepoTopicOptions"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	validTopics, invalidTopics := models.SanitizeAndValidateTopics(form.Topics)
+	if len(invalidTopics) > 0 {
+		ctx.Error(422, "", "invalid topics: "+strings.Join(invalidTopics, ", "))
+		return
+	}
+	if len(validTopics) > models.MaxTopicsPerRepo {
+		ctx.Error(422, "", "exceeding maximum number of topics per repo")
+		return
+	}
+
+	if err := models.SaveTopics(ctx.Repo.Repository.ID, validTopics...); err != nil {
+		ctx.Error(500, "SaveTopics", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// AddTopic adds a topic name to a repository
+func AddTopic(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/topics/{topic} repository repoAddTopic
+	// ---
+	// summary: Add a topic to a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: topic
+	//   in: path
+	//   description: name of the topic to add
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	topicName := strings.ToLower(strings.TrimSpace(ctx.Params(":topic")))
+	if !models.ValidateTopic(topicName) {
+		ctx.Error(422, "", "invalid topic: "+topicName)
+		return
+	}
+
+	if _, err := models.GetRepoTopicByName(ctx.Repo.Repository.ID, topicName); err == nil {
+		ctx.Status(204)
+		return
+	} else if !models.IsErrTopicNotExist(err) {
+		ctx.Error(500, "GetRepoTopicByName", err)
+		return
+	}
+
+	count, err := models.CountRepoTopics(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(500, "CountRepoTopics", err)
+		return
+	} else if count >= models.MaxTopicsPerRepo {
+		ctx.Error(422, "", "exceeding maximum number of topics per repo")
+		return
+	}
+
+	if _, err = models.AddTopic(ctx.Repo.Repository.ID, topicName); err != nil {
+		ctx.Error(500, "AddTopic", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// DeleteTopic removes a topic name from a repository
+func DeleteTopic(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/topics/{topic} repository repoDeleteTopic
+	// ---
+	// summary: Delete a topic from a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: topic
+	//   in: path
+	//   description: name of the topic to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	topicName := strings.ToLower(strings.TrimSpace(ctx.Params(":topic")))
+	if _, err := models.DeleteTopic(ctx.Repo.Repository.ID, topicName); err != nil {
+		if models.IsErrTopicNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "DeleteTopic", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
+
+// TopicSearch searches for topics by keyword
+func TopicSearch(ctx *context.APIContext) {
+	// swagger:operation GET /topics/search repository topicSearch
+	// ---
+	// summary: Search topics via keyword
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keywords to search
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, maximum page size is 50
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TopicListResponse"
+	topics, err := models.FindTopics(&models.FindTopicOptions{
+		Keyword:  strings.TrimSpace(ctx.Query("q")),
+		Page:     ctx.QueryInt("page"),
+		PageSize: convert.ToCorrectPageSize(ctx.QueryInt("limit")),
+	})
+	if err != nil {
+		ctx.Error(500, "FindTopics", err)
+		return
+	}
+
+	apiTopics := make([]*api.TopicResponse, len(topics))
+	for i := range topics {
+		apiTopics[i] = topics[i].APIFormat()
+	}
+	ctx.JSON(200, apiTopics)
+}
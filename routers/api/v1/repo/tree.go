@@ -0,0 +1,165 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"path"
+
+	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// treeEntriesPageSize is the maximum number of entries of a tree response
+const treeEntriesPageSize = 1000
+
+// GetTree get a tree of a repository
+func GetTree(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/trees/{sha} repository repoGetTree
+	// ---
+	// summary: Get a tree of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the tree, or a branch, tag or commit to get the root tree of
+	//   type: string
+	//   required: true
+	// - name: recursive
+	//   in: query
+	//   description: list the entries of the subtrees too
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of the entries to return (1-based), the response is truncated if there are more pages
+	//                and the total count is only given for the last page
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitTreeResponse"
+	sha := ctx.Params(":sha")
+	var tree *git.Tree
+	if commit, err := getCommitByRef(ctx.Repo.GitRepo, sha); err == nil {
+		tree = &commit.Tree
+	} else if tree, err = ctx.Repo.GitRepo.GetTree(sha); err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetTree", err)
+		}
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	start, end := (page-1)*treeEntriesPageSize, page*treeEntriesPageSize
+
+	// Stop walking the tree once it is known whether there is another page,
+	// large repositories could have a huge number of entries.
+	entries, err := listTreeEntries(tree, "", ctx.QueryBool("recursive"), end+1, nil)
+	if err != nil {
+		ctx.Error(500, "listTreeEntries", err)
+		return
+	}
+
+	truncated := len(entries) > end
+	if start > len(entries) {
+		start = len(entries)
+	}
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	// only look up the sizes of the returned blobs, every lookup runs a git process
+	apiEntries := make([]*api.GitEntry, 0, end-start)
+	for _, entry := range entries[start:end] {
+		apiEntries = append(apiEntries, toGitEntry(ctx.Repo.Repository, entry))
+	}
+
+	// the total count is only known if the whole tree has been walked
+	var totalCount int
+	if !truncated {
+		totalCount = len(entries)
+		ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", totalCount))
+	}
+	ctx.JSON(200, &api.GitTreeResponse{
+		SHA:        tree.ID.String(),
+		URL:        ctx.Repo.Repository.APIURL() + "/git/trees/" + tree.ID.String(),
+		Entries:    apiEntries,
+		Truncated:  truncated,
+		Page:       page,
+		TotalCount: totalCount,
+	})
+}
+
+// treeListEntry is an entry of a tree with its path relative to the listed tree
+type treeListEntry struct {
+	Path  string
+	Entry *git.TreeEntry
+}
+
+// listTreeEntries appends the entries of the tree to entries, with the entries
+// of its subtrees if recursive is true, until limit entries have been listed
+func listTreeEntries(tree *git.Tree, parentPath string, recursive bool, limit int, entries []treeListEntry) ([]treeListEntry, error) {
+	treeEntries, err := tree.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range treeEntries {
+		if len(entries) >= limit {
+			break
+		}
+		entryPath := path.Join(parentPath, entry.Name())
+		entries = append(entries, treeListEntry{Path: entryPath, Entry: entry})
+
+		if recursive && entry.IsDir() {
+			subTree, err := tree.SubTree(entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			if entries, err = listTreeEntries(subTree, entryPath, true, limit, entries); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return entries, nil
+}
+
+func toGitEntry(repo *models.Repository, e treeListEntry) *api.GitEntry {
+	apiEntry := &api.GitEntry{
+		Path: e.Path,
+		Mode: fmt.Sprintf("%06o", e.Entry.Mode()),
+		SHA:  e.Entry.ID.String(),
+	}
+	switch {
+	case e.Entry.IsDir():
+		apiEntry.Type = "tree"
+		apiEntry.URL = repo.APIURL() + "/git/trees/" + apiEntry.SHA
+	case e.Entry.IsSubModule():
+		apiEntry.Type = "commit"
+	default:
+		apiEntry.Type = "blob"
+		apiEntry.Size = e.Entry.Size()
+		apiEntry.URL = repo.APIURL() + "/git/blobs/" + apiEntry.SHA
+	}
+	return apiEntry
+}
@@ -0,0 +1,459 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"code.gitea.io/git"
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/routers/api/v1/convert"
+)
+
+// ListWikiPages list the pages of a wiki
+func ListWikiPages(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/wiki/pages repository repoListWikiPages
+	// ---
+	// summary: List the pages of a repository's wiki
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WikiPageList"
+	wikiRepo, commit := getWikiCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	pages := make([]*api.WikiPage, 0, 10)
+	if commit == nil {
+		ctx.JSON(200, &pages)
+		return
+	}
+
+	entries, err := commit.ListEntries()
+	if err != nil {
+		ctx.Error(500, "ListEntries", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.Type != git.ObjectBlob {
+			continue
+		}
+		wikiName, err := models.WikiFilenameToName(entry.Name())
+		if err != nil {
+			if models.IsErrWikiInvalidFileName(err) {
+				continue
+			}
+			ctx.Error(500, "WikiFilenameToName", err)
+			return
+		}
+
+		page, err := toWikiPage(ctx.Repo.Repository, wikiRepo, entry, wikiName, false)
+		if err != nil {
+			ctx.Error(500, "toWikiPage", err)
+			return
+		}
+		pages = append(pages, page)
+	}
+	ctx.JSON(200, &pages)
+}
+
+// GetWikiPage get a page of a wiki, with its rendered content
+func GetWikiPage(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/wiki/pages/{pageName} repository repoGetWikiPage
+	// ---
+	// summary: Get a page of a repository's wiki
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: pageName
+	//   in: path
+	//   description: name of the page, as in the URL of the page
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WikiPage"
+	writeWikiPage(ctx, 200, models.NormalizeWikiName(ctx.Params(":pageName")))
+}
+
+// CreateWikiPage create a page in a wiki
+func CreateWikiPage(ctx *context.APIContext, form api.CreateWikiPageOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/wiki/pages repository repoCreateWikiPage
+	// ---
+	// summary: Create a page in a repository's wiki
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateWikiPageOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/WikiPage"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	wikiName := models.NormalizeWikiName(strings.TrimSpace(form.Title))
+	if len(wikiName) == 0 {
+		ctx.Error(422, "", "Title is required")
+		return
+	}
+
+	if err := ctx.Repo.Repository.AddWikiPage(ctx.User, wikiName, form.Content, form.Message); err != nil {
+		if models.IsErrWikiReservedName(err) {
+			ctx.Error(422, "", fmt.Sprintf("reserved page name: %s", wikiName))
+		} else if models.IsErrWikiAlreadyExist(err) {
+			ctx.Error(422, "", fmt.Sprintf("page already exists: %s", wikiName))
+		} else {
+			ctx.Error(500, "AddWikiPage", err)
+		}
+		return
+	}
+
+	writeWikiPage(ctx, 201, wikiName)
+}
+
+// EditWikiPage edit a page of a wiki, and rename it if its title changes
+func EditWikiPage(ctx *context.APIContext, form api.EditWikiPageOptions) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/wiki/pages/{pageName} repository repoEditWikiPage
+	// ---
+	// summary: Edit a page of a repository's wiki
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: pageName
+	//   in: path
+	//   description: name of the page, as in the URL of the page
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditWikiPageOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WikiPage"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	oldWikiName := models.NormalizeWikiName(ctx.Params(":pageName"))
+	_, commit := getWikiCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+	entry := findWikiEntry(ctx, commit, oldWikiName)
+	if ctx.Written() {
+		return
+	}
+
+	newWikiName := oldWikiName
+	if form.Title != nil {
+		newWikiName = models.NormalizeWikiName(strings.TrimSpace(*form.Title))
+		if len(newWikiName) == 0 {
+			ctx.Error(422, "", "Title cannot be empty")
+			return
+		}
+	}
+	if newWikiName != oldWikiName {
+		// renaming must not overwrite another page
+		if existing, err := findEntryInWiki(commit, newWikiName); err != nil {
+			ctx.Error(500, "findEntryInWiki", err)
+			return
+		} else if existing != nil {
+			ctx.Error(422, "", fmt.Sprintf("page already exists: %s", newWikiName))
+			return
+		}
+	}
+
+	var content string
+	if form.Content != nil {
+		content = *form.Content
+	} else {
+		data, err := readWikiEntry(entry)
+		if err != nil {
+			ctx.Error(500, "readWikiEntry", err)
+			return
+		}
+		content = string(data)
+	}
+
+	if err := ctx.Repo.Repository.EditWikiPage(ctx.User, oldWikiName, newWikiName, content, form.Message); err != nil {
+		if models.IsErrWikiReservedName(err) {
+			ctx.Error(422, "", fmt.Sprintf("reserved page name: %s", newWikiName))
+		} else {
+			ctx.Error(500, "EditWikiPage", err)
+		}
+		return
+	}
+
+	writeWikiPage(ctx, 200, newWikiName)
+}
+
+// DeleteWikiPage delete a page of a wiki
+func DeleteWikiPage(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/wiki/pages/{pageName} repository repoDeleteWikiPage
+	// ---
+	// summary: Delete a page of a repository's wiki
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: pageName
+	//   in: path
+	//   description: name of the page, as in the URL of the page
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	wikiName := models.NormalizeWikiName(ctx.Params(":pageName"))
+	_, commit := getWikiCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+	findWikiEntry(ctx, commit, wikiName)
+	if ctx.Written() {
+		return
+	}
+
+	if err := ctx.Repo.Repository.DeleteWikiPage(ctx.User, wikiName); err != nil {
+		ctx.Error(500, "DeleteWikiPage", err)
+		return
+	}
+	ctx.Status(204)
+}
+
+// ListWikiPageRevisions list the commits which changed a page of a wiki
+func ListWikiPageRevisions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/wiki/pages/{pageName}/revisions repository repoListWikiPageRevisions
+	// ---
+	// summary: List the revisions of a page of a repository's wiki, from the most recent one
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: pageName
+	//   in: path
+	//   description: name of the page, as in the URL of the page
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of the results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WikiCommitList"
+	wikiRepo, commit := getWikiCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+	entry := findWikiEntry(ctx, commit, models.NormalizeWikiName(ctx.Params(":pageName")))
+	if ctx.Written() {
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	count, err := wikiRepo.FileCommitsCount("master", entry.Name())
+	if err != nil {
+		ctx.Error(500, "FileCommitsCount", err)
+		return
+	}
+	commits, err := wikiRepo.CommitsByFileAndRange("master", entry.Name(), page)
+	if err != nil {
+		ctx.Error(500, "CommitsByFileAndRange", err)
+		return
+	}
+
+	apiCommits := make([]*api.WikiCommit, 0, commits.Len())
+	for e := commits.Front(); e != nil; e = e.Next() {
+		apiCommits = append(apiCommits, convert.ToWikiCommit(e.Value.(*git.Commit)))
+	}
+
+	ctx.SetLinkHeader(int(count), git.CommitsRangeSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.JSON(200, &apiCommits)
+}
+
+// getWikiCommit returns the wiki repository and its last commit, the commit
+// is nil if the wiki has no page yet
+func getWikiCommit(ctx *context.APIContext) (*git.Repository, *git.Commit) {
+	if !ctx.Repo.Repository.HasWiki() {
+		return nil, nil
+	}
+
+	wikiRepo, err := git.OpenRepository(ctx.Repo.Repository.WikiPath())
+	if err != nil {
+		ctx.Error(500, "OpenRepository", err)
+		return nil, nil
+	}
+	commit, err := wikiRepo.GetBranchCommit("master")
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return wikiRepo, nil
+		}
+		ctx.Error(500, "GetBranchCommit", err)
+		return nil, nil
+	}
+	return wikiRepo, commit
+}
+
+// findEntryInWiki returns the tree entry of a wiki page, or nil if the page does not exist
+func findEntryInWiki(commit *git.Commit, wikiName string) (*git.TreeEntry, error) {
+	if commit == nil {
+		return nil, nil
+	}
+
+	entries, err := commit.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	filename := models.WikiNameToFilename(wikiName)
+	for _, entry := range entries {
+		if entry.Type == git.ObjectBlob && entry.Name() == filename {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// findWikiEntry returns the tree entry of a wiki page, or responds with 404
+// if the page does not exist
+func findWikiEntry(ctx *context.APIContext, commit *git.Commit, wikiName string) *git.TreeEntry {
+	entry, err := findEntryInWiki(commit, wikiName)
+	if err != nil {
+		ctx.Error(500, "findEntryInWiki", err)
+		return nil
+	} else if entry == nil {
+		ctx.Status(404)
+		return nil
+	}
+	return entry
+}
+
+func readWikiEntry(entry *git.TreeEntry) ([]byte, error) {
+	r, err := entry.Blob().Data()
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+// writeWikiPage responds with a page of the wiki and its content
+func writeWikiPage(ctx *context.APIContext, status int, wikiName string) {
+	wikiRepo, commit := getWikiCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+	entry := findWikiEntry(ctx, commit, wikiName)
+	if ctx.Written() {
+		return
+	}
+
+	page, err := toWikiPage(ctx.Repo.Repository, wikiRepo, entry, wikiName, true)
+	if err != nil {
+		ctx.Error(500, "toWikiPage", err)
+		return
+	}
+	ctx.JSON(status, page)
+}
+
+func toWikiPage(repo *models.Repository, wikiRepo *git.Repository, entry *git.TreeEntry, wikiName string, withContent bool) (*api.WikiPage, error) {
+	lastCommit, err := wikiRepo.GetCommitByPath(entry.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	subURL := models.WikiNameToSubURL(wikiName)
+	page := &api.WikiPage{
+		Title:      wikiName,
+		SubURL:     subURL,
+		HTMLURL:    repo.HTMLURL() + "/wiki/" + subURL,
+		LastCommit: convert.ToWikiCommit(lastCommit),
+	}
+	if !withContent {
+		return page, nil
+	}
+
+	data, err := readWikiEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	page.Content = string(data)
+	page.HTML = markup.RenderWiki(entry.Name(), data, repo.HTMLURL(), repo.ComposeMetas())
+	return page, nil
+}
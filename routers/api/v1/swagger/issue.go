@@ -67,3 +67,15 @@ type swaggerResponseTrackedTimeList struct {
 	// in:body
 	Body []api.TrackedTime `json:"body"`
 }
+
+// swagger:response Reaction
+type swaggerResponseReaction struct {
+	// in:body
+	Body api.Reaction `json:"body"`
+}
+
+// swagger:response ReactionList
+type swaggerResponseReactionList struct {
+	// in:body
+	Body []api.Reaction `json:"body"`
+}
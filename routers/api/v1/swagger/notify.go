@@ -0,0 +1,21 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/sdk/gitea"
+)
+
+// swagger:response NotificationThread
+type swaggerResponseNotificationThread struct {
+	// in:body
+	Body api.NotificationThread `json:"body"`
+}
+
+// swagger:response NotificationThreadList
+type swaggerResponseNotificationThreadList struct {
+	// in:body
+	Body []api.NotificationThread `json:"body"`
+}
@@ -16,6 +16,8 @@ import (
 type swaggerParameterBodies struct {
 	AddCollaboratorOption api.AddCollaboratorOption
 
+	CreateAccessTokenOption api.CreateAccessTokenOption
+
 	CreateEmailOption api.CreateEmailOption
 	DeleteEmailOption api.DeleteEmailOption
 
@@ -30,6 +32,10 @@ type swaggerParameterBodies struct {
 
 	IssueLabelsOption api.IssueLabelsOption
 
+	IssueMeta api.IssueMeta
+
+	EditReactionOption api.EditReactionOption
+
 	CreateKeyOption api.CreateKeyOption
 
 	CreateLabelOption api.CreateLabelOption
@@ -46,12 +52,28 @@ type swaggerParameterBodies struct {
 	CreatePullRequestOption api.CreatePullRequestOption
 	EditPullRequestOption   api.EditPullRequestOption
 
+	CreatePullReviewOptions api.CreatePullReviewOptions
+
+	CreatePushMirrorOption api.CreatePushMirrorOption
+
+	RepoTopicOptions api.RepoTopicOptions
+
 	CreateReleaseOption api.CreateReleaseOption
 	EditReleaseOption   api.EditReleaseOption
 
 	CreateRepoOption api.CreateRepoOption
 	CreateForkOption api.CreateForkOption
 
+	CreateFileOptions api.CreateFileOptions
+	UpdateFileOptions api.UpdateFileOptions
+	DeleteFileOptions api.DeleteFileOptions
+
+	CreateWikiPageOptions api.CreateWikiPageOptions
+	EditWikiPageOptions   api.EditWikiPageOptions
+
+	CreateBranchProtectionOption api.CreateBranchProtectionOption
+	EditBranchProtectionOption   api.EditBranchProtectionOption
+
 	CreateStatusOption api.CreateStatusOption
 
 	CreateTeamOption api.CreateTeamOption
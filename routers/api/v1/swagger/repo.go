@@ -44,6 +44,18 @@ type swaggerResponseHookList struct {
 	Body []api.Branch `json:"body"`
 }
 
+// swagger:response HookDelivery
+type swaggerResponseHookDelivery struct {
+	// in:body
+	Body api.HookDelivery `json:"body"`
+}
+
+// swagger:response HookDeliveryList
+type swaggerResponseHookDeliveryList struct {
+	// in:body
+	Body []api.HookDelivery `json:"body"`
+}
+
 // swagger:response Release
 type swaggerResponseRelease struct {
 	// in:body
@@ -68,6 +80,36 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// swagger:response PullReview
+type swaggerResponsePullReview struct {
+	// in:body
+	Body api.PullReview `json:"body"`
+}
+
+// swagger:response PullReviewList
+type swaggerResponsePullReviewList struct {
+	// in:body
+	Body []api.PullReview `json:"body"`
+}
+
+// swagger:response PullReviewCommentList
+type swaggerResponsePullReviewCommentList struct {
+	// in:body
+	Body []api.PullReviewComment `json:"body"`
+}
+
+// swagger:response PushMirror
+type swaggerResponsePushMirror struct {
+	// in:body
+	Body api.PushMirror `json:"body"`
+}
+
+// swagger:response PushMirrorList
+type swaggerResponsePushMirrorList struct {
+	// in:body
+	Body []api.PushMirror `json:"body"`
+}
+
 // swagger:response Status
 type swaggerResponseStatus struct {
 	// in:body
@@ -102,3 +144,93 @@ type swaggerResponseAttachment struct {
 	//in: body
 	Body api.Attachment `json:"body"`
 }
+
+// swagger:response TopicListResponse
+type swaggerTopicListResponse struct {
+	// in: body
+	Body []api.TopicResponse `json:"body"`
+}
+
+// swagger:response TopicNames
+type swaggerTopicNames struct {
+	// in: body
+	Body api.TopicName `json:"body"`
+}
+
+// swagger:response ContentsResponse
+type swaggerContentsResponse struct {
+	// in: body
+	Body api.ContentsResponse `json:"body"`
+}
+
+// swagger:response FileResponse
+type swaggerFileResponse struct {
+	// in: body
+	Body api.FileResponse `json:"body"`
+}
+
+// swagger:response Commit
+type swaggerCommit struct {
+	// in: body
+	Body api.Commit `json:"body"`
+}
+
+// swagger:response CommitList
+type swaggerCommitList struct {
+	// in: body
+	Body []api.Commit `json:"body"`
+}
+
+// swagger:response GitTreeResponse
+type swaggerGitTreeResponse struct {
+	// in: body
+	Body api.GitTreeResponse `json:"body"`
+}
+
+// swagger:response GitBlobResponse
+type swaggerGitBlobResponse struct {
+	// in: body
+	Body api.GitBlobResponse `json:"body"`
+}
+
+// swagger:response ReferenceList
+type swaggerReferenceList struct {
+	// in: body
+	Body []api.Reference `json:"body"`
+}
+
+// swagger:response TagList
+type swaggerTagList struct {
+	// in: body
+	Body []api.Tag `json:"body"`
+}
+
+// swagger:response WikiPage
+type swaggerWikiPage struct {
+	// in: body
+	Body api.WikiPage `json:"body"`
+}
+
+// swagger:response WikiPageList
+type swaggerWikiPageList struct {
+	// in: body
+	Body []api.WikiPage `json:"body"`
+}
+
+// swagger:response WikiCommitList
+type swaggerWikiCommitList struct {
+	// in: body
+	Body []api.WikiCommit `json:"body"`
+}
+
+// swagger:response BranchProtection
+type swaggerBranchProtection struct {
+	// in: body
+	Body api.BranchProtection `json:"body"`
+}
+
+// swagger:response BranchProtectionList
+type swaggerBranchProtectionList struct {
+	// in: body
+	Body []api.BranchProtection `json:"body"`
+}
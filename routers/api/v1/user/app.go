@@ -5,10 +5,13 @@
 package user
 
 import (
+	"fmt"
+
 	api "code.gitea.io/sdk/gitea"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // ListAccessTokens list all the access tokens
@@ -29,10 +32,7 @@ func ListAccessTokens(ctx *context.APIContext) {
 
 	apiTokens := make([]*api.AccessToken, len(tokens))
 	for i := range tokens {
-		apiTokens[i] = &api.AccessToken{
-			Name: tokens[i].Name,
-			Sha1: tokens[i].Sha1,
-		}
+		apiTokens[i] = tokens[i].APIFormat()
 	}
 	ctx.JSON(200, &apiTokens)
 }
@@ -46,19 +46,49 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 	// - application/json
 	// produces:
 	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateAccessTokenOption"
 	// responses:
-	//   "200":
+	//   "201":
 	//     "$ref": "#/responses/AccessToken"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	scopes, err := models.ParseAccessTokenScopes(form.Scopes)
+	if err != nil {
+		ctx.Error(422, "", err)
+		return
+	}
+
+	repoIDs, err := models.GetRepositoryIDsByFullNames(form.Repos)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Error(422, "", fmt.Sprintf("Repository does not exist: %v", err))
+		} else {
+			ctx.Error(500, "GetRepositoryIDsByFullNames", err)
+		}
+		return
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:     ctx.User.ID,
+		Name:    form.Name,
+		Scopes:  scopes,
+		RepoIDs: repoIDs,
 	}
-	if err := models.NewAccessToken(t); err != nil {
+	if form.ExpiresAt != nil {
+		t.ExpiredUnix = util.TimeStamp(form.ExpiresAt.Unix())
+		if t.IsExpired() {
+			ctx.Error(422, "", "Expiry date must be in the future")
+			return
+		}
+	}
+
+	if err = models.NewAccessToken(t); err != nil {
 		ctx.Error(500, "NewAccessToken", err)
 		return
 	}
-	ctx.JSON(201, &api.AccessToken{
-		Name: t.Name,
-		Sha1: t.Sha1,
-	})
+	ctx.JSON(201, t.APIFormat())
 }
@@ -13,19 +13,22 @@ import (
 
 // getStarredRepos returns the repos that the user with the specified userID has
 // starred
-func getStarredRepos(userID int64, private bool) ([]*api.Repository, error) {
+func getStarredRepos(ctx *context.APIContext, userID int64, private bool) ([]*api.Repository, error) {
 	starredRepos, err := models.GetStarredRepos(userID, private)
 	if err != nil {
 		return nil, err
 	}
 
-	repos := make([]*api.Repository, len(starredRepos))
-	for i, starred := range starredRepos {
+	repos := make([]*api.Repository, 0, len(starredRepos))
+	for _, starred := range starredRepos {
+		if !ctx.TokenCanAccessRepo(starred) {
+			continue
+		}
 		access, err := models.AccessLevel(userID, starred)
 		if err != nil {
 			return nil, err
 		}
-		repos[i] = starred.APIFormat(access)
+		repos = append(repos, starred.APIFormat(access))
 	}
 	return repos, nil
 }
@@ -48,7 +51,7 @@ func GetStarredRepos(ctx *context.APIContext) {
 	//     "$ref": "#/responses/RepositoryList"
 	user := GetUserByParams(ctx)
 	private := user.ID == ctx.User.ID
-	repos, err := getStarredRepos(user.ID, private)
+	repos, err := getStarredRepos(ctx, user.ID, private)
 	if err != nil {
 		ctx.Error(500, "getStarredRepos", err)
 	}
@@ -65,7 +68,7 @@ func GetMyStarredRepos(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
-	repos, err := getStarredRepos(ctx.User.ID, true)
+	repos, err := getStarredRepos(ctx, ctx.User.ID, true)
 	if err != nil {
 		ctx.Error(500, "getStarredRepos", err)
 	}
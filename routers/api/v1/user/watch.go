@@ -14,19 +14,22 @@ import (
 
 // getWatchedRepos returns the repos that the user with the specified userID is
 // watching
-func getWatchedRepos(userID int64, private bool) ([]*api.Repository, error) {
+func getWatchedRepos(ctx *context.APIContext, userID int64, private bool) ([]*api.Repository, error) {
 	watchedRepos, err := models.GetWatchedRepos(userID, private)
 	if err != nil {
 		return nil, err
 	}
 
-	repos := make([]*api.Repository, len(watchedRepos))
-	for i, watched := range watchedRepos {
+	repos := make([]*api.Repository, 0, len(watchedRepos))
+	for _, watched := range watchedRepos {
+		if !ctx.TokenCanAccessRepo(watched) {
+			continue
+		}
 		access, err := models.AccessLevel(userID, watched)
 		if err != nil {
 			return nil, err
 		}
-		repos[i] = watched.APIFormat(access)
+		repos = append(repos, watched.APIFormat(access))
 	}
 	return repos, nil
 }
@@ -48,7 +51,7 @@ func GetWatchedRepos(ctx *context.APIContext) {
 	//     "$ref": "#/responses/RepositoryList"
 	user := GetUserByParams(ctx)
 	private := user.ID == ctx.User.ID
-	repos, err := getWatchedRepos(user.ID, private)
+	repos, err := getWatchedRepos(ctx, user.ID, private)
 	if err != nil {
 		ctx.Error(500, "getWatchedRepos", err)
 	}
@@ -65,7 +68,7 @@ func GetMyWatchedRepos(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
-	repos, err := getWatchedRepos(ctx.User.ID, true)
+	repos, err := getWatchedRepos(ctx, ctx.User.ID, true)
 	if err != nil {
 		ctx.Error(500, "getWatchedRepos", err)
 	}
@@ -7,8 +7,10 @@ package utils
 import (
 	api "code.gitea.io/sdk/gitea"
 
+	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/routers/api/v1/convert"
 	"encoding/json"
 	"github.com/Unknwon/com"
@@ -45,6 +47,30 @@ func GetRepoHook(ctx *context.APIContext, repoID, hookID int64) (*models.Webhook
 	return w, nil
 }
 
+// GetAdminHook get a system or default webhook. If there is an error, write
+// to `ctx` accordingly and return the error
+func GetAdminHook(ctx *context.APIContext, hookID int64) (*models.Webhook, error) {
+	w, err := models.GetSystemOrDefaultWebhook(hookID)
+	if err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetSystemOrDefaultWebhook", err)
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
+// AdminHooksLink returns the link of the web pages managing the system webhooks,
+// or the default webhooks
+func AdminHooksLink(isSystemWebhook bool) string {
+	if isSystemWebhook {
+		return setting.AppSubURL + "/admin/system-hooks"
+	}
+	return setting.AppSubURL + "/admin/default-hooks"
+}
+
 // CheckCreateHookOption check if a CreateHookOption form is valid. If invalid,
 // write the appropriate error to `ctx`. Return whether the form is valid
 func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption) bool {
@@ -68,24 +94,32 @@ func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption)
 // AddOrgHook add a hook to an organization. Writes to `ctx` accordingly
 func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	org := ctx.Org.Organization
-	hook, ok := addHook(ctx, form, org.ID, 0)
+	hook, ok := addHook(ctx, form, org.ID, 0, false)
 	if ok {
-		ctx.JSON(http.StatusCreated, convert.ToHook(org.HomeLink(), hook))
+		ctx.JSON(http.StatusCreated, convert.ToHook(org.HomeLink()+"/settings/hooks", hook))
 	}
 }
 
 // AddRepoHook add a hook to a repo. Writes to `ctx` accordingly
 func AddRepoHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	repo := ctx.Repo
-	hook, ok := addHook(ctx, form, 0, repo.Repository.ID)
+	hook, ok := addHook(ctx, form, 0, repo.Repository.ID, false)
+	if ok {
+		ctx.JSON(http.StatusCreated, convert.ToHook(repo.RepoLink+"/settings/hooks", hook))
+	}
+}
+
+// AddAdminHook add a system or default hook. Writes to `ctx` accordingly
+func AddAdminHook(ctx *context.APIContext, form *api.CreateHookOption, isSystemWebhook bool) {
+	hook, ok := addHook(ctx, form, 0, 0, isSystemWebhook)
 	if ok {
-		ctx.JSON(http.StatusCreated, convert.ToHook(repo.RepoLink, hook))
+		ctx.JSON(http.StatusCreated, convert.ToHook(AdminHooksLink(isSystemWebhook), hook))
 	}
 }
 
 // addHook add the hook specified by `form`, `orgID` and `repoID`. If there is
 // an error, write to `ctx` accordingly. Return (webhook, ok)
-func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64) (*models.Webhook, bool) {
+func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64, isSystemWebhook bool) (*models.Webhook, bool) {
 	if len(form.Events) == 0 {
 		form.Events = []string{"push"}
 	}
@@ -98,13 +132,22 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 		HookEvent: &models.HookEvent{
 			ChooseEvents: true,
 			HookEvents: models.HookEvents{
-				Create:      com.IsSliceContainsStr(form.Events, string(models.HookEventCreate)),
-				Push:        com.IsSliceContainsStr(form.Events, string(models.HookEventPush)),
-				PullRequest: com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest)),
+				Create:            com.IsSliceContainsStr(form.Events, string(models.HookEventCreate)),
+				Delete:            com.IsSliceContainsStr(form.Events, string(models.HookEventDelete)),
+				Fork:              com.IsSliceContainsStr(form.Events, string(models.HookEventFork)),
+				Issues:            com.IsSliceContainsStr(form.Events, string(models.HookEventIssues)),
+				IssueComment:      com.IsSliceContainsStr(form.Events, string(models.HookEventIssueComment)),
+				Push:              com.IsSliceContainsStr(form.Events, string(models.HookEventPush)),
+				PullRequest:       com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest)),
+				PullRequestReview: com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequestReview)),
+				Release:           com.IsSliceContainsStr(form.Events, string(models.HookEventRelease)),
+				Label:             com.IsSliceContainsStr(form.Events, string(models.HookEventLabel)),
+				Milestone:         com.IsSliceContainsStr(form.Events, string(models.HookEventMilestone)),
 			},
 		},
-		IsActive:     form.Active,
-		HookTaskType: models.ToHookTaskType(form.Type),
+		IsActive:        form.Active,
+		HookTaskType:    models.ToHookTaskType(form.Type),
+		IsSystemWebhook: isSystemWebhook,
 	}
 	if w.HookTaskType == models.SLACK {
 		channel, ok := form.Config["channel"]
@@ -123,6 +166,18 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 			return nil, false
 		}
 		w.Meta = string(meta)
+	} else if w.HookTaskType == models.MATTERMOST {
+		meta, err := json.Marshal(&models.MattermostMeta{
+			Channel:  form.Config["channel"],
+			Username: form.Config["username"],
+			IconURL:  form.Config["icon_url"],
+			Color:    form.Config["color"],
+		})
+		if err != nil {
+			ctx.Error(500, "mattermost: JSON marshal failed", err)
+			return nil, false
+		}
+		w.Meta = string(meta)
 	}
 
 	if err := w.UpdateEvent(); err != nil {
@@ -149,7 +204,7 @@ func EditOrgHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64
 	if err != nil {
 		return
 	}
-	ctx.JSON(200, convert.ToHook(org.HomeLink(), updated))
+	ctx.JSON(200, convert.ToHook(org.HomeLink()+"/settings/hooks", updated))
 }
 
 // EditRepoHook edit webhook `w` according to `form`. Writes to `ctx` accordingly
@@ -166,7 +221,24 @@ func EditRepoHook(ctx *context.APIContext, form *api.EditHookOption, hookID int6
 	if err != nil {
 		return
 	}
-	ctx.JSON(200, convert.ToHook(repo.RepoLink, updated))
+	ctx.JSON(200, convert.ToHook(repo.RepoLink+"/settings/hooks", updated))
+}
+
+// EditAdminHook edit system or default webhook `w` according to `form`. Writes
+// to `ctx` accordingly
+func EditAdminHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
+	hook, err := GetAdminHook(ctx, hookID)
+	if err != nil {
+		return
+	}
+	if !editHook(ctx, form, hook) {
+		return
+	}
+	updated, err := GetAdminHook(ctx, hookID)
+	if err != nil {
+		return
+	}
+	ctx.JSON(200, convert.ToHook(AdminHooksLink(updated.IsSystemWebhook), updated))
 }
 
 // editHook edit the webhook `w` according to `form`. If an error occurs, write
@@ -198,6 +270,26 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 				}
 				w.Meta = string(meta)
 			}
+		} else if w.HookTaskType == models.MATTERMOST {
+			mattermost := w.GetMattermostHook()
+			if channel, ok := form.Config["channel"]; ok {
+				mattermost.Channel = channel
+			}
+			if username, ok := form.Config["username"]; ok {
+				mattermost.Username = username
+			}
+			if iconURL, ok := form.Config["icon_url"]; ok {
+				mattermost.IconURL = iconURL
+			}
+			if color, ok := form.Config["color"]; ok {
+				mattermost.Color = color
+			}
+			meta, err := json.Marshal(mattermost)
+			if err != nil {
+				ctx.Error(500, "mattermost: JSON marshal failed", err)
+				return false
+			}
+			w.Meta = string(meta)
 		}
 	}
 
@@ -209,8 +301,16 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.SendEverything = false
 	w.ChooseEvents = true
 	w.Create = com.IsSliceContainsStr(form.Events, string(models.HookEventCreate))
+	w.Delete = com.IsSliceContainsStr(form.Events, string(models.HookEventDelete))
+	w.Fork = com.IsSliceContainsStr(form.Events, string(models.HookEventFork))
+	w.Issues = com.IsSliceContainsStr(form.Events, string(models.HookEventIssues))
+	w.IssueComment = com.IsSliceContainsStr(form.Events, string(models.HookEventIssueComment))
 	w.Push = com.IsSliceContainsStr(form.Events, string(models.HookEventPush))
 	w.PullRequest = com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequest))
+	w.PullRequestReview = com.IsSliceContainsStr(form.Events, string(models.HookEventPullRequestReview))
+	w.Release = com.IsSliceContainsStr(form.Events, string(models.HookEventRelease))
+	w.Label = com.IsSliceContainsStr(form.Events, string(models.HookEventLabel))
+	w.Milestone = com.IsSliceContainsStr(form.Events, string(models.HookEventMilestone))
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(500, "UpdateEvent", err)
 		return false
@@ -226,3 +326,84 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	}
 	return true
 }
+
+// ListHookDeliveries list the deliveries of webhook `w`. Writes to `ctx` accordingly
+func ListHookDeliveries(ctx *context.APIContext, w *models.Webhook) {
+	page := ctx.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	tasks, err := models.HookTasks(w.ID, page)
+	if err != nil {
+		ctx.Error(500, "HookTasks", err)
+		return
+	}
+	deliveries := make([]*api.HookDelivery, len(tasks))
+	for i := range tasks {
+		deliveries[i] = convert.ToHookDelivery(tasks[i])
+	}
+	ctx.JSON(200, &deliveries)
+}
+
+// getHookDelivery get the delivery of webhook `w` with the UUID of the `:uuid`
+// parameter. If there is an error, write to `ctx` accordingly and return the error
+func getHookDelivery(ctx *context.APIContext, w *models.Webhook) (*models.HookTask, error) {
+	t, err := models.GetHookTaskByUUID(w.ID, ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetHookTaskByUUID", err)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetHookDelivery get a delivery of webhook `w`. Writes to `ctx` accordingly
+func GetHookDelivery(ctx *context.APIContext, w *models.Webhook) {
+	t, err := getHookDelivery(ctx, w)
+	if err != nil {
+		return
+	}
+	ctx.JSON(200, convert.ToHookDelivery(t))
+}
+
+// RedeliverHook deliver again the payload of a delivery of webhook `w`. Writes
+// to `ctx` accordingly
+func RedeliverHook(ctx *context.APIContext, w *models.Webhook) {
+	t, err := getHookDelivery(ctx, w)
+	if err != nil {
+		return
+	}
+	redelivery, err := models.RedeliverHookTask(w, t)
+	if err != nil {
+		ctx.Error(500, "RedeliverHookTask", err)
+		return
+	}
+	go models.HookQueue.Add(redelivery.RepoID)
+	ctx.JSON(http.StatusCreated, convert.ToHookDelivery(redelivery))
+}
+
+// TestHook send a fake push event of the latest commit of the default branch
+// of `repo` to webhook `w`. Writes to `ctx` accordingly
+func TestHook(ctx *context.APIContext, w *models.Webhook, repo *models.Repository) {
+	var commit *git.Commit
+	if !repo.IsBare {
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
+		if err != nil {
+			ctx.Error(500, "OpenRepository", err)
+			return
+		}
+		// A missing default branch is not an error, a fake commit is sent instead
+		commit, _ = gitRepo.GetBranchCommit(repo.DefaultBranch)
+	}
+
+	p := models.NewTestPushPayload(repo, commit, ctx.User)
+	if err := models.PrepareWebhook(w, repo, models.HookEventPush, p); err != nil {
+		ctx.Error(500, "PrepareWebhook", err)
+		return
+	}
+	go models.HookQueue.Add(repo.ID)
+	ctx.Status(204)
+}
@@ -106,6 +106,18 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 	}
 
 	keyword := strings.Trim(ctx.Query("q"), " ")
+	topicOnly := ctx.QueryBool("topic")
+
+	var archived util.OptionalBool
+	ctx.Data["ArchivedType"] = ctx.Query("archived")
+	switch ctx.Query("archived") {
+	case "only":
+		archived = util.OptionalBoolTrue
+	case "exclude":
+		archived = util.OptionalBoolFalse
+	default:
+		ctx.Data["ArchivedType"] = ""
+	}
 
 	repos, count, err = models.SearchRepositoryByName(&models.SearchRepoOptions{
 		Page:      page,
@@ -113,14 +125,17 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		OrderBy:   orderBy,
 		Private:   opts.Private,
 		Keyword:   keyword,
+		TopicOnly: topicOnly,
 		OwnerID:   opts.OwnerID,
 		AllPublic: true,
+		Archived:  archived,
 	})
 	if err != nil {
 		ctx.ServerError("SearchRepositoryByName", err)
 		return
 	}
 	ctx.Data["Keyword"] = keyword
+	ctx.Data["TopicOnly"] = topicOnly
 	ctx.Data["Total"] = count
 	ctx.Data["Page"] = paginater.New(int(count), opts.PageSize, page, 5)
 	ctx.Data["Repos"] = repos
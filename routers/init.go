@@ -16,9 +16,11 @@ import (
 	"code.gitea.io/gitea/modules/highlight"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/mailer"
+	"code.gitea.io/gitea/modules/mailer/incoming"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/ssh"
+	"code.gitea.io/gitea/modules/storage"
 
 	macaron "gopkg.in/macaron.v1"
 )
@@ -56,6 +58,9 @@ func GlobalInit() {
 		highlight.NewContext()
 		markup.Init()
 
+		if err := storage.Init(); err != nil {
+			log.Fatal(4, "Failed to initialize storage: %v", err)
+		}
 		if err := models.NewEngine(migrations.Migrate); err != nil {
 			log.Fatal(4, "Failed to initialize ORM engine: %v", err)
 		}
@@ -86,4 +91,9 @@ func GlobalInit() {
 		ssh.Listen(setting.SSH.ListenHost, setting.SSH.ListenPort, setting.SSH.ServerCiphers, setting.SSH.ServerKeyExchanges, setting.SSH.ServerMACs)
 		log.Info("SSH server started on %s:%d. Cipher list (%v), key exchange algorithms (%v), MACs (%v)", setting.SSH.ListenHost, setting.SSH.ListenPort, setting.SSH.ServerCiphers, setting.SSH.ServerKeyExchanges, setting.SSH.ServerMACs)
 	}
+
+	if setting.InstallLock && setting.IncomingEmail.Enabled {
+		incoming.Listen()
+		log.Info("Incoming mail server started on %s", setting.IncomingEmail.ListenAddr)
+	}
 }
@@ -23,6 +23,8 @@ const (
 	tplSettingsDelete base.TplName = "org/settings/delete"
 	// tplSettingsHooks template path for render hook settings
 	tplSettingsHooks base.TplName = "org/settings/hooks"
+	// tplSettingsApplications template path for render oauth2 application settings
+	tplSettingsApplications base.TplName = "org/settings/applications"
 )
 
 // Settings render the main settings page
@@ -146,7 +148,7 @@ func SettingsDelete(ctx *context.Context) {
 func Webhooks(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("org.settings")
 	ctx.Data["PageIsSettingsHooks"] = true
-	ctx.Data["BaseLink"] = ctx.Org.OrgLink
+	ctx.Data["BaseLink"] = ctx.Org.OrgLink + "/settings/hooks"
 	ctx.Data["Description"] = ctx.Tr("org.settings.hooks_desc")
 
 	ws, err := models.GetWebhooksByOrgID(ctx.Org.Organization.ID)
@@ -159,6 +161,20 @@ func Webhooks(ctx *context.Context) {
 	ctx.HTML(200, tplSettingsHooks)
 }
 
+// Applications render the oauth2 applications of the organization
+func Applications(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsApplications"] = true
+
+	apps, err := models.GetOAuth2ApplicationsByUserID(ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.ServerError("GetOAuth2ApplicationsByUserID", err)
+		return
+	}
+	ctx.Data["Applications"] = apps
+	ctx.HTML(200, tplSettingsApplications)
+}
+
 // DeleteWebhook response for delete webhook
 func DeleteWebhook(ctx *context.Context) {
 	if err := models.DeleteWebhookByOrgID(ctx.Org.Organization.ID, ctx.QueryInt64("id")); err != nil {
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 
 	macaron "gopkg.in/macaron.v1"
 )
@@ -43,5 +44,14 @@ func PushUpdate(ctx *macaron.Context) {
 		}
 		return
 	}
+
+	if strings.HasPrefix(opt.RefFullName, git.BranchPrefix) && opt.NewCommitID != git.EmptySHA {
+		repo, err := models.GetRepositoryByOwnerAndName(opt.RepoUserName, opt.RepoName)
+		if err != nil {
+			log.Error(4, "GetRepositoryByOwnerAndName: %v", err)
+		} else {
+			notification.Service.NotifyPush(repo, branch, opt.NewCommitID, opt.PusherID)
+		}
+	}
 	ctx.Status(202)
 }
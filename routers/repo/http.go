@@ -149,7 +149,7 @@ func HTTP(ctx *context.Context) {
 				// Assume password is a token.
 				token, err := models.GetAccessTokenBySHA(authToken)
 				if err != nil {
-					if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) {
+					if models.IsErrAccessTokenNotExist(err) || models.IsErrAccessTokenEmpty(err) || models.IsErrAccessTokenExpired(err) {
 						ctx.HandleText(http.StatusUnauthorized, "invalid credentials")
 					} else {
 						ctx.ServerError("GetAccessTokenBySha", err)
@@ -168,6 +168,11 @@ func HTTP(ctx *context.Context) {
 					return
 				}
 
+				if !token.AllowsRepoAccess(repo, accessMode) {
+					ctx.HandleText(http.StatusForbidden, "token does not have the required scope")
+					return
+				}
+
 				token.UpdatedUnix = util.TimeStampNow()
 				if err = models.UpdateAccessToken(token); err != nil {
 					ctx.ServerError("UpdateAccessToken", err)
@@ -210,6 +215,11 @@ func HTTP(ctx *context.Context) {
 					ctx.HandleText(http.StatusForbidden, "mirror repository is read-only")
 					return
 				}
+
+				if !isPull && repo.IsArchived {
+					ctx.HandleText(http.StatusForbidden, "repository is archived")
+					return
+				}
 			}
 		}
 
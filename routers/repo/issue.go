@@ -294,6 +294,8 @@ func RetrieveRepoMetas(ctx *context.Context, repo *models.Repository) []*models.
 	if ctx.Written() {
 		return nil
 	}
+	// Nothing is selected yet, ValidateRepoMetas overwrites this with the submitted assignees.
+	ctx.Data["AssigneeIDsMark"] = map[int64]bool{}
 
 	brs, err := ctx.Repo.GitRepo.GetBranches()
 	if err != nil {
@@ -364,7 +366,7 @@ func NewIssue(ctx *context.Context) {
 }
 
 // ValidateRepoMetas check and returns repository's meta informations
-func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64, int64, int64) {
+func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64, []int64, int64) {
 	var (
 		repo = ctx.Repo.Repository
 		err  error
@@ -372,11 +374,11 @@ func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64
 
 	labels := RetrieveRepoMetas(ctx, ctx.Repo.Repository)
 	if ctx.Written() {
-		return nil, 0, 0
+		return nil, nil, 0
 	}
 
 	if !ctx.Repo.IsWriter() {
-		return nil, 0, 0
+		return nil, nil, 0
 	}
 
 	var labelIDs []int64
@@ -385,7 +387,7 @@ func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64
 	if len(form.LabelIDs) > 0 {
 		labelIDs, err = base.StringsToInt64s(strings.Split(form.LabelIDs, ","))
 		if err != nil {
-			return nil, 0, 0
+			return nil, nil, 0
 		}
 		labelIDMark := base.Int64sToMap(labelIDs)
 
@@ -407,23 +409,33 @@ func ValidateRepoMetas(ctx *context.Context, form auth.CreateIssueForm) ([]int64
 		ctx.Data["Milestone"], err = repo.GetMilestoneByID(milestoneID)
 		if err != nil {
 			ctx.ServerError("GetMilestoneByID", err)
-			return nil, 0, 0
+			return nil, nil, 0
 		}
 		ctx.Data["milestone_id"] = milestoneID
 	}
 
-	// Check assignee.
-	assigneeID := form.AssigneeID
-	if assigneeID > 0 {
-		ctx.Data["Assignee"], err = repo.GetAssigneeByID(assigneeID)
+	// Check assignees.
+	var assigneeIDs []int64
+	if len(form.AssigneeIDs) > 0 {
+		assigneeIDs, err = base.StringsToInt64s(strings.Split(form.AssigneeIDs, ","))
 		if err != nil {
-			ctx.ServerError("GetAssigneeByID", err)
-			return nil, 0, 0
+			return nil, nil, 0
+		}
+
+		// Check if the passed assignees actually exist and have write access to the repo.
+		for _, aID := range assigneeIDs {
+			if _, err = repo.GetAssigneeByID(aID); err != nil {
+				ctx.ServerError("GetAssigneeByID", err)
+				return nil, nil, 0
+			}
 		}
-		ctx.Data["assignee_id"] = assigneeID
 	}
 
-	return labelIDs, milestoneID, assigneeID
+	ctx.Data["AssigneeIDsMark"] = base.Int64sToMap(assigneeIDs)
+	ctx.Data["HasSelectedAssignee"] = len(assigneeIDs) > 0
+	ctx.Data["assignee_ids"] = form.AssigneeIDs
+
+	return labelIDs, assigneeIDs, milestoneID
 }
 
 // NewIssuePost response for creating new issue
@@ -440,7 +452,7 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		attachments []string
 	)
 
-	labelIDs, milestoneID, assigneeID := ValidateRepoMetas(ctx, form)
+	labelIDs, assigneeIDs, milestoneID := ValidateRepoMetas(ctx, form)
 	if ctx.Written() {
 		return
 	}
@@ -460,16 +472,15 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		PosterID:    ctx.User.ID,
 		Poster:      ctx.User,
 		MilestoneID: milestoneID,
-		AssigneeID:  assigneeID,
 		Content:     form.Content,
 		Ref:         form.Ref,
 	}
-	if err := models.NewIssue(repo, issue, labelIDs, attachments); err != nil {
+	if err := models.NewIssue(repo, issue, labelIDs, assigneeIDs, attachments); err != nil {
 		ctx.ServerError("NewIssue", err)
 		return
 	}
 
-	notification.Service.NotifyIssue(issue, ctx.User.ID)
+	notification.Service.NotifyNewIssue(issue, ctx.User.ID)
 
 	log.Trace("Issue created: %d/%d", repo.ID, issue.ID)
 	ctx.Redirect(ctx.Repo.RepoLink + "/issues/" + com.ToStr(issue.Index))
@@ -594,12 +605,18 @@ func ViewIssue(ctx *context.Context) {
 	ctx.Data["HasSelectedLabel"] = hasSelected
 	ctx.Data["Labels"] = labels
 
-	// Check milestone and assignee.
+	// Check milestone and assignees.
 	if ctx.Repo.IsWriter() {
 		RetrieveRepoMilestonesAndAssignees(ctx, repo)
 		if ctx.Written() {
 			return
 		}
+
+		assigneeIDMark := make(map[int64]bool, len(issue.Assignees))
+		for _, assignee := range issue.Assignees {
+			assigneeIDMark[assignee.ID] = true
+		}
+		ctx.Data["AssigneeIDsMark"] = assigneeIDMark
 	}
 
 	if ctx.IsSigned {
@@ -610,6 +627,16 @@ func ViewIssue(ctx *context.Context) {
 		}
 	}
 
+	// Get dependencies.
+	if ctx.Data["BlockedBy"], err = issue.BlockedBy(ctx.User); err != nil {
+		ctx.ServerError("BlockedBy", err)
+		return
+	}
+	if ctx.Data["Blocking"], err = issue.Blocking(ctx.User); err != nil {
+		ctx.ServerError("Blocking", err)
+		return
+	}
+
 	var (
 		tag          models.CommentTag
 		ok           bool
@@ -706,6 +733,24 @@ func ViewIssue(ctx *context.Context) {
 				ctx.ServerError("LoadAssignees", err)
 				return
 			}
+		} else if comment.Type == models.CommentTypeAddDependency || comment.Type == models.CommentTypeRemoveDependency {
+			if err = comment.LoadDependentIssue(ctx.User); err != nil {
+				ctx.ServerError("LoadDependentIssue", err)
+				return
+			}
+		} else if comment.Type == models.CommentTypeReview {
+			if err = comment.LoadReview(); err != nil {
+				ctx.ServerError("LoadReview", err)
+				return
+			}
+			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
+				ctx.Repo.Repository.ComposeMetas()))
+			if comment.Review != nil {
+				for _, codeComment := range comment.Review.CodeComments {
+					codeComment.RenderedContent = string(markdown.Render([]byte(codeComment.Content), ctx.Repo.RepoLink,
+						ctx.Repo.Repository.ComposeMetas()))
+				}
+			}
 		}
 	}
 
@@ -748,6 +793,36 @@ func ViewIssue(ctx *context.Context) {
 			}
 		}
 		ctx.Data["IsPullBranchDeletable"] = canDelete && pull.HeadRepo != nil && git.IsBranchExist(pull.HeadRepo.RepoPath(), pull.HeadBranch)
+
+		if ctx.IsSigned && !pull.HasMerged && !issue.IsClosed && pull.CanAutoMerge() {
+			if err = pull.CheckUserAllowedToMerge(ctx.User); err != nil {
+				if !models.IsErrNotAllowedToMerge(err) {
+					ctx.ServerError("CheckUserAllowedToMerge", err)
+					return
+				}
+				ctx.Data["MergeBlockedReason"] = err.(models.ErrNotAllowedToMerge).Reason
+			}
+		}
+
+		if ctx.Data["Reviewers"], err = models.GetReviewersByIssueID(issue.ID); err != nil {
+			ctx.ServerError("GetReviewersByIssueID", err)
+			return
+		}
+		if ctx.IsSigned {
+			currentReview, err := models.GetCurrentReview(ctx.User, issue)
+			if err != nil && !models.IsErrReviewNotExist(err) {
+				ctx.ServerError("GetCurrentReview", err)
+				return
+			}
+			if currentReview != nil {
+				if err = currentReview.LoadCodeComments(); err != nil {
+					ctx.ServerError("LoadCodeComments", err)
+					return
+				}
+			}
+			ctx.Data["CurrentReview"] = currentReview
+			ctx.Data["CanReview"] = !issue.IsPoster(ctx.User.ID)
+		}
 	}
 
 	ctx.Data["Participants"] = participants
@@ -895,7 +970,7 @@ func UpdateIssueMilestone(ctx *context.Context) {
 	})
 }
 
-// UpdateIssueAssignee change issue's assignee
+// UpdateIssueAssignee change issue's assignees
 func UpdateIssueAssignee(ctx *context.Context) {
 	issues := getActionIssues(ctx)
 	if ctx.Written() {
@@ -903,12 +978,31 @@ func UpdateIssueAssignee(ctx *context.Context) {
 	}
 
 	assigneeID := ctx.QueryInt64("id")
+	action := ctx.Query("action")
 	for _, issue := range issues {
-		if issue.AssigneeID == assigneeID {
+		if action == "clear" || assigneeID == 0 {
+			if err := models.DeleteNotPassedAssignee(issue, ctx.User, []*models.User{}); err != nil {
+				ctx.ServerError("ClearAssignees", err)
+				return
+			}
 			continue
 		}
-		if err := issue.ChangeAssignee(ctx.User, assigneeID); err != nil {
-			ctx.ServerError("ChangeAssignee", err)
+
+		isAssigned, err := models.IsUserAssignedToIssue(issue, &models.User{ID: assigneeID})
+		if err != nil {
+			ctx.ServerError("IsUserAssignedToIssue", err)
+			return
+		}
+		// Only "detach" removes an assignee, anything else adds one.
+		if isAssigned != (action == "detach") {
+			continue
+		}
+		if _, err = issue.ToggleAssignee(ctx.User, assigneeID); err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(404, "ToggleAssignee")
+			} else {
+				ctx.ServerError("ToggleAssignee", err)
+			}
 			return
 		}
 	}
@@ -917,6 +1011,15 @@ func UpdateIssueAssignee(ctx *context.Context) {
 	})
 }
 
+// dependenciesLeftMessage returns the message telling the user that the issue
+// can't be closed, mentioning the blockers the user can't see.
+func dependenciesLeftMessage(ctx *context.Context, issue *models.Issue, err error) string {
+	if hidden := err.(models.ErrDependenciesLeft).NumHidden; hidden > 0 {
+		return ctx.Tr("repo.issues.dependency.close_blocked_hidden", issue.Index, hidden)
+	}
+	return ctx.Tr("repo.issues.dependency.close_blocked", issue.Index)
+}
+
 // UpdateIssueStatus change issue's status
 func UpdateIssueStatus(ctx *context.Context) {
 	issues := getActionIssues(ctx)
@@ -940,6 +1043,10 @@ func UpdateIssueStatus(ctx *context.Context) {
 	}
 	for _, issue := range issues {
 		if err := issue.ChangeStatus(ctx.User, issue.Repo, isClosed); err != nil {
+			if models.IsErrDependenciesLeft(err) {
+				ctx.Flash.Error(dependenciesLeftMessage(ctx, issue, err))
+				continue
+			}
 			ctx.ServerError("ChangeStatus", err)
 			return
 		}
@@ -1002,7 +1109,11 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 				ctx.Flash.Info(ctx.Tr("repo.pulls.open_unmerged_pull_exists", pr.Index))
 			} else {
 				if err := issue.ChangeStatus(ctx.User, ctx.Repo.Repository, form.Status == "close"); err != nil {
-					log.Error(4, "ChangeStatus: %v", err)
+					if models.IsErrDependenciesLeft(err) {
+						ctx.Flash.Error(dependenciesLeftMessage(ctx, issue, err))
+					} else {
+						log.Error(4, "ChangeStatus: %v", err)
+					}
 				} else {
 					log.Trace("Issue [%d] status changed to closed: %v", issue.ID, issue.IsClosed)
 
@@ -1034,7 +1145,7 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 		return
 	}
 
-	notification.Service.NotifyIssue(issue, ctx.User.ID)
+	notification.Service.NotifyComment(issue, comment, ctx.User.ID)
 
 	log.Trace("Comment created: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
 }
@@ -1055,6 +1166,7 @@ func UpdateCommentContent(ctx *context.Context) {
 		return
 	}
 
+	oldContent := comment.Content
 	comment.Content = ctx.Query("content")
 	if len(comment.Content) == 0 {
 		ctx.JSON(200, map[string]interface{}{
@@ -1062,7 +1174,7 @@ func UpdateCommentContent(ctx *context.Context) {
 		})
 		return
 	}
-	if err = models.UpdateComment(comment); err != nil {
+	if err = models.UpdateComment(ctx.User, comment, oldContent); err != nil {
 		ctx.ServerError("UpdateComment", err)
 		return
 	}
@@ -1088,7 +1200,7 @@ func DeleteComment(ctx *context.Context) {
 		return
 	}
 
-	if err = models.DeleteComment(comment); err != nil {
+	if err = models.DeleteComment(ctx.User, comment); err != nil {
 		ctx.ServerError("DeleteCommentByID", err)
 		return
 	}
@@ -1179,7 +1291,7 @@ func NewMilestonePost(ctx *context.Context, form auth.CreateMilestoneForm) {
 		return
 	}
 
-	if err = models.NewMilestone(&models.Milestone{
+	if err = models.NewMilestone(ctx.User, &models.Milestone{
 		RepoID:       ctx.Repo.Repository.ID,
 		Name:         form.Title,
 		Content:      form.Content,
@@ -1253,7 +1365,7 @@ func EditMilestonePost(ctx *context.Context, form auth.CreateMilestoneForm) {
 	m.Name = form.Title
 	m.Content = form.Content
 	m.DeadlineUnix = util.TimeStamp(deadline.Unix())
-	if err = models.UpdateMilestone(m); err != nil {
+	if err = models.UpdateMilestone(ctx.User, m); err != nil {
 		ctx.ServerError("UpdateMilestone", err)
 		return
 	}
@@ -1277,7 +1389,7 @@ func ChangeMilestonStatus(ctx *context.Context) {
 	switch ctx.Params(":action") {
 	case "open":
 		if m.IsClosed {
-			if err = models.ChangeMilestoneStatus(m, false); err != nil {
+			if err = models.ChangeMilestoneStatus(ctx.User, m, false); err != nil {
 				ctx.ServerError("ChangeMilestoneStatus", err)
 				return
 			}
@@ -1286,7 +1398,7 @@ func ChangeMilestonStatus(ctx *context.Context) {
 	case "close":
 		if !m.IsClosed {
 			m.ClosedDateUnix = util.TimeStampNow()
-			if err = models.ChangeMilestoneStatus(m, true); err != nil {
+			if err = models.ChangeMilestoneStatus(ctx.User, m, true); err != nil {
 				ctx.ServerError("ChangeMilestoneStatus", err)
 				return
 			}
@@ -1299,7 +1411,7 @@ func ChangeMilestonStatus(ctx *context.Context) {
 
 // DeleteMilestone delete a milestone
 func DeleteMilestone(ctx *context.Context) {
-	if err := models.DeleteMilestoneByRepoID(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+	if err := models.DeleteMilestoneByRepoID(ctx.User, ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteMilestoneByRepoID: " + err.Error())
 	} else {
 		ctx.Flash.Success(ctx.Tr("repo.milestones.deletion_success"))
@@ -0,0 +1,119 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// getDependencyIssue returns the issue referenced by the form and flashes an error if
+// it doesn't exist or the user isn't allowed to link it. Blocking an issue requires write
+// access to its repository, being blocked by it only read access.
+func getDependencyIssue(ctx *context.Context, form auth.IssueDependencyForm) *models.Issue {
+	dep, err := models.GetIssueByRef(ctx.Repo.Repository, form.Reference)
+	if err != nil {
+		ctx.ServerError("GetIssueByRef", err)
+		return nil
+	}
+	if dep != nil {
+		mode := models.AccessModeRead
+		if form.Type == "blocks" {
+			mode = models.AccessModeWrite
+		}
+		has, err := models.HasAccess(ctx.User.ID, dep.Repo, mode)
+		if err != nil {
+			ctx.ServerError("HasAccess", err)
+			return nil
+		} else if has {
+			return dep
+		}
+	}
+	ctx.Flash.Error(ctx.Tr("repo.issues.dependency.issue_not_exist", form.Reference))
+	return nil
+}
+
+// dependencyPair returns the blocked and the blocking issue depending on the type
+// of the dependency as seen from issue
+func dependencyPair(issue, dep *models.Issue, depType string) (*models.Issue, *models.Issue) {
+	if depType == "blocks" {
+		return dep, issue
+	}
+	return issue, dep
+}
+
+// AddIssueDependency adds a dependency to an issue
+func AddIssueDependency(ctx *context.Context, form auth.IssueDependencyForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	url := issue.HTMLURL()
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(url, http.StatusSeeOther)
+		return
+	}
+
+	dep := getDependencyIssue(ctx, form)
+	if ctx.Written() {
+		return
+	} else if dep == nil {
+		ctx.Redirect(url, http.StatusSeeOther)
+		return
+	}
+
+	blocked, blocking := dependencyPair(issue, dep, form.Type)
+	if err := models.CreateIssueDependency(ctx.User, blocked, blocking); err != nil {
+		if models.IsErrDependencyExists(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.already_exists"))
+		} else if models.IsErrCircularDependency(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.circular"))
+		} else {
+			ctx.ServerError("CreateIssueDependency", err)
+			return
+		}
+	}
+
+	ctx.Redirect(url, http.StatusSeeOther)
+}
+
+// RemoveIssueDependency removes a dependency from an issue
+func RemoveIssueDependency(ctx *context.Context, form auth.IssueDependencyForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	url := issue.HTMLURL()
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(url, http.StatusSeeOther)
+		return
+	}
+
+	dep := getDependencyIssue(ctx, form)
+	if ctx.Written() {
+		return
+	} else if dep == nil {
+		ctx.Redirect(url, http.StatusSeeOther)
+		return
+	}
+
+	blocked, blocking := dependencyPair(issue, dep, form.Type)
+	if err := models.RemoveIssueDependency(ctx.User, blocked, blocking); err != nil {
+		if !models.IsErrDependencyNotExist(err) {
+			ctx.ServerError("RemoveIssueDependency", err)
+			return
+		}
+		ctx.Flash.Error(ctx.Tr("repo.issues.dependency.not_exist"))
+	}
+
+	ctx.Redirect(url, http.StatusSeeOther)
+}
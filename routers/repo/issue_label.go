@@ -47,7 +47,7 @@ func InitializeLabels(ctx *context.Context, form auth.InitializeLabelsForm) {
 			Color:  list[i][1],
 		}
 	}
-	if err := models.NewLabels(labels...); err != nil {
+	if err := models.NewLabels(ctx.User, labels...); err != nil {
 		ctx.ServerError("NewLabels", err)
 		return
 	}
@@ -85,7 +85,7 @@ func NewLabel(ctx *context.Context, form auth.CreateLabelForm) {
 		Name:   form.Title,
 		Color:  form.Color,
 	}
-	if err := models.NewLabel(l); err != nil {
+	if err := models.NewLabel(ctx.User, l); err != nil {
 		ctx.ServerError("NewLabel", err)
 		return
 	}
@@ -107,7 +107,7 @@ func UpdateLabel(ctx *context.Context, form auth.CreateLabelForm) {
 
 	l.Name = form.Title
 	l.Color = form.Color
-	if err := models.UpdateLabel(l); err != nil {
+	if err := models.UpdateLabel(ctx.User, l); err != nil {
 		ctx.ServerError("UpdateLabel", err)
 		return
 	}
@@ -116,7 +116,7 @@ func UpdateLabel(ctx *context.Context, form auth.CreateLabelForm) {
 
 // DeleteLabel delete a label
 func DeleteLabel(ctx *context.Context) {
-	if err := models.DeleteLabel(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+	if err := models.DeleteLabel(ctx.User, ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteLabel: " + err.Error())
 	} else {
 		ctx.Flash.Success(ctx.Tr("repo.issues.label_deletion_success"))
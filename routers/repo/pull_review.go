@@ -0,0 +1,106 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// pullHeadCommitID returns the commit ID the head of the pull request currently points to
+func pullHeadCommitID(ctx *context.Context, issue *models.Issue) string {
+	commitID, err := ctx.Repo.GitRepo.GetRefCommitID(issue.PullRequest.GetGitRefName())
+	if err != nil {
+		log.Error(4, "GetRefCommitID(%s): %v", issue.PullRequest.GetGitRefName(), err)
+		return ""
+	}
+	return commitID
+}
+
+// CreateCodeComment adds a line comment to the pending review of the current user
+func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issueLink := fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	commitSHA := form.CommitSHA
+	if len(commitSHA) == 0 {
+		commitSHA = pullHeadCommitID(ctx, issue)
+	}
+
+	comment, err := models.CreateCodeComment(&models.CreateCodeCommentOptions{
+		Doer:      ctx.User,
+		Repo:      ctx.Repo.Repository,
+		Issue:     issue,
+		Content:   form.Content,
+		TreePath:  form.TreePath,
+		LineNum:   form.Line,
+		CommitSHA: commitSHA,
+	})
+	if err != nil {
+		ctx.ServerError("CreateCodeComment", err)
+		return
+	}
+
+	log.Trace("Code comment created: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, comment.ID)
+	ctx.Redirect(issueLink)
+}
+
+// SubmitReview publishes the pending review of the current user
+func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issueLink := fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	reviewType := form.ReviewType()
+	if (reviewType == models.ReviewTypeApprove || reviewType == models.ReviewTypeReject) &&
+		issue.IsPoster(ctx.User.ID) {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.review_self_not_allowed"))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	commitID := form.CommitID
+	if len(commitID) == 0 {
+		commitID = pullHeadCommitID(ctx, issue)
+	}
+
+	review, comment, err := models.SubmitReview(ctx.User, ctx.Repo.Repository, issue, reviewType, form.Content, commitID)
+	if err != nil {
+		if models.IsErrReviewEmpty(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.review_empty"))
+			ctx.Redirect(issueLink)
+			return
+		}
+		ctx.ServerError("SubmitReview", err)
+		return
+	}
+
+	notification.Service.NotifyIssue(issue, ctx.User.ID)
+
+	log.Trace("Review submitted: %d/%d/%d", ctx.Repo.Repository.ID, issue.ID, review.ID)
+	ctx.Redirect(fmt.Sprintf("%s#%s", issueLink, comment.HashTag()))
+}
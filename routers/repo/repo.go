@@ -6,12 +6,12 @@ package repo
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"strings"
 
-	"github.com/Unknwon/com"
-
 	"code.gitea.io/git"
 
 	"code.gitea.io/gitea/models"
@@ -20,12 +20,15 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 )
 
 const (
 	tplCreate  base.TplName = "repo/create"
 	tplMigrate base.TplName = "repo/migrate"
+
+	tplSubscription base.TplName = "repo/subscription"
 )
 
 // MustBeNotBare render when a repo is a bare git dir
@@ -314,24 +317,72 @@ func Action(ctx *context.Context) {
 	ctx.Redirect(redirectTo)
 }
 
+// Subscription render the page choosing the events the watcher of a repository is notified of
+func Subscription(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.subscription")
+
+	events := models.WatchEventsDefault
+	watch, err := models.GetWatch(ctx.User.ID, ctx.Repo.Repository.ID)
+	if err == nil {
+		events = watch.Events
+	} else if !models.IsErrWatchNotExist(err) {
+		ctx.ServerError("GetWatch", err)
+		return
+	}
+
+	ctx.Data["Subscription"] = auth.RepoSubscriptionForm{
+		Issues:       events&models.WatchEventIssue != 0,
+		PullRequests: events&models.WatchEventPullRequest != 0,
+		Comments:     events&models.WatchEventComment != 0,
+		Releases:     events&models.WatchEventRelease != 0,
+		Pushes:       events&models.WatchEventPush != 0,
+	}
+	ctx.HTML(200, tplSubscription)
+}
+
+// SubscriptionPost watch a repository, notified of the chosen events only
+func SubscriptionPost(ctx *context.Context, form auth.RepoSubscriptionForm) {
+	var events models.WatchEvent
+	if form.Issues {
+		events |= models.WatchEventIssue
+	}
+	if form.PullRequests {
+		events |= models.WatchEventPullRequest
+	}
+	if form.Comments {
+		events |= models.WatchEventComment
+	}
+	if form.Releases {
+		events |= models.WatchEventRelease
+	}
+	if form.Pushes {
+		events |= models.WatchEventPush
+	}
+
+	if err := models.SetWatchEvents(ctx.User.ID, ctx.Repo.Repository.ID, events); err != nil {
+		ctx.ServerError("SetWatchEvents", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.subscription_saved"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/subscription")
+}
+
 // Download download an archive of a repository
 func Download(ctx *context.Context) {
 	var (
 		uri         = ctx.Params("*")
 		refName     string
 		ext         string
-		archivePath string
 		archiveType git.ArchiveType
 	)
 
 	switch {
 	case strings.HasSuffix(uri, ".zip"):
 		ext = ".zip"
-		archivePath = path.Join(ctx.Repo.GitRepo.Path, "archives/zip")
 		archiveType = git.ZIP
 	case strings.HasSuffix(uri, ".tar.gz"):
 		ext = ".tar.gz"
-		archivePath = path.Join(ctx.Repo.GitRepo.Path, "archives/targz")
 		archiveType = git.TARGZ
 	default:
 		log.Trace("Unknown format: %s", uri)
@@ -340,13 +391,6 @@ func Download(ctx *context.Context) {
 	}
 	refName = strings.TrimSuffix(uri, ext)
 
-	if !com.IsDir(archivePath) {
-		if err := os.MkdirAll(archivePath, os.ModePerm); err != nil {
-			ctx.ServerError("Download -> os.MkdirAll(archivePath)", err)
-			return
-		}
-	}
-
 	// Get corresponding commit.
 	var (
 		commit *git.Commit
@@ -376,13 +420,61 @@ func Download(ctx *context.Context) {
 		return
 	}
 
-	archivePath = path.Join(archivePath, base.ShortSha(commit.ID.String())+ext)
-	if !com.IsFile(archivePath) {
-		if err := commit.CreateArchive(archivePath, archiveType); err != nil {
+	archivePath := models.RepoArchiveRelativePath(ctx.Repo.Repository.ID, commit.ID.String()+ext)
+	if _, err = storage.RepoArchives.Stat(archivePath); err != nil {
+		if !os.IsNotExist(err) {
+			ctx.ServerError("Download -> Stat "+archivePath, err)
+			return
+		}
+		if err = createRepoArchive(commit, archiveType, archivePath); err != nil {
 			ctx.ServerError("Download -> CreateArchive "+archivePath, err)
 			return
 		}
 	}
 
-	ctx.ServeFile(archivePath, ctx.Repo.Repository.Name+"-"+refName+ext)
+	fr, err := storage.RepoArchives.Open(archivePath)
+	if err != nil {
+		ctx.ServerError("Download -> Open "+archivePath, err)
+		return
+	}
+	defer fr.Close()
+
+	fi, err := fr.Stat()
+	if err != nil {
+		ctx.ServerError("Download -> Stat "+archivePath, err)
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Description", "File Transfer")
+	ctx.Resp.Header().Set("Content-Type", "application/octet-stream")
+	ctx.Resp.Header().Set("Content-Disposition", "attachment; filename="+ctx.Repo.Repository.Name+"-"+refName+ext)
+	ctx.Resp.Header().Set("Content-Transfer-Encoding", "binary")
+	ctx.Resp.Header().Set("Expires", "0")
+	ctx.Resp.Header().Set("Cache-Control", "must-revalidate")
+	ctx.Resp.Header().Set("Pragma", "public")
+	http.ServeContent(ctx.Resp, ctx.Req.Request, path.Base(archivePath), fi.ModTime(), fr)
+}
+
+// createRepoArchive creates the archive of the commit in a temporary file and
+// moves it to the repository archives storage.
+func createRepoArchive(commit *git.Commit, archiveType git.ArchiveType, archivePath string) error {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "gitea-archive")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := path.Join(tmpDir, path.Base(archivePath))
+	if err = commit.CreateArchive(tmpPath, archiveType); err != nil {
+		return err
+	}
+
+	f, err := os.Open(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = storage.RepoArchives.Save(archivePath, f)
+	return err
 }
@@ -7,6 +7,7 @@ package repo
 import (
 	"strings"
 	"time"
+	"unicode"
 
 	"code.gitea.io/git"
 
@@ -34,6 +35,17 @@ const (
 func Settings(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+
+	pushMirrors, err := models.GetPushMirrorsByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetPushMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PushMirrors"] = pushMirrors
+	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
+	ctx.Data["RepoTopics"] = strings.Join(ctx.Repo.Repository.Topics, " ")
+	ctx.Data["MaxTopicsPerRepo"] = models.MaxTopicsPerRepo
+
 	ctx.HTML(200, tplSettingsOptions)
 }
 
@@ -44,6 +56,16 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 
 	repo := ctx.Repo.Repository
 
+	pushMirrors, err := models.GetPushMirrorsByRepoID(repo.ID)
+	if err != nil {
+		ctx.ServerError("GetPushMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PushMirrors"] = pushMirrors
+	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
+	ctx.Data["RepoTopics"] = strings.Join(repo.Topics, " ")
+	ctx.Data["MaxTopicsPerRepo"] = models.MaxTopicsPerRepo
+
 	switch ctx.Query("action") {
 	case "update":
 		if ctx.HasError() {
@@ -108,6 +130,31 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "topics":
+		topics := strings.FieldsFunc(form.Topics, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		validTopics, invalidTopics := models.SanitizeAndValidateTopics(topics)
+		if len(invalidTopics) > 0 {
+			ctx.Data["Err_Topics"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.topics.invalid", strings.Join(invalidTopics, ", ")), tplSettingsOptions, &form)
+			return
+		}
+		if len(validTopics) > models.MaxTopicsPerRepo {
+			ctx.Data["Err_Topics"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.topics.count_prompt", models.MaxTopicsPerRepo), tplSettingsOptions, &form)
+			return
+		}
+
+		if err := models.SaveTopics(repo.ID, validTopics...); err != nil {
+			ctx.ServerError("SaveTopics", err)
+			return
+		}
+		log.Trace("Repository topics updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "mirror":
 		if !repo.IsMirror {
 			ctx.NotFound("", nil)
@@ -144,6 +191,56 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "push-mirror-add":
+		interval, err := time.ParseDuration(form.PushMirrorInterval)
+		if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+			ctx.Data["Err_PushMirrorInterval"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		addr, err := models.ParsePushMirrorAddr(form.PushMirrorAddress, form.PushMirrorUsername, form.PushMirrorPassword)
+		if err != nil {
+			ctx.Data["Err_PushMirrorAddress"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.push_mirror_address_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		m, err := models.CreatePushMirror(repo, addr, interval)
+		if err != nil {
+			ctx.ServerError("CreatePushMirror", err)
+			return
+		}
+		go models.PushMirrorQueue.Add(m.ID)
+		log.Trace("Push mirror added: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, m.Address())
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_add_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-remove":
+		m := getPushMirrorFromForm(ctx, form.PushMirrorID)
+		if ctx.Written() {
+			return
+		}
+		if err := models.DeletePushMirror(m); err != nil {
+			ctx.ServerError("DeletePushMirror", err)
+			return
+		}
+		log.Trace("Push mirror removed: %s/%s -> %s", ctx.Repo.Owner.Name, repo.Name, m.Address())
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_remove_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-sync":
+		m := getPushMirrorFromForm(ctx, form.PushMirrorID)
+		if ctx.Written() {
+			return
+		}
+
+		go models.PushMirrorQueue.Add(m.ID)
+		ctx.Flash.Info(ctx.Tr("repo.settings.push_mirror_sync_in_progress"))
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "advanced":
 		var units []models.RepoUnit
 
@@ -202,6 +299,7 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					Config: &models.IssuesConfig{
 						EnableTimetracker:                form.EnableTimetracker,
 						AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
+						EnableDependencyCheck:            form.EnableDependencyCheck,
 					},
 				})
 			}
@@ -323,11 +421,69 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.wiki_deletion_success"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
 
+	case "archive":
+		if !ctx.Repo.IsOwner() {
+			ctx.Error(403)
+			return
+		}
+
+		if repo.IsMirror {
+			ctx.Flash.Error(ctx.Tr("repo.settings.archive.error_ismirror"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+			return
+		}
+
+		if err := repo.SetArchiveRepoState(true); err != nil {
+			log.Error(4, "Tried to archive a repo: %s", err)
+			ctx.Flash.Error(ctx.Tr("repo.settings.archive.error"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+			return
+		}
+		log.Trace("Repository was archived: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.archive.success"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
+	case "unarchive":
+		if !ctx.Repo.IsOwner() {
+			ctx.Error(403)
+			return
+		}
+
+		if err := repo.SetArchiveRepoState(false); err != nil {
+			log.Error(4, "Tried to unarchive a repo: %s", err)
+			ctx.Flash.Error(ctx.Tr("repo.settings.unarchive.error"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+			return
+		}
+		log.Trace("Repository was un-archived: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.unarchive.success"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
 	default:
 		ctx.NotFound("", nil)
 	}
 }
 
+// getPushMirrorFromForm returns the push mirror with given ID if it belongs to the current repository
+func getPushMirrorFromForm(ctx *context.Context, id int64) *models.PushMirror {
+	m, err := models.GetPushMirrorByID(id)
+	if err != nil {
+		if models.IsErrPushMirrorNotExist(err) {
+			ctx.NotFound("GetPushMirrorByID", err)
+		} else {
+			ctx.ServerError("GetPushMirrorByID", err)
+		}
+		return nil
+	}
+	if m.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound("", nil)
+		return nil
+	}
+	return m
+}
+
 // Collaboration render a repository's collaboration page
 func Collaboration(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
@@ -5,8 +5,10 @@
 package routes
 
 import (
+	"net/http"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models"
@@ -17,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/options"
 	"code.gitea.io/gitea/modules/public"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/templates"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers"
@@ -39,6 +42,46 @@ import (
 	"gopkg.in/macaron.v1"
 )
 
+// storageHandler serves the objects of a storage under the given url prefix.
+// The storage is resolved lazily as it is only available once gitea is installed.
+func storageHandler(prefix string, getStorage func() storage.ObjectStorage) macaron.Handler {
+	prefix = "/" + prefix + "/"
+	return func(ctx *macaron.Context) {
+		if ctx.Req.Method != "GET" && ctx.Req.Method != "HEAD" {
+			return
+		}
+		if !strings.HasPrefix(ctx.Req.URL.Path, prefix) {
+			return
+		}
+		objStore := getStorage()
+		if objStore == nil {
+			return
+		}
+
+		p := strings.TrimPrefix(ctx.Req.URL.Path, prefix)
+		obj, err := objStore.Open(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				ctx.Error(404)
+			} else {
+				log.Error(4, "Open object %s: %v", p, err)
+				ctx.Error(500)
+			}
+			return
+		}
+		defer obj.Close()
+
+		fi, err := obj.Stat()
+		if err != nil {
+			log.Error(4, "Stat object %s: %v", p, err)
+			ctx.Error(500)
+			return
+		}
+		ctx.Resp.Header().Set("Cache-Control", "public,max-age=21600")
+		http.ServeContent(ctx.Resp, ctx.Req.Request, path.Base(p), fi.ModTime(), obj)
+	}
+}
+
 // NewMacaron initializes Macaron instance.
 func NewMacaron() *macaron.Macaron {
 	m := macaron.New()
@@ -65,14 +108,18 @@ func NewMacaron() *macaron.Macaron {
 			ExpiresAfter: time.Hour * 6,
 		},
 	))
-	m.Use(public.StaticHandler(
-		setting.AvatarUploadPath,
-		&public.Options{
-			Prefix:       "avatars",
-			SkipLogging:  setting.DisableRouterLog,
-			ExpiresAfter: time.Hour * 6,
-		},
-	))
+	if setting.AvatarStorage.Type == setting.LocalStorageType {
+		m.Use(public.StaticHandler(
+			setting.AvatarStorage.Path,
+			&public.Options{
+				Prefix:       "avatars",
+				SkipLogging:  setting.DisableRouterLog,
+				ExpiresAfter: time.Hour * 6,
+			},
+		))
+	} else {
+		m.Use(storageHandler("avatars", func() storage.ObjectStorage { return storage.Avatars }))
+	}
 
 	m.Use(templates.Renderer())
 	models.InitMailRender(templates.Mailer())
@@ -153,6 +200,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 		}
 	}
 
+	oauth2Enabled := func(ctx *context.Context) {
+		if !setting.OAuth2.Enable {
+			ctx.Error(404)
+			return
+		}
+	}
+
 	m.Use(user.GetNotificationCount)
 
 	// FIXME: not all routes need go through same middlewares.
@@ -213,6 +267,19 @@ func RegisterRoutes(m *macaron.Macaron) {
 		})
 	}, reqSignOut)
 
+	// ***** START: OAuth2 provider *****
+	m.Group("/login/oauth", func() {
+		m.Get("/authorize", bindIgnErr(auth.AuthorizationForm{}), user.AuthorizeOAuth)
+		m.Post("/grant", bindIgnErr(auth.GrantApplicationForm{}), user.GrantApplicationOAuth)
+	}, oauth2Enabled, reqSignIn)
+	m.Group("/login/oauth", func() {
+		m.Post("/access_token", bindIgnErr(auth.AccessTokenForm{}), user.AccessTokenOAuth)
+		m.Post("/revoke", bindIgnErr(auth.RevokeTokenForm{}), user.RevokeTokenOAuth)
+		m.Get("/userinfo", user.InfoOAuth)
+	}, oauth2Enabled, ignSignInAndCsrf)
+	m.Get("/.well-known/openid-configuration", oauth2Enabled, user.OIDCWellKnown)
+	// ***** END: OAuth2 provider *****
+
 	m.Group("/user/settings", func() {
 		m.Get("", user.Settings)
 		m.Post("", bindIgnErr(auth.UpdateProfileForm{}), user.SettingsPost)
@@ -236,6 +303,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Combo("/applications").Get(user.SettingsApplications).
 			Post(bindIgnErr(auth.NewAccessTokenForm{}), user.SettingsApplicationsPost)
 		m.Post("/applications/delete", user.SettingsDeleteApplication)
+		m.Group("/applications/oauth2", func() {
+			m.Post("", bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.OAuthApplicationsPost)
+			m.Post("/delete", user.DeleteOAuth2Application)
+			m.Post("/revoke", user.RevokeOAuth2Grant)
+			m.Get("/:id", user.OAuthApplicationsEdit)
+			m.Post("/:id", bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.OAuthApplicationsEditPost)
+			m.Post("/:id/regenerate_secret", user.OAuthApplicationsRegenerateSecret)
+		}, oauth2Enabled)
 		m.Route("/delete", "GET,POST", user.SettingsDelete)
 		m.Combo("/account_link").Get(user.SettingsAccountLinks).Post(user.SettingsDeleteAccountLink)
 		m.Get("/organization", user.SettingsOrganization)
@@ -258,6 +333,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Get("/forgot_password", user.ForgotPasswd)
 		m.Post("/forgot_password", user.ForgotPasswdPost)
 		m.Get("/logout", user.SignOut)
+		m.Combo("/unsubscribe/:token", ignSignInAndCsrf).Get(user.Unsubscribe).Post(user.UnsubscribePost)
 	})
 	// ***** END: User *****
 
@@ -294,6 +370,30 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Post("/:authid/delete", admin.DeleteAuthSource)
 		})
 
+		m.Get("/hooks", admin.Hooks)
+		adminHooks := func() {
+			m.Get("", admin.RedirectToHooks)
+			m.Post("/delete", admin.DeleteDefaultOrSystemWebhook)
+			m.Get("/:type/new", repo.WebhooksNew)
+			m.Post("/gitea/new", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksNewPost)
+			m.Post("/gogs/new", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksNewPost)
+			m.Post("/slack/new", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksNewPost)
+			m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
+			m.Post("/dingtalk/new", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
+			m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
+			m.Post("/mattermost/new", bindIgnErr(auth.NewMattermostHookForm{}), repo.MattermostHooksNewPost)
+			m.Get("/:id", repo.WebHooksEdit)
+			m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
+			m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
+			m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
+			m.Post("/discord/:id", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
+			m.Post("/dingtalk/:id", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksEditPost)
+			m.Post("/msteams/:id", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
+			m.Post("/mattermost/:id", bindIgnErr(auth.NewMattermostHookForm{}), repo.MattermostHooksEditPost)
+		}
+		m.Group("/system-hooks", adminHooks, admin.HooksContext(true))
+		m.Group("/default-hooks", adminHooks, admin.HooksContext(false))
+
 		m.Group("/notices", func() {
 			m.Get("", admin.Notices)
 			m.Post("/delete", admin.DeleteNotices)
@@ -320,7 +420,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				return
 			}
 
-			fr, err := os.Open(attach.LocalPath())
+			fr, err := attach.Open()
 			if err != nil {
 				ctx.ServerError("Open", err)
 				return
@@ -396,14 +496,27 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/slack/new", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksNewPost)
 					m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
 					m.Post("/dingtalk/new", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
+					m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
+					m.Post("/mattermost/new", bindIgnErr(auth.NewMattermostHookForm{}), repo.MattermostHooksNewPost)
 					m.Get("/:id", repo.WebHooksEdit)
 					m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
 					m.Post("/gogs/:id", bindIgnErr(auth.NewGogshookForm{}), repo.GogsHooksEditPost)
 					m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
 					m.Post("/discord/:id", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
 					m.Post("/dingtalk/:id", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksEditPost)
+					m.Post("/msteams/:id", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
+					m.Post("/mattermost/:id", bindIgnErr(auth.NewMattermostHookForm{}), repo.MattermostHooksEditPost)
 				})
 
+				m.Group("/applications", func() {
+					m.Get("", org.Applications)
+					m.Post("/oauth2", bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.OAuthApplicationsPost)
+					m.Post("/oauth2/delete", user.DeleteOAuth2Application)
+					m.Get("/oauth2/:id", user.OAuthApplicationsEdit)
+					m.Post("/oauth2/:id", bindIgnErr(auth.EditOAuth2ApplicationForm{}), user.OAuthApplicationsEditPost)
+					m.Post("/oauth2/:id/regenerate_secret", user.OAuthApplicationsRegenerateSecret)
+				}, oauth2Enabled)
+
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 		}, context.OrgAssignment(true, true))
@@ -446,6 +559,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/slack/new", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksNewPost)
 				m.Post("/discord/new", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksNewPost)
 				m.Post("/dingtalk/new", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksNewPost)
+				m.Post("/msteams/new", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksNewPost)
+				m.Post("/mattermost/new", bindIgnErr(auth.NewMattermostHookForm{}), repo.MattermostHooksNewPost)
 				m.Get("/:id", repo.WebHooksEdit)
 				m.Post("/:id/test", repo.TestWebhook)
 				m.Post("/gitea/:id", bindIgnErr(auth.NewWebhookForm{}), repo.WebHooksEditPost)
@@ -453,6 +568,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/slack/:id", bindIgnErr(auth.NewSlackHookForm{}), repo.SlackHooksEditPost)
 				m.Post("/discord/:id", bindIgnErr(auth.NewDiscordHookForm{}), repo.DiscordHooksEditPost)
 				m.Post("/dingtalk/:id", bindIgnErr(auth.NewDingtalkHookForm{}), repo.DingtalkHooksEditPost)
+				m.Post("/msteams/:id", bindIgnErr(auth.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
+				m.Post("/mattermost/:id", bindIgnErr(auth.NewMattermostHookForm{}), repo.MattermostHooksEditPost)
 
 				m.Group("/git", func() {
 					m.Get("", repo.GitHooks)
@@ -473,6 +590,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 	}, reqSignIn, context.RepoAssignment(), reqRepoAdmin, context.UnitTypes(), context.LoadRepoUnits(), context.RepoRef())
 
 	m.Get("/:username/:reponame/action/:action", reqSignIn, context.RepoAssignment(), repo.Action)
+	m.Combo("/:username/:reponame/subscription", reqSignIn, context.RepoAssignment()).
+		Get(repo.Subscription).
+		Post(bindIgnErr(auth.RepoSubscriptionForm{}), repo.SubscriptionPost)
 
 	m.Group("/:username/:reponame", func() {
 		m.Group("/issues", func() {
@@ -486,6 +606,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/title", repo.UpdateIssueTitle)
 				m.Post("/content", repo.UpdateIssueContent)
 				m.Post("/watch", repo.IssueWatch)
+				m.Group("/dependency", func() {
+					m.Post("/add", bindIgnErr(auth.IssueDependencyForm{}), repo.AddIssueDependency)
+					m.Post("/delete", bindIgnErr(auth.IssueDependencyForm{}), repo.RemoveIssueDependency)
+				}, reqRepoWriter)
 				m.Combo("/comments").Post(bindIgnErr(auth.CreateCommentForm{}), repo.NewComment)
 				m.Group("/times", func() {
 					m.Post("/add", bindIgnErr(auth.AddTimeManuallyForm{}), repo.AddTimeManually)
@@ -555,7 +679,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Post("/restore", repo.RestoreBranchPost)
 		}, reqRepoWriter, repo.MustBeNotBare, context.CheckUnit(models.UnitTypeCode))
 
-	}, reqSignIn, context.RepoAssignment(), context.UnitTypes(), context.LoadRepoUnits())
+	}, reqSignIn, context.RepoAssignment(), context.RepoMustNotBeArchived(), context.UnitTypes(), context.LoadRepoUnits())
 
 	// Releases
 	m.Group("/:username/:reponame", func() {
@@ -566,11 +690,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/new", repo.NewRelease)
 			m.Post("/new", bindIgnErr(auth.NewReleaseForm{}), repo.NewReleasePost)
 			m.Post("/delete", repo.DeleteRelease)
-		}, reqSignIn, repo.MustBeNotBare, reqRepoWriter, context.RepoRef())
+		}, reqSignIn, repo.MustBeNotBare, reqRepoWriter, context.RepoMustNotBeArchived(), context.RepoRef())
 		m.Group("/releases", func() {
 			m.Get("/edit/*", repo.EditRelease)
 			m.Post("/edit/*", bindIgnErr(auth.EditReleaseForm{}), repo.EditReleasePost)
-		}, reqSignIn, repo.MustBeNotBare, reqRepoWriter, func(ctx *context.Context) {
+		}, reqSignIn, repo.MustBeNotBare, reqRepoWriter, context.RepoMustNotBeArchived(), func(ctx *context.Context) {
 			var err error
 			ctx.Repo.Commit, err = ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
 			if err != nil {
@@ -604,7 +728,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:page/_edit").Get(repo.EditWiki).
 					Post(bindIgnErr(auth.NewWikiForm{}), repo.EditWikiPost)
 				m.Post("/:page/delete", repo.DeleteWikiPagePost)
-			}, reqSignIn, reqRepoWriter)
+			}, reqSignIn, reqRepoWriter, context.RepoMustNotBeArchived())
 		}, repo.MustEnableWiki, context.RepoRef())
 
 		m.Group("/wiki", func() {
@@ -627,8 +751,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get(".patch", repo.DownloadPullPatch)
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Get("/files", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.ViewPullFiles)
-			m.Post("/merge", reqRepoWriter, bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
-			m.Post("/cleanup", context.RepoRef(), repo.CleanUpPullRequest)
+			m.Post("/merge", reqRepoWriter, context.RepoMustNotBeArchived(), bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
+			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
+			m.Group("/files/reviews", func() {
+				m.Post("/comments", bindIgnErr(auth.CodeCommentForm{}), repo.CreateCodeComment)
+				m.Post("/submit", bindIgnErr(auth.SubmitReviewForm{}), repo.SubmitReview)
+			}, reqSignIn, context.RepoMustNotBeArchived())
 		}, repo.MustAllowPulls)
 
 		m.Group("/raw", func() {
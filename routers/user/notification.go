@@ -16,6 +16,7 @@ import (
 
 const (
 	tplNotification base.TplName = "user/notification/notification"
+	tplUnsubscribe  base.TplName = "user/notification/unsubscribe"
 )
 
 // GetNotificationCount is the middleware that sets the notification count in the context
@@ -65,6 +66,12 @@ func Notifications(c *context.Context) {
 		c.ServerError("ErrNotificationsForUser", err)
 		return
 	}
+	for _, n := range notifications {
+		if err = n.LoadAttributes(); err != nil {
+			c.ServerError("LoadAttributes", err)
+			return
+		}
+	}
 
 	total, err := models.GetNotificationCount(c.User, status)
 	if err != nil {
@@ -124,3 +131,53 @@ func NotificationPurgePost(c *context.Context) {
 	url := fmt.Sprintf("%s/notifications", setting.AppSubURL)
 	c.Redirect(url, 303)
 }
+
+// Unsubscribe asks for confirmation before disabling the email notifications,
+// so that mail scanners following the link don't unsubscribe the user
+func Unsubscribe(c *context.Context) {
+	u := getUnsubscribingUser(c)
+	if c.Written() {
+		return
+	}
+
+	c.Data["Title"] = c.Tr("notification.unsubscribe")
+	c.Data["UnsubscribingEmail"] = u.Email
+	c.HTML(200, tplUnsubscribe)
+}
+
+// UnsubscribePost disables the email notifications of the user the token of
+// the one-click unsubscribe link was signed for
+func UnsubscribePost(c *context.Context) {
+	u := getUnsubscribingUser(c)
+	if c.Written() {
+		return
+	}
+
+	if err := u.SetEmailNotifications(models.EmailNotificationsDisabled); err != nil {
+		c.ServerError("SetEmailNotifications", err)
+		return
+	}
+
+	// mail clients supporting RFC 8058 do not need a page to render
+	if c.Query("List-Unsubscribe") == "One-Click" {
+		c.Status(200)
+		return
+	}
+
+	c.Data["Title"] = c.Tr("notification.unsubscribe")
+	c.Data["UnsubscribedEmail"] = u.Email
+	c.HTML(200, tplUnsubscribe)
+}
+
+func getUnsubscribingUser(c *context.Context) *models.User {
+	u, err := models.GetUserByUnsubscribeToken(c.Params(":token"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			c.NotFound("GetUserByUnsubscribeToken", err)
+		} else {
+			c.ServerError("GetUserByUnsubscribeToken", err)
+		}
+		return nil
+	}
+	return u
+}
@@ -0,0 +1,698 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplGrantAccess base.TplName = "user/auth/grant"
+	tplGrantError  base.TplName = "user/auth/grant_error"
+)
+
+// AuthorizeErrorCode represents an error code specified in RFC 6749
+type AuthorizeErrorCode string
+
+const (
+	// ErrorCodeInvalidRequest represents the according error in RFC 6749
+	ErrorCodeInvalidRequest AuthorizeErrorCode = "invalid_request"
+	// ErrorCodeUnauthorizedClient represents the according error in RFC 6749
+	ErrorCodeUnauthorizedClient AuthorizeErrorCode = "unauthorized_client"
+	// ErrorCodeAccessDenied represents the according error in RFC 6749
+	ErrorCodeAccessDenied AuthorizeErrorCode = "access_denied"
+	// ErrorCodeUnsupportedResponseType represents the according error in RFC 6749
+	ErrorCodeUnsupportedResponseType AuthorizeErrorCode = "unsupported_response_type"
+	// ErrorCodeInvalidScope represents the according error in RFC 6749
+	ErrorCodeInvalidScope AuthorizeErrorCode = "invalid_scope"
+	// ErrorCodeServerError represents the according error in RFC 6749
+	ErrorCodeServerError AuthorizeErrorCode = "server_error"
+	// ErrorCodeTemporaryUnavailable represents the according error in RFC 6749
+	ErrorCodeTemporaryUnavailable AuthorizeErrorCode = "temporarily_unavailable"
+)
+
+// AuthorizeError represents an error type specified in RFC 6749
+type AuthorizeError struct {
+	ErrorCode        AuthorizeErrorCode `json:"error" form:"error"`
+	ErrorDescription string
+	State            string
+}
+
+// Error returns the error message
+func (err AuthorizeError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.ErrorDescription)
+}
+
+// AccessTokenErrorCode represents an error code specified in RFC 6749
+type AccessTokenErrorCode string
+
+const (
+	// AccessTokenErrorCodeInvalidRequest represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidRequest AccessTokenErrorCode = "invalid_request"
+	// AccessTokenErrorCodeInvalidClient represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidClient AccessTokenErrorCode = "invalid_client"
+	// AccessTokenErrorCodeInvalidGrant represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidGrant AccessTokenErrorCode = "invalid_grant"
+	// AccessTokenErrorCodeUnauthorizedClient represents an error code specified in RFC 6749
+	AccessTokenErrorCodeUnauthorizedClient AccessTokenErrorCode = "unauthorized_client"
+	// AccessTokenErrorCodeUnsupportedGrantType represents an error code specified in RFC 6749
+	AccessTokenErrorCodeUnsupportedGrantType AccessTokenErrorCode = "unsupported_grant_type"
+	// AccessTokenErrorCodeInvalidScope represents an error code specified in RFC 6749
+	AccessTokenErrorCodeInvalidScope AccessTokenErrorCode = "invalid_scope"
+)
+
+// AccessTokenError represents an error response specified in RFC 6749
+type AccessTokenError struct {
+	ErrorCode        AccessTokenErrorCode `json:"error" form:"error"`
+	ErrorDescription string               `json:"error_description"`
+}
+
+// Error returns the error message
+func (err AccessTokenError) Error() string {
+	return fmt.Sprintf("%s: %s", err.ErrorCode, err.ErrorDescription)
+}
+
+// TokenType specifies the kind of token
+type TokenType string
+
+const (
+	// TokenTypeBearer represents a token type specified in RFC 6749
+	TokenTypeBearer TokenType = "bearer"
+	// TokenTypeMAC represents a token type specified in RFC 6749
+	TokenTypeMAC TokenType = "mac"
+)
+
+// AccessTokenResponse represents a successful access token response
+type AccessTokenResponse struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    TokenType `json:"token_type"`
+	ExpiresIn    int64     `json:"expires_in"`
+	RefreshToken string    `json:"refresh_token"`
+	IDToken      string    `json:"id_token,omitempty"`
+}
+
+func newAccessTokenResponse(grant *models.OAuth2Grant) (*AccessTokenResponse, *AccessTokenError) {
+	now := time.Now()
+
+	// generate access token to access the API
+	expirationDate := now.Add(time.Duration(setting.OAuth2.AccessTokenExpirationTime) * time.Second)
+	accessToken := &models.OAuth2Token{
+		GrantID: grant.ID,
+		Type:    models.TypeAccessToken,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expirationDate.Unix(),
+		},
+	}
+	signedAccessToken, err := accessToken.SignToken()
+	if err != nil {
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot sign token",
+		}
+	}
+
+	// generate refresh token to request an access token after it expired later
+	refreshExpirationDate := now.Add(time.Duration(setting.OAuth2.RefreshTokenExpirationTime) * time.Hour)
+	refreshToken := &models.OAuth2Token{
+		GrantID: grant.ID,
+		Counter: grant.Counter,
+		Type:    models.TypeRefreshToken,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: refreshExpirationDate.Unix(),
+		},
+	}
+	signedRefreshToken, err := refreshToken.SignToken()
+	if err != nil {
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot sign token",
+		}
+	}
+
+	// generate OpenID Connect id_token
+	signedIDToken := ""
+	if grant.ScopeContains("openid") {
+		if err := grant.LoadApplication(); err != nil {
+			log.Error(4, "LoadApplication: %v", err)
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot find application",
+			}
+		}
+		user, err := models.GetUserByID(grant.UserID)
+		if err != nil {
+			log.Error(4, "GetUserByID: %v", err)
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot find user",
+			}
+		}
+
+		idToken := &models.OIDCToken{
+			StandardClaims: jwt.StandardClaims{
+				ExpiresAt: expirationDate.Unix(),
+				Issuer:    strings.TrimSuffix(setting.AppURL, "/"),
+				Audience:  grant.Application.ClientID,
+				Subject:   fmt.Sprint(grant.UserID),
+				IssuedAt:  now.Unix(),
+			},
+			Nonce: grant.Nonce,
+		}
+		if grant.ScopeContains("profile") {
+			idToken.Name = user.FullName
+			idToken.PreferredUsername = user.Name
+			idToken.Profile = user.HTMLURL()
+			idToken.Picture = user.AvatarLink()
+			idToken.Website = user.Website
+			idToken.UpdatedAt = int64(user.UpdatedUnix)
+		}
+		if grant.ScopeContains("email") {
+			idToken.Email = user.Email
+			idToken.EmailVerified = user.IsActive
+		}
+
+		signedIDToken, err = idToken.SignToken()
+		if err != nil {
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot sign token",
+			}
+		}
+	}
+
+	return &AccessTokenResponse{
+		AccessToken:  signedAccessToken,
+		TokenType:    TokenTypeBearer,
+		ExpiresIn:    setting.OAuth2.AccessTokenExpirationTime,
+		RefreshToken: signedRefreshToken,
+		IDToken:      signedIDToken,
+	}, nil
+}
+
+// getClientCredentials returns the client id and secret of the request, they may be sent
+// in the request body or using HTTP basic authentication.
+func getClientCredentials(ctx *context.Context, clientID, clientSecret string) (string, string) {
+	if len(clientID) > 0 {
+		return clientID, clientSecret
+	}
+	auths := strings.Fields(ctx.Req.Header.Get("Authorization"))
+	if len(auths) == 2 && auths[0] == "Basic" {
+		id, secret, err := base.BasicAuthDecode(auths[1])
+		if err == nil {
+			return id, secret
+		}
+	}
+	return "", ""
+}
+
+// getAuthorizeRedirectURI returns the redirect uri requested by the client if it has been
+// registered for the application. Without a requested uri the only registered uri is used.
+func getAuthorizeRedirectURI(app *models.OAuth2Application, redirectURI string) (string, bool) {
+	if len(redirectURI) == 0 {
+		if len(app.RedirectURIs) != 1 {
+			return "", false
+		}
+		return app.RedirectURIs[0], true
+	}
+	return redirectURI, app.ContainsRedirectURI(redirectURI)
+}
+
+// AuthorizeOAuth manages authorize requests
+func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
+	if ctx.HasError() {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidRequest,
+			ErrorDescription: ctx.GetErrMsg(),
+			State:            form.State,
+		}, "")
+		return
+	}
+
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		if models.IsErrOAuthClientIDInvalid(err) {
+			handleAuthorizeError(ctx, AuthorizeError{
+				ErrorCode:        ErrorCodeUnauthorizedClient,
+				ErrorDescription: "Client ID not registered",
+				State:            form.State,
+			}, "")
+			return
+		}
+		ctx.ServerError("GetOAuth2ApplicationByClientID", err)
+		return
+	}
+	if err := app.LoadOwner(); err != nil {
+		ctx.ServerError("LoadOwner", err)
+		return
+	}
+
+	redirectURI, ok := getAuthorizeRedirectURI(app, form.RedirectURI)
+	if !ok {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidRequest,
+			ErrorDescription: "Unregistered Redirect URI",
+			State:            form.State,
+		}, "")
+		return
+	}
+
+	if form.ResponseType != "code" {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeUnsupportedResponseType,
+			ErrorDescription: "Only code response type is supported.",
+			State:            form.State,
+		}, redirectURI)
+		return
+	}
+
+	// pkce support
+	switch form.CodeChallengeMethod {
+	case "S256", "plain":
+		if len(form.CodeChallenge) == 0 {
+			handleAuthorizeError(ctx, AuthorizeError{
+				ErrorCode:        ErrorCodeInvalidRequest,
+				ErrorDescription: "code challenge required",
+				State:            form.State,
+			}, redirectURI)
+			return
+		}
+	case "":
+		// "plain" is the default method if a code challenge is given, see https://tools.ietf.org/html/rfc7636#section-4.3
+		if len(form.CodeChallenge) > 0 {
+			form.CodeChallengeMethod = "plain"
+		}
+	default:
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidRequest,
+			ErrorDescription: "unsupported code challenge method",
+			State:            form.State,
+		}, redirectURI)
+		return
+	}
+
+	grant, err := app.GetGrantByUserID(ctx.User.ID)
+	if err != nil {
+		handleServerError(ctx, form.State, redirectURI)
+		return
+	}
+
+	// Redirect if the user already granted access with the same scope
+	if grant != nil && grant.Scope == form.Scope {
+		if err := grant.SetNonce(form.Nonce); err != nil {
+			log.Error(4, "Unable to update nonce: %v", err)
+		}
+		code, err := grant.GenerateNewAuthorizationCode(redirectURI, form.CodeChallenge, form.CodeChallengeMethod)
+		if err != nil {
+			handleServerError(ctx, form.State, redirectURI)
+			return
+		}
+		redirect, err := code.GenerateRedirectURI(form.State)
+		if err != nil {
+			handleServerError(ctx, form.State, redirectURI)
+			return
+		}
+		ctx.Redirect(redirect.String(), 302)
+		return
+	}
+
+	// show authorize page to grant access
+	ctx.Data["Title"] = ctx.Tr("auth.authorize_title", app.Name)
+	ctx.Data["Application"] = app
+	ctx.Data["RedirectURI"] = redirectURI
+	ctx.Data["State"] = form.State
+	ctx.Data["Scope"] = form.Scope
+	ctx.Data["Scopes"] = strings.Fields(form.Scope)
+	ctx.Data["Nonce"] = form.Nonce
+	ctx.Data["CodeChallengeMethod"] = form.CodeChallengeMethod
+	ctx.Data["CodeChallenge"] = form.CodeChallenge
+	ctx.Data["ApplicationUserLink"] = "<a href=\"" + html.EscapeString(app.Owner.HTMLURL()) + "\">@" + html.EscapeString(app.Owner.Name) + "</a>"
+	if u, err := url.Parse(redirectURI); err == nil {
+		ctx.Data["ApplicationRedirectDomainHTML"] = "<strong>" + html.EscapeString(u.Host) + "</strong>"
+	}
+	ctx.HTML(200, tplGrantAccess)
+}
+
+// GrantApplicationOAuth manages the post request submitted when a user grants access to an application
+func GrantApplicationOAuth(ctx *context.Context, form auth.GrantApplicationForm) {
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
+	if err != nil {
+		if models.IsErrOAuthClientIDInvalid(err) {
+			ctx.Error(400)
+			return
+		}
+		ctx.ServerError("GetOAuth2ApplicationByClientID", err)
+		return
+	}
+	redirectURI, ok := getAuthorizeRedirectURI(app, form.RedirectURI)
+	if !ok {
+		ctx.Error(400)
+		return
+	}
+
+	if ctx.Query("granted") != "true" {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeAccessDenied,
+			ErrorDescription: "the request is denied",
+			State:            form.State,
+		}, redirectURI)
+		return
+	}
+
+	grant, err := app.CreateGrant(ctx.User.ID, form.Scope)
+	if err != nil {
+		handleServerError(ctx, form.State, redirectURI)
+		return
+	}
+	if err := grant.SetNonce(form.Nonce); err != nil {
+		log.Error(4, "Unable to update nonce: %v", err)
+	}
+
+	code, err := grant.GenerateNewAuthorizationCode(redirectURI, form.CodeChallenge, form.CodeChallengeMethod)
+	if err != nil {
+		handleServerError(ctx, form.State, redirectURI)
+		return
+	}
+	redirect, err := code.GenerateRedirectURI(form.State)
+	if err != nil {
+		handleServerError(ctx, form.State, redirectURI)
+		return
+	}
+	ctx.Redirect(redirect.String(), 302)
+}
+
+// AccessTokenOAuth manages all access token requests by the client
+func AccessTokenOAuth(ctx *context.Context, form auth.AccessTokenForm) {
+	form.ClientID, form.ClientSecret = getClientCredentials(ctx, form.ClientID, form.ClientSecret)
+
+	switch form.GrantType {
+	case "refresh_token":
+		handleRefreshToken(ctx, form)
+	case "authorization_code":
+		handleAuthorizationCode(ctx, form)
+	default:
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnsupportedGrantType,
+			ErrorDescription: "Only refresh_token or authorization_code grant type is supported",
+		})
+	}
+}
+
+// getOAuth2Client returns the application of the given client after authenticating it
+// with its secret. Only public clients may omit the secret and only if allowPublic is set.
+func getOAuth2Client(ctx *context.Context, clientID, clientSecret string, allowPublic bool) *models.OAuth2Application {
+	app, err := models.GetOAuth2ApplicationByClientID(clientID)
+	if err != nil {
+		if !models.IsErrOAuthClientIDInvalid(err) {
+			log.Error(4, "GetOAuth2ApplicationByClientID: %v", err)
+		}
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", clientID),
+		})
+		return nil
+	}
+	if len(clientSecret) == 0 && allowPublic && !app.ConfidentialClient {
+		return app
+	}
+	if !app.ValidateClientSecret(clientSecret) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return nil
+	}
+	return app
+}
+
+func handleRefreshToken(ctx *context.Context, form auth.AccessTokenForm) {
+	// RFC 6749 section 6 requires clients to authenticate when refreshing tokens
+	app := getOAuth2Client(ctx, form.ClientID, form.ClientSecret, false)
+	if app == nil {
+		return
+	}
+
+	token, err := models.ParseOAuth2Token(form.RefreshToken)
+	if err != nil || token.Type != models.TypeRefreshToken {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return
+	}
+	// get grant before increasing counter
+	grant, err := models.GetOAuth2GrantByID(token.GrantID)
+	if err != nil || grant == nil || grant.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "grant does not exist",
+		})
+		return
+	}
+
+	// check if token got already used
+	if grant.Counter != token.Counter || token.Counter == 0 {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "token was already used",
+		})
+		log.Warn("A client tried to use a refresh token for grant_id = %d was used twice!", grant.ID)
+		return
+	}
+	if err := grant.IncreaseCounter(); err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "cannot increase the grant counter",
+		})
+		return
+	}
+
+	accessToken, tokenErr := newAccessTokenResponse(grant)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, accessToken)
+}
+
+func handleAuthorizationCode(ctx *context.Context, form auth.AccessTokenForm) {
+	app := getOAuth2Client(ctx, form.ClientID, form.ClientSecret, true)
+	if app == nil {
+		return
+	}
+
+	authorizationCode, err := models.GetOAuth2AuthorizationByCode(form.Code)
+	if err != nil || authorizationCode == nil || authorizationCode.IsExpired() ||
+		authorizationCode.Grant.ApplicationID != app.ID {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return
+	}
+	if len(form.RedirectURI) > 0 && form.RedirectURI != authorizationCode.RedirectURI {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
+			ErrorDescription: "redirect uri does not match",
+		})
+		return
+	}
+
+	// Public clients without a secret have to prove the possession of the code using PKCE.
+	if len(form.ClientSecret) == 0 && len(authorizationCode.CodeChallenge) == 0 {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: "client secret or code verifier required",
+		})
+		return
+	}
+	if !authorizationCode.ValidateCodeChallenge(form.CodeVerifier) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return
+	}
+
+	// remove token from database to deny duplicate usage
+	if err := authorizationCode.Invalidate(); err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot proceed your request",
+		})
+		return
+	}
+
+	accessToken, tokenErr := newAccessTokenResponse(authorizationCode.Grant)
+	if tokenErr != nil {
+		handleAccessTokenError(ctx, *tokenErr)
+		return
+	}
+	ctx.JSON(200, accessToken)
+}
+
+// RevokeTokenOAuth revokes the grant an access or refresh token has been issued for, see RFC 7009
+func RevokeTokenOAuth(ctx *context.Context, form auth.RevokeTokenForm) {
+	form.ClientID, form.ClientSecret = getClientCredentials(ctx, form.ClientID, form.ClientSecret)
+	// like the authorization code, the token to revoke proves the possession of a public client
+	app := getOAuth2Client(ctx, form.ClientID, form.ClientSecret, true)
+	if app == nil {
+		return
+	}
+
+	// Invalid tokens do not cause an error response, the client cannot do anything about them.
+	token, err := models.ParseOAuth2Token(form.Token)
+	if err == nil {
+		grant, err := models.GetOAuth2GrantByID(token.GrantID)
+		if err != nil {
+			log.Error(4, "GetOAuth2GrantByID: %v", err)
+			ctx.Status(503)
+			return
+		}
+		if grant != nil && grant.ApplicationID == app.ID {
+			if err := models.RevokeOAuth2Grant(grant.ID, grant.UserID); err != nil {
+				log.Error(4, "RevokeOAuth2Grant: %v", err)
+				ctx.Status(503)
+				return
+			}
+		}
+	}
+	ctx.Status(200)
+}
+
+// userInfoResponse represents the claims returned by the userinfo endpoint
+type userInfoResponse struct {
+	Sub               string `json:"sub"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Picture           string `json:"picture"`
+	Profile           string `json:"profile"`
+	Website           string `json:"website,omitempty"`
+}
+
+// InfoOAuth returns the OpenID Connect claims of the user an access token has been issued for
+func InfoOAuth(ctx *context.Context) {
+	auths := strings.Fields(ctx.Req.Header.Get("Authorization"))
+	if len(auths) != 2 || strings.ToLower(auths[0]) != "bearer" {
+		ctx.Resp.Header().Set("WWW-Authenticate", `Bearer realm=""`)
+		ctx.Status(401)
+		return
+	}
+
+	uid, _, err := models.GetUserIDByOAuth2AccessToken(auths[1])
+	if err != nil {
+		ctx.ServerError("GetUserIDByOAuth2AccessToken", err)
+		return
+	} else if uid == 0 {
+		ctx.Resp.Header().Set("WWW-Authenticate", `Bearer realm="", error="invalid_token"`)
+		ctx.Status(401)
+		return
+	}
+
+	user, err := models.GetUserByID(uid)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Resp.Header().Set("WWW-Authenticate", `Bearer realm="", error="invalid_token"`)
+			ctx.Status(401)
+		} else {
+			ctx.ServerError("GetUserByID", err)
+		}
+		return
+	}
+
+	ctx.JSON(200, &userInfoResponse{
+		Sub:               fmt.Sprint(user.ID),
+		Name:              user.FullName,
+		PreferredUsername: user.Name,
+		Email:             user.Email,
+		EmailVerified:     user.IsActive,
+		Picture:           user.AvatarLink(),
+		Profile:           user.HTMLURL(),
+		Website:           user.Website,
+	})
+}
+
+// OIDCWellKnown generates the OpenID Connect discovery document
+func OIDCWellKnown(ctx *context.Context) {
+	appURL := strings.TrimSuffix(setting.AppURL, "/")
+
+	scopes := []string{"openid", "profile", "email"}
+	for _, scope := range models.AccessTokenScopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"issuer":                                appURL,
+		"authorization_endpoint":                appURL + "/login/oauth/authorize",
+		"token_endpoint":                        appURL + "/login/oauth/access_token",
+		"userinfo_endpoint":                     appURL + "/login/oauth/userinfo",
+		"revocation_endpoint":                   appURL + "/login/oauth/revoke",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"HS256"},
+		"scopes_supported":                      scopes,
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"plain", "S256"},
+		"claims_supported": []string{
+			"aud", "exp", "iat", "iss", "sub", "nonce",
+			"name", "preferred_username", "profile", "picture", "website", "updated_at",
+			"email", "email_verified",
+		},
+	})
+}
+
+func handleAccessTokenError(ctx *context.Context, acErr AccessTokenError) {
+	status := 400
+	if acErr.ErrorCode == AccessTokenErrorCodeInvalidClient {
+		status = 401
+	}
+	ctx.JSON(status, acErr)
+}
+
+func handleServerError(ctx *context.Context, state string, redirectURI string) {
+	handleAuthorizeError(ctx, AuthorizeError{
+		ErrorCode:        ErrorCodeServerError,
+		ErrorDescription: "A server error occurred",
+		State:            state,
+	}, redirectURI)
+}
+
+func handleAuthorizeError(ctx *context.Context, authErr AuthorizeError, redirectURI string) {
+	if redirectURI == "" {
+		log.Warn("Authorization failed: %v", authErr.ErrorDescription)
+		ctx.Data["Title"] = ctx.Tr("auth.authorize_error_title")
+		ctx.Data["Error"] = authErr
+		ctx.HTML(400, tplGrantError)
+		return
+	}
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		ctx.ServerError("url.Parse", err)
+		return
+	}
+	q := redirect.Query()
+	q.Set("error", string(authErr.ErrorCode))
+	q.Set("error_description", authErr.ErrorDescription)
+	if len(authErr.State) > 0 {
+		q.Set("state", authErr.State)
+	}
+	redirect.RawQuery = q.Encode()
+	ctx.Redirect(redirect.String(), 302)
+}
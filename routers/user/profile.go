@@ -132,6 +132,8 @@ func Profile(ctx *context.Context) {
 
 	keyword := strings.Trim(ctx.Query("q"), " ")
 	ctx.Data["Keyword"] = keyword
+	topicOnly := ctx.QueryBool("topic")
+	ctx.Data["TopicOnly"] = topicOnly
 	switch tab {
 	case "activity":
 		retrieveFeeds(ctx, models.GetFeedsOptions{RequestedUser: ctxUser,
@@ -159,6 +161,7 @@ func Profile(ctx *context.Context) {
 		} else {
 			repos, count, err = models.SearchRepositoryByName(&models.SearchRepoOptions{
 				Keyword:     keyword,
+				TopicOnly:   topicOnly,
 				OwnerID:     ctxUser.ID,
 				OrderBy:     orderBy,
 				Private:     showPrivate,
@@ -202,6 +205,7 @@ func Profile(ctx *context.Context) {
 		} else {
 			repos, count, err = models.SearchRepositoryByName(&models.SearchRepoOptions{
 				Keyword:   keyword,
+				TopicOnly: topicOnly,
 				OwnerID:   ctxUser.ID,
 				OrderBy:   orderBy,
 				Private:   showPrivate,
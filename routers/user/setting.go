@@ -10,8 +10,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
+	"unicode"
 
-	"github.com/Unknwon/com"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 
@@ -25,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 )
 
 const (
@@ -149,7 +151,7 @@ func UpdateAvatarSetting(ctx *context.Context, form auth.AvatarForm, ctxUser *mo
 	} else {
 		// No avatar is uploaded but setting has been changed to enable,
 		// generate a random one when needed.
-		if ctxUser.UseCustomAvatar && !com.IsFile(ctxUser.CustomAvatarPath()) {
+		if ctxUser.UseCustomAvatar && !ctxUser.CustomAvatarExists() {
 			if err := ctxUser.GenerateRandomAvatar(); err != nil {
 				log.Error(4, "GenerateRandomAvatar[%d]: %v", ctxUser.ID, err)
 			}
@@ -278,6 +280,24 @@ func SettingsEmailPost(ctx *context.Context, form auth.AddEmailForm) {
 		return
 	}
 
+	// Set email notifications preference.
+	if ctx.Query("_method") == "NOTIFICATION" {
+		preference := ctx.Query("preference")
+		if !models.IsValidEmailNotificationsPreference(preference) {
+			ctx.Error(400, "invalid email notifications preference")
+			return
+		}
+		if err := ctx.User.SetEmailNotifications(preference); err != nil {
+			ctx.ServerError("SetEmailNotifications", err)
+			return
+		}
+
+		log.Trace("Email notifications preference set to %s: %s", preference, ctx.User.Name)
+		ctx.Flash.Success(ctx.Tr("settings.email_preference_set_success"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/email")
+		return
+	}
+
 	// Add Email address.
 	emails, err := models.GetEmailAddresses(ctx.User.ID)
 	if err != nil {
@@ -471,14 +491,35 @@ func SettingsApplications(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("settings")
 	ctx.Data["PageIsSettingsApplications"] = true
 
+	if !loadApplicationsData(ctx) {
+		return
+	}
+
+	ctx.HTML(200, tplSettingsApplications)
+}
+
+func loadApplicationsData(ctx *context.Context) bool {
 	tokens, err := models.ListAccessTokens(ctx.User.ID)
 	if err != nil {
 		ctx.ServerError("ListAccessTokens", err)
-		return
+		return false
 	}
 	ctx.Data["Tokens"] = tokens
+	ctx.Data["AccessTokenScopes"] = models.AccessTokenScopes
 
-	ctx.HTML(200, tplSettingsApplications)
+	if setting.OAuth2.Enable {
+		ctx.Data["Applications"], err = models.GetOAuth2ApplicationsByUserID(ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("GetOAuth2ApplicationsByUserID", err)
+			return false
+		}
+		ctx.Data["Grants"], err = models.GetOAuth2GrantsByUserID(ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("GetOAuth2GrantsByUserID", err)
+			return false
+		}
+	}
+	return true
 }
 
 // SettingsApplicationsPost response for add user's access token
@@ -486,21 +527,64 @@ func SettingsApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm
 	ctx.Data["Title"] = ctx.Tr("settings")
 	ctx.Data["PageIsSettingsApplications"] = true
 
+	if !loadApplicationsData(ctx) {
+		return
+	}
+
 	if ctx.HasError() {
-		tokens, err := models.ListAccessTokens(ctx.User.ID)
-		if err != nil {
-			ctx.ServerError("ListAccessTokens", err)
-			return
-		}
-		ctx.Data["Tokens"] = tokens
 		ctx.HTML(200, tplSettingsApplications)
 		return
 	}
 
+	scopes, err := models.ParseAccessTokenScopes(form.Scopes)
+	if err != nil {
+		if models.IsErrInvalidAccessTokenScope(err) {
+			ctx.Data["Err_Scopes"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.token_scopes_invalid", err.(models.ErrInvalidAccessTokenScope).Scope), tplSettingsApplications, &form)
+		} else {
+			ctx.ServerError("ParseAccessTokenScopes", err)
+		}
+		return
+	}
+
+	repoNames := strings.FieldsFunc(form.Repos, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	repoIDs, err := models.GetRepositoryIDsByFullNames(repoNames)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			repoName := err.(models.ErrRepoNotExist).Name
+			if ownerName := err.(models.ErrRepoNotExist).OwnerName; len(ownerName) > 0 {
+				repoName = ownerName + "/" + repoName
+			}
+			ctx.Data["Err_Repos"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.token_repo_not_exist", repoName), tplSettingsApplications, &form)
+		} else {
+			ctx.ServerError("GetRepositoryIDsByFullNames", err)
+		}
+		return
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:     ctx.User.ID,
+		Name:    form.Name,
+		Scopes:  scopes,
+		RepoIDs: repoIDs,
 	}
+
+	if len(form.ExpiresAt) > 0 {
+		expiresAt, err := time.ParseInLocation("2006-01-02", form.ExpiresAt, time.Local)
+		if err == nil {
+			// The token is valid until the end of the given day.
+			t.ExpiredUnix = util.TimeStamp(expiresAt.AddDate(0, 0, 1).Unix())
+		}
+		if err != nil || t.IsExpired() {
+			ctx.Data["Err_ExpiresAt"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.token_expiry_invalid"), tplSettingsApplications, &form)
+			return
+		}
+	}
+
 	if err := models.NewAccessToken(t); err != nil {
 		ctx.ServerError("NewAccessToken", err)
 		return
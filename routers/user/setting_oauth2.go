@@ -0,0 +1,229 @@
+// Copyright 2018 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsOAuthApplicationEdit    base.TplName = "user/settings/applications_oauth2_edit"
+	tplOrgSettingsOAuthApplicationEdit base.TplName = "org/settings/applications_oauth2_edit"
+)
+
+type oauth2OwnerCtx struct {
+	OwnerID      int64
+	Title        string
+	Link         string
+	EditTemplate base.TplName
+}
+
+// getOAuth2OwnerCtx determines whether the applications of a user or an organization are managed.
+func getOAuth2OwnerCtx(ctx *context.Context) *oauth2OwnerCtx {
+	if ctx.Org != nil && len(ctx.Org.OrgLink) > 0 {
+		return &oauth2OwnerCtx{
+			OwnerID:      ctx.Org.Organization.ID,
+			Title:        ctx.Tr("org.settings"),
+			Link:         ctx.Org.OrgLink + "/settings/applications",
+			EditTemplate: tplOrgSettingsOAuthApplicationEdit,
+		}
+	}
+	return &oauth2OwnerCtx{
+		OwnerID:      ctx.User.ID,
+		Title:        ctx.Tr("settings"),
+		Link:         setting.AppSubURL + "/user/settings/applications",
+		EditTemplate: tplSettingsOAuthApplicationEdit,
+	}
+}
+
+// parseOAuth2RedirectURIs splits the given text into redirect uris, every uri must be absolute
+// and must not contain a fragment, see https://tools.ietf.org/html/rfc6749#section-3.1.2
+func parseOAuth2RedirectURIs(text string) ([]string, error) {
+	uris := strings.Fields(text)
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || len(u.Fragment) > 0 {
+			return nil, fmt.Errorf("invalid redirect uri: %s", uri)
+		}
+	}
+	return uris, nil
+}
+
+// getOAuth2Application returns the application given by the :id parameter if it
+// belongs to the current owner, otherwise it responds with a 404.
+func getOAuth2Application(ctx *context.Context, oaCtx *oauth2OwnerCtx) *models.OAuth2Application {
+	app, err := models.GetOAuth2ApplicationByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrOAuthApplicationNotFound(err) {
+			ctx.NotFound("GetOAuth2ApplicationByID", err)
+		} else {
+			ctx.ServerError("GetOAuth2ApplicationByID", err)
+		}
+		return nil
+	}
+	if app.UID != oaCtx.OwnerID {
+		ctx.NotFound("GetOAuth2ApplicationByID", nil)
+		return nil
+	}
+	return app
+}
+
+// OAuthApplicationsPost response for registering a new oauth2 application
+func OAuthApplicationsPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	oaCtx := getOAuth2OwnerCtx(ctx)
+	ctx.Data["Title"] = oaCtx.Title
+	ctx.Data["PageIsSettingsApplications"] = true
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(oaCtx.Link)
+		return
+	}
+
+	uris, err := parseOAuth2RedirectURIs(form.RedirectURIs)
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("settings.oauth2_redirect_uri_invalid"))
+		ctx.Redirect(oaCtx.Link)
+		return
+	}
+
+	app, err := models.CreateOAuth2Application(models.CreateOAuth2ApplicationOptions{
+		Name:               form.Name,
+		UserID:             oaCtx.OwnerID,
+		RedirectURIs:       uris,
+		ConfidentialClient: form.ConfidentialClient,
+	})
+	if err != nil {
+		ctx.ServerError("CreateOAuth2Application", err)
+		return
+	}
+	secret, err := app.GenerateClientSecret()
+	if err != nil {
+		ctx.ServerError("GenerateClientSecret", err)
+		return
+	}
+
+	// The client secret can only be shown right after it has been generated.
+	ctx.Data["App"] = app
+	ctx.Data["FormActionLink"] = fmt.Sprintf("%s/oauth2/%d", oaCtx.Link, app.ID)
+	ctx.Data["ClientSecret"] = secret
+	ctx.Flash.SuccessMsg = ctx.Tr("settings.create_oauth2_application_success")
+	ctx.Data["Flash"] = ctx.Flash
+	ctx.HTML(200, oaCtx.EditTemplate)
+}
+
+// OAuthApplicationsEdit renders the settings page of an oauth2 application
+func OAuthApplicationsEdit(ctx *context.Context) {
+	oaCtx := getOAuth2OwnerCtx(ctx)
+	ctx.Data["Title"] = oaCtx.Title
+	ctx.Data["PageIsSettingsApplications"] = true
+
+	app := getOAuth2Application(ctx, oaCtx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["App"] = app
+	ctx.Data["FormActionLink"] = fmt.Sprintf("%s/oauth2/%d", oaCtx.Link, app.ID)
+	ctx.HTML(200, oaCtx.EditTemplate)
+}
+
+// OAuthApplicationsEditPost response for updating an oauth2 application
+func OAuthApplicationsEditPost(ctx *context.Context, form auth.EditOAuth2ApplicationForm) {
+	oaCtx := getOAuth2OwnerCtx(ctx)
+	ctx.Data["Title"] = oaCtx.Title
+	ctx.Data["PageIsSettingsApplications"] = true
+
+	app := getOAuth2Application(ctx, oaCtx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["App"] = app
+	ctx.Data["FormActionLink"] = fmt.Sprintf("%s/oauth2/%d", oaCtx.Link, app.ID)
+
+	if ctx.HasError() {
+		ctx.HTML(200, oaCtx.EditTemplate)
+		return
+	}
+
+	uris, err := parseOAuth2RedirectURIs(form.RedirectURIs)
+	if err != nil {
+		ctx.Data["Err_RedirectURIs"] = true
+		ctx.RenderWithErr(ctx.Tr("settings.oauth2_redirect_uri_invalid"), oaCtx.EditTemplate, &form)
+		return
+	}
+
+	if _, err = models.UpdateOAuth2Application(models.UpdateOAuth2ApplicationOptions{
+		ID:                 app.ID,
+		Name:               form.Name,
+		UserID:             oaCtx.OwnerID,
+		RedirectURIs:       uris,
+		ConfidentialClient: form.ConfidentialClient,
+	}); err != nil {
+		ctx.ServerError("UpdateOAuth2Application", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.update_oauth2_application_success"))
+	ctx.Redirect(fmt.Sprintf("%s/oauth2/%d", oaCtx.Link, app.ID))
+}
+
+// OAuthApplicationsRegenerateSecret replaces the client secret of an oauth2 application
+func OAuthApplicationsRegenerateSecret(ctx *context.Context) {
+	oaCtx := getOAuth2OwnerCtx(ctx)
+	ctx.Data["Title"] = oaCtx.Title
+	ctx.Data["PageIsSettingsApplications"] = true
+
+	app := getOAuth2Application(ctx, oaCtx)
+	if ctx.Written() {
+		return
+	}
+	secret, err := app.GenerateClientSecret()
+	if err != nil {
+		ctx.ServerError("GenerateClientSecret", err)
+		return
+	}
+
+	ctx.Data["App"] = app
+	ctx.Data["FormActionLink"] = fmt.Sprintf("%s/oauth2/%d", oaCtx.Link, app.ID)
+	ctx.Data["ClientSecret"] = secret
+	ctx.Flash.SuccessMsg = ctx.Tr("settings.update_oauth2_application_success")
+	ctx.Data["Flash"] = ctx.Flash
+	ctx.HTML(200, oaCtx.EditTemplate)
+}
+
+// DeleteOAuth2Application deletes an oauth2 application together with its grants
+func DeleteOAuth2Application(ctx *context.Context) {
+	oaCtx := getOAuth2OwnerCtx(ctx)
+	if err := models.DeleteOAuth2Application(ctx.QueryInt64("id"), oaCtx.OwnerID); err != nil {
+		ctx.Flash.Error("DeleteOAuth2Application: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.remove_oauth2_application_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": oaCtx.Link,
+	})
+}
+
+// RevokeOAuth2Grant revokes the access the user has granted to an oauth2 application
+func RevokeOAuth2Grant(ctx *context.Context) {
+	if err := models.RevokeOAuth2Grant(ctx.QueryInt64("id"), ctx.User.ID); err != nil {
+		ctx.Flash.Error("RevokeOAuth2Grant: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.revoke_oauth2_grant_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/applications",
+	})
+}